@@ -11,6 +11,7 @@ import (
 	"armario-mascota-me/pricing"
 	"armario-mascota-me/repository"
 	"armario-mascota-me/service"
+	"armario-mascota-me/utils"
 )
 
 // Initialize initializes the application
@@ -62,7 +63,13 @@ func Initialize() error {
 	reservedOrderRepo := repository.NewReservedOrderRepository()
 	saleRepo := repository.NewSaleRepository()
 	financeTransactionRepo := repository.NewFinanceTransactionRepository()
+	financeTemplateRepo := repository.NewFinanceTemplateRepository()
+	recurringTransactionRepo := repository.NewRecurringTransactionRepository()
+	financeBudgetRepo := repository.NewFinanceBudgetRepository()
+	financeReferenceRepo := repository.NewFinanceReferenceRepository()
 	catalogRepo := repository.NewCatalogRepository()
+	webhookFailureRepo := repository.NewWebhookFailureRepository()
+	reportRepo := repository.NewReportRepository()
 
 	// Initialize sync service
 	syncService := service.NewSyncService(driveService, designAssetRepo)
@@ -70,6 +77,15 @@ func Initialize() error {
 	// Initialize download service
 	downloadService := service.NewDownloadService(driveService)
 
+	// Initialize sale webhook service (disabled when SALE_WEBHOOK_URL is empty)
+	saleWebhookService := service.NewSaleWebhookService(os.Getenv("SALE_WEBHOOK_URL"), webhookFailureRepo)
+
+	// Start recurring finance transactions worker (materializes due recurrences daily)
+	service.NewRecurringTransactionWorker(recurringTransactionRepo).Start()
+
+	// Start reserved order hold worker (cancels orders whose holdUntil has passed)
+	service.NewOrderHoldWorker(reservedOrderRepo).Start()
+
 	// Initialize pricing engine
 	pricingConfigPath := os.Getenv("PRICING_CONFIG_PATH")
 	if pricingConfigPath == "" {
@@ -79,30 +95,44 @@ func Initialize() error {
 		return fmt.Errorf("failed to initialize pricing engine: %w", err)
 	}
 
+	// Initialize auto-tag rules for pending design assets
+	autoTagRulesPath := os.Getenv("AUTO_TAG_RULES_PATH")
+	if autoTagRulesPath == "" {
+		autoTagRulesPath = "configs/auto_tag_rules.json"
+	}
+	autoTagService, err := service.NewAutoTagService(autoTagRulesPath)
+	if err != nil {
+		return fmt.Errorf("failed to initialize auto-tag rules: %w", err)
+	}
+
 	// Get base URL for catalog service (for image fetching)
-	baseURL := os.Getenv("BASE_URL")
-	if baseURL == "" {
-		// Only use localhost fallback in local development
-		env := os.Getenv("ENV")
-		if env != "production" {
-			baseURL = "http://localhost:8080"
-			fmt.Printf("DEBUG: BASE_URL not set, using localhost fallback for local development\n")
+	baseURL := utils.BaseURL()
+	if os.Getenv("BASE_URL") == "" {
+		if os.Getenv("ENV") != "production" {
+			fmt.Printf("DEBUG: BASE_URL not set, using %s for local development\n", baseURL)
 		} else {
 			// In production, BASE_URL should be set
-			fmt.Printf("WARNING: BASE_URL environment variable is not set in production. Catalog generation may fail.\n")
-			baseURL = "http://localhost:8080" // Fallback, but will likely fail in production
+			fmt.Printf("WARNING: BASE_URL environment variable is not set in production, falling back to %s. Catalog generation may fail.\n", baseURL)
 		}
 	}
 
 	// Create controllers
 	controllers := &router.Controllers{
-		DesignAsset:        controller.NewDesignAssetController(syncService, designAssetRepo, driveService),
-		Item:               controller.NewItemController(itemRepo),
-		ReservedOrder:      controller.NewReservedOrderController(reservedOrderRepo),
-		Sale:               controller.NewSaleController(saleRepo),
-		FinanceTransaction: controller.NewFinanceTransactionController(financeTransactionRepo),
-		Catalog:            controller.NewCatalogController(catalogRepo, designAssetRepo, driveService, baseURL),
-		Download:           controller.NewDownloadController(downloadService),
+		DesignAsset:          controller.NewDesignAssetController(syncService, designAssetRepo, driveService, autoTagService),
+		Item:                 controller.NewItemController(itemRepo),
+		ReservedOrder:        controller.NewReservedOrderController(reservedOrderRepo),
+		Sale:                 controller.NewSaleController(saleRepo, saleWebhookService),
+		FinanceTransaction:   controller.NewFinanceTransactionController(financeTransactionRepo, financeTemplateRepo),
+		FinanceTemplate:      controller.NewFinanceTemplateController(financeTemplateRepo),
+		RecurringTransaction: controller.NewRecurringTransactionController(recurringTransactionRepo),
+		FinanceBudget:        controller.NewFinanceBudgetController(financeBudgetRepo),
+		FinanceDestination:   controller.NewFinanceReferenceController(financeReferenceRepo, repository.FinanceReferenceDestination, "/admin/finance/destinations"),
+		FinanceCategory:      controller.NewFinanceReferenceController(financeReferenceRepo, repository.FinanceReferenceCategory, "/admin/finance/categories"),
+		Catalog:              controller.NewCatalogController(catalogRepo, designAssetRepo, driveService, baseURL),
+		Download:             controller.NewDownloadController(downloadService),
+		Webhook:              controller.NewWebhookController(webhookFailureRepo, saleWebhookService),
+		Report:               controller.NewReportController(reportRepo),
+		Pricing:              controller.NewPricingController(itemRepo, pricingConfigPath),
 	}
 
 	// Setup routes using standard http router
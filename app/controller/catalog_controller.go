@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"armario-mascota-me/models"
+	"armario-mascota-me/pricing"
 	"armario-mascota-me/repository"
 	"armario-mascota-me/service"
 	"armario-mascota-me/utils"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // CatalogController handles HTTP requests for catalog generation
@@ -27,6 +32,13 @@ type CatalogController struct {
 	// Temporary storage for PNG pages (key: sessionID, value: map of page number to PNG data)
 	pngStorage      map[string]map[int][]byte
 	pngStorageMutex sync.RWMutex
+	// In-memory async generation jobs (key: jobID) and finished PDFs of those jobs
+	jobs            map[string]*models.CatalogJob
+	jobPDFs         map[string][]byte
+	jobsMutex       sync.RWMutex
+	// renderGroup collapses concurrent PDF/PNG renders for the same size+format into one call
+	renderGroup     singleflight.Group
+	dedupRenders    bool
 }
 
 // NewCatalogController creates a new CatalogController
@@ -44,9 +56,27 @@ func NewCatalogController(
 		driveService:    driveService,
 		baseURL:         baseURL,
 		pngStorage:      make(map[string]map[int][]byte),
+		jobs:            make(map[string]*models.CatalogJob),
+		jobPDFs:         make(map[string][]byte),
+		dedupRenders:    os.Getenv("CATALOG_RENDER_DEDUP") != "false",
 	}
 }
 
+// renderOnce runs fn at most once at a time per size+format+options. Concurrent callers
+// with the same key wait for the in-flight render and reuse its result.
+// Setting CATALOG_RENDER_DEDUP=false disables the guard.
+func (c *CatalogController) renderOnce(size, format string, opts service.CatalogOptions, fn func() (interface{}, error)) (interface{}, error) {
+	if !c.dedupRenders {
+		return fn()
+	}
+	key := fmt.Sprintf("%s|%s|%d|%s|%s|%s|%s", size, format, opts.PerPage, opts.Filters.ColorPrimary, opts.Filters.HoodieType, opts.Filters.Sort, opts.Paper)
+	result, err, shared := c.renderGroup.Do(key, fn)
+	if shared {
+		log.Printf("🔁 GenerateCatalog: Reused in-flight %s render for size=%s", format, size)
+	}
+	return result, err
+}
+
 // validSizes is a map of valid size values
 var validSizes = map[string]bool{
 	"XS": true,
@@ -58,6 +88,93 @@ var validSizes = map[string]bool{
 	"IT": true, // Intermedio
 }
 
+// parseCatalogFilters reads the optional color and hoodieType query parameters, accepting either
+// readable names (e.g., "negro", "buso estándar") or codes (e.g., "NG", "BU"), and the optional
+// sort parameter (newest, price_asc, price_desc, color)
+func parseCatalogFilters(r *http.Request) (repository.CatalogFilterParams, error) {
+	var filters repository.CatalogFilterParams
+	if color := strings.TrimSpace(r.URL.Query().Get("color")); color != "" {
+		filters.ColorPrimary = utils.MapColorToCode(color)
+	}
+	if hoodieType := strings.TrimSpace(r.URL.Query().Get("hoodieType")); hoodieType != "" {
+		filters.HoodieType = utils.MapHoodieTypeToCode(hoodieType)
+	}
+	filters.Sort = strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sort")))
+	if !repository.IsValidCatalogSort(filters.Sort) {
+		return filters, fmt.Errorf("Invalid sort. Valid sorts: newest, price_asc, price_desc, color")
+	}
+	return filters, nil
+}
+
+// parseCatalogPaper reads the optional paper preset (catalog, a4, letter, custom) and the
+// paperWidth/paperHeight parameters in mm, defaulting to the 210mm x 350mm catalog paper
+func parseCatalogPaper(r *http.Request) (utils.CatalogPaper, error) {
+	query := r.URL.Query()
+	return utils.ParseCatalogPaper(query.Get("paper"), query.Get("paperWidth"), query.Get("paperHeight"))
+}
+
+// parseCatalogSizes reads the sizes to render: a comma-separated sizes parameter (e.g., "MN,IT,S")
+// for a combined catalog, or a single size parameter. Sizes are normalized and de-duplicated,
+// keeping the requested order
+func parseCatalogSizes(r *http.Request) ([]string, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get("sizes"))
+	if raw == "" {
+		raw = strings.TrimSpace(r.URL.Query().Get("size"))
+		if raw == "" {
+			return nil, fmt.Errorf("size parameter is required")
+		}
+		normalizedSize := utils.NormalizeSize(raw)
+		if !validSizes[normalizedSize] {
+			return nil, fmt.Errorf("Invalid size. Valid sizes: XS, S, M, L, XL, MN (Mini), IT (Intermedio)")
+		}
+		return []string{normalizedSize}, nil
+	}
+
+	var sizes []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		normalizedSize := utils.NormalizeSize(part)
+		if !validSizes[normalizedSize] {
+			return nil, fmt.Errorf("Invalid size %q in sizes. Valid sizes: XS, S, M, L, XL, MN (Mini), IT (Intermedio)", strings.TrimSpace(part))
+		}
+		if seen[normalizedSize] {
+			continue
+		}
+		seen[normalizedSize] = true
+		sizes = append(sizes, normalizedSize)
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("sizes parameter must list at least one size")
+	}
+	return sizes, nil
+}
+
+// catalogLabel joins sizes into the label used in filenames and PNG session IDs (e.g., "MN-IT-S")
+func catalogLabel(sizes []string) string {
+	return strings.Join(sizes, "-")
+}
+
+// fetchCatalogGroups loads the catalog items of each size. Sizes without items are left out so a
+// combined catalog has no empty sections; the result is empty when no size has items
+func (c *CatalogController) fetchCatalogGroups(ctx context.Context, sizes []string, filters repository.CatalogFilterParams) ([]service.CatalogSizeGroup, error) {
+	groups := make([]service.CatalogSizeGroup, 0, len(sizes))
+	for _, size := range sizes {
+		items, err := c.repository.GetItemsBySizeForCatalog(ctx, size, filters)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			log.Printf("⚠️  fetchCatalogGroups: No items found for size=%s, skipping", size)
+			continue
+		}
+		groups = append(groups, service.CatalogSizeGroup{Size: size, Items: items})
+	}
+	return groups, nil
+}
+
 // validFormats is a map of valid format values
 var validFormats = map[string]bool{
 	"html": true,
@@ -65,7 +182,13 @@ var validFormats = map[string]bool{
 	"png":  true,
 }
 
-// GenerateCatalog handles GET /admin/catalog?size=XS&format=pdf|png|html
+// GenerateCatalog handles GET /admin/catalog?size=XS&format=pdf|png|html&perPage=9&color=negro&hoodieType=BU&sort=newest
+// sizes=MN,IT,S may be used instead of size to combine several sizes into one catalog: each size
+// gets its own intro page followed by its product pages, in the given order. Sizes without items are skipped
+// perPage is optional (default 9) and clamped to 1-12
+// color (primary color) and hoodieType are optional filters, as names or codes
+// sort is optional: newest, price_asc, price_desc or color (default: by design code)
+// paper is optional: catalog (210x350mm, default), a4, letter, or custom with paperWidth and paperHeight in mm
 func (c *CatalogController) GenerateCatalog(w http.ResponseWriter, r *http.Request) {
 	// Check if this is actually a png-page request that got routed here
 	if strings.HasPrefix(r.URL.Path, "/admin/catalog/png-page") {
@@ -75,65 +198,84 @@ func (c *CatalogController) GenerateCatalog(w http.ResponseWriter, r *http.Reque
 	
 	if r.Method != http.MethodGet {
 		log.Printf("❌ GenerateCatalog: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 
 	// Parse query parameters
-	size := strings.TrimSpace(r.URL.Query().Get("size"))
 	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
 
-	// Validate size parameter
-	if size == "" {
-		log.Printf("❌ GenerateCatalog: size parameter is required")
-		http.Error(w, "size parameter is required", http.StatusBadRequest)
-		return
-	}
-
-	// Normalize size
-	normalizedSize := utils.NormalizeSize(size)
-	if !validSizes[normalizedSize] {
-		log.Printf("❌ GenerateCatalog: Invalid size: %s", size)
-		http.Error(w, fmt.Sprintf("Invalid size. Valid sizes: XS, S, M, L, XL, MN (Mini), IT (Intermedio)"), http.StatusBadRequest)
+	// Validate and normalize size/sizes parameter
+	sizes, err := parseCatalogSizes(r)
+	if err != nil {
+		log.Printf("❌ GenerateCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	label := catalogLabel(sizes)
 
 	// Validate format parameter
 	if format == "" {
 		log.Printf("❌ GenerateCatalog: format parameter is required")
-		http.Error(w, "format parameter is required. Valid formats: html, pdf, png", http.StatusBadRequest)
+		writeError(w, "format parameter is required. Valid formats: html, pdf, png", http.StatusBadRequest)
 		return
 	}
 
 	if !validFormats[format] {
 		log.Printf("❌ GenerateCatalog: Invalid format: %s", format)
-		http.Error(w, "Invalid format. Valid formats: html, pdf, png", http.StatusBadRequest)
+		writeError(w, "Invalid format. Valid formats: html, pdf, png", http.StatusBadRequest)
 		return
 	}
 
+	perPage, err := utils.ParseCatalogPerPage(r.URL.Query().Get("perPage"))
+	if err != nil {
+		log.Printf("❌ GenerateCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filters, err := parseCatalogFilters(r)
+	if err != nil {
+		log.Printf("❌ GenerateCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	paper, err := parseCatalogPaper(r)
+	if err != nil {
+		log.Printf("❌ GenerateCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := service.CatalogOptions{PerPage: perPage, Filters: filters, Paper: paper}
+
 	// Get items from repository
-	items, err := c.repository.GetItemsBySizeForCatalog(ctx, normalizedSize)
+	groups, err := c.fetchCatalogGroups(ctx, sizes, opts.Filters)
 	if err != nil {
 		log.Printf("❌ GenerateCatalog: Error fetching items: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to fetch items: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to fetch items: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// Check if there are any items
-	if len(items) == 0 {
-		log.Printf("⚠️  GenerateCatalog: No items found for size=%s", normalizedSize)
-		http.Error(w, fmt.Sprintf("No active items found for size %s", normalizedSize), http.StatusNotFound)
+	if len(groups) == 0 {
+		log.Printf("⚠️  GenerateCatalog: No items found for size=%s", label)
+		writeError(w, fmt.Sprintf("No active items found for size %s", strings.Join(sizes, ", ")), http.StatusNotFound)
 		return
 	}
 
+	// Only render the sizes that have items
+	renderSizes := make([]string, 0, len(groups))
+	for _, group := range groups {
+		renderSizes = append(renderSizes, group.Size)
+	}
+
 	// Render HTML (with base64 images for PDF/PNG)
 	useBase64 := format == "pdf" || format == "png"
-	htmlContent, err := c.catalogService.RenderCatalogHTML(ctx, normalizedSize, items, useBase64)
+	htmlContent, err := c.catalogService.RenderCatalogHTML(ctx, groups, useBase64, perPage, paper)
 	if err != nil {
 		log.Printf("❌ GenerateCatalog: Error rendering HTML: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to render catalog: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to render catalog: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -149,15 +291,18 @@ func (c *CatalogController) GenerateCatalog(w http.ResponseWriter, r *http.Reque
 
 	case "pdf":
 		// Generate PDF using render endpoint
-		pdfData, err := c.catalogService.GeneratePDF(ctx, normalizedSize)
+		result, err := c.renderOnce(catalogLabel(renderSizes), format, opts, func() (interface{}, error) {
+			return c.catalogService.GeneratePDF(ctx, renderSizes, opts)
+		})
 		if err != nil {
 			log.Printf("❌ GenerateCatalog: Error generating PDF: %v", err)
-			http.Error(w, fmt.Sprintf("Failed to generate PDF: %v", err), http.StatusInternalServerError)
+			writeError(w, fmt.Sprintf("Failed to generate PDF: %v", err), http.StatusInternalServerError)
 			return
 		}
+		pdfData := result.([]byte)
 
 		// Set headers and return PDF
-		filename := fmt.Sprintf("catalog_%s.pdf", normalizedSize)
+		filename := fmt.Sprintf("catalog_%s.pdf", label)
 		w.Header().Set("Content-Type", "application/pdf")
 		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
 		w.WriteHeader(http.StatusOK)
@@ -167,59 +312,23 @@ func (c *CatalogController) GenerateCatalog(w http.ResponseWriter, r *http.Reque
 
 	case "png":
 		// Generate PNG using render endpoint
-		pngs, err := c.catalogService.GeneratePNG(ctx, normalizedSize)
+		result, err := c.renderOnce(catalogLabel(renderSizes), format, opts, func() (interface{}, error) {
+			return c.catalogService.GeneratePNG(ctx, renderSizes, opts)
+		})
 		if err != nil {
 			log.Printf("❌ GenerateCatalog: Error generating PNG: %v", err)
-			http.Error(w, fmt.Sprintf("Failed to generate PNG: %v", err), http.StatusInternalServerError)
+			writeError(w, fmt.Sprintf("Failed to generate PNG: %v", err), http.StatusInternalServerError)
 			return
 		}
+		// Pages may be shared with concurrent callers; they are only read after this point
+		pngs := result.(map[int][]byte)
+
+		sessionID, pages := c.storePNGPages(label, pngs)
 
-		// Generate a unique session ID
-		sessionID := fmt.Sprintf("%s_%d", normalizedSize, time.Now().UnixNano())
-		
-		// Store PNGs temporarily
-		c.pngStorageMutex.Lock()
-		c.pngStorage[sessionID] = pngs
-		c.pngStorageMutex.Unlock()
-		
-		// Schedule cleanup after 10 minutes
-		go func() {
-			time.Sleep(10 * time.Minute)
-			c.pngStorageMutex.Lock()
-			delete(c.pngStorage, sessionID)
-			c.pngStorageMutex.Unlock()
-		}()
-		
-		// Generate download links for each page
-		type PageLink struct {
-			Page     int    `json:"page"`
-			URL      string `json:"url"`
-			Filename string `json:"filename"`
-		}
-		
-		var pages []PageLink
-		pageNums := getPageNumbers(pngs)
-		for _, pageNum := range pageNums {
-			// Only return the path, not the full URL
-			downloadPath := fmt.Sprintf("/admin/catalog/png-page?session=%s&page=%d", sessionID, pageNum)
-			// For single page, use simpler filename without page number
-			var filename string
-			if len(pageNums) == 1 {
-				filename = fmt.Sprintf("catalog_%s.png", normalizedSize)
-			} else {
-				filename = fmt.Sprintf("catalog_%s_page_%d.png", normalizedSize, pageNum)
-			}
-			pages = append(pages, PageLink{
-				Page:     pageNum,
-				URL:      downloadPath,
-				Filename: filename,
-			})
-		}
-		
 		response := map[string]interface{}{
 			"sessionId": sessionID,
-			"totalPages": len(pageNums),
-			"size": normalizedSize,
+			"totalPages": len(pages),
+			"size": label,
 			"pages": pages,
 		}
 		
@@ -231,55 +340,69 @@ func (c *CatalogController) GenerateCatalog(w http.ResponseWriter, r *http.Reque
 	}
 }
 
-// RenderCatalog handles GET /admin/catalog/render?size=XS
+// RenderCatalog handles GET /admin/catalog/render?size=XS&perPage=9&color=NG&hoodieType=BU&sort=newest
+// (or sizes=MN,IT,S for a combined catalog, and paper/paperWidth/paperHeight for the page size)
 // Returns the HTML template for the catalog (used by chromedp for PDF/PNG generation)
+// chromedp calls this with a short-lived renderToken (see utils.IsAuthorizedRenderRequest)
+// so rendering keeps working when /admin/* requires authentication
 func (c *CatalogController) RenderCatalog(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		log.Printf("❌ RenderCatalog: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 
-	// Parse query parameters
-	size := strings.TrimSpace(r.URL.Query().Get("size"))
+	// Validate and normalize size/sizes parameter
+	sizes, err := parseCatalogSizes(r)
+	if err != nil {
+		log.Printf("❌ RenderCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Validate size parameter
-	if size == "" {
-		log.Printf("❌ RenderCatalog: size parameter is required")
-		http.Error(w, "size parameter is required", http.StatusBadRequest)
+	perPage, err := utils.ParseCatalogPerPage(r.URL.Query().Get("perPage"))
+	if err != nil {
+		log.Printf("❌ RenderCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Normalize size
-	normalizedSize := utils.NormalizeSize(size)
-	if !validSizes[normalizedSize] {
-		log.Printf("❌ RenderCatalog: Invalid size: %s", size)
-		http.Error(w, fmt.Sprintf("Invalid size. Valid sizes: XS, S, M, L, XL, MN (Mini), IT (Intermedio)"), http.StatusBadRequest)
+	filters, err := parseCatalogFilters(r)
+	if err != nil {
+		log.Printf("❌ RenderCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paper, err := parseCatalogPaper(r)
+	if err != nil {
+		log.Printf("❌ RenderCatalog: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Get items from repository
-	items, err := c.repository.GetItemsBySizeForCatalog(ctx, normalizedSize)
+	groups, err := c.fetchCatalogGroups(ctx, sizes, filters)
 	if err != nil {
 		log.Printf("❌ RenderCatalog: Error fetching items: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to fetch items: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to fetch items: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// Check if there are any items
-	if len(items) == 0 {
-		log.Printf("⚠️  RenderCatalog: No items found for size=%s", normalizedSize)
-		http.Error(w, fmt.Sprintf("No active items found for size %s", normalizedSize), http.StatusNotFound)
+	if len(groups) == 0 {
+		log.Printf("⚠️  RenderCatalog: No items found for size=%s", catalogLabel(sizes))
+		writeError(w, fmt.Sprintf("No active items found for size %s", strings.Join(sizes, ", ")), http.StatusNotFound)
 		return
 	}
 
 	// Render HTML with absolute URLs (no base64)
-	htmlContent, err := c.catalogService.RenderCatalogHTML(ctx, normalizedSize, items, false)
+	htmlContent, err := c.catalogService.RenderCatalogHTML(ctx, groups, false, perPage, paper)
 	if err != nil {
 		log.Printf("❌ RenderCatalog: Error rendering HTML: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to render catalog: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to render catalog: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -291,12 +414,140 @@ func (c *CatalogController) RenderCatalog(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// catalogExportSizes is the order in which sizes are written by ExportCatalogJSON
+var catalogExportSizes = []string{"XS", "S", "M", "L", "XL", "MN", "IT"}
+
+// ExportCatalogJSON handles GET /admin/catalog/export.json?size=XS
+// Returns every active, catalog-visible item grouped by size with labels, prices and image URLs.
+// size is optional: without it all sizes are exported. The response is streamed one size at a
+// time so large catalogs are never held in memory at once.
+// Example response:
+// {
+//   "generatedAt": "2026-01-04T10:30:00Z",
+//   "currency": "COP",
+//   "sizes": [
+//     {
+//       "size": "XS",
+//       "retailPrice": 12000,
+//       "wholesalePrice": 9500,
+//       "itemCount": 1,
+//       "items": [
+//         {
+//           "id": 12,
+//           "designAssetId": 45,
+//           "sku": "XS_ABC123",
+//           "code": "ABC123",
+//           "colorPrimary": "NG",
+//           "colorPrimaryName": "Negro",
+//           "colorSecondary": "AC",
+//           "colorSecondaryName": "azul cielo",
+//           "hoodieType": "BE",
+//           "hoodieTypeName": "Buso Tipo Esqueleto",
+//           "availableQty": 3,
+//           "isCustom": false,
+//           "imageUrlThumb": "http://localhost:8080/admin/design-assets/pending/45/image?size=thumb",
+//           "imageUrlMedium": "http://localhost:8080/admin/design-assets/pending/45/image?size=medium"
+//         }
+//       ]
+//     }
+//   ]
+// }
+func (c *CatalogController) ExportCatalogJSON(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ExportCatalogJSON: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ ExportCatalogJSON: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sizes := catalogExportSizes
+	if size := strings.TrimSpace(r.URL.Query().Get("size")); size != "" {
+		normalizedSize := utils.NormalizeSize(size)
+		if !validSizes[normalizedSize] {
+			log.Printf("❌ ExportCatalogJSON: Invalid size: %s", size)
+			writeError(w, "Invalid size. Valid sizes: XS, S, M, L, XL, MN (Mini), IT (Intermedio)", http.StatusBadRequest)
+			return
+		}
+		sizes = []string{normalizedSize}
+	}
+
+	ctx := requestContext(r)
+	engine := pricing.GetEngine()
+	flusher, _ := w.(http.Flusher)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "inline; filename=catalog-export.json")
+	fmt.Fprintf(w, `{"generatedAt":%q,"currency":"COP","sizes":[`, time.Now().UTC().Format(time.RFC3339))
+
+	totalItems := 0
+	for i, size := range sizes {
+		items, err := c.repository.GetItemsBySizeForCatalog(ctx, size, repository.CatalogFilterParams{})
+		if err != nil {
+			// Headers are already sent: stop here so the client gets invalid JSON instead of a silently partial export
+			log.Printf("❌ ExportCatalogJSON: Error fetching items for size=%s, aborting export: %v", size, err)
+			return
+		}
+
+		exportSize := models.CatalogExportSize{
+			Size:      size,
+			ItemCount: len(items),
+			Items:     make([]models.CatalogExportItem, 0, len(items)),
+		}
+		if engine != nil {
+			if retail, wholesale, ok := engine.GetCatalogBusoPrices(size); ok {
+				exportSize.RetailPrice = &retail
+				exportSize.WholesalePrice = &wholesale
+			}
+		}
+
+		for _, item := range items {
+			exportSize.Items = append(exportSize.Items, models.CatalogExportItem{
+				ID:                 item.ID,
+				DesignAssetID:      item.DesignAssetID,
+				SKU:                item.SKU,
+				Code:               item.Code,
+				ColorPrimary:       item.ColorPrimary,
+				ColorPrimaryName:   item.ColorPrimaryName,
+				ColorSecondary:     item.ColorSecondary,
+				ColorSecondaryName: utils.MapCodeToColor(item.ColorSecondary),
+				HoodieType:         item.HoodieType,
+				HoodieTypeName:     item.HoodieTypeName,
+				AvailableQty:       item.AvailableQty,
+				IsCustom:           item.IsCustom,
+				ImageUrlThumb:      fmt.Sprintf("%s/admin/design-assets/pending/%d/image?size=thumb", c.baseURL, item.DesignAssetID),
+				ImageUrlMedium:     fmt.Sprintf("%s/admin/design-assets/pending/%d/image?size=medium", c.baseURL, item.DesignAssetID),
+			})
+		}
+
+		if i > 0 {
+			w.Write([]byte(","))
+		}
+		data, err := json.Marshal(exportSize)
+		if err != nil {
+			log.Printf("❌ ExportCatalogJSON: Error encoding size=%s, aborting export: %v", size, err)
+			return
+		}
+		if _, err := w.Write(data); err != nil {
+			log.Printf("❌ ExportCatalogJSON: Error writing size=%s: %v", size, err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		totalItems += len(items)
+	}
+
+	w.Write([]byte("]}"))
+	log.Printf("✅ ExportCatalogJSON: Exported %d items across %d sizes", totalItems, len(sizes))
+}
+
 // DownloadPNGPage handles GET /admin/catalog/png-page?session=XXX&page=N
 // Returns a specific PNG page from temporary storage
 func (c *CatalogController) DownloadPNGPage(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		log.Printf("❌ DownloadPNGPage: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -305,14 +556,14 @@ func (c *CatalogController) DownloadPNGPage(w http.ResponseWriter, r *http.Reque
 
 	if sessionID == "" {
 		log.Printf("❌ DownloadPNGPage: session parameter is required")
-		http.Error(w, "session parameter is required", http.StatusBadRequest)
+		writeError(w, "session parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	pageNum, err := strconv.Atoi(pageStr)
 	if err != nil || pageNum < 1 {
 		log.Printf("❌ DownloadPNGPage: Invalid page number: %s", pageStr)
-		http.Error(w, "Invalid page number", http.StatusBadRequest)
+		writeError(w, "Invalid page number", http.StatusBadRequest)
 		return
 	}
 
@@ -323,27 +574,27 @@ func (c *CatalogController) DownloadPNGPage(w http.ResponseWriter, r *http.Reque
 
 	if !exists {
 		log.Printf("❌ DownloadPNGPage: Session not found: %s", sessionID)
-		http.Error(w, "Session expired or not found", http.StatusNotFound)
+		writeError(w, "Session expired or not found", http.StatusNotFound)
 		return
 	}
 
 	pngData, exists := pngs[pageNum]
 	if !exists {
 		log.Printf("❌ DownloadPNGPage: Page %d not found in session %s", pageNum, sessionID)
-		http.Error(w, fmt.Sprintf("Page %d not found", pageNum), http.StatusNotFound)
+		writeError(w, fmt.Sprintf("Page %d not found", pageNum), http.StatusNotFound)
 		return
 	}
 
 	// Validate PNG data (PNG files start with PNG signature)
 	if len(pngData) < 8 {
 		log.Printf("❌ DownloadPNGPage: PNG data too short for page %d (%d bytes)", pageNum, len(pngData))
-		http.Error(w, "Invalid PNG data", http.StatusInternalServerError)
+		writeError(w, "Invalid PNG data", http.StatusInternalServerError)
 		return
 	}
 	pngSignature := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
 	if len(pngData) < 8 || !equalBytes(pngData[:8], pngSignature) {
 		log.Printf("❌ DownloadPNGPage: Invalid PNG signature for page %d (first 8 bytes: %x)", pageNum, pngData[:8])
-		http.Error(w, "Invalid PNG data", http.StatusInternalServerError)
+		writeError(w, "Invalid PNG data", http.StatusInternalServerError)
 		return
 	}
 
@@ -379,6 +630,47 @@ func (c *CatalogController) DownloadPNGPage(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// storePNGPages keeps generated PNG pages in temporary storage for 10 minutes and
+// returns the session ID with one download link per page
+func (c *CatalogController) storePNGPages(size string, pngs map[int][]byte) (string, []models.CatalogPageLink) {
+	// Generate a unique session ID
+	sessionID := fmt.Sprintf("%s_%d", size, time.Now().UnixNano())
+
+	// Store PNGs temporarily
+	c.pngStorageMutex.Lock()
+	c.pngStorage[sessionID] = pngs
+	c.pngStorageMutex.Unlock()
+
+	// Schedule cleanup after 10 minutes
+	go func() {
+		time.Sleep(10 * time.Minute)
+		c.pngStorageMutex.Lock()
+		delete(c.pngStorage, sessionID)
+		c.pngStorageMutex.Unlock()
+	}()
+
+	// Generate download links for each page
+	var pages []models.CatalogPageLink
+	pageNums := getPageNumbers(pngs)
+	for _, pageNum := range pageNums {
+		// Only return the path, not the full URL
+		downloadPath := fmt.Sprintf("/admin/catalog/png-page?session=%s&page=%d", sessionID, pageNum)
+		// For single page, use simpler filename without page number
+		var filename string
+		if len(pageNums) == 1 {
+			filename = fmt.Sprintf("catalog_%s.png", size)
+		} else {
+			filename = fmt.Sprintf("catalog_%s_page_%d.png", size, pageNum)
+		}
+		pages = append(pages, models.CatalogPageLink{
+			Page:     pageNum,
+			URL:      downloadPath,
+			Filename: filename,
+		})
+	}
+	return sessionID, pages
+}
+
 // equalBytes compares two byte slices
 func equalBytes(a, b []byte) bool {
 	if len(a) != len(b) {
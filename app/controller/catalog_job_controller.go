@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/service"
+	"armario-mascota-me/utils"
+)
+
+// catalogJobTTL is how long a finished job (and its PDF) is kept in memory
+const catalogJobTTL = 10 * time.Minute
+
+// CreateCatalogJob handles POST /admin/catalog/jobs?size=XS&format=pdf|png&perPage=9&color=negro&hoodieType=BU&sort=newest&paper=a4
+// Starts PDF/PNG generation in the background and returns the job immediately (202 Accepted).
+// Poll GET /admin/catalog/jobs/:id for status and download links.
+func (c *CatalogController) CreateCatalogJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		log.Printf("❌ CreateCatalogJob: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	size := strings.TrimSpace(r.URL.Query().Get("size"))
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+
+	if size == "" {
+		log.Printf("❌ CreateCatalogJob: size parameter is required")
+		writeError(w, "size parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	normalizedSize := utils.NormalizeSize(size)
+	if !validSizes[normalizedSize] {
+		log.Printf("❌ CreateCatalogJob: Invalid size: %s", size)
+		writeError(w, "Invalid size. Valid sizes: XS, S, M, L, XL, MN (Mini), IT (Intermedio)", http.StatusBadRequest)
+		return
+	}
+
+	if format != "pdf" && format != "png" {
+		log.Printf("❌ CreateCatalogJob: Invalid format: %s", format)
+		writeError(w, "Invalid format. Valid formats for jobs: pdf, png", http.StatusBadRequest)
+		return
+	}
+
+	perPage, err := utils.ParseCatalogPerPage(r.URL.Query().Get("perPage"))
+	if err != nil {
+		log.Printf("❌ CreateCatalogJob: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filters, err := parseCatalogFilters(r)
+	if err != nil {
+		log.Printf("❌ CreateCatalogJob: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	paper, err := parseCatalogPaper(r)
+	if err != nil {
+		log.Printf("❌ CreateCatalogJob: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := service.CatalogOptions{PerPage: perPage, Filters: filters, Paper: paper}
+
+	// Fail fast when there is nothing to render instead of creating a job that will fail
+	items, err := c.repository.GetItemsBySizeForCatalog(requestContext(r), normalizedSize, opts.Filters)
+	if err != nil {
+		log.Printf("❌ CreateCatalogJob: Error fetching items: %v", err)
+		writeError(w, fmt.Sprintf("Failed to fetch items: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if len(items) == 0 {
+		log.Printf("⚠️  CreateCatalogJob: No items found for size=%s", normalizedSize)
+		writeError(w, fmt.Sprintf("No active items found for size %s", normalizedSize), http.StatusNotFound)
+		return
+	}
+
+	job := &models.CatalogJob{
+		ID:        fmt.Sprintf("%s_%s_%d", normalizedSize, format, time.Now().UnixNano()),
+		Status:    models.CatalogJobPending,
+		Size:      normalizedSize,
+		Format:    format,
+		CreatedAt: time.Now(),
+	}
+
+	c.jobsMutex.Lock()
+	c.jobs[job.ID] = job
+	response := *job
+	c.jobsMutex.Unlock()
+
+	go c.runCatalogJob(job.ID, normalizedSize, format, opts)
+
+	log.Printf("📥 CreateCatalogJob: Queued job %s (size=%s, format=%s)", job.ID, normalizedSize, format)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ CreateCatalogJob: Error encoding JSON response: %v", err)
+	}
+}
+
+// runCatalogJob generates the catalog for a job and records the outcome.
+// The job is removed catalogJobTTL after it finishes.
+func (c *CatalogController) runCatalogJob(jobID, size, format string, opts service.CatalogOptions) {
+	// Detached from the request, which returns as soon as the job is queued
+	ctx := context.Background()
+	c.updateCatalogJob(jobID, func(job *models.CatalogJob) {
+		job.Status = models.CatalogJobRunning
+	})
+
+	var links []models.CatalogPageLink
+	var pdfData []byte
+	var err error
+
+	switch format {
+	case "pdf":
+		var result interface{}
+		result, err = c.renderOnce(size, format, opts, func() (interface{}, error) {
+			return c.catalogService.GeneratePDF(ctx, []string{size}, opts)
+		})
+		if err == nil {
+			pdfData = result.([]byte)
+			links = []models.CatalogPageLink{{
+				Page:     1,
+				URL:      fmt.Sprintf("/admin/catalog/jobs/%s/download", jobID),
+				Filename: fmt.Sprintf("catalog_%s.pdf", size),
+			}}
+		}
+	case "png":
+		var result interface{}
+		result, err = c.renderOnce(size, format, opts, func() (interface{}, error) {
+			return c.catalogService.GeneratePNG(ctx, []string{size}, opts)
+		})
+		if err == nil {
+			_, links = c.storePNGPages(size, result.(map[int][]byte))
+		}
+	}
+
+	c.jobsMutex.Lock()
+	if job, exists := c.jobs[jobID]; exists {
+		finishedAt := time.Now()
+		job.FinishedAt = &finishedAt
+		if err != nil {
+			job.Status = models.CatalogJobFailed
+			job.Error = err.Error()
+		} else {
+			job.Status = models.CatalogJobDone
+			job.Links = links
+			if pdfData != nil {
+				c.jobPDFs[jobID] = pdfData
+			}
+		}
+	}
+	c.jobsMutex.Unlock()
+
+	if err != nil {
+		log.Printf("❌ runCatalogJob: Job %s failed: %v", jobID, err)
+	} else {
+		log.Printf("✅ runCatalogJob: Job %s done (%d links)", jobID, len(links))
+	}
+
+	// Schedule cleanup once the job has been available for catalogJobTTL
+	time.AfterFunc(catalogJobTTL, func() {
+		c.jobsMutex.Lock()
+		delete(c.jobs, jobID)
+		delete(c.jobPDFs, jobID)
+		c.jobsMutex.Unlock()
+	})
+}
+
+// updateCatalogJob applies fn to a job while holding the jobs lock
+func (c *CatalogController) updateCatalogJob(jobID string, fn func(job *models.CatalogJob)) {
+	c.jobsMutex.Lock()
+	defer c.jobsMutex.Unlock()
+	if job, exists := c.jobs[jobID]; exists {
+		fn(job)
+	}
+}
+
+// GetCatalogJob handles GET /admin/catalog/jobs/:id
+// Returns the job status (pending/running/done/failed) and, when done, the download links
+func (c *CatalogController) GetCatalogJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetCatalogJob: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := strings.TrimPrefix(r.URL.Path, "/admin/catalog/jobs/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		log.Printf("❌ GetCatalogJob: Invalid path: %s", r.URL.Path)
+		writeError(w, "invalid job id parameter", http.StatusBadRequest)
+		return
+	}
+
+	c.jobsMutex.RLock()
+	job, exists := c.jobs[jobID]
+	var response models.CatalogJob
+	if exists {
+		response = *job
+	}
+	c.jobsMutex.RUnlock()
+
+	if !exists {
+		log.Printf("❌ GetCatalogJob: Job not found: %s", jobID)
+		writeError(w, "Job expired or not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ GetCatalogJob: Error encoding JSON response: %v", err)
+	}
+}
+
+// DownloadCatalogJobPDF handles GET /admin/catalog/jobs/:id/download
+// Returns the PDF generated by a finished job
+func (c *CatalogController) DownloadCatalogJobPDF(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		log.Printf("❌ DownloadCatalogJobPDF: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/catalog/jobs/"), "/download")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		log.Printf("❌ DownloadCatalogJobPDF: Invalid path: %s", r.URL.Path)
+		writeError(w, "invalid job id parameter", http.StatusBadRequest)
+		return
+	}
+
+	c.jobsMutex.RLock()
+	job, exists := c.jobs[jobID]
+	pdfData, hasPDF := c.jobPDFs[jobID]
+	var size string
+	if exists {
+		size = job.Size
+	}
+	c.jobsMutex.RUnlock()
+
+	if !exists || !hasPDF {
+		log.Printf("❌ DownloadCatalogJobPDF: PDF not available for job %s", jobID)
+		writeError(w, "Job expired, not finished or not found", http.StatusNotFound)
+		return
+	}
+
+	filename := fmt.Sprintf("catalog_%s.pdf", size)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(pdfData); err != nil {
+		log.Printf("❌ DownloadCatalogJobPDF: Error writing PDF response: %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+)
+
+// requestContext returns a context carrying the request's values (such as the request id used by
+// utils.Logf) that is not canceled when the client disconnects, so repository work and transactions
+// keep running to completion as before
+func requestContext(r *http.Request) context.Context {
+	return context.WithoutCancel(r.Context())
+}
@@ -1,8 +1,8 @@
 package controller
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -22,14 +22,16 @@ type DesignAssetController struct {
 	syncService  service.SyncServiceInterface
 	repository   repository.DesignAssetRepositoryInterface
 	driveService service.DriveServiceInterface
+	autoTagger   *service.AutoTagService
 }
 
 // NewDesignAssetController creates a new DesignAssetController
-func NewDesignAssetController(syncService service.SyncServiceInterface, repo repository.DesignAssetRepositoryInterface, driveService service.DriveServiceInterface) *DesignAssetController {
+func NewDesignAssetController(syncService service.SyncServiceInterface, repo repository.DesignAssetRepositoryInterface, driveService service.DriveServiceInterface, autoTagger *service.AutoTagService) *DesignAssetController {
 	return &DesignAssetController{
 		syncService:  syncService,
 		repository:   repo,
 		driveService: driveService,
+		autoTagger:   autoTagger,
 	}
 }
 
@@ -69,7 +71,7 @@ func (c *DesignAssetController) LoadImages(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Execute synchronization (fetches from Drive and syncs to DB)
-	ctx := context.Background()
+	ctx := requestContext(r)
 	designAssets, inserted, skipped, total, err := c.syncService.SyncDesignAssetsWithStats(ctx, folderID, status)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to load and sync design assets: %v", err), http.StatusInternalServerError)
@@ -89,6 +91,7 @@ func (c *DesignAssetController) LoadImages(w http.ResponseWriter, r *http.Reques
 			"inserted": inserted,
 			"skipped":  skipped,
 			"total":    total,
+			"notes":    syncNotes(designAssets),
 			"assets":   designAssets,
 		}
 	}
@@ -117,7 +120,7 @@ func (c *DesignAssetController) GetDesignAssetByCode(w http.ResponseWriter, r *h
 	}
 
 	code := path
-	ctx := context.Background()
+	ctx := requestContext(r)
 
 	// Get design asset from database
 	asset, err := c.repository.GetByCode(ctx, code)
@@ -135,7 +138,10 @@ func (c *DesignAssetController) GetDesignAssetByCode(w http.ResponseWriter, r *h
 }
 
 // UpdateDesignAsset handles PUT /admin/design-assets/:code
-// Updates description and has_highlights fields
+// Updates description and has_highlights, plus the optional classification codes
+// (colorPrimary, colorSecondary, hoodieType, imageType, decoBase). Unknown codes are rejected with 400
+// Example request:
+// { "description": "Buso negro", "hasHighlights": true, "colorPrimary": "NG", "colorSecondary": "AC", "hoodieType": "BE", "imageType": "MnSML", "decoBase": "C" }
 func (c *DesignAssetController) UpdateDesignAsset(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -158,10 +164,19 @@ func (c *DesignAssetController) UpdateDesignAsset(w http.ResponseWriter, r *http
 		return
 	}
 
-	ctx := context.Background()
+	if err := normalizeDesignAssetCodes(&updateReq); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
 
 	// Update design asset
-	if err := c.repository.UpdateDescriptionAndHighlights(ctx, code, updateReq.Description, updateReq.HasHighlights); err != nil {
+	if err := c.repository.Update(ctx, code, &updateReq); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		http.Error(w, fmt.Sprintf("Failed to update design asset: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -176,6 +191,258 @@ func (c *DesignAssetController) UpdateDesignAsset(w http.ResponseWriter, r *http
 	})
 }
 
+// normalizeDesignAssetCodes trims the classification codes of an update request, uppercases the
+// color, hoodie type and deco base codes, and validates each non-empty code against the utils maps.
+// Image type codes keep their case since the size format is case-sensitive ("MnSML")
+func normalizeDesignAssetCodes(req *models.DesignAssetUpdateRequest) error {
+	req.ColorPrimary = strings.ToUpper(strings.TrimSpace(req.ColorPrimary))
+	req.ColorSecondary = strings.ToUpper(strings.TrimSpace(req.ColorSecondary))
+	req.HoodieType = strings.ToUpper(strings.TrimSpace(req.HoodieType))
+	req.ImageType = strings.TrimSpace(req.ImageType)
+	req.DecoBase = strings.ToUpper(strings.TrimSpace(req.DecoBase))
+
+	if req.ColorPrimary != "" && !utils.IsKnownColorCode(req.ColorPrimary) {
+		return fmt.Errorf("invalid colorPrimary code: %s", req.ColorPrimary)
+	}
+	if req.ColorSecondary != "" && !utils.IsKnownColorCode(req.ColorSecondary) {
+		return fmt.Errorf("invalid colorSecondary code: %s", req.ColorSecondary)
+	}
+	if req.HoodieType != "" && !utils.IsKnownHoodieTypeCode(req.HoodieType) {
+		return fmt.Errorf("invalid hoodieType code: %s", req.HoodieType)
+	}
+	if req.ImageType != "" && !utils.IsKnownImageTypeCode(req.ImageType) {
+		return fmt.Errorf("invalid imageType code: %s", req.ImageType)
+	}
+	if req.DecoBase != "" && !utils.IsKnownDecoBaseCode(req.DecoBase) {
+		return fmt.Errorf("invalid decoBase code: %s", req.DecoBase)
+	}
+	return nil
+}
+
+// SetDesignAssetActive handles PATCH /admin/design-assets/:code/active
+// Soft-deletes (or restores) a design asset without touching the items that reference it
+// Example request:
+// { "isActive": false }
+// Example response: See DesignAssetDetail structure
+func (c *DesignAssetController) SetDesignAssetActive(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SetDesignAssetActive: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/design-assets/{code}/active
+	path := strings.TrimPrefix(r.URL.Path, "/admin/design-assets/")
+	code := strings.TrimSuffix(path, "/active")
+	if code == "" || code == path || strings.Contains(code, "/") {
+		http.Error(w, "code parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetDesignAssetActiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+
+	asset, err := c.repository.SetActive(ctx, code, req.IsActive)
+	if err != nil {
+		log.Printf("❌ SetDesignAssetActive: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update design asset: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(asset); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// maxBulkStatusAssets caps the number of design assets accepted by a single BulkUpdateStatus call
+const maxBulkStatusAssets = 500
+
+// BulkUpdateStatus handles PATCH /admin/design-assets/status
+// Moves a batch of design assets (by code and/or id) to one status in a single transaction.
+// If any asset does not exist nothing is updated and 404 is returned
+// Example request:
+// { "codes": ["ABC123", "DEF456"], "ids": [45], "status": "ready" }
+// Example response:
+// { "status": "ready", "requested": 3, "updated": 2 }
+func (c *DesignAssetController) BulkUpdateStatus(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 BulkUpdateStatus: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.BulkUpdateDesignAssetStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	req.Status = strings.ToLower(strings.TrimSpace(req.Status))
+	if !repository.IsValidDesignAssetStatus(req.Status) {
+		http.Error(w, "status must be pending, ready, custom-pending or custom-ready", http.StatusBadRequest)
+		return
+	}
+
+	codes := make([]string, 0, len(req.Codes))
+	for _, code := range req.Codes {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	for _, id := range req.IDs {
+		if id <= 0 {
+			http.Error(w, fmt.Sprintf("invalid id %d", id), http.StatusBadRequest)
+			return
+		}
+	}
+	total := len(codes) + len(req.IDs)
+	if total == 0 {
+		http.Error(w, "codes or ids are required", http.StatusBadRequest)
+		return
+	}
+	if total > maxBulkStatusAssets {
+		http.Error(w, fmt.Sprintf("too many design assets: maximum is %d", maxBulkStatusAssets), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+
+	response, err := c.repository.BulkUpdateStatus(ctx, codes, req.IDs, req.Status)
+	if err != nil {
+		log.Printf("❌ BulkUpdateStatus: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update design asset status: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// PublishDesignAsset handles POST /admin/design-assets/:id/publish
+// Activates a classified design asset (pending -> ready, custom-pending -> custom-ready) and creates
+// one item per size in a single transaction. Sizes that already have an item are skipped
+// Example request:
+// { "items": [ { "size": "M", "price": 12000, "stockTotal": 3 }, { "size": "L", "price": 13000 } ] }
+// Example response:
+// { "designAssetId": 45, "status": "ready", "created": [ { "id": 120, "size": "M", "sku": "M_NG_AC-BE-MnSML45-C", ... } ], "skipped": ["L"] }
+func (c *DesignAssetController) PublishDesignAsset(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 PublishDesignAsset: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/design-assets/{id}/publish
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/design-assets/"), "/publish")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PublishDesignAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+
+	response, err := c.repository.Publish(ctx, id, &req)
+	if err != nil {
+		log.Printf("❌ PublishDesignAsset: %v", err)
+		errMsg := err.Error()
+		switch {
+		case strings.Contains(errMsg, "not found"):
+			http.Error(w, errMsg, http.StatusNotFound)
+		case strings.Contains(errMsg, "already exists"):
+			http.Error(w, errMsg, http.StatusConflict)
+		case strings.Contains(errMsg, "invalid") || strings.Contains(errMsg, "required") || strings.Contains(errMsg, "must be"):
+			http.Error(w, errMsg, http.StatusBadRequest)
+		default:
+			http.Error(w, fmt.Sprintf("Failed to publish design asset: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteDesignAsset handles DELETE /admin/design-assets/:code
+// Permanently removes a design asset. Returns 409 with the number of referencing items when
+// any item still points to the asset; deactivate it instead in that case
+func (c *DesignAssetController) DeleteDesignAsset(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 DeleteDesignAsset: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	code := strings.TrimPrefix(r.URL.Path, "/admin/design-assets/")
+	if code == "" || strings.Contains(code, "/") {
+		http.Error(w, "code parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+
+	if err := c.repository.Delete(ctx, code); err != nil {
+		log.Printf("❌ DeleteDesignAsset: %v", err)
+		var inUse *repository.DesignAssetInUseError
+		if errors.As(err, &inUse) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(models.DesignAssetDeleteConflictResponse{
+				Error:     "design asset is referenced by items",
+				Code:      inUse.Code,
+				ItemCount: inUse.ItemCount,
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete design asset: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // buildPendingResponse is a helper method that builds the response with optimized image URLs
 // This method contains the common logic used by GetPendingDesignAssets and GetCustomPendingDesignAssets
 func (c *DesignAssetController) buildPendingResponse(assets []models.DesignAssetDetail) []models.DesignAssetDetailWithOptimizedURL {
@@ -192,24 +459,64 @@ func (c *DesignAssetController) buildPendingResponse(assets []models.DesignAsset
 }
 
 // GetPendingDesignAssets handles GET /admin/design-assets/pending
-// Returns all design assets with status = 'pending' (metadata only, no image processing)
+// Returns a page of design assets with status = 'pending', oldest first (metadata only, no image processing)
+// Query params: limit (optional, default 10, max 200), offset (optional, default 0),
+// incomplete (optional bool, only assets missing classification fields)
 func (c *DesignAssetController) GetPendingDesignAssets(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	ctx := context.Background()
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if parsed > 200 {
+			parsed = 200
+		}
+		limit = parsed
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		offset = parsed
+	}
+
+	incompleteOnly := false
+	if incompleteStr := r.URL.Query().Get("incomplete"); incompleteStr != "" {
+		parsed, err := strconv.ParseBool(incompleteStr)
+		if err != nil {
+			http.Error(w, "incomplete must be a boolean", http.StatusBadRequest)
+			return
+		}
+		incompleteOnly = parsed
+	}
+
+	ctx := requestContext(r)
 
 	// Get pending design assets from database
-	assets, err := c.repository.GetPending(ctx)
+	assets, totalPending, err := c.repository.GetPending(ctx, limit, offset, incompleteOnly)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to get pending design assets: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// Build response with optimized image URLs (lazy processing - URLs only, no actual processing)
-	response := c.buildPendingResponse(assets)
+	response := models.PendingDesignAssetsResponse{
+		Assets:       c.buildPendingResponse(assets),
+		TotalPending: totalPending,
+		Limit:        limit,
+		Offset:       offset,
+	}
 
 	// Set content type and return JSON
 	w.Header().Set("Content-Type", "application/json")
@@ -219,6 +526,42 @@ func (c *DesignAssetController) GetPendingDesignAssets(w http.ResponseWriter, r
 	}
 }
 
+// GetDuplicateDesignAssets handles GET /admin/design-assets/duplicates
+// Returns design assets grouped by original image hash, only for hashes shared by more than one asset,
+// so reviewers can merge re-synced duplicates that got a different Drive file id
+func (c *DesignAssetController) GetDuplicateDesignAssets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := requestContext(r)
+
+	groups, err := c.repository.GetDuplicates(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get duplicate design assets: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.DesignAssetDuplicatesResponse{
+		Groups:      make([]models.DesignAssetDuplicateGroup, len(groups)),
+		TotalGroups: len(groups),
+	}
+	for i, group := range groups {
+		response.Groups[i] = models.DesignAssetDuplicateGroup{
+			ImageHash: group.ImageHash,
+			Count:     len(group.Assets),
+			Assets:    c.buildPendingResponse(group.Assets),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // GetCustomPendingDesignAssets handles GET /admin/design-assets/custom-pending
 // Returns all design assets with status = 'custom-pending' (metadata only, no image processing)
 func (c *DesignAssetController) GetCustomPendingDesignAssets(w http.ResponseWriter, r *http.Request) {
@@ -227,7 +570,7 @@ func (c *DesignAssetController) GetCustomPendingDesignAssets(w http.ResponseWrit
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 
 	// Get custom-pending design assets from database
 	assets, err := c.repository.GetCustomPending(ctx)
@@ -247,8 +590,13 @@ func (c *DesignAssetController) GetCustomPendingDesignAssets(w http.ResponseWrit
 	}
 }
 
-// GetOptimizedImage handles GET /admin/design-assets/pending/:id/image?size=thumb|medium
+// GetOptimizedImage handles GET /admin/design-assets/pending/:id/image?size=thumb|medium&w=320&format=jpeg&rotate=90|180|270
 // Returns optimized image with lazy processing and cache
+// w requests an explicit pixel width (clamped to 64-1200) instead of the size preset, cached per width
+// Images follow their EXIF orientation plus the rotation stored with SetImageRotation; rotate previews
+// another clockwise rotation without storing it or touching the cache, and is rejected on render-token requests
+// Images are always JPEG: format is optional and any value other than jpeg (e.g. webp) is rejected with 400
+// Responses carry an ETag; a matching If-None-Match gets 304 Not Modified
 func (c *DesignAssetController) GetOptimizedImage(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -286,7 +634,37 @@ func (c *DesignAssetController) GetOptimizedImage(w http.ResponseWriter, r *http
 		size = "medium"
 	}
 
-	ctx := context.Background()
+	// Explicit pixel width overrides the named preset
+	if widthStr := r.URL.Query().Get("w"); widthStr != "" {
+		width, err := strconv.Atoi(widthStr)
+		if err != nil {
+			http.Error(w, "invalid w parameter", http.StatusBadRequest)
+			return
+		}
+		size = service.WidthImageSize(width)
+	}
+
+	// Output format: only JPEG can be produced, so other formats are rejected instead of mislabeled
+	if err := service.ValidateImageFormat(r.URL.Query().Get("format")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Optional rotation preview (the catalog render only ever gets the stored rotation)
+	rotateParam, hasRotate := r.URL.Query()["rotate"]
+	rotation := 0
+	if hasRotate {
+		if utils.IsAuthorizedRenderRequest(r) {
+			http.Error(w, "rotate is not allowed on render requests", http.StatusForbidden)
+			return
+		}
+		if rotation, err = service.ParseImageRotation(rotateParam[0]); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := requestContext(r)
 
 	// Get design asset from database
 	asset, err := c.repository.GetByID(ctx, id)
@@ -295,6 +673,12 @@ func (c *DesignAssetController) GetOptimizedImage(w http.ResponseWriter, r *http
 		return
 	}
 
+	// A preview differs from the cached sizes, so it is processed fresh and never cached
+	preview := hasRotate && rotation != asset.ImageRotation
+	if !preview {
+		rotation = asset.ImageRotation
+	}
+
 	// Ensure cache directory exists
 	if err := service.EnsureCacheDir(); err != nil {
 		http.Error(w, fmt.Sprintf("Failed to ensure cache directory: %v", err), http.StatusInternalServerError)
@@ -306,7 +690,7 @@ func (c *DesignAssetController) GetOptimizedImage(w http.ResponseWriter, r *http
 
 	// Check if cached image exists
 	var imageData []byte
-	if service.CacheExists(cachePath) {
+	if !preview && service.CacheExists(cachePath) {
 		// Read from cache
 		imageData, err = service.ReadFromCache(cachePath)
 		if err != nil {
@@ -318,6 +702,12 @@ func (c *DesignAssetController) GetOptimizedImage(w http.ResponseWriter, r *http
 
 	// If not in cache or failed to read, process the image
 	if imageData == nil {
+		// Originals flagged as too large are never downloaded again
+		if asset.ImageRejectedReason != "" {
+			http.Error(w, fmt.Sprintf("Image rejected: %s", asset.ImageRejectedReason), http.StatusUnprocessableEntity)
+			return
+		}
+
 		// Download image from Drive
 		originalData, err := c.driveService.DownloadImage(asset.DriveFileID)
 		if err != nil {
@@ -326,19 +716,38 @@ func (c *DesignAssetController) GetOptimizedImage(w http.ResponseWriter, r *http
 		}
 
 		// Optimize image
-		imageData, err = service.OptimizeImage(originalData, size)
+		imageData, err = service.OptimizeImage(originalData, size, rotation)
+		if errors.Is(err, service.ErrSourceImageTooLarge) {
+			log.Printf("⚠️  GetOptimizedImage: flagging design asset %d as rejected: %v", id, err)
+			if markErr := c.repository.MarkImageRejected(ctx, id, err.Error()); markErr != nil {
+				log.Printf("❌ GetOptimizedImage: failed to flag design asset %d: %v", id, markErr)
+			}
+			http.Error(w, fmt.Sprintf("Image rejected: %v", err), http.StatusUnprocessableEntity)
+			return
+		}
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to optimize image: %v", err), http.StatusInternalServerError)
 			return
 		}
 
-		// Save to cache
-		if err := service.SaveToCache(cachePath, imageData); err != nil {
-			log.Printf("⚠️  Warning: Failed to save to cache: %v", err)
-			// Continue anyway, we still have the image data
+		// Save to cache (previews are served as-is)
+		if !preview {
+			if err := service.SaveToCache(cachePath, imageData); err != nil {
+				log.Printf("⚠️  Warning: Failed to save to cache: %v", err)
+				// Continue anyway, we still have the image data
+			}
 		}
 	}
 
+	// Let browsers revalidate instead of keeping a stale image after the cache is invalidated
+	etag := service.ImageETag(imageData)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	// Return image
 	w.Header().Set("Content-Type", "image/jpeg")
 	w.WriteHeader(http.StatusOK)
@@ -347,6 +756,123 @@ func (c *DesignAssetController) GetOptimizedImage(w http.ResponseWriter, r *http
 	}
 }
 
+// SetImageRotation handles PATCH /admin/design-assets/pending/:id/image/rotation
+// Stores a manual clockwise rotation (0, 90, 180 or 270; 0 clears it) applied after the EXIF orientation
+// and invalidates the asset's cached sizes so the next request re-processes them
+// Example request:
+// { "rotation": 90 }
+// Example response: {"assetId": 45, "rotation": 90, "removed": 2}
+func (c *DesignAssetController) SetImageRotation(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SetImageRotation: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/design-assets/pending/{id}/image/rotation
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/design-assets/pending/"), "/image/rotation")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetImageRotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	rotation, err := service.ParseImageRotation(strconv.Itoa(req.Rotation))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.repository.SetImageRotation(requestContext(r), id, rotation); err != nil {
+		log.Printf("❌ SetImageRotation: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to set image rotation: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// The new rotation makes every cached size stale
+	removed, err := service.RemoveCachedImages(id)
+	if err != nil {
+		log.Printf("⚠️  SetImageRotation: failed to invalidate cache for design asset %d: %v", id, err)
+	}
+
+	log.Printf("✅ SetImageRotation: Set rotation %d for design asset %d (removed %d cached images)", rotation, id, removed)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(models.ImageRotationResponse{AssetID: id, Rotation: rotation, Removed: removed}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// InvalidateImageCache handles DELETE /admin/design-assets/pending/:id/image/cache
+// Removes the thumb and medium cache files so the next request re-processes the image from Drive
+// Example response: {"assetId": 45, "removed": 2}
+func (c *DesignAssetController) InvalidateImageCache(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 InvalidateImageCache: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/design-assets/pending/{id}/image/cache
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/design-assets/pending/"), "/image/cache")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid id parameter", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := service.RemoveCachedImages(id)
+	if err != nil {
+		log.Printf("❌ InvalidateImageCache: Error removing cache for design asset %d: %v", id, err)
+		http.Error(w, fmt.Sprintf("Failed to invalidate image cache: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ InvalidateImageCache: Removed %d cached images for design asset %d", removed, id)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(models.ImageCacheClearResponse{AssetID: id, Removed: removed}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ClearImageCache handles POST /admin/design-assets/cache/clear
+// Wipes every optimized image in the cache directory
+// Example response: {"removed": 120}
+func (c *DesignAssetController) ClearImageCache(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ClearImageCache: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	removed, err := service.ClearCache()
+	if err != nil {
+		log.Printf("❌ ClearImageCache: Error clearing cache: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to clear image cache: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ClearImageCache: Removed %d cached images", removed)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(models.ImageCacheClearResponse{Removed: removed}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // UpdateFullDesignAsset handles POST /admin/design-assets/update
 // Updates all fields of a design asset including code generation
 func (c *DesignAssetController) UpdateFullDesignAsset(w http.ResponseWriter, r *http.Request) {
@@ -455,7 +981,7 @@ func (c *DesignAssetController) UpdateFullDesignAsset(w http.ResponseWriter, r *
 
 	log.Printf("💾 UpdateFullDesignAsset: Preparing to update database - ID: %d, Code: %s, DecoID: %s, Status: %s", id, code, decoID, status)
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 
 	// Update design asset with determined status
 	if err := c.repository.UpdateFullDesignAsset(ctx, id, code, descriptionUpper, colorPrimaryUpper, colorSecondaryUpper, hoodieTypeUpper, imageTypeUpper, decoID, decoBaseUpperDB, updateReq.HasHighlights, status); err != nil {
@@ -485,7 +1011,7 @@ func (c *DesignAssetController) FilterDesignAssets(w http.ResponseWriter, r *htt
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 
 	// Parse query parameters
 	queryParams := r.URL.Query()
@@ -607,3 +1133,89 @@ func (c *DesignAssetController) FilterDesignAssets(w http.ResponseWriter, r *htt
 		return
 	}
 }
+
+// syncNotes collects the per-file notes produced by a sync, keyed by drive file ID
+func syncNotes(assets []models.DesignAsset) map[string]string {
+	notes := make(map[string]string)
+	for _, asset := range assets {
+		if asset.Note != "" {
+			notes[asset.DriveFileID] = asset.Note
+		}
+	}
+	return notes
+}
+
+// AutoTag handles POST /admin/design-assets/auto-tag
+// Matches pending assets' filenames/codes against the configured auto-tag rules and sets the
+// parsed attributes (color, hoodie type, image type, deco base). Assets stay pending for review.
+// Query param: dryRun=true previews what would be set without writing anything
+func (c *DesignAssetController) AutoTag(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 AutoTag: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ AutoTag: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dryRun"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Printf("❌ AutoTag: Invalid dryRun: %s", raw)
+			http.Error(w, "dryRun must be true or false", http.StatusBadRequest)
+			return
+		}
+		dryRun = parsed
+	}
+
+	ctx := requestContext(r)
+	assets, err := c.repository.GetPendingForAutoTag(ctx)
+	if err != nil {
+		log.Printf("❌ AutoTag: Error fetching pending assets: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to get pending design assets: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AutoTagResponse{
+		DryRun:  dryRun,
+		Scanned: len(assets),
+		Results: []models.AutoTagResult{},
+	}
+
+	for _, asset := range assets {
+		result := models.AutoTagResult{
+			ID:             asset.ID,
+			SourceFilename: asset.SourceFilename,
+			Status:         "unmatched",
+		}
+
+		rule, fields, ok := c.autoTagger.Parse(asset)
+		if ok {
+			response.Matched++
+			result.Rule = rule
+			result.Parsed = &fields
+			if dryRun {
+				result.Status = "would-tag"
+			} else if err := c.repository.ApplyAutoTags(ctx, asset.ID, fields); err != nil {
+				log.Printf("❌ AutoTag: Error tagging asset id=%d: %v", asset.ID, err)
+				result.Status = "failed"
+				result.Error = err.Error()
+			} else {
+				result.Status = "tagged"
+				response.Tagged++
+			}
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	log.Printf("✅ AutoTag: scanned=%d matched=%d tagged=%d dryRun=%v", response.Scanned, response.Matched, response.Tagged, dryRun)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ AutoTag: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ErrorBody is the JSON error envelope returned by the API
+// Example: {"error": {"code": "insufficient_stock", "message": "insufficient stock: available 1, requested 2"}}
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail carries a stable, machine-readable code and a human-readable message
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorCodeMatches maps repository error messages to stable error codes.
+// Checked in order, before falling back to a code derived from the HTTP status
+var errorCodeMatches = []struct {
+	substring string
+	code      string
+}{
+	{"insufficient", "insufficient_stock"},
+	{"not in reserved status", "not_in_reserved_status"},
+	{"not in paid status", "not_in_paid_status"},
+	{"already refunded", "already_refunded"},
+	{"already has a sale", "sale_already_exists"},
+	{"already completed without a sale", "order_already_completed"},
+	{"idempotency key already used", "idempotency_key_reused"},
+	{"system-generated", "system_generated"},
+}
+
+// errorCodeForStatus returns the fallback error code for an HTTP status
+func errorCodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "invalid_request"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusServiceUnavailable:
+		return "service_unavailable"
+	}
+	if status >= http.StatusInternalServerError {
+		return "internal_error"
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// errorCode picks the stable code for an error message and HTTP status.
+// Only client errors are matched by message, server errors are always internal_error
+func errorCode(status int, message string) string {
+	if status >= http.StatusInternalServerError {
+		return errorCodeForStatus(status)
+	}
+	lowered := strings.ToLower(message)
+	for _, match := range errorCodeMatches {
+		if strings.Contains(lowered, match.substring) {
+			return match.code
+		}
+	}
+	return errorCodeForStatus(status)
+}
+
+// writeJSONError writes {"error":{"code":...,"message":...}} with the given HTTP status
+func writeJSONError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(ErrorBody{Error: ErrorDetail{Code: code, Message: message}}); err != nil {
+		log.Printf("❌ writeJSONError: Error encoding error response: %v", err)
+	}
+}
+
+// writeError is a drop-in replacement for http.Error that emits the JSON error envelope,
+// deriving the code from the message and status
+func writeError(w http.ResponseWriter, message string, status int) {
+	writeJSONError(w, status, errorCode(status, message), message)
+}
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/repository"
+)
+
+// FinanceBudgetController handles HTTP requests for monthly finance budgets
+type FinanceBudgetController struct {
+	repository repository.FinanceBudgetRepositoryInterface
+}
+
+// NewFinanceBudgetController creates a new FinanceBudgetController
+func NewFinanceBudgetController(repo repository.FinanceBudgetRepositoryInterface) *FinanceBudgetController {
+	return &FinanceBudgetController{
+		repository: repo,
+	}
+}
+
+// List handles GET /admin/finance/budgets?month=YYYY-MM (month is optional)
+// Example response: {"budgets": [{"id": 1, "category": "publicidad", "month": "2026-02", "limitAmount": 300000, ...}]}
+func (c *FinanceBudgetController) List(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ListFinanceBudgets: Received %s request to %s", r.Method, r.URL.Path)
+
+	var month *string
+	if value := r.URL.Query().Get("month"); value != "" {
+		month = &value
+	}
+
+	ctx := context.Background()
+	budgets, err := c.repository.List(ctx, month)
+	if err != nil {
+		log.Printf("❌ ListFinanceBudgets: Error fetching budgets: %v", err)
+		writeFinanceBudgetError(w, err)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, models.FinanceBudgetListResponse{Budgets: budgets})
+}
+
+// Create handles POST /admin/finance/budgets
+// Example request: {"category": "publicidad", "month": "2026-02", "limitAmount": 300000}
+func (c *FinanceBudgetController) Create(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 CreateFinanceBudget: Received %s request to %s", r.Method, r.URL.Path)
+
+	var req models.FinanceBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ CreateFinanceBudget: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	budget, err := c.repository.Create(ctx, &req)
+	if err != nil {
+		log.Printf("❌ CreateFinanceBudget: Error creating budget: %v", err)
+		writeFinanceBudgetError(w, err)
+		return
+	}
+
+	log.Printf("✅ CreateFinanceBudget: Successfully created budget id=%d", budget.ID)
+	writeFinanceTemplateJSON(w, http.StatusCreated, budget)
+}
+
+// Get handles GET /admin/finance/budgets/:id
+func (c *FinanceBudgetController) Get(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetFinanceBudget: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseFinanceBudgetID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	budget, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("❌ GetFinanceBudget: Error fetching budget: %v", err)
+		writeFinanceBudgetError(w, err)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, budget)
+}
+
+// Update handles PUT /admin/finance/budgets/:id (replaces category, month and limitAmount)
+func (c *FinanceBudgetController) Update(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 UpdateFinanceBudget: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseFinanceBudgetID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.FinanceBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ UpdateFinanceBudget: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	budget, err := c.repository.Update(ctx, id, &req)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceBudget: Error updating budget: %v", err)
+		writeFinanceBudgetError(w, err)
+		return
+	}
+
+	log.Printf("✅ UpdateFinanceBudget: Successfully updated budget id=%d", id)
+	writeFinanceTemplateJSON(w, http.StatusOK, budget)
+}
+
+// Delete handles DELETE /admin/finance/budgets/:id
+func (c *FinanceBudgetController) Delete(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 DeleteFinanceBudget: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseFinanceBudgetID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	if err := c.repository.Delete(ctx, id); err != nil {
+		log.Printf("❌ DeleteFinanceBudget: Error deleting budget: %v", err)
+		writeFinanceBudgetError(w, err)
+		return
+	}
+
+	log.Printf("✅ DeleteFinanceBudget: Successfully deleted budget id=%d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Status handles GET /admin/finance/budgets/status?month=YYYY-MM (defaults to the current month)
+// Compares each budgeted category's expenses for the month against its limit; overBudget flags categories above 100%
+// Example response:
+// {
+//   "currency": "COP",
+//   "month": "2026-02",
+//   "totalLimit": 500000,
+//   "totalActual": 620000,
+//   "totalRemaining": -120000,
+//   "overBudgetCount": 1,
+//   "categories": [
+//     { "budgetId": 1, "category": "publicidad", "limitAmount": 300000, "actualExpense": 450000, "remaining": -150000, "percentUsed": 150, "overBudget": true, "transactionCount": 4 },
+//     { "budgetId": 2, "category": "insumos", "limitAmount": 200000, "actualExpense": 170000, "remaining": 30000, "percentUsed": 85, "overBudget": false, "transactionCount": 6 }
+//   ],
+//   "unbudgeted": [
+//     { "category": "transporte", "amount": 40000, "percentage": 6.06, "count": 2 }
+//   ]
+// }
+func (c *FinanceBudgetController) Status(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 FinanceBudgetStatus: Received %s request to %s", r.Method, r.URL.Path)
+
+	month := strings.TrimSpace(r.URL.Query().Get("month"))
+	if month == "" {
+		month = time.Now().Format("2006-01")
+	}
+
+	ctx := context.Background()
+	status, err := c.repository.Status(ctx, month)
+	if err != nil {
+		log.Printf("❌ FinanceBudgetStatus: Error calculating budget status: %v", err)
+		writeFinanceBudgetError(w, err)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, status)
+}
+
+// parseFinanceBudgetID extracts the budget ID from /admin/finance/budgets/{id}
+func parseFinanceBudgetID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/admin/finance/budgets/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		log.Printf("❌ FinanceBudget: Invalid budget id: %s", idStr)
+		writeError(w, "invalid budget id parameter", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeFinanceBudgetError maps repository errors to HTTP status codes
+func writeFinanceBudgetError(w http.ResponseWriter, err error) {
+	errMsg := err.Error()
+	if strings.Contains(errMsg, "not found") {
+		writeError(w, errMsg, http.StatusNotFound)
+		return
+	}
+	if strings.Contains(errMsg, "already exists") {
+		writeError(w, errMsg, http.StatusConflict)
+		return
+	}
+	if strings.Contains(errMsg, "required") || strings.Contains(errMsg, "must be") || strings.Contains(errMsg, "invalid") {
+		writeError(w, errMsg, http.StatusBadRequest)
+		return
+	}
+	writeError(w, fmt.Sprintf("Finance budget operation failed: %v", err), http.StatusInternalServerError)
+}
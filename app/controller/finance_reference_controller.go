@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/repository"
+)
+
+// FinanceReferenceController handles HTTP requests for one managed finance list
+// (destinations or categories), served under basePath
+type FinanceReferenceController struct {
+	repository repository.FinanceReferenceRepositoryInterface
+	kind       string
+	basePath   string
+}
+
+// NewFinanceReferenceController creates a new FinanceReferenceController
+// kind is repository.FinanceReferenceDestination or repository.FinanceReferenceCategory
+// basePath is the collection path, e.g. "/admin/finance/destinations"
+func NewFinanceReferenceController(repo repository.FinanceReferenceRepositoryInterface, kind, basePath string) *FinanceReferenceController {
+	return &FinanceReferenceController{
+		repository: repo,
+		kind:       kind,
+		basePath:   basePath,
+	}
+}
+
+// List handles GET /admin/finance/destinations and GET /admin/finance/categories
+// Returns the known values so the UI can offer a dropdown
+// Example response: {"values": [{"id": 1, "name": "Caja", ...}, {"id": 2, "name": "Nequi", ...}]}
+func (c *FinanceReferenceController) List(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ListFinanceReferences: Received %s request to %s", r.Method, r.URL.Path)
+
+	ctx := requestContext(r)
+	values, err := c.repository.List(ctx, c.kind)
+	if err != nil {
+		log.Printf("❌ ListFinanceReferences: Error fetching %s values: %v", c.kind, err)
+		writeError(w, fmt.Sprintf("Failed to fetch %s values: %v", c.kind, err), http.StatusInternalServerError)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, models.FinanceReferenceListResponse{Values: values})
+}
+
+// Create handles POST /admin/finance/destinations and POST /admin/finance/categories
+// Example request: {"name": "Nequi"}
+func (c *FinanceReferenceController) Create(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 CreateFinanceReference: Received %s request to %s", r.Method, r.URL.Path)
+
+	var req models.FinanceReferenceValueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ CreateFinanceReference: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	value, err := c.repository.Create(ctx, c.kind, &req)
+	if err != nil {
+		log.Printf("❌ CreateFinanceReference: Error creating %s: %v", c.kind, err)
+		writeFinanceReferenceError(w, err)
+		return
+	}
+
+	log.Printf("✅ CreateFinanceReference: Successfully created %s id=%d", c.kind, value.ID)
+	writeFinanceTemplateJSON(w, http.StatusCreated, value)
+}
+
+// Update handles PUT /admin/finance/destinations/:id and PUT /admin/finance/categories/:id (rename)
+// Example request: {"name": "Nequi Erika"}
+func (c *FinanceReferenceController) Update(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 UpdateFinanceReference: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := c.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.FinanceReferenceValueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ UpdateFinanceReference: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	value, err := c.repository.Update(ctx, c.kind, id, &req)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceReference: Error updating %s: %v", c.kind, err)
+		writeFinanceReferenceError(w, err)
+		return
+	}
+
+	log.Printf("✅ UpdateFinanceReference: Successfully updated %s id=%d", c.kind, id)
+	writeFinanceTemplateJSON(w, http.StatusOK, value)
+}
+
+// Delete handles DELETE /admin/finance/destinations/:id and DELETE /admin/finance/categories/:id
+func (c *FinanceReferenceController) Delete(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 DeleteFinanceReference: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := c.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := requestContext(r)
+	if err := c.repository.Delete(ctx, c.kind, id); err != nil {
+		log.Printf("❌ DeleteFinanceReference: Error deleting %s: %v", c.kind, err)
+		writeFinanceReferenceError(w, err)
+		return
+	}
+
+	log.Printf("✅ DeleteFinanceReference: Successfully deleted %s id=%d", c.kind, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseID extracts the value ID from {basePath}/{id}
+func (c *FinanceReferenceController) parseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	idStr := strings.TrimPrefix(r.URL.Path, c.basePath+"/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		log.Printf("❌ FinanceReference: Invalid %s id: %s", c.kind, idStr)
+		writeError(w, fmt.Sprintf("invalid %s id parameter", c.kind), http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeFinanceReferenceError maps repository errors to HTTP status codes
+func writeFinanceReferenceError(w http.ResponseWriter, err error) {
+	errMsg := err.Error()
+	if strings.Contains(errMsg, "not found") {
+		writeError(w, errMsg, http.StatusNotFound)
+		return
+	}
+	if strings.Contains(errMsg, "already exists") {
+		writeError(w, errMsg, http.StatusConflict)
+		return
+	}
+	if strings.Contains(errMsg, "required") {
+		writeError(w, errMsg, http.StatusBadRequest)
+		return
+	}
+	writeError(w, fmt.Sprintf("Finance reference operation failed: %v", err), http.StatusInternalServerError)
+}
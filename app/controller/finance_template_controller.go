@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/repository"
+)
+
+// FinanceTemplateController handles HTTP requests for finance templates
+type FinanceTemplateController struct {
+	repository repository.FinanceTemplateRepositoryInterface
+}
+
+// NewFinanceTemplateController creates a new FinanceTemplateController
+func NewFinanceTemplateController(repo repository.FinanceTemplateRepositoryInterface) *FinanceTemplateController {
+	return &FinanceTemplateController{
+		repository: repo,
+	}
+}
+
+// List handles GET /admin/finance/templates
+// Example response: {"templates": [{"id": 1, "label": "Compra telas", "type": "expense", "category": "materiales", ...}]}
+func (c *FinanceTemplateController) List(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ListFinanceTemplates: Received %s request to %s", r.Method, r.URL.Path)
+
+	ctx := requestContext(r)
+	templates, err := c.repository.List(ctx)
+	if err != nil {
+		log.Printf("❌ ListFinanceTemplates: Error fetching templates: %v", err)
+		writeError(w, fmt.Sprintf("Failed to fetch finance templates: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, models.FinanceTemplateListResponse{Templates: templates})
+}
+
+// Create handles POST /admin/finance/templates
+// Example request: {"label": "Compra telas", "type": "expense", "category": "materiales", "destination": "Caja", "notes": "compra telas"}
+func (c *FinanceTemplateController) Create(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 CreateFinanceTemplate: Received %s request to %s", r.Method, r.URL.Path)
+
+	var req models.FinanceTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ CreateFinanceTemplate: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	template, err := c.repository.Create(ctx, &req)
+	if err != nil {
+		log.Printf("❌ CreateFinanceTemplate: Error creating template: %v", err)
+		writeFinanceTemplateError(w, err)
+		return
+	}
+
+	log.Printf("✅ CreateFinanceTemplate: Successfully created template id=%d", template.ID)
+	writeFinanceTemplateJSON(w, http.StatusCreated, template)
+}
+
+// Get handles GET /admin/finance/templates/:id
+func (c *FinanceTemplateController) Get(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetFinanceTemplate: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseFinanceTemplateID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := requestContext(r)
+	template, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("❌ GetFinanceTemplate: Error fetching template: %v", err)
+		writeFinanceTemplateError(w, err)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, template)
+}
+
+// Update handles PUT /admin/finance/templates/:id (replaces all fields)
+func (c *FinanceTemplateController) Update(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 UpdateFinanceTemplate: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseFinanceTemplateID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.FinanceTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ UpdateFinanceTemplate: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	template, err := c.repository.Update(ctx, id, &req)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceTemplate: Error updating template: %v", err)
+		writeFinanceTemplateError(w, err)
+		return
+	}
+
+	log.Printf("✅ UpdateFinanceTemplate: Successfully updated template id=%d", id)
+	writeFinanceTemplateJSON(w, http.StatusOK, template)
+}
+
+// Delete handles DELETE /admin/finance/templates/:id
+func (c *FinanceTemplateController) Delete(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 DeleteFinanceTemplate: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseFinanceTemplateID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := requestContext(r)
+	if err := c.repository.Delete(ctx, id); err != nil {
+		log.Printf("❌ DeleteFinanceTemplate: Error deleting template: %v", err)
+		writeFinanceTemplateError(w, err)
+		return
+	}
+
+	log.Printf("✅ DeleteFinanceTemplate: Successfully deleted template id=%d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseFinanceTemplateID extracts the template ID from /admin/finance/templates/{id}
+func parseFinanceTemplateID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/admin/finance/templates/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		log.Printf("❌ FinanceTemplate: Invalid template id: %s", idStr)
+		writeError(w, "invalid template id parameter", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeFinanceTemplateError maps repository errors to HTTP status codes
+func writeFinanceTemplateError(w http.ResponseWriter, err error) {
+	errMsg := err.Error()
+	if strings.Contains(errMsg, "not found") {
+		writeError(w, errMsg, http.StatusNotFound)
+		return
+	}
+	if strings.Contains(errMsg, "already exists") {
+		writeError(w, errMsg, http.StatusConflict)
+		return
+	}
+	if strings.Contains(errMsg, "required") || strings.Contains(errMsg, "must be") {
+		writeError(w, errMsg, http.StatusBadRequest)
+		return
+	}
+	writeError(w, fmt.Sprintf("Finance template operation failed: %v", err), http.StatusInternalServerError)
+}
+
+// writeFinanceTemplateJSON writes a JSON response with the given status code
+func writeFinanceTemplateJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("❌ FinanceTemplate: Error encoding response: %v", err)
+	}
+}
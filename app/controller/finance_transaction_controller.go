@@ -1,28 +1,34 @@
 package controller
 
 import (
-	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"armario-mascota-me/models"
 	"armario-mascota-me/repository"
+	"armario-mascota-me/utils"
 )
 
 // FinanceTransactionController handles HTTP requests for finance transactions
 type FinanceTransactionController struct {
-	repository repository.FinanceTransactionRepositoryInterface
+	repository   repository.FinanceTransactionRepositoryInterface
+	templateRepo repository.FinanceTemplateRepositoryInterface
 }
 
 // NewFinanceTransactionController creates a new FinanceTransactionController
-func NewFinanceTransactionController(repo repository.FinanceTransactionRepositoryInterface) *FinanceTransactionController {
+func NewFinanceTransactionController(repo repository.FinanceTransactionRepositoryInterface, templateRepo repository.FinanceTemplateRepositoryInterface) *FinanceTransactionController {
 	return &FinanceTransactionController{
-		repository: repo,
+		repository:   repo,
+		templateRepo: templateRepo,
 	}
 }
 
@@ -50,70 +56,455 @@ func NewFinanceTransactionController(repo repository.FinanceTransactionRepositor
 //   "notes": "Franela 10m",
 //   "createdAt": "2026-01-04T15:20:00Z"
 // }
+// Optional "templateId" pre-fills type, destination, category, counterparty and notes from a
+// finance template; non-empty body values take precedence. The response then includes
+// "appliedTemplateId" and "appliedTemplateLabel".
+// destination and category must exist in /admin/finance/destinations and /admin/finance/categories
+// (case-insensitive, saved with the managed spelling) unless "allowFreeText": true is sent.
 func (c *FinanceTransactionController) Create(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📥 CreateFinanceTransaction: Received %s request to %s", r.Method, r.URL.Path)
 
 	if r.Method != http.MethodPost {
 		log.Printf("❌ CreateFinanceTransaction: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	var req models.CreateFinanceTransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("❌ CreateFinanceTransaction: Failed to decode request body: %v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
+	ctx := requestContext(r)
+
+	// Pre-fill empty fields from the template (explicit body values win)
+	var template *models.FinanceTemplate
+	if req.TemplateID != nil {
+		var err error
+		template, err = c.templateRepo.GetByID(ctx, *req.TemplateID)
+		if err != nil {
+			log.Printf("❌ CreateFinanceTransaction: Error fetching template %d: %v", *req.TemplateID, err)
+			if strings.Contains(err.Error(), "not found") {
+				writeError(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			writeError(w, fmt.Sprintf("Failed to fetch finance template: %v", err), http.StatusInternalServerError)
+			return
+		}
+		applyFinanceTemplate(&req, template)
+		log.Printf("💰 CreateFinanceTransaction: Applied template id=%d (%s)", template.ID, template.Label)
+	}
+
 	// Validate required fields
 	if req.Type != "income" && req.Type != "expense" {
 		log.Printf("❌ CreateFinanceTransaction: Invalid type: %s", req.Type)
-		http.Error(w, "type must be 'income' or 'expense'", http.StatusBadRequest)
+		writeError(w, "type must be 'income' or 'expense'", http.StatusBadRequest)
 		return
 	}
 
 	if req.Amount <= 0 {
 		log.Printf("❌ CreateFinanceTransaction: amount must be greater than 0: %d", req.Amount)
-		http.Error(w, "amount must be greater than 0", http.StatusBadRequest)
+		writeError(w, "amount must be greater than 0", http.StatusBadRequest)
 		return
 	}
 
 	if strings.TrimSpace(req.Destination) == "" {
 		log.Printf("❌ CreateFinanceTransaction: destination is required")
-		http.Error(w, "destination is required", http.StatusBadRequest)
+		writeError(w, "destination is required", http.StatusBadRequest)
 		return
 	}
 
 	// Note: source and sourceId are automatically set to 'manual' and NULL in the repository
 	// The request body doesn't need to include them
 
-	ctx := context.Background()
 	transaction, err := c.repository.Create(ctx, &req)
 	if err != nil {
 		log.Printf("❌ CreateFinanceTransaction: Error creating transaction: %v", err)
 		errMsg := err.Error()
-		if strings.Contains(errMsg, "Invalid") || strings.Contains(errMsg, "required") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+		if strings.Contains(errMsg, "Invalid") || strings.Contains(errMsg, "required") || strings.Contains(errMsg, "unknown") {
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to create finance transaction: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to create finance transaction: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	if template != nil {
+		transaction.AppliedTemplateID = &template.ID
+		transaction.AppliedTemplateLabel = template.Label
+	}
+
 	log.Printf("✅ CreateFinanceTransaction: Successfully created transaction id=%d", transaction.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(transaction); err != nil {
 		log.Printf("❌ CreateFinanceTransaction: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// maxFinanceImportBytes and maxFinanceImportRows bound a single CSV import
+const (
+	maxFinanceImportBytes = 5 << 20
+	maxFinanceImportRows  = 5000
+)
+
+// financeImportColumns are the CSV columns accepted by Import; type, amount and destination are required
+var financeImportColumns = []string{"type", "amount", "destination", "category", "counterparty", "notes", "occurredAt"}
+
+// Import handles POST /admin/finance/transactions/import
+// Accepts a multipart form with a CSV "file" whose header row names the columns
+// type,amount,destination,category,counterparty,notes,occurredAt (any order, case-insensitive;
+// type, amount and destination are required). occurredAt is RFC3339 or YYYY-MM-DD (start of day in
+// the store timezone) and defaults to now. Each row is validated like Create; valid rows are inserted
+// in a single transaction and rejected rows are reported with their line number. If any row is rejected
+// nothing is imported (imported: 0) unless the optional form field allowPartial=true is set, in which
+// case the valid rows are still inserted.
+// Optional form field allowFreeText=true skips the managed destination/category check.
+// Example request:
+// curl -F file=@movimientos.csv -F allowFreeText=true -F allowPartial=true /admin/finance/transactions/import
+// Example response: See FinanceImportResponse structure
+func (c *FinanceTransactionController) Import(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ImportFinanceTransactions: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ ImportFinanceTransactions: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxFinanceImportBytes)
+	if err := r.ParseMultipartForm(maxFinanceImportBytes); err != nil {
+		log.Printf("❌ ImportFinanceTransactions: Failed to parse multipart form: %v", err)
+		writeError(w, fmt.Sprintf("Invalid multipart form (max %d MB): %v", maxFinanceImportBytes>>20, err), http.StatusBadRequest)
+		return
+	}
+
+	allowFreeText := false
+	if value := strings.TrimSpace(r.FormValue("allowFreeText")); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, "allowFreeText must be 'true' or 'false'", http.StatusBadRequest)
+			return
+		}
+		allowFreeText = parsed
+	}
+
+	allowPartial := false
+	if value := strings.TrimSpace(r.FormValue("allowPartial")); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, "allowPartial must be 'true' or 'false'", http.StatusBadRequest)
+			return
+		}
+		allowPartial = parsed
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		log.Printf("❌ ImportFinanceTransactions: Missing file: %v", err)
+		writeError(w, "file is required (multipart field \"file\")", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	rows, err := parseFinanceImportCSV(file, allowFreeText)
+	if err != nil {
+		log.Printf("❌ ImportFinanceTransactions: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.Import(ctx, rows, allowPartial)
+	if err != nil {
+		log.Printf("❌ ImportFinanceTransactions: Error importing transactions: %v", err)
+		writeError(w, fmt.Sprintf("Failed to import finance transactions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ImportFinanceTransactions: Imported %d rows, rejected %d", response.Imported, response.Rejected)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ ImportFinanceTransactions: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseFinanceImportCSV reads the header and rows of an import file. Malformed values (e.g. a
+// non-numeric amount or a wrong column count) reject only their row; a missing or invalid header
+// or unreadable CSV fails the whole file
+func parseFinanceImportCSV(reader io.Reader, allowFreeText bool) ([]models.FinanceImportRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "\ufeff")
+		known := ""
+		for _, column := range financeImportColumns {
+			if strings.EqualFold(name, column) {
+				known = column
+				break
+			}
+		}
+		if known == "" {
+			return nil, fmt.Errorf("unknown CSV column %q: expected %s", name, strings.Join(financeImportColumns, ","))
+		}
+		if _, duplicate := columns[known]; duplicate {
+			return nil, fmt.Errorf("duplicate CSV column %q", known)
+		}
+		columns[known] = i
+	}
+	for _, required := range []string{"type", "amount", "destination"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV column %q is required", required)
+		}
+	}
+
+	var rows []models.FinanceImportRow
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := csvReader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(rows) >= maxFinanceImportRows {
+			return nil, fmt.Errorf("CSV has more than %d rows", maxFinanceImportRows)
+		}
+
+		row := models.FinanceImportRow{Line: line}
+		if err != nil {
+			row.Error = fmt.Sprintf("expected %d columns, got %d", len(header), len(record))
+			rows = append(rows, row)
+			continue
+		}
+
+		field := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row.Request = models.CreateFinanceTransactionRequest{
+			Type:          strings.ToLower(field("type")),
+			Destination:   field("destination"),
+			Category:      field("category"),
+			Counterparty:  field("counterparty"),
+			Notes:         field("notes"),
+			AllowFreeText: allowFreeText,
+		}
+
+		amount, err := strconv.ParseInt(field("amount"), 10, 64)
+		if err != nil {
+			row.Error = fmt.Sprintf("invalid amount %q: must be an integer", field("amount"))
+			rows = append(rows, row)
+			continue
+		}
+		row.Request.Amount = amount
+
+		if occurredAt := field("occurredAt"); occurredAt != "" {
+			if date, err := utils.ParseLocalDate(occurredAt); err == nil {
+				occurredAt = date.Format(time.RFC3339)
+			}
+			row.Request.OccurredAt = occurredAt
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV file has no rows")
+	}
+	return rows, nil
+}
+
+// Update handles PUT /admin/finance/transactions/:id
+// Edits amount, destination, category, counterparty, notes and occurredAt of a manual transaction.
+// Omitted fields are left unchanged. Changing type is rejected (400) and system-generated
+// transactions (sales, refunds, reprice adjustments) cannot be edited (409).
+// A new destination or category must be in the managed lists unless "allowFreeText": true is sent.
+// Example request:
+// PUT /admin/finance/transactions/12
+// { "amount": 54000, "notes": "Franela 12m" }
+// Example response: the updated transaction (see Create)
+func (c *FinanceTransactionController) Update(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 UpdateFinanceTransaction: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPut {
+		log.Printf("❌ UpdateFinanceTransaction: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract transaction ID from URL path
+	// Path format: /admin/finance/transactions/{id}
+	idStr := strings.TrimPrefix(r.URL.Path, "/admin/finance/transactions/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		log.Printf("❌ UpdateFinanceTransaction: Invalid transaction id: %s", idStr)
+		writeError(w, "invalid transaction id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateFinanceTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ UpdateFinanceTransaction: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	transaction, err := c.repository.Update(ctx, id, &req)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceTransaction: Error updating transaction: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "system-generated") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		if strings.Contains(errMsg, "invalid") || strings.Contains(errMsg, "required") ||
+			strings.Contains(errMsg, "must be") || strings.Contains(errMsg, "cannot be changed") ||
+			strings.Contains(errMsg, "unknown") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to update finance transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ UpdateFinanceTransaction: Successfully updated transaction id=%d", transaction.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(transaction); err != nil {
+		log.Printf("❌ UpdateFinanceTransaction: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Delete handles DELETE /admin/finance/transactions/:id
+// Only manual transactions can be deleted. Sale-generated ones return 409 (refund the sale instead),
+// unknown ids return 404 and a successful delete returns 204 with no body
+func (c *FinanceTransactionController) Delete(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 DeleteFinanceTransaction: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodDelete {
+		log.Printf("❌ DeleteFinanceTransaction: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract transaction ID from URL path
+	// Path format: /admin/finance/transactions/{id}
+	idStr := strings.TrimPrefix(r.URL.Path, "/admin/finance/transactions/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		log.Printf("❌ DeleteFinanceTransaction: Invalid transaction id: %s", idStr)
+		writeError(w, "invalid transaction id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	if err := c.repository.Delete(ctx, id); err != nil {
+		log.Printf("❌ DeleteFinanceTransaction: Error deleting transaction: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "system-generated") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to delete finance transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ DeleteFinanceTransaction: Successfully deleted transaction id=%d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Transfer handles POST /admin/finance/transfer
+// Records an internal transfer as a linked expense (fromDestination) and income (toDestination),
+// both with category "transferencia". Summary and dashboard leave them out of income/expense totals
+// by default (excludeCategories); use excludeTransfers=true to hide them from the list.
+// Example request:
+// POST /admin/finance/transfer
+// { "fromDestination": "Nequi", "toDestination": "Caja", "amount": 50000, "notes": "Retiro efectivo" }
+// Example response:
+// {
+//   "transferGroupId": 4,
+//   "expense": { "id": 20, "type": "expense", "source": "transfer", "amount": 50000, "destination": "Nequi", "category": "transferencia", "counterparty": "Caja", "transferGroupId": 4, ... },
+//   "income": { "id": 21, "type": "income", "source": "transfer", "amount": 50000, "destination": "Caja", "category": "transferencia", "counterparty": "Nequi", "transferGroupId": 4, ... }
+// }
+func (c *FinanceTransactionController) Transfer(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 FinanceTransfer: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ FinanceTransfer: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ FinanceTransfer: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.Transfer(ctx, &req)
+	if err != nil {
+		log.Printf("❌ FinanceTransfer: Error creating transfer: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "invalid") || strings.Contains(errMsg, "required") || strings.Contains(errMsg, "must be") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to create transfer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ FinanceTransfer: Successfully created transfer group=%d", response.TransferGroupID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ FinanceTransfer: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
 // List handles GET /admin/finance/transactions
-// Query params: from, to, type, source, destination, category, q, limit, cursor
+// Query params: from, to, type, source, destination, category, q, minAmount, maxAmount, limit, cursor, excludeTransfers
+// excludeTransfers=true hides transfer rows (category "transferencia") from the list
+// minAmount/maxAmount are inclusive, non-negative bounds on amount (minAmount <= maxAmount)
 // Example response:
 // {
 //   "transactions": [
@@ -137,7 +528,7 @@ func (c *FinanceTransactionController) List(w http.ResponseWriter, r *http.Reque
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ ListFinanceTransactions: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -149,7 +540,7 @@ func (c *FinanceTransactionController) List(w http.ResponseWriter, r *http.Reque
 		_, err := time.Parse("2006-01-02", fromStr)
 		if err != nil {
 			log.Printf("❌ ListFinanceTransactions: Invalid from date format: %s", fromStr)
-			http.Error(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		req.From = &fromStr
@@ -160,7 +551,7 @@ func (c *FinanceTransactionController) List(w http.ResponseWriter, r *http.Reque
 		_, err := time.Parse("2006-01-02", toStr)
 		if err != nil {
 			log.Printf("❌ ListFinanceTransactions: Invalid to date format: %s", toStr)
-			http.Error(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		req.To = &toStr
@@ -169,7 +560,7 @@ func (c *FinanceTransactionController) List(w http.ResponseWriter, r *http.Reque
 	if typeStr := r.URL.Query().Get("type"); typeStr != "" {
 		if typeStr != "income" && typeStr != "expense" {
 			log.Printf("❌ ListFinanceTransactions: Invalid type: %s", typeStr)
-			http.Error(w, "type must be 'income' or 'expense'", http.StatusBadRequest)
+			writeError(w, "type must be 'income' or 'expense'", http.StatusBadRequest)
 			return
 		}
 		req.Type = &typeStr
@@ -191,11 +582,37 @@ func (c *FinanceTransactionController) List(w http.ResponseWriter, r *http.Reque
 		req.Q = &qStr
 	}
 
+	if minStr := r.URL.Query().Get("minAmount"); minStr != "" {
+		minAmount, err := strconv.ParseInt(minStr, 10, 64)
+		if err != nil || minAmount < 0 {
+			log.Printf("❌ ListFinanceTransactions: Invalid minAmount: %s", minStr)
+			writeError(w, "minAmount must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		req.MinAmount = &minAmount
+	}
+
+	if maxStr := r.URL.Query().Get("maxAmount"); maxStr != "" {
+		maxAmount, err := strconv.ParseInt(maxStr, 10, 64)
+		if err != nil || maxAmount < 0 {
+			log.Printf("❌ ListFinanceTransactions: Invalid maxAmount: %s", maxStr)
+			writeError(w, "maxAmount must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		req.MaxAmount = &maxAmount
+	}
+
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		log.Printf("❌ ListFinanceTransactions: minAmount %d greater than maxAmount %d", *req.MinAmount, *req.MaxAmount)
+		writeError(w, "minAmount must be less than or equal to maxAmount", http.StatusBadRequest)
+		return
+	}
+
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		limit, err := strconv.Atoi(limitStr)
 		if err != nil || limit <= 0 {
 			log.Printf("❌ ListFinanceTransactions: Invalid limit: %s", limitStr)
-			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			writeError(w, "limit must be a positive integer", http.StatusBadRequest)
 			return
 		}
 		if limit > 200 {
@@ -208,16 +625,24 @@ func (c *FinanceTransactionController) List(w http.ResponseWriter, r *http.Reque
 		req.Cursor = &cursorStr
 	}
 
-	ctx := context.Background()
+	excludeTransfers, err := parseExcludeTransfers(r)
+	if err != nil {
+		log.Printf("❌ ListFinanceTransactions: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.ExcludeTransfers = excludeTransfers
+
+	ctx := requestContext(r)
 	response, err := c.repository.List(ctx, req)
 	if err != nil {
 		log.Printf("❌ ListFinanceTransactions: Error fetching transactions: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "Invalid") || strings.Contains(errMsg, "invalid") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to fetch transactions: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to fetch transactions: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -226,16 +651,20 @@ func (c *FinanceTransactionController) List(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("❌ ListFinanceTransactions: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
 // Summary handles GET /admin/finance/summary
-// Query params: from (optional YYYY-MM-DD), to (optional YYYY-MM-DD)
+// Query params: from (optional YYYY-MM-DD), to (optional YYYY-MM-DD), excludeCategories (optional, comma-separated)
+// excludeCategories removes those categories from range.income/expense/net only (see parseExcludeCategories,
+// defaults to "transferencia"); balanceAllTime, byDestinationAllTime, opening/closing balances and
+// byDestinationRange always include them. balanceAllTime is still correct since transfers net to zero overall
 // Example response:
 // {
 //   "currency": "COP",
+//   "excludedCategories": ["transferencia"],
 //   "balanceAllTime": 350000,
 //   "byDestinationAllTime": [
 //     { "destination": "Nequi", "balance": 200000 },
@@ -260,7 +689,7 @@ func (c *FinanceTransactionController) Summary(w http.ResponseWriter, r *http.Re
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ SummaryFinanceTransactions: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -272,7 +701,7 @@ func (c *FinanceTransactionController) Summary(w http.ResponseWriter, r *http.Re
 		_, err := time.Parse("2006-01-02", fromStr)
 		if err != nil {
 			log.Printf("❌ SummaryFinanceTransactions: Invalid from date format: %s", fromStr)
-			http.Error(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		from = &fromStr
@@ -283,7 +712,7 @@ func (c *FinanceTransactionController) Summary(w http.ResponseWriter, r *http.Re
 		_, err := time.Parse("2006-01-02", toStr)
 		if err != nil {
 			log.Printf("❌ SummaryFinanceTransactions: Invalid to date format: %s", toStr)
-			http.Error(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		to = &toStr
@@ -292,20 +721,27 @@ func (c *FinanceTransactionController) Summary(w http.ResponseWriter, r *http.Re
 	// Both from and to must be provided together for range calculations
 	if (from != nil && to == nil) || (from == nil && to != nil) {
 		log.Printf("❌ SummaryFinanceTransactions: Both from and to must be provided together")
-		http.Error(w, "Both from and to must be provided together for range calculations", http.StatusBadRequest)
+		writeError(w, "Both from and to must be provided together for range calculations", http.StatusBadRequest)
+		return
+	}
+
+	excludeCategories, err := parseExcludeCategories(r)
+	if err != nil {
+		log.Printf("❌ SummaryFinanceTransactions: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-	response, err := c.repository.Summary(ctx, from, to)
+	ctx := requestContext(r)
+	response, err := c.repository.Summary(ctx, from, to, excludeCategories)
 	if err != nil {
 		log.Printf("❌ SummaryFinanceTransactions: Error calculating summary: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "Invalid") || strings.Contains(errMsg, "invalid") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to calculate summary: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to calculate summary: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -314,20 +750,22 @@ func (c *FinanceTransactionController) Summary(w http.ResponseWriter, r *http.Re
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("❌ SummaryFinanceTransactions: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
 // Dashboard handles GET /admin/finance/dashboard
-// Query params: period (month|quarter|year), from (YYYY-MM-DD), to (YYYY-MM-DD), compareWith (previous|last_year)
+// Query params: period (month|quarter|year), from (YYYY-MM-DD), to (YYYY-MM-DD), compareWith (previous|last_year), excludeCategories (comma-separated)
+// excludeCategories (default "transferencia", see parseExcludeCategories) removes those categories from period
+// metrics, cash flow, category, counterparty and top transactions; byDestination always includes them
 // Example response: See FinanceDashboardResponse structure
 func (c *FinanceTransactionController) Dashboard(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📥 DashboardFinanceTransactions: Received %s request to %s", r.Method, r.URL.Path)
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ DashboardFinanceTransactions: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -337,7 +775,7 @@ func (c *FinanceTransactionController) Dashboard(w http.ResponseWriter, r *http.
 	if periodStr := r.URL.Query().Get("period"); periodStr != "" {
 		if periodStr != "month" && periodStr != "quarter" && periodStr != "year" {
 			log.Printf("❌ DashboardFinanceTransactions: Invalid period: %s", periodStr)
-			http.Error(w, "period must be 'month', 'quarter', or 'year'", http.StatusBadRequest)
+			writeError(w, "period must be 'month', 'quarter', or 'year'", http.StatusBadRequest)
 			return
 		}
 		req.Period = &periodStr
@@ -348,7 +786,7 @@ func (c *FinanceTransactionController) Dashboard(w http.ResponseWriter, r *http.
 		_, err := time.Parse("2006-01-02", fromStr)
 		if err != nil {
 			log.Printf("❌ DashboardFinanceTransactions: Invalid from date format: %s", fromStr)
-			http.Error(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		req.From = &fromStr
@@ -359,7 +797,7 @@ func (c *FinanceTransactionController) Dashboard(w http.ResponseWriter, r *http.
 		_, err := time.Parse("2006-01-02", toStr)
 		if err != nil {
 			log.Printf("❌ DashboardFinanceTransactions: Invalid to date format: %s", toStr)
-			http.Error(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		req.To = &toStr
@@ -368,29 +806,37 @@ func (c *FinanceTransactionController) Dashboard(w http.ResponseWriter, r *http.
 	// Validate that if from/to are provided, both must be provided
 	if (req.From != nil && req.To == nil) || (req.From == nil && req.To != nil) {
 		log.Printf("❌ DashboardFinanceTransactions: Both from and to must be provided together")
-		http.Error(w, "Both from and to must be provided together", http.StatusBadRequest)
+		writeError(w, "Both from and to must be provided together", http.StatusBadRequest)
 		return
 	}
 
 	if compareWithStr := r.URL.Query().Get("compareWith"); compareWithStr != "" {
 		if compareWithStr != "previous" && compareWithStr != "last_year" {
 			log.Printf("❌ DashboardFinanceTransactions: Invalid compareWith: %s", compareWithStr)
-			http.Error(w, "compareWith must be 'previous' or 'last_year'", http.StatusBadRequest)
+			writeError(w, "compareWith must be 'previous' or 'last_year'", http.StatusBadRequest)
 			return
 		}
 		req.CompareWith = &compareWithStr
 	}
 
-	ctx := context.Background()
+	excludeCategories, err := parseExcludeCategories(r)
+	if err != nil {
+		log.Printf("❌ DashboardFinanceTransactions: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.ExcludeCategories = excludeCategories
+
+	ctx := requestContext(r)
 	response, err := c.repository.Dashboard(ctx, req)
 	if err != nil {
 		log.Printf("❌ DashboardFinanceTransactions: Error calculating dashboard: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "Invalid") || strings.Contains(errMsg, "invalid") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to calculate dashboard: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to calculate dashboard: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -399,8 +845,184 @@ func (c *FinanceTransactionController) Dashboard(w http.ResponseWriter, r *http.
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("❌ DashboardFinanceTransactions: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Integrity handles GET /admin/finance/integrity
+// Verifies balanceAllTime == sum(byDestinationAllTime.balance) and that each sale's amount_paid
+// equals the sum of its frozen line totals. Always returns 200 with the list of violations (if any)
+// Example response (all consistent):
+// {
+//   "consistent": true,
+//   "balanceAllTime": 150000,
+//   "sumByDestinationAllTime": 150000,
+//   "salesChecked": 42,
+//   "violations": []
+// }
+func (c *FinanceTransactionController) Integrity(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 FinanceIntegrity: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ FinanceIntegrity: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.Integrity(ctx)
+	if err != nil {
+		log.Printf("❌ FinanceIntegrity: Error running integrity checks: %v", err)
+		writeError(w, fmt.Sprintf("Failed to run integrity checks: %v", err), http.StatusInternalServerError)
 		return
 	}
+
+	log.Printf("✅ FinanceIntegrity: consistent=%v, violations=%d", response.Consistent, len(response.Violations))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ FinanceIntegrity: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseExcludeTransfers parses the optional excludeTransfers query param (true/false, 1/0)
+func parseExcludeTransfers(r *http.Request) (bool, error) {
+	value := strings.TrimSpace(r.URL.Query().Get("excludeTransfers"))
+	if value == "" {
+		return false, nil
+	}
+	excludeTransfers, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("excludeTransfers must be 'true' or 'false'")
+	}
+	return excludeTransfers, nil
+}
+
+// parseExcludeCategories parses the optional excludeCategories query param (comma-separated) used by
+// summary and dashboard. When it is missing, transfers are excluded unless excludeTransfers=false;
+// an empty value (excludeCategories=) excludes nothing
+func parseExcludeCategories(r *http.Request) ([]string, error) {
+	values, ok := r.URL.Query()["excludeCategories"]
+	if !ok {
+		excludeTransfers := true
+		if value := strings.TrimSpace(r.URL.Query().Get("excludeTransfers")); value != "" {
+			var err error
+			if excludeTransfers, err = strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("excludeTransfers must be 'true' or 'false'")
+			}
+		}
+		if !excludeTransfers {
+			return []string{}, nil
+		}
+		return []string{repository.TransferCategory}, nil
+	}
+
+	categories := []string{}
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, category := range strings.Split(value, ",") {
+			category = strings.TrimSpace(category)
+			if category == "" || seen[category] {
+				continue
+			}
+			seen[category] = true
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+// applyFinanceTemplate fills the empty fields of a create request from a finance template
+func applyFinanceTemplate(req *models.CreateFinanceTransactionRequest, template *models.FinanceTemplate) {
+	if strings.TrimSpace(req.Type) == "" {
+		req.Type = template.Type
+	}
+	if strings.TrimSpace(req.Destination) == "" {
+		req.Destination = template.Destination
+	}
+	if strings.TrimSpace(req.Category) == "" {
+		req.Category = template.Category
+	}
+	if strings.TrimSpace(req.Counterparty) == "" {
+		req.Counterparty = template.Counterparty
+	}
+	if strings.TrimSpace(req.Notes) == "" {
+		req.Notes = template.Notes
+	}
 }
 
+// Ledger handles GET /admin/finance/destinations/:name/ledger
+// Query params: from (YYYY-MM-DD), to (YYYY-MM-DD), both optional
+// Returns the destination's transactions in chronological order with the running balance after each one,
+// starting from the opening balance before from
+func (c *FinanceTransactionController) Ledger(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 DestinationLedger: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ DestinationLedger: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract destination name from URL path
+	// Path format: /admin/finance/destinations/{name}/ledger
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/admin/finance/destinations/")
+	rawName := strings.TrimSuffix(path, "/ledger")
+	if rawName == path || rawName == "" || strings.Contains(rawName, "/") {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+	destination, err := url.PathUnescape(rawName)
+	if err != nil || strings.TrimSpace(destination) == "" {
+		log.Printf("❌ DestinationLedger: Invalid destination: %s", rawName)
+		writeError(w, "invalid destination parameter", http.StatusBadRequest)
+		return
+	}
+
+	var from, to *string
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if _, err := time.Parse("2006-01-02", fromStr); err != nil {
+			log.Printf("❌ DestinationLedger: Invalid from date format: %s", fromStr)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = &fromStr
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if _, err := time.Parse("2006-01-02", toStr); err != nil {
+			log.Printf("❌ DestinationLedger: Invalid to date format: %s", toStr)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = &toStr
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.Ledger(ctx, destination, from, to)
+	if err != nil {
+		log.Printf("❌ DestinationLedger: Error building ledger: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "invalid") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to build ledger: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ DestinationLedger: Successfully built ledger with %d entries", len(response.Entries))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ DestinationLedger: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -1,12 +1,12 @@
 package controller
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"armario-mascota-me/models"
@@ -68,7 +68,7 @@ func (c *ItemController) AddStock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 
 	// Call repository to upsert stock
 	response, err := c.repository.UpsertStock(ctx, req.DesignAssetID, sizeTrimmed, req.Quantity)
@@ -104,7 +104,7 @@ func (c *ItemController) FilterItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 
 	// Parse query parameters
 	queryParams := r.URL.Query()
@@ -202,3 +202,697 @@ func (c *ItemController) FilterItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// UpdateDesignAsset handles PATCH /admin/items/:id/design-asset
+// Relinks an item to another active design asset (data correction for mislinked items).
+// SKU and price are kept unless "recompute": true asks to recalculate them from the new design asset.
+// Example request:
+// PATCH /admin/items/12/design-asset
+// {
+//   "designAssetId": 45,
+//   "recompute": true
+// }
+// Example response:
+// {
+//   "id": 12,
+//   "sku": "XS_ABC123",
+//   "size": "XS",
+//   "price": 12000,
+//   "stockTotal": 3,
+//   "stockReserved": 1,
+//   "stockAvailable": 2,
+//   "designAssetId": 45,
+//   "hoodieType": "BE",
+//   "hoodieTypeLabel": "buso tipo esqueleto",
+//   "imageUrlThumb": "/admin/design-assets/pending/45/image?size=thumb",
+//   "imageUrlMedium": "/admin/design-assets/pending/45/image?size=medium"
+// }
+func (c *ItemController) UpdateDesignAsset(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 UpdateDesignAsset: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPatch {
+		log.Printf("❌ UpdateDesignAsset: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract item ID from URL path
+	// Path format: /admin/items/{id}/design-asset
+	path := strings.TrimPrefix(r.URL.Path, "/admin/items/")
+	idStr := strings.TrimSuffix(path, "/design-asset")
+	if idStr == path || idStr == "" {
+		http.Error(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ UpdateDesignAsset: Invalid item id: %s", idStr)
+		http.Error(w, "invalid item id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateItemDesignAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ UpdateDesignAsset: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.DesignAssetID <= 0 {
+		log.Printf("❌ UpdateDesignAsset: Invalid designAssetId: %d", req.DesignAssetID)
+		http.Error(w, "designAssetId must be greater than 0", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	item, err := c.repository.UpdateDesignAsset(ctx, itemID, req.DesignAssetID, req.Recompute)
+	if err != nil {
+		log.Printf("❌ UpdateDesignAsset: Error updating design asset: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") || strings.Contains(errMsg, "does not exist") {
+			http.Error(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "not active") || strings.Contains(errMsg, "already") {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update design asset: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Build image endpoints and apply mappings for readable labels
+	item.ImageUrlThumb = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=thumb", item.DesignAssetID)
+	item.ImageUrlMedium = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=medium", item.DesignAssetID)
+	item.ColorPrimaryLabel = utils.MapCodeToColor(item.ColorPrimary)
+	item.ColorSecondaryLabel = utils.MapCodeToColor(item.ColorSecondary)
+	item.HoodieTypeLabel = utils.MapCodeToHoodieType(item.HoodieType)
+	item.ImageTypeLabel = utils.MapCodeToImageType(item.ImageType)
+	item.DecoBaseLabel = utils.MapCodeToDecoBase(item.DecoBase)
+
+	log.Printf("✅ UpdateDesignAsset: Successfully relinked item id=%d to design asset id=%d", itemID, item.DesignAssetID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		log.Printf("❌ UpdateDesignAsset: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// PriceQuote handles GET /admin/items/:id/price-quote?qty=3&orderType=
+// Returns the effective price of a single item at the given quantity without creating an order
+func (c *ItemController) PriceQuote(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 PriceQuote: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ PriceQuote: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract item ID from URL path
+	// Path format: /admin/items/{id}/price-quote
+	path := strings.TrimPrefix(r.URL.Path, "/admin/items/")
+	idStr := strings.TrimSuffix(path, "/price-quote")
+	if idStr == path || idStr == "" {
+		http.Error(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ PriceQuote: Invalid item id: %s", idStr)
+		http.Error(w, "invalid item id parameter", http.StatusBadRequest)
+		return
+	}
+
+	qty := 1
+	if qtyStr := r.URL.Query().Get("qty"); qtyStr != "" {
+		qty, err = strconv.Atoi(qtyStr)
+		if err != nil || qty <= 0 {
+			log.Printf("❌ PriceQuote: Invalid qty: %s", qtyStr)
+			http.Error(w, "qty must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	orderType := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("orderType")))
+	if orderType != "" && orderType != "mayorista" && orderType != "detal" {
+		log.Printf("❌ PriceQuote: Invalid orderType: %s", orderType)
+		http.Error(w, "orderType must be 'mayorista' or 'detal'", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	quote, err := c.repository.QuotePrice(ctx, itemID, qty, orderType)
+	if err != nil {
+		log.Printf("❌ PriceQuote: Error quoting price: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to quote price: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ PriceQuote: item id=%d, qty=%d, total=%d", itemID, qty, quote.Total)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(quote); err != nil {
+		log.Printf("❌ PriceQuote: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseItemID extracts the item ID from /admin/items/{id}{suffix}
+func parseItemID(path, suffix string) (int64, error) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(path, "/admin/items/"), suffix)
+	if idStr == "" || strings.Contains(idStr, "/") {
+		return 0, fmt.Errorf("invalid path format")
+	}
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || itemID <= 0 {
+		return 0, fmt.Errorf("invalid item id parameter")
+	}
+	return itemID, nil
+}
+
+// writeItemError maps item repository errors to HTTP status codes
+func writeItemError(w http.ResponseWriter, err error, action string) {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "not found") || strings.Contains(errMsg, "does not exist"):
+		http.Error(w, errMsg, http.StatusNotFound)
+	case strings.Contains(errMsg, "already"):
+		http.Error(w, errMsg, http.StatusConflict)
+	case strings.Contains(errMsg, "invalid") || strings.Contains(errMsg, "required") ||
+		strings.Contains(errMsg, "must be") || strings.Contains(errMsg, "cannot"):
+		http.Error(w, errMsg, http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to %s: %v", action, err), http.StatusInternalServerError)
+	}
+}
+
+// CreateItem handles POST /admin/items
+// Creates an item for a design asset and size. The SKU is generated when not provided.
+// Returns 409 when the SKU is already used or the design asset already has an item for that size.
+// Example request:
+// { "designAssetId": 45, "size": "M", "price": 12000, "stockTotal": 3 }
+// Example response:
+// { "id": 120, "designAssetId": 45, "size": "M", "sku": "M_ABC123", "price": 12000, "stockTotal": 3, "stockReserved": 0, "isActive": true, "createdAt": "2026-01-05T09:00:00Z" }
+func (c *ItemController) CreateItem(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 CreateItem: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ CreateItem: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ CreateItem: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.DesignAssetID <= 0 {
+		log.Printf("❌ CreateItem: Invalid designAssetId: %d", req.DesignAssetID)
+		http.Error(w, "designAssetId must be greater than 0", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Size) == "" {
+		log.Printf("❌ CreateItem: size is required")
+		http.Error(w, "size is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	item, err := c.repository.Create(ctx, &req)
+	if err != nil {
+		log.Printf("❌ CreateItem: Error creating item: %v", err)
+		writeItemError(w, err, "create item")
+		return
+	}
+
+	log.Printf("✅ CreateItem: Successfully created item id=%d, sku=%s", item.ID, item.SKU)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		log.Printf("❌ CreateItem: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetItem handles GET /admin/items/:id
+// Example response: See Item structure
+func (c *ItemController) GetItem(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetItem: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetItem: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	itemID, err := parseItemID(r.URL.Path, "")
+	if err != nil {
+		log.Printf("❌ GetItem: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	item, err := c.repository.GetByID(ctx, itemID)
+	if err != nil {
+		log.Printf("❌ GetItem: Error fetching item: %v", err)
+		writeItemError(w, err, "get item")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		log.Printf("❌ GetItem: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateItem handles PUT/PATCH /admin/items/:id
+// Updates sku, price and/or stockTotal. stockTotal cannot go below stockReserved;
+// a SKU already used by another item returns 409.
+// Example request:
+// { "price": 13000, "stockTotal": 5 }
+// Example response: See Item structure
+func (c *ItemController) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 UpdateItem: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
+		log.Printf("❌ UpdateItem: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	itemID, err := parseItemID(r.URL.Path, "")
+	if err != nil {
+		log.Printf("❌ UpdateItem: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ UpdateItem: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	item, err := c.repository.Update(ctx, itemID, &req)
+	if err != nil {
+		log.Printf("❌ UpdateItem: Error updating item: %v", err)
+		writeItemError(w, err, "update item")
+		return
+	}
+
+	log.Printf("✅ UpdateItem: Successfully updated item id=%d", itemID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		log.Printf("❌ UpdateItem: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetItemActive handles PATCH /admin/items/:id/active
+// Example request:
+// { "isActive": false }
+// Example response: See Item structure
+func (c *ItemController) SetItemActive(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SetItemActive: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPatch {
+		log.Printf("❌ SetItemActive: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	itemID, err := parseItemID(r.URL.Path, "/active")
+	if err != nil {
+		log.Printf("❌ SetItemActive: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetItemActiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ SetItemActive: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	item, err := c.repository.SetActive(ctx, itemID, req.IsActive)
+	if err != nil {
+		log.Printf("❌ SetItemActive: Error updating item: %v", err)
+		writeItemError(w, err, "update item")
+		return
+	}
+
+	log.Printf("✅ SetItemActive: item id=%d is_active=%t", itemID, item.IsActive)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		log.Printf("❌ SetItemActive: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// AdjustStock handles POST /admin/items/:id/adjust
+// Corrects stock_total by delta (positive or negative) and records the change in the audit log.
+// Returns 400 when the result would drop stockTotal below stockReserved
+// Example request:
+// { "delta": -2, "reason": "conteo físico", "user": "erika" }
+// Example response:
+// {
+//   "item": { "id": 120, "stockTotal": 3, "stockReserved": 1, ... },
+//   "adjustment": { "id": 7, "itemId": 120, "delta": -2, "stockTotalBefore": 5, "stockTotalAfter": 3,
+//                   "reason": "conteo físico", "user": "erika", "createdAt": "2026-01-05T09:00:00Z" }
+// }
+func (c *ItemController) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 AdjustStock: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ AdjustStock: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	itemID, err := parseItemID(r.URL.Path, "/adjust")
+	if err != nil {
+		log.Printf("❌ AdjustStock: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.AdjustItemStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ AdjustStock: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.AdjustStock(ctx, itemID, &req)
+	if err != nil {
+		log.Printf("❌ AdjustStock: Error adjusting stock: %v", err)
+		writeItemError(w, err, "adjust stock")
+		return
+	}
+
+	log.Printf("✅ AdjustStock: item id=%d stock_total=%d", itemID, response.Item.StockTotal)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ AdjustStock: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAdjustments handles GET /admin/items/:id/adjustments
+// Returns the stock adjustment history of an item, newest first
+// Example response:
+// { "itemId": 120, "adjustments": [ { "id": 7, "delta": -2, "stockTotalBefore": 5, "stockTotalAfter": 3, ... } ] }
+func (c *ItemController) GetAdjustments(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetAdjustments: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetAdjustments: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	itemID, err := parseItemID(r.URL.Path, "/adjustments")
+	if err != nil {
+		log.Printf("❌ GetAdjustments: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.GetAdjustments(ctx, itemID)
+	if err != nil {
+		log.Printf("❌ GetAdjustments: Error fetching adjustments: %v", err)
+		writeItemError(w, err, "get adjustments")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ GetAdjustments: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// CheckAvailability handles POST /admin/items/availability
+// Dry-run stock check: reports per item whether the requested quantity is available without reserving
+// anything. Lines repeating an item are summed. Missing or inactive items are reported as unavailable
+// Example request:
+// [{"itemId": 123, "qty": 10}, {"itemId": 124, "qty": 2}]
+// Example response:
+// {
+//   "allAvailable": false,
+//   "items": [
+//     { "itemId": 123, "requestedQty": 10, "available": 4, "isAvailable": false, "found": true, "isActive": true },
+//     { "itemId": 124, "requestedQty": 2, "available": 6, "isAvailable": true, "found": true, "isActive": true }
+//   ]
+// }
+func (c *ItemController) CheckAvailability(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 CheckAvailability: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ CheckAvailability: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var lines []models.ItemAvailabilityLine
+	if err := json.NewDecoder(r.Body).Decode(&lines); err != nil {
+		log.Printf("❌ CheckAvailability: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.CheckAvailability(ctx, lines)
+	if err != nil {
+		log.Printf("❌ CheckAvailability: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "must") || strings.Contains(errMsg, "too many items") {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to check availability: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ CheckAvailability: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// LowStock handles GET /admin/items/low-stock?threshold=3
+// Lists active items whose available stock (stockTotal - stockReserved) is at or below threshold
+// (defaults to 5), lowest availability first
+// Example response:
+// {
+//   "threshold": 3,
+//   "count": 1,
+//   "items": [
+//     {
+//       "id": 12, "sku": "MN_ABC123", "size": "MN", "stockTotal": 2, "stockReserved": 1, "available": 1,
+//       "designAssetId": 45, "description": "Buso negro con azul cielo",
+//       "colorPrimary": "NG", "colorSecondary": "AC", "hoodieType": "BE",
+//       "colorPrimaryLabel": "negro", "colorSecondaryLabel": "azul cielo", "hoodieTypeLabel": "buso tipo esqueleto",
+//       "imageUrlThumb": "/admin/design-assets/pending/45/image?size=thumb"
+//     }
+//   ]
+// }
+func (c *ItemController) LowStock(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 LowStock: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ LowStock: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	threshold, err := utils.ParseLowStockThreshold(r.URL.Query().Get("threshold"))
+	if err != nil {
+		log.Printf("❌ LowStock: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.GetLowStock(ctx, threshold)
+	if err != nil {
+		log.Printf("❌ LowStock: Error getting low stock items: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to get low stock items: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	for i := range response.Items {
+		item := &response.Items[i]
+		item.ColorPrimaryLabel = utils.MapCodeToColor(item.ColorPrimary)
+		item.ColorSecondaryLabel = utils.MapCodeToColor(item.ColorSecondary)
+		item.HoodieTypeLabel = utils.MapCodeToHoodieType(item.HoodieType)
+		item.ImageUrlThumb = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=thumb", item.DesignAssetID)
+	}
+
+	log.Printf("✅ LowStock: Returning %d items (threshold=%d)", response.Count, threshold)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ LowStock: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// defaultItemSearchLimit and maxItemSearchLimit bound the limit of SearchItems
+const (
+	defaultItemSearchLimit = 20
+	maxItemSearchLimit     = 50
+)
+
+// SearchItems handles GET /admin/items/search?q=negro&limit=20
+// Type-ahead search over active items by SKU, design asset description and readable color/hoodie labels.
+// Exact SKU matches come first. limit defaults to 20 (max 50)
+// Example response: See ItemSearchResponse structure
+func (c *ItemController) SearchItems(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SearchItems: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ SearchItems: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		log.Printf("❌ SearchItems: q is required")
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultItemSearchLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			log.Printf("❌ SearchItems: Invalid limit: %s", limitStr)
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if parsed > maxItemSearchLimit {
+			parsed = maxItemSearchLimit
+		}
+		limit = parsed
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.SearchItems(ctx, query, limit)
+	if err != nil {
+		log.Printf("❌ SearchItems: Error searching items: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to search items: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	for i := range response.Items {
+		item := &response.Items[i]
+		item.ColorPrimaryLabel = utils.MapCodeToColor(item.ColorPrimary)
+		item.ColorSecondaryLabel = utils.MapCodeToColor(item.ColorSecondary)
+		item.HoodieTypeLabel = utils.MapCodeToHoodieType(item.HoodieType)
+		item.ImageUrlThumb = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=thumb", item.DesignAssetID)
+		item.ImageUrlMedium = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=medium", item.DesignAssetID)
+	}
+
+	log.Printf("✅ SearchItems: Returning %d items for q=%q", response.Count, query)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ SearchItems: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDesignAssetItems handles GET /admin/design-assets/:id/items
+// Lists every item (SKU) generated from a design asset, ordered MN, IT, XS, S, M, L, XL
+// Example response:
+// {
+//   "designAssetId": 45,
+//   "count": 2,
+//   "items": [
+//     { "id": 12, "sku": "MN_ABC123", "size": "MN", "price": 9000, "stockTotal": 2, "stockReserved": 1, "stockAvailable": 1, "isActive": true },
+//     { "id": 13, "sku": "S_ABC123", "size": "S", "price": 12000, "stockTotal": 0, "stockReserved": 0, "stockAvailable": 0, "isActive": true }
+//   ]
+// }
+func (c *ItemController) GetDesignAssetItems(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetDesignAssetItems: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetDesignAssetItems: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/design-assets/{id}/items
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/design-assets/"), "/items")
+	designAssetID, err := strconv.Atoi(idStr)
+	if err != nil || designAssetID <= 0 {
+		log.Printf("❌ GetDesignAssetItems: Invalid design asset id: %s", idStr)
+		http.Error(w, "invalid design asset id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.GetItemsByDesignAsset(ctx, designAssetID)
+	if err != nil {
+		log.Printf("❌ GetDesignAssetItems: Error fetching items: %v", err)
+		writeItemError(w, err, "get design asset items")
+		return
+	}
+
+	log.Printf("✅ GetDesignAssetItems: Returning %d items for design_asset_id=%d", response.Count, designAssetID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ GetDesignAssetItems: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/pricing"
+	"armario-mascota-me/repository"
+)
+
+// PricingController handles HTTP requests for the pricing engine
+type PricingController struct {
+	itemRepository repository.ItemRepositoryInterface
+	configPath     string
+}
+
+// NewPricingController creates a new PricingController
+// configPath is the pricing config file re-read by Reload
+func NewPricingController(itemRepo repository.ItemRepositoryInterface, configPath string) *PricingController {
+	return &PricingController{
+		itemRepository: itemRepo,
+		configPath:     configPath,
+	}
+}
+
+// Preview handles POST /admin/pricing/preview
+// Prices an arbitrary cart with bundles and wholesale applied, without creating a reserved order
+// Example request:
+// [{"itemId": 12, "qty": 3}, {"itemId": 40, "qty": 2}]
+// Example request with coupon (see PricingPreviewRequest):
+// {"lines": [{"itemId": 12, "qty": 3}], "couponCode": "VERANO10"}
+// Example response: See PricingBreakdown structure (includes appliedRules, orderType, coupon and a per-line explanation)
+func (c *PricingController) Preview(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 PricingPreview: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ PricingPreview: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		log.Printf("❌ PricingPreview: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// The body is either a bare array of lines or a PricingPreviewRequest object
+	var req models.PricingPreviewRequest
+	var err error
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(body, &req.Lines)
+	} else {
+		err = json.Unmarshal(body, &req)
+	}
+	if err != nil {
+		log.Printf("❌ PricingPreview: Failed to decode request body: %v", err)
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	lines := req.Lines
+
+	ctx := requestContext(r)
+	breakdown, err := c.itemRepository.PreviewPricing(ctx, lines, req.CouponCode)
+	if err != nil {
+		log.Printf("❌ PricingPreview: Error previewing pricing: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			http.Error(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "invalid") || strings.Contains(errMsg, "required") || strings.Contains(errMsg, "must be") {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to preview pricing: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ PricingPreview: %d lines, total=%d, orderType=%s", len(lines), breakdown.Total, breakdown.OrderType)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(breakdown); err != nil {
+		log.Printf("❌ PricingPreview: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Reload handles POST /admin/pricing/reload
+// Re-reads the pricing config file without a restart. A malformed config is rejected and the
+// previous config stays active.
+// Example response:
+// { "currency": "COP", "ruleCount": 5 }
+func (c *PricingController) Reload(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 PricingReload: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ PricingReload: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	engine := pricing.GetEngine()
+	if engine == nil {
+		log.Printf("❌ PricingReload: Pricing engine not available")
+		http.Error(w, "pricing engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := engine.Reload(c.configPath); err != nil {
+		log.Printf("❌ PricingReload: Error reloading pricing config: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to reload pricing config: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	currency, ruleCount := engine.ConfigInfo()
+	response := models.PricingReloadResponse{
+		Currency:  currency,
+		RuleCount: ruleCount,
+	}
+
+	log.Printf("✅ PricingReload: currency=%s, rules=%d", currency, ruleCount)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ PricingReload: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Validate handles GET /admin/pricing/validate
+// Lists the (hoodie type, size) combinations of active items that the pricing config does not cover
+// and would therefore be priced with fallback prices
+// Example response: See PricingValidationResponse structure
+func (c *PricingController) Validate(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 PricingValidate: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ PricingValidate: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	engine := pricing.GetEngine()
+	if engine == nil {
+		log.Printf("❌ PricingValidate: Pricing engine not available")
+		http.Error(w, "pricing engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := engine.ValidateAgainstCatalog(ctx)
+	if err != nil {
+		log.Printf("❌ PricingValidate: Error validating pricing config: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to validate pricing config: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ PricingValidate: valid=%v, unmapped=%d", response.Valid, len(response.Unmapped))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ PricingValidate: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
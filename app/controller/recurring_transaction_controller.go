@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/repository"
+)
+
+// RecurringTransactionController handles HTTP requests for recurring finance transactions
+type RecurringTransactionController struct {
+	repository repository.RecurringTransactionRepositoryInterface
+}
+
+// NewRecurringTransactionController creates a new RecurringTransactionController
+func NewRecurringTransactionController(repo repository.RecurringTransactionRepositoryInterface) *RecurringTransactionController {
+	return &RecurringTransactionController{
+		repository: repo,
+	}
+}
+
+// List handles GET /admin/finance/recurring
+// Example response: {"recurring": [{"id": 1, "label": "Arriendo", "type": "expense", "amount": 1200000, "frequency": "monthly", "dayOfMonth": 5, ...}]}
+func (c *RecurringTransactionController) List(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ListRecurringTransactions: Received %s request to %s", r.Method, r.URL.Path)
+
+	ctx := requestContext(r)
+	recurring, err := c.repository.List(ctx)
+	if err != nil {
+		log.Printf("❌ ListRecurringTransactions: Error fetching recurring transactions: %v", err)
+		writeError(w, fmt.Sprintf("Failed to fetch recurring transactions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, models.RecurringTransactionListResponse{Recurring: recurring})
+}
+
+// Create handles POST /admin/finance/recurring
+// Example request: {"label": "Arriendo", "type": "expense", "amount": 1200000, "destination": "Bancolombia", "category": "arriendo", "frequency": "monthly", "dayOfMonth": 5}
+// Weekly example: {"label": "Aseo", "type": "expense", "amount": 80000, "destination": "Caja", "frequency": "weekly", "dayOfWeek": 6}
+func (c *RecurringTransactionController) Create(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 CreateRecurringTransaction: Received %s request to %s", r.Method, r.URL.Path)
+
+	var req models.RecurringTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ CreateRecurringTransaction: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	rec, err := c.repository.Create(ctx, &req)
+	if err != nil {
+		log.Printf("❌ CreateRecurringTransaction: Error creating recurring transaction: %v", err)
+		writeRecurringTransactionError(w, err)
+		return
+	}
+
+	log.Printf("✅ CreateRecurringTransaction: Successfully created recurring transaction id=%d", rec.ID)
+	writeFinanceTemplateJSON(w, http.StatusCreated, rec)
+}
+
+// Get handles GET /admin/finance/recurring/:id
+func (c *RecurringTransactionController) Get(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetRecurringTransaction: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseRecurringTransactionID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := requestContext(r)
+	rec, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("❌ GetRecurringTransaction: Error fetching recurring transaction: %v", err)
+		writeRecurringTransactionError(w, err)
+		return
+	}
+
+	writeFinanceTemplateJSON(w, http.StatusOK, rec)
+}
+
+// Update handles PUT /admin/finance/recurring/:id (replaces all fields, isActive is kept when omitted)
+func (c *RecurringTransactionController) Update(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 UpdateRecurringTransaction: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseRecurringTransactionID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RecurringTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ UpdateRecurringTransaction: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	rec, err := c.repository.Update(ctx, id, &req)
+	if err != nil {
+		log.Printf("❌ UpdateRecurringTransaction: Error updating recurring transaction: %v", err)
+		writeRecurringTransactionError(w, err)
+		return
+	}
+
+	log.Printf("✅ UpdateRecurringTransaction: Successfully updated recurring transaction id=%d", id)
+	writeFinanceTemplateJSON(w, http.StatusOK, rec)
+}
+
+// Delete handles DELETE /admin/finance/recurring/:id
+func (c *RecurringTransactionController) Delete(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 DeleteRecurringTransaction: Received %s request to %s", r.Method, r.URL.Path)
+
+	id, ok := parseRecurringTransactionID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := requestContext(r)
+	if err := c.repository.Delete(ctx, id); err != nil {
+		log.Printf("❌ DeleteRecurringTransaction: Error deleting recurring transaction: %v", err)
+		writeRecurringTransactionError(w, err)
+		return
+	}
+
+	log.Printf("✅ DeleteRecurringTransaction: Successfully deleted recurring transaction id=%d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseRecurringTransactionID extracts the recurrence ID from /admin/finance/recurring/{id}
+func parseRecurringTransactionID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/admin/finance/recurring/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		log.Printf("❌ RecurringTransaction: Invalid recurring transaction id: %s", idStr)
+		writeError(w, "invalid recurring transaction id parameter", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeRecurringTransactionError maps repository errors to HTTP status codes
+func writeRecurringTransactionError(w http.ResponseWriter, err error) {
+	errMsg := err.Error()
+	if strings.Contains(errMsg, "not found") {
+		writeError(w, errMsg, http.StatusNotFound)
+		return
+	}
+	if strings.Contains(errMsg, "required") || strings.Contains(errMsg, "must be") || strings.Contains(errMsg, "invalid") {
+		writeError(w, errMsg, http.StatusBadRequest)
+		return
+	}
+	writeError(w, fmt.Sprintf("Recurring transaction operation failed: %v", err), http.StatusInternalServerError)
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"armario-mascota-me/repository"
+)
+
+// ReportController handles HTTP requests for admin reports
+type ReportController struct {
+	repository repository.ReportRepositoryInterface
+}
+
+// NewReportController creates a new ReportController
+func NewReportController(repo repository.ReportRepositoryInterface) *ReportController {
+	return &ReportController{
+		repository: repo,
+	}
+}
+
+// GroupsCoverage handles GET /admin/reports/groups-coverage
+// Lists every hoodie type present in active items with its resolved pricing group (or ungrouped)
+// and whether the pricebook covers its size buckets
+// Example response: See GroupsCoverageResponse structure
+func (c *ReportController) GroupsCoverage(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GroupsCoverage: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GroupsCoverage: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := requestContext(r)
+	report, err := c.repository.GroupsCoverage(ctx)
+	if err != nil {
+		log.Printf("❌ GroupsCoverage: Error building report: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to build groups coverage report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("❌ GroupsCoverage: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// TimeToSell handles GET /admin/reports/time-to-sell?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Returns, per design, the average days from item creation and from first reservation to sale.
+// Designs without sales in the range are excluded.
+// Example response: See TimeToSellResponse structure
+func (c *ReportController) TimeToSell(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 TimeToSell: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ TimeToSell: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fromStr := r.URL.Query().Get("from")
+	toStr := r.URL.Query().Get("to")
+
+	var from, to *string
+	if fromStr != "" {
+		if _, err := time.Parse("2006-01-02", fromStr); err != nil {
+			log.Printf("❌ TimeToSell: Invalid from date format: %s", fromStr)
+			http.Error(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = &fromStr
+	}
+	if toStr != "" {
+		if _, err := time.Parse("2006-01-02", toStr); err != nil {
+			log.Printf("❌ TimeToSell: Invalid to date format: %s", toStr)
+			http.Error(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = &toStr
+	}
+
+	ctx := requestContext(r)
+	report, err := c.repository.TimeToSell(ctx, from, to)
+	if err != nil {
+		log.Printf("❌ TimeToSell: Error building report: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to build time-to-sell report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("❌ TimeToSell: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// InventoryDashboard handles GET /admin/inventory/dashboard
+// Splits the stock of active items into units reserved by open carts and units available, overall,
+// by size and by pricing group, with the count of items whose stock is fully reserved
+// Example response: See InventoryDashboardResponse structure
+func (c *ReportController) InventoryDashboard(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 InventoryDashboard: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ InventoryDashboard: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := requestContext(r)
+	report, err := c.repository.InventoryDashboard(ctx)
+	if err != nil {
+		log.Printf("❌ InventoryDashboard: Error building dashboard: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to build inventory dashboard: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("❌ InventoryDashboard: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -8,11 +8,15 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"armario-mascota-me/events"
 	"armario-mascota-me/models"
 	"armario-mascota-me/repository"
+	"armario-mascota-me/service"
 	"armario-mascota-me/utils"
 )
 
@@ -34,10 +38,16 @@ func NewReservedOrderController(repo repository.ReservedOrderRepositoryInterface
 // {
 //   "assignedTo": "Erika",
 //   "orderType": "detal",
+//   "priority": "normal",
 //   "customerName": "Juan Pérez",
 //   "customerPhone": "+1234567890",
-//   "notes": "Cliente VIP"
+//   "notes": "Cliente VIP",
+//   "discountType": "percent",
+//   "discountValue": 10,
+//   "couponCode": "VERANO10"
 // }
+// discountType is optional: "none" (default), "flat" (discountValue is an amount) or "percent" (0-99)
+// couponCode is optional: it is resolved against the pricing config coupons, invalid codes are ignored
 // Example response:
 // {
 //   "id": 1,
@@ -47,6 +57,9 @@ func NewReservedOrderController(repo repository.ReservedOrderRepositoryInterface
 //   "customerName": "Juan Pérez",
 //   "customerPhone": "+1234567890",
 //   "notes": "Cliente VIP",
+//   "discountType": "percent",
+//   "discountValue": 10,
+//   "couponCode": "VERANO10",
 //   "createdAt": "2024-01-15T10:30:00Z",
 //   "updatedAt": "2024-01-15T10:30:00Z"
 // }
@@ -55,7 +68,7 @@ func (c *ReservedOrderController) CreateOrder(w http.ResponseWriter, r *http.Req
 
 	if r.Method != http.MethodPost {
 		log.Printf("❌ CreateOrder: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -63,7 +76,7 @@ func (c *ReservedOrderController) CreateOrder(w http.ResponseWriter, r *http.Req
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		log.Printf("❌ CreateOrder: Failed to read request body: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
+		writeError(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
 		return
 	}
 	defer r.Body.Close()
@@ -77,27 +90,31 @@ func (c *ReservedOrderController) CreateOrder(w http.ResponseWriter, r *http.Req
 	var req models.CreateReservedOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("❌ CreateOrder: Failed to decode request body: %v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
 	if strings.TrimSpace(req.AssignedTo) == "" {
 		log.Printf("❌ CreateOrder: assigned_to is required")
-		http.Error(w, "assigned_to is required", http.StatusBadRequest)
+		writeError(w, "assigned_to is required", http.StatusBadRequest)
 		return
 	}
 
 	if strings.TrimSpace(req.OrderType) == "" {
 		log.Printf("❌ CreateOrder: order_type is required")
-		http.Error(w, "order_type is required", http.StatusBadRequest)
+		writeError(w, "order_type is required", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	order, err := c.repository.Create(ctx, &req)
 	if err != nil {
 		log.Printf("❌ CreateOrder: Error creating order: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to create order: %v", err), http.StatusInternalServerError)
+		if strings.Contains(err.Error(), "priority must") || strings.Contains(err.Error(), "discount") {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to create order: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -107,12 +124,13 @@ func (c *ReservedOrderController) CreateOrder(w http.ResponseWriter, r *http.Req
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(order); err != nil {
 		log.Printf("❌ CreateOrder: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
 // AddItem handles POST /admin/reserved-orders/:id/items
+// Non-blocking issues (e.g. the item will be priced with a fallback price) are returned in "warnings"
 // Example request:
 // POST /admin/reserved-orders/1/items
 // {
@@ -128,12 +146,13 @@ func (c *ReservedOrderController) CreateOrder(w http.ResponseWriter, r *http.Req
 //   "unitPrice": 50000,
 //   "createdAt": "2024-01-15T10:30:00Z"
 // }
+// With ?full=1 the response is the complete order (see GetOrder) with the recomputed total and orderType
 func (c *ReservedOrderController) AddItem(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📥 AddItem: Received %s request to %s", r.Method, r.URL.Path)
 
 	if r.Method != http.MethodPost {
 		log.Printf("❌ AddItem: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -141,40 +160,47 @@ func (c *ReservedOrderController) AddItem(w http.ResponseWriter, r *http.Request
 	// Path format: /admin/reserved-orders/{id}/items
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
 	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	// Extract ID (remove /items suffix)
 	idStr := strings.TrimSuffix(path, "/items")
 	if idStr == path {
-		http.Error(w, "invalid path format", http.StatusBadRequest)
+		writeError(w, "invalid path format", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		log.Printf("❌ AddItem: Invalid order id: %s", idStr)
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	full, err := wantsFullOrder(r)
+	if err != nil {
+		log.Printf("❌ AddItem: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	var req models.AddItemToOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("❌ AddItem: Failed to decode request body: %v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
 	if req.ItemID <= 0 {
 		log.Printf("❌ AddItem: Invalid item_id: %d", req.ItemID)
-		http.Error(w, "item_id must be greater than 0", http.StatusBadRequest)
+		writeError(w, "item_id must be greater than 0", http.StatusBadRequest)
 		return
 	}
 
 	if req.Qty <= 0 {
 		log.Printf("❌ AddItem: Invalid qty: %d", req.Qty)
-		http.Error(w, "qty must be greater than 0", http.StatusBadRequest)
+		writeError(w, "qty must be greater than 0", http.StatusBadRequest)
 		return
 	}
 
@@ -185,45 +211,191 @@ func (c *ReservedOrderController) AddItem(w http.ResponseWriter, r *http.Request
 		// Read custom fields and construct custom code
 		if req.PrimaryColor == "" || req.SecondaryColor == "" || req.HoodieType == "" {
 			log.Printf("❌ AddItem: Custom type requires primaryColor, secondaryColor, and hoodieType")
-			http.Error(w, "custom type requires primaryColor, secondaryColor, and hoodieType", http.StatusBadRequest)
+			writeError(w, "custom type requires primaryColor, secondaryColor, and hoodieType", http.StatusBadRequest)
 			return
 		}
 
-		// Map colors and hoodie type to codes using existing utilities
-		primaryColorCode := utils.MapColorToCode(req.PrimaryColor)
-		secondaryColorCode := utils.MapColorToCode(req.SecondaryColor)
-		hoodieTypeCode := utils.MapHoodieTypeToCode(req.HoodieType)
-
 		// Construct custom code: primaryColor_secondaryColor_hoodieType
-		constructedCode := fmt.Sprintf("%s_%s_%s", primaryColorCode, secondaryColorCode, hoodieTypeCode)
+		constructedCode := utils.BuildCustomCode(req.PrimaryColor, req.SecondaryColor, req.HoodieType)
 		customCode = &constructedCode
 		log.Printf("🔧 AddItem: Custom type detected, constructed custom code: %s", constructedCode)
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	line, err := c.repository.AddItem(ctx, orderID, req.ItemID, req.Qty, customCode)
 	if err != nil {
 		log.Printf("❌ AddItem: Error adding item: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "insufficient stock") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
 		if strings.Contains(errMsg, "not found") || strings.Contains(errMsg, "not in reserved status") {
-			http.Error(w, errMsg, http.StatusNotFound)
+			writeError(w, errMsg, http.StatusNotFound)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to add item: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to add item: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	log.Printf("✅ AddItem: Successfully added item to order: line_id=%d", line.ID)
 
+	if full {
+		c.writeFullOrder(w, ctx, orderID, "AddItem")
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(line); err != nil {
 		log.Printf("❌ AddItem: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// decorateOrderLines builds the image endpoints and readable labels of each order line.
+// Lines with a customCode show the custom colors/hoodie type instead of the item's own
+func decorateOrderLines(order *models.ReservedOrderResponse) {
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		item := &line.Item
+		designAssetID := item.DesignAssetID
+
+		// Build image endpoints
+		item.ImageUrlThumb = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=thumb", designAssetID)
+		item.ImageUrlMedium = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=medium", designAssetID)
+
+		// If customCode is present, parse it and override item fields
+		// Format: primaryColor_secondaryColor_hoodieType (e.g., "CSM_NG_BE")
+		if line.CustomCode != nil && *line.CustomCode != "" {
+			customCodeParts := strings.Split(*line.CustomCode, "_")
+			if len(customCodeParts) == 3 {
+				primaryColorCode := customCodeParts[0]
+				secondaryColorCode := customCodeParts[1]
+				hoodieTypeCode := customCodeParts[2]
+
+				// Override item fields with custom code values
+				item.ColorPrimary = primaryColorCode
+				item.ColorSecondary = secondaryColorCode
+				item.HoodieType = hoodieTypeCode
+
+				log.Printf("🔧 decorateOrderLines: Mapped customCode=%s to colorPrimary=%s, colorSecondary=%s, hoodieType=%s",
+					*line.CustomCode, primaryColorCode, secondaryColorCode, hoodieTypeCode)
+			} else {
+				log.Printf("⚠️ decorateOrderLines: Invalid customCode format: %s (expected format: primaryColor_secondaryColor_hoodieType)", *line.CustomCode)
+			}
+		}
+
+		// Apply mappings for readable labels (will use custom values if customCode was present)
+		item.ColorPrimaryLabel = utils.MapCodeToColor(item.ColorPrimary)
+		item.ColorSecondaryLabel = utils.MapCodeToColor(item.ColorSecondary)
+		item.HoodieTypeLabel = utils.MapCodeToHoodieType(item.HoodieType)
+		item.ImageTypeLabel = utils.MapCodeToImageType(item.ImageType)
+		item.DecoBaseLabel = utils.MapCodeToDecoBase(item.DecoBase)
+	}
+}
+
+// wantsFullOrder reports whether the request asked (?full=1) for the complete order in the response
+func wantsFullOrder(r *http.Request) (bool, error) {
+	value := strings.TrimSpace(r.URL.Query().Get("full"))
+	if value == "" {
+		return false, nil
+	}
+	full, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("full must be '1', 'true', '0' or 'false'")
+	}
+	return full, nil
+}
+
+// writeFullOrder re-reads the order after a mutation and writes it as a ReservedOrderResponse,
+// so the caller gets the recomputed total and orderType without a second request
+func (c *ReservedOrderController) writeFullOrder(w http.ResponseWriter, ctx context.Context, orderID int64, action string) {
+	order, err := c.repository.GetByID(ctx, orderID)
+	if err != nil {
+		log.Printf("❌ %s: Error fetching updated order: %v", action, err)
+		writeError(w, fmt.Sprintf("Failed to fetch updated order: %v", err), http.StatusInternalServerError)
+		return
+	}
+	decorateOrderLines(order)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		log.Printf("❌ %s: Error encoding response: %v", action, err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// BulkAddItems handles POST /admin/reserved-orders/:id/items/bulk
+// Adds several items in one transaction. The body is a JSON array of AddItemToOrderRequest.
+// All-or-nothing: if any item fails, nothing is added and the response (400) lists each failing item.
+// Example request:
+// [{"itemId": 123, "qty": 2}, {"itemId": 124, "qty": 1, "type": "custom", "primaryColor": "negro", "secondaryColor": "rojo", "hoodieType": "buso estándar"}]
+// Example response: See BulkAddItemsResponse structure
+func (c *ReservedOrderController) BulkAddItems(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 BulkAddItems: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ BulkAddItems: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/items/bulk
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/items/bulk")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ BulkAddItems: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var items []models.AddItemToOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		log.Printf("❌ BulkAddItems: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.BulkAddItems(ctx, orderID, items)
+	if err != nil {
+		log.Printf("❌ BulkAddItems: Error adding items: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "must not be empty") || strings.Contains(errMsg, "too many items") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(errMsg, "not found") || strings.Contains(errMsg, "not in reserved status") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to add items: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if len(response.Errors) > 0 {
+		log.Printf("❌ BulkAddItems: %d items could not be added, batch rolled back", len(response.Errors))
+		status = http.StatusBadRequest
+	} else {
+		log.Printf("✅ BulkAddItems: Successfully added %d lines to order id=%d", len(response.Lines), orderID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ BulkAddItems: Error encoding response: %v", err)
 		return
 	}
 }
@@ -236,12 +408,13 @@ func (c *ReservedOrderController) AddItem(w http.ResponseWriter, r *http.Request
 // {
 //   "message": "Item removed successfully"
 // }
+// With ?full=1 the response is the complete order (see GetOrder) with the recomputed total and orderType
 func (c *ReservedOrderController) RemoveItem(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📥 RemoveItem: Received %s request to %s", r.Method, r.URL.Path)
 
 	if r.Method != http.MethodDelete {
 		log.Printf("❌ RemoveItem: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -249,7 +422,7 @@ func (c *ReservedOrderController) RemoveItem(w http.ResponseWriter, r *http.Requ
 	// Path format: /admin/reserved-orders/{orderId}/items/{itemId}
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
 	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
 		return
 	}
 
@@ -257,49 +430,61 @@ func (c *ReservedOrderController) RemoveItem(w http.ResponseWriter, r *http.Requ
 	// Expected format: {orderId}/items/{itemId}
 	parts := strings.Split(path, "/")
 	if len(parts) != 3 || parts[1] != "items" {
-		http.Error(w, "invalid path format. Expected: /admin/reserved-orders/{orderId}/items/{itemId}", http.StatusBadRequest)
+		writeError(w, "invalid path format. Expected: /admin/reserved-orders/{orderId}/items/{itemId}", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
 		log.Printf("❌ RemoveItem: Invalid order id: %s", parts[0])
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
 		return
 	}
 
 	itemID, err := strconv.ParseInt(parts[2], 10, 64)
 	if err != nil {
 		log.Printf("❌ RemoveItem: Invalid item id: %s", parts[2])
-		http.Error(w, "invalid item id parameter", http.StatusBadRequest)
+		writeError(w, "invalid item id parameter", http.StatusBadRequest)
+		return
+	}
+
+	full, err := wantsFullOrder(r)
+	if err != nil {
+		log.Printf("❌ RemoveItem: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	err = c.repository.RemoveItem(ctx, orderID, itemID)
 	if err != nil {
 		log.Printf("❌ RemoveItem: Error removing item: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "not found") {
-			http.Error(w, errMsg, http.StatusNotFound)
+			writeError(w, errMsg, http.StatusNotFound)
 			return
 		}
 		if strings.Contains(errMsg, "not in reserved status") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to remove item: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to remove item: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	log.Printf("✅ RemoveItem: Successfully removed item_id=%d from order_id=%d", itemID, orderID)
 
+	if full {
+		c.writeFullOrder(w, ctx, orderID, "RemoveItem")
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	response := map[string]string{"message": "Item removed successfully"}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("❌ RemoveItem: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
@@ -307,6 +492,8 @@ func (c *ReservedOrderController) RemoveItem(w http.ResponseWriter, r *http.Requ
 // UpdateOrder handles PUT /admin/reserved-orders/:id
 // Updates a reserved order with its lines
 // If qty = 0 in a line, that line will be deleted and stock will be released
+// Send "expectedUpdatedAt" (the updatedAt last read) to get 409 "order changed" instead of overwriting
+// a concurrent edit
 // Example request:
 // PUT /admin/reserved-orders/1
 // {
@@ -360,54 +547,54 @@ func (c *ReservedOrderController) UpdateOrder(w http.ResponseWriter, r *http.Req
 
 	if r.Method != http.MethodPut {
 		log.Printf("❌ UpdateOrder: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	// Extract order ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
 	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	// Check if path contains sub-paths (like /items, /cancel, /complete)
 	if strings.Contains(path, "/") {
-		http.Error(w, "invalid path format", http.StatusBadRequest)
+		writeError(w, "invalid path format", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(path, 10, 64)
 	if err != nil {
 		log.Printf("❌ UpdateOrder: Invalid order id: %s", path)
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
 		return
 	}
 
 	var req models.UpdateReservedOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("❌ UpdateOrder: Failed to decode request body: %v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
 	// Validate that the order ID in the body matches the URL
 	if req.ID != orderID {
 		log.Printf("❌ UpdateOrder: Order ID mismatch: URL=%d, body=%d", orderID, req.ID)
-		http.Error(w, "order id in URL does not match order id in body", http.StatusBadRequest)
+		writeError(w, "order id in URL does not match order id in body", http.StatusBadRequest)
 		return
 	}
 
 	// Validate required fields
 	if strings.TrimSpace(req.AssignedTo) == "" {
 		log.Printf("❌ UpdateOrder: assignedTo is required")
-		http.Error(w, "assignedTo is required", http.StatusBadRequest)
+		writeError(w, "assignedTo is required", http.StatusBadRequest)
 		return
 	}
 
 	if strings.TrimSpace(req.OrderType) == "" {
 		log.Printf("❌ UpdateOrder: orderType is required")
-		http.Error(w, "orderType is required", http.StatusBadRequest)
+		writeError(w, "orderType is required", http.StatusBadRequest)
 		return
 	}
 
@@ -415,34 +602,39 @@ func (c *ReservedOrderController) UpdateOrder(w http.ResponseWriter, r *http.Req
 	for i, line := range req.Lines {
 		if line.Qty < 0 {
 			log.Printf("❌ UpdateOrder: Line %d has invalid qty: %d (qty must be >= 0)", i, line.Qty)
-			http.Error(w, fmt.Sprintf("line %d: qty must be >= 0 (0 to delete, >0 to update/add)", i), http.StatusBadRequest)
+			writeError(w, fmt.Sprintf("line %d: qty must be >= 0 (0 to delete, >0 to update/add)", i), http.StatusBadRequest)
 			return
 		}
 		if line.ReservedOrderID != orderID {
 			log.Printf("❌ UpdateOrder: Line %d reservedOrderId mismatch: %d != %d", i, line.ReservedOrderID, orderID)
-			http.Error(w, fmt.Sprintf("line %d: reservedOrderId must match order id", i), http.StatusBadRequest)
+			writeError(w, fmt.Sprintf("line %d: reservedOrderId must match order id", i), http.StatusBadRequest)
 			return
 		}
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	order, err := c.repository.UpdateOrder(ctx, &req)
 	if err != nil {
 		log.Printf("❌ UpdateOrder: Error updating order: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "not found") {
-			http.Error(w, errMsg, http.StatusNotFound)
+			writeError(w, errMsg, http.StatusNotFound)
 			return
 		}
-		if strings.Contains(errMsg, "not in reserved status") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+		if strings.Contains(errMsg, "order changed") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		if strings.Contains(errMsg, "not in reserved status") || strings.Contains(errMsg, "priority must") ||
+			strings.Contains(errMsg, "discount") || strings.Contains(errMsg, "expectedUpdatedAt") {
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
 		if strings.Contains(errMsg, "insufficient stock") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to update order: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to update order: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -452,7 +644,82 @@ func (c *ReservedOrderController) UpdateOrder(w http.ResponseWriter, r *http.Req
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(order); err != nil {
 		log.Printf("❌ UpdateOrder: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// PatchOrder handles PATCH /admin/reserved-orders/:id
+// Updates only the provided fields (assignedTo, customerName, customerPhone, notes, orderType) and never
+// touches lines, so a metadata edit cannot drop lines like a PUT without them would.
+// expectedUpdatedAt works as in PUT (409 "order changed")
+// Example request:
+// PATCH /admin/reserved-orders/1
+// { "customerPhone": "3152956953" }
+// Example response: See ReservedOrderResponse structure
+func (c *ReservedOrderController) PatchOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 PatchOrder: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPatch {
+		log.Printf("❌ PatchOrder: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	if path == "" {
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
+		return
+	}
+	if strings.Contains(path, "/") {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(path, 10, 64)
+	if err != nil {
+		log.Printf("❌ PatchOrder: Invalid order id: %s", path)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PatchReservedOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ PatchOrder: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	order, err := c.repository.PatchOrder(ctx, orderID, &req)
+	if err != nil {
+		log.Printf("❌ PatchOrder: Error patching order: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "order changed") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		if strings.Contains(errMsg, "not in reserved status") || strings.Contains(errMsg, "required") ||
+			strings.Contains(errMsg, "cannot be empty") || strings.Contains(errMsg, "expectedUpdatedAt") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to update order: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ PatchOrder: Successfully patched order_id=%d", orderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		log.Printf("❌ PatchOrder: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
@@ -483,7 +750,7 @@ func (c *ReservedOrderController) UpdateItemQuantity(w http.ResponseWriter, r *h
 
 	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
 		log.Printf("❌ UpdateItemQuantity: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -491,7 +758,7 @@ func (c *ReservedOrderController) UpdateItemQuantity(w http.ResponseWriter, r *h
 	// Path format: /admin/reserved-orders/{orderId}/items/{itemId}
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
 	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
 		return
 	}
 
@@ -499,53 +766,53 @@ func (c *ReservedOrderController) UpdateItemQuantity(w http.ResponseWriter, r *h
 	// Expected format: {orderId}/items/{itemId}
 	parts := strings.Split(path, "/")
 	if len(parts) != 3 || parts[1] != "items" {
-		http.Error(w, "invalid path format. Expected: /admin/reserved-orders/{orderId}/items/{itemId}", http.StatusBadRequest)
+		writeError(w, "invalid path format. Expected: /admin/reserved-orders/{orderId}/items/{itemId}", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
 		log.Printf("❌ UpdateItemQuantity: Invalid order id: %s", parts[0])
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
 		return
 	}
 
 	itemID, err := strconv.ParseInt(parts[2], 10, 64)
 	if err != nil {
 		log.Printf("❌ UpdateItemQuantity: Invalid item id: %s", parts[2])
-		http.Error(w, "invalid item id parameter", http.StatusBadRequest)
+		writeError(w, "invalid item id parameter", http.StatusBadRequest)
 		return
 	}
 
 	var req models.UpdateItemQuantityRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("❌ UpdateItemQuantity: Failed to decode request body: %v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
 	if req.Qty < 0 {
 		log.Printf("❌ UpdateItemQuantity: Invalid qty: %d", req.Qty)
-		http.Error(w, "qty must be >= 0 (0 to delete, >0 to update)", http.StatusBadRequest)
+		writeError(w, "qty must be >= 0 (0 to delete, >0 to update)", http.StatusBadRequest)
 		return
 	}
 
 	// If qty is 0, treat as deletion
 	if req.Qty == 0 {
-		ctx := context.Background()
+		ctx := requestContext(r)
 		err = c.repository.RemoveItem(ctx, orderID, itemID)
 		if err != nil {
 			log.Printf("❌ UpdateItemQuantity: Error removing item: %v", err)
 			errMsg := err.Error()
 			if strings.Contains(errMsg, "not found") {
-				http.Error(w, errMsg, http.StatusNotFound)
+				writeError(w, errMsg, http.StatusNotFound)
 				return
 			}
 			if strings.Contains(errMsg, "not in reserved status") {
-				http.Error(w, errMsg, http.StatusBadRequest)
+				writeError(w, errMsg, http.StatusBadRequest)
 				return
 			}
-			http.Error(w, fmt.Sprintf("Failed to remove item: %v", err), http.StatusInternalServerError)
+			writeError(w, fmt.Sprintf("Failed to remove item: %v", err), http.StatusInternalServerError)
 			return
 		}
 
@@ -556,30 +823,30 @@ func (c *ReservedOrderController) UpdateItemQuantity(w http.ResponseWriter, r *h
 		response := map[string]string{"message": "Item removed successfully (qty=0)"}
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			log.Printf("❌ UpdateItemQuantity: Error encoding response: %v", err)
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			writeError(w, "Failed to encode response", http.StatusInternalServerError)
 			return
 		}
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	line, err := c.repository.UpdateItemQuantity(ctx, orderID, itemID, req.Qty)
 	if err != nil {
 		log.Printf("❌ UpdateItemQuantity: Error updating item quantity: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "not found") {
-			http.Error(w, errMsg, http.StatusNotFound)
+			writeError(w, errMsg, http.StatusNotFound)
 			return
 		}
 		if strings.Contains(errMsg, "not in reserved status") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
 		if strings.Contains(errMsg, "insufficient stock") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to update item quantity: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to update item quantity: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -589,12 +856,15 @@ func (c *ReservedOrderController) UpdateItemQuantity(w http.ResponseWriter, r *h
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(line); err != nil {
 		log.Printf("❌ UpdateItemQuantity: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
-// GetOrder handles GET /admin/reserved-orders/:id
+// GetOrder handles GET /admin/reserved-orders/:id?breakdown=1
+// Non-blocking issues (e.g. lines priced with a fallback price) are returned in "warnings"
+// breakdown=1 adds the pricing engine breakdown for reserved orders (per line: qtyInBundle, qtyRetail,
+// appliedRules, explanation) so the cart can show which units got a promo price
 // Example response:
 // {
 //   "id": 1,
@@ -646,7 +916,7 @@ func (c *ReservedOrderController) GetOrder(w http.ResponseWriter, r *http.Reques
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ GetOrder: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -654,85 +924,118 @@ func (c *ReservedOrderController) GetOrder(w http.ResponseWriter, r *http.Reques
 	// Path format: /admin/reserved-orders/{id}
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
 	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	// Check if path contains sub-paths (like /items, /cancel, /complete)
 	if strings.Contains(path, "/") {
-		http.Error(w, "invalid path format", http.StatusBadRequest)
+		writeError(w, "invalid path format", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(path, 10, 64)
 	if err != nil {
 		log.Printf("❌ GetOrder: Invalid order id: %s", path)
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-	order, err := c.repository.GetByID(ctx, orderID)
+	includeBreakdown := false
+	if raw := strings.TrimSpace(r.URL.Query().Get("breakdown")); raw != "" {
+		includeBreakdown, err = strconv.ParseBool(raw)
+		if err != nil {
+			log.Printf("❌ GetOrder: Invalid breakdown parameter: %s", raw)
+			writeError(w, "invalid breakdown parameter, expected 1/0 or true/false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := requestContext(r)
+	var order *models.ReservedOrderResponse
+	if includeBreakdown {
+		order, err = c.repository.GetByIDWithBreakdown(ctx, orderID)
+	} else {
+		order, err = c.repository.GetByID(ctx, orderID)
+	}
 	if err != nil {
 		log.Printf("❌ GetOrder: Error fetching order: %v", err)
 		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeError(w, err.Error(), http.StatusNotFound)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to fetch order: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to fetch order: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	// Build image endpoints and apply mappings for readable labels
-	for i := range order.Lines {
-		line := &order.Lines[i]
-		item := &line.Item
-		designAssetID := item.DesignAssetID
-		
-		// Build image endpoints
-		item.ImageUrlThumb = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=thumb", designAssetID)
-		item.ImageUrlMedium = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=medium", designAssetID)
-		
-		// If customCode is present, parse it and override item fields
-		// Format: primaryColor_secondaryColor_hoodieType (e.g., "CSM_NG_BE")
-		if line.CustomCode != nil && *line.CustomCode != "" {
-			customCodeParts := strings.Split(*line.CustomCode, "_")
-			if len(customCodeParts) == 3 {
-				primaryColorCode := customCodeParts[0]
-				secondaryColorCode := customCodeParts[1]
-				hoodieTypeCode := customCodeParts[2]
-				
-				// Override item fields with custom code values
-				item.ColorPrimary = primaryColorCode
-				item.ColorSecondary = secondaryColorCode
-				item.HoodieType = hoodieTypeCode
-				
-				log.Printf("🔧 GetOrder: Mapped customCode=%s to colorPrimary=%s, colorSecondary=%s, hoodieType=%s", 
-					*line.CustomCode, primaryColorCode, secondaryColorCode, hoodieTypeCode)
-			} else {
-				log.Printf("⚠️ GetOrder: Invalid customCode format: %s (expected format: primaryColor_secondaryColor_hoodieType)", *line.CustomCode)
-			}
-		}
-		
-		// Apply mappings for readable labels (will use custom values if customCode was present)
-		item.ColorPrimaryLabel = utils.MapCodeToColor(item.ColorPrimary)
-		item.ColorSecondaryLabel = utils.MapCodeToColor(item.ColorSecondary)
-		item.HoodieTypeLabel = utils.MapCodeToHoodieType(item.HoodieType)
-		item.ImageTypeLabel = utils.MapCodeToImageType(item.ImageType)
-		item.DecoBaseLabel = utils.MapCodeToDecoBase(item.DecoBase)
-	}
+	decorateOrderLines(order)
 
 	log.Printf("✅ GetOrder: Successfully fetched order id=%d", orderID)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(order); err != nil {
 		log.Printf("❌ GetOrder: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetOrderSummaryText handles GET /admin/reserved-orders/:id/summary.txt
+// Returns a plain-text summary of the order for sellers to paste into WhatsApp
+// Example response:
+// Pedido #12
+// Cliente: María Pérez
+//
+// 2x Buso tipo esqueleto negro talla M — $24.000
+// 1x Camiseta azul cielo con negro talla S — $15.000
+//
+// Total: $39.000
+// Tipo de pedido: detal
+func (c *ReservedOrderController) GetOrderSummaryText(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetOrderSummaryText: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetOrderSummaryText: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/reserved-orders/{id}/summary.txt
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/"), "/summary.txt")
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ GetOrderSummaryText: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	order, err := c.repository.GetByID(ctx, orderID)
+	if err != nil {
+		log.Printf("❌ GetOrderSummaryText: Error fetching order: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to fetch order: %v", err), http.StatusInternalServerError)
 		return
 	}
+
+	decorateOrderLines(order)
+	summary := service.BuildOrderSummaryText(order)
+
+	log.Printf("✅ GetOrderSummaryText: Built summary for order id=%d (%d lines)", orderID, len(order.Lines))
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(w, summary); err != nil {
+		log.Printf("❌ GetOrderSummaryText: Error writing response: %v", err)
+	}
 }
 
-// ListOrders handles GET /admin/reserved-orders?status=reserved
+// ListOrders handles GET /admin/reserved-orders?status=reserved&q=juan&assignedTo=Erika&limit=50&cursor=...
+// q searches customer name and phone (case-insensitive); assignedTo filters by assignee
+// limit defaults to 50 (max 200); pass pagination.nextCursor as cursor to fetch the next page
 // Example response:
 // {
 //   "orders": [
@@ -746,45 +1049,75 @@ func (c *ReservedOrderController) GetOrder(w http.ResponseWriter, r *http.Reques
 //       "lineCount": 2,
 //       "total": 100000
 //     }
-//   ]
+//   ],
+//   "totalCount": 1,
+//   "pagination": { "limit": 50 }
 // }
 func (c *ReservedOrderController) ListOrders(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📥 ListOrders: Received %s request to %s", r.Method, r.URL.Path)
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ ListOrders: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	req := &models.ReservedOrderListRequest{}
+
 	// Parse status query parameter
 	status := r.URL.Query().Get("status")
-	var statusPtr *string
 	if status != "" {
 		// Normalize status to lowercase for consistency
 		status = strings.ToLower(strings.TrimSpace(status))
-		statusPtr = &status
+		req.Status = &status
 		log.Printf("🔍 ListOrders: Filtering by status=%s", status)
 	}
 
-	ctx := context.Background()
-	orders, err := c.repository.List(ctx, statusPtr)
+	if q := strings.TrimSpace(r.URL.Query().Get("q")); q != "" {
+		req.Q = &q
+		log.Printf("🔍 ListOrders: Searching customer name/phone for q=%s", q)
+	}
+
+	if assignedTo := strings.TrimSpace(r.URL.Query().Get("assignedTo")); assignedTo != "" {
+		req.AssignedTo = &assignedTo
+		log.Printf("🔍 ListOrders: Filtering by assignedTo=%s", assignedTo)
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			log.Printf("❌ ListOrders: Invalid limit: %s", limitStr)
+			writeError(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if limit > 200 {
+			limit = 200
+		}
+		req.Limit = limit
+	}
+
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		req.Cursor = &cursorStr
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.List(ctx, req)
 	if err != nil {
 		log.Printf("❌ ListOrders: Error fetching orders: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to fetch orders: %v", err), http.StatusInternalServerError)
+		if strings.Contains(err.Error(), "invalid cursor") {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to fetch orders: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("✅ ListOrders: Successfully fetched %d orders", len(orders))
-
-	response := models.ReservedOrderListResponse{
-		Orders: orders,
-	}
+	log.Printf("✅ ListOrders: Successfully fetched %d of %d orders", len(response.Orders), response.TotalCount)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("❌ ListOrders: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
@@ -803,7 +1136,7 @@ func (c *ReservedOrderController) CancelOrder(w http.ResponseWriter, r *http.Req
 
 	if r.Method != http.MethodPost {
 		log.Printf("❌ CancelOrder: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -811,34 +1144,34 @@ func (c *ReservedOrderController) CancelOrder(w http.ResponseWriter, r *http.Req
 	// Path format: /admin/reserved-orders/{id}/cancel
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
 	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	// Extract ID (remove /cancel suffix)
 	idStr := strings.TrimSuffix(path, "/cancel")
 	if idStr == path {
-		http.Error(w, "invalid path format", http.StatusBadRequest)
+		writeError(w, "invalid path format", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		log.Printf("❌ CancelOrder: Invalid order id: %s", idStr)
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	order, err := c.repository.Cancel(ctx, orderID)
 	if err != nil {
 		log.Printf("❌ CancelOrder: Error canceling order: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "not found") || strings.Contains(errMsg, "not in reserved status") {
-			http.Error(w, errMsg, http.StatusNotFound)
+			writeError(w, errMsg, http.StatusNotFound)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to cancel order: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to cancel order: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -848,65 +1181,223 @@ func (c *ReservedOrderController) CancelOrder(w http.ResponseWriter, r *http.Req
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(order); err != nil {
 		log.Printf("❌ CancelOrder: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
-// CompleteOrder handles POST /admin/reserved-orders/:id/complete
+// ReopenOrder handles POST /admin/reserved-orders/:id/reopen
+// Moves a canceled order back to "reserved", re-reserving stock for every line.
+// All-or-nothing: if any line cannot be re-reserved the order stays canceled and the error names the item
 // Example response:
 // {
 //   "id": 1,
-//   "status": "completed",
+//   "status": "reserved",
 //   "assignedTo": "Erika",
 //   "createdAt": "2024-01-15T10:30:00Z",
-//   "updatedAt": "2024-01-15T11:00:00Z"
+//   "updatedAt": "2024-01-16T09:00:00Z"
 // }
-func (c *ReservedOrderController) CompleteOrder(w http.ResponseWriter, r *http.Request) {
-	log.Printf("📥 CompleteOrder: Received %s request to %s", r.Method, r.URL.Path)
+// Example error (400):
+// { "error": { "code": "insufficient_stock", "message": "insufficient stock for item 123: available 1, requested 2" } }
+func (c *ReservedOrderController) ReopenOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ReopenOrder: Received %s request to %s", r.Method, r.URL.Path)
 
 	if r.Method != http.MethodPost {
-		log.Printf("❌ CompleteOrder: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		log.Printf("❌ ReopenOrder: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	// Extract order ID from URL path
-	// Path format: /admin/reserved-orders/{id}/complete
+	// Path format: /admin/reserved-orders/{id}/reopen
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
-	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
-		return
-	}
-
-	// Extract ID (remove /complete suffix)
-	idStr := strings.TrimSuffix(path, "/complete")
-	if idStr == path {
-		http.Error(w, "invalid path format", http.StatusBadRequest)
+	idStr := strings.TrimSuffix(path, "/reopen")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		log.Printf("❌ CompleteOrder: Invalid order id: %s", idStr)
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		log.Printf("❌ ReopenOrder: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-	order, err := c.repository.Complete(ctx, orderID)
+	ctx := requestContext(r)
+	order, err := c.repository.Reopen(ctx, orderID)
 	if err != nil {
-		log.Printf("❌ CompleteOrder: Error completing order: %v", err)
+		log.Printf("❌ ReopenOrder: Error reopening order: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "order not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "not in canceled status") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		if strings.Contains(errMsg, "insufficient stock") || strings.Contains(errMsg, "inactive") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to reopen order: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ReopenOrder: Successfully reopened order id=%d", orderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		log.Printf("❌ ReopenOrder: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ExtendHold handles POST /admin/reserved-orders/:id/extend-hold
+// Pushes forward the time at which the order's stock hold expires and the order is auto-canceled
+// Example request: {"hours": 48} or {"holdUntil": "2026-01-10T18:00:00-05:00"}
+// Example response:
+// {
+//   "orderId": 1,
+//   "previousHoldUntil": "2026-01-05T10:30:00Z",
+//   "holdUntil": "2026-01-07T10:30:00Z"
+// }
+func (c *ReservedOrderController) ExtendHold(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ExtendHold: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ ExtendHold: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/extend-hold
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/extend-hold")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ ExtendHold: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ExtendHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ ExtendHold: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := requestContext(r)
+	hold, err := c.repository.ExtendHold(ctx, orderID, &req)
+	if err != nil {
+		log.Printf("❌ ExtendHold: Error extending hold: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "order not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "not in reserved status") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		if strings.Contains(errMsg, "required") ||
+			strings.Contains(errMsg, "must") ||
+			strings.Contains(errMsg, "invalid") ||
+			strings.Contains(errMsg, "cannot") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to extend hold: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ExtendHold: Order id=%d held until %s", orderID, hold.HoldUntil)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(hold); err != nil {
+		log.Printf("❌ ExtendHold: Error encoding response: %v", err)
+	}
+}
+
+// CompleteOrder handles POST /admin/reserved-orders/:id/complete
+// Deducts stock and marks the order completed without recording a sale; use /sell to record money.
+// Optional query param: intent=sell makes the request fail with 409 instead of completing.
+// Example response:
+// {
+//   "id": 1,
+//   "status": "completed",
+//   "assignedTo": "Erika",
+//   "createdAt": "2024-01-15T10:30:00Z",
+//   "updatedAt": "2024-01-15T11:00:00Z"
+// }
+func (c *ReservedOrderController) CompleteOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 CompleteOrder: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ CompleteOrder: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/complete
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	if path == "" {
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	// Extract ID (remove /complete suffix)
+	idStr := strings.TrimSuffix(path, "/complete")
+	if idStr == path {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ CompleteOrder: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	// Complete never records money; callers that intend to sell must use the sell endpoint
+	if strings.EqualFold(strings.TrimSpace(r.URL.Query().Get("intent")), "sell") {
+		log.Printf("❌ CompleteOrder: Rejected complete with intent=sell for order id=%d", orderID)
+		writeError(w, fmt.Sprintf("complete does not record a sale; use POST /admin/reserved-orders/%d/sell instead", orderID), http.StatusConflict)
+		return
+	}
+
+	ctx := requestContext(r)
+	order, err := c.repository.Complete(ctx, orderID)
+	if err != nil {
+		log.Printf("❌ CompleteOrder: Error completing order: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "not found") || strings.Contains(errMsg, "not in reserved status") {
-			http.Error(w, errMsg, http.StatusNotFound)
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "already has a sale") {
+			writeError(w, errMsg, http.StatusConflict)
 			return
 		}
 		if strings.Contains(errMsg, "insufficient reserved stock") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to complete order: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to complete order: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -916,7 +1407,7 @@ func (c *ReservedOrderController) CompleteOrder(w http.ResponseWriter, r *http.R
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(order); err != nil {
 		log.Printf("❌ CompleteOrder: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
@@ -979,7 +1470,7 @@ func (c *ReservedOrderController) GetSeparatedCarts(w http.ResponseWriter, r *ht
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ GetSeparatedCarts: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -993,11 +1484,11 @@ func (c *ReservedOrderController) GetSeparatedCarts(w http.ResponseWriter, r *ht
 		log.Printf("🔍 GetSeparatedCarts: Filtering by status=%s", status)
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	carts, err := c.repository.GetAllWithFullItems(ctx, statusPtr)
 	if err != nil {
 		log.Printf("❌ GetSeparatedCarts: Error fetching carts: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to fetch carts: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to fetch carts: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -1051,8 +1542,479 @@ func (c *ReservedOrderController) GetSeparatedCarts(w http.ResponseWriter, r *ht
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("❌ GetSeparatedCarts: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
+
+// ClaimStock handles POST /admin/reserved-orders/:id/claim-stock
+// Moves reserved units from a lower-priority order to this (higher-priority) order.
+// The order losing stock must be confirmed explicitly via confirmFromOrderId.
+// Example request:
+// POST /admin/reserved-orders/3/claim-stock
+// {
+//   "fromOrderId": 7,
+//   "confirmFromOrderId": 7,
+//   "itemId": 123,
+//   "qty": 1,
+//   "reason": "Pedido mayorista pagado"
+// }
+// Example response:
+// {
+//   "id": 1,
+//   "claimantOrderId": 3,
+//   "donorOrderId": 7,
+//   "itemId": 123,
+//   "qty": 1,
+//   "reason": "Pedido mayorista pagado",
+//   "createdAt": "2024-01-15T10:30:00Z"
+// }
+func (c *ReservedOrderController) ClaimStock(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ClaimStock: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ ClaimStock: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/claim-stock
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/claim-stock")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ ClaimStock: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ClaimStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ ClaimStock: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := requestContext(r)
+	claim, err := c.repository.ClaimStock(ctx, orderID, &req)
+	if err != nil {
+		log.Printf("❌ ClaimStock: Error claiming stock: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "required") ||
+			strings.Contains(errMsg, "must") ||
+			strings.Contains(errMsg, "cannot") ||
+			strings.Contains(errMsg, "insufficient") ||
+			strings.Contains(errMsg, "not in reserved status") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to claim stock: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ClaimStock: Successfully claimed stock for order id=%d (claim_id=%d)", orderID, claim.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(claim); err != nil {
+		log.Printf("❌ ClaimStock: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// eventsKeepAliveInterval is how often an idle event stream sends a comment so proxies keep it open
+const eventsKeepAliveInterval = 25 * time.Second
+
+// IssueEventsToken handles POST /admin/reserved-orders/events/token
+// Issues a short-lived token that opens the events stream, since EventSource cannot send the admin
+// key header. Call it with the admin key, then open the returned url before the token expires.
+// Example response: See EventsStreamTokenResponse structure
+func (c *ReservedOrderController) IssueEventsToken(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 IssueEventsToken: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ IssueEventsToken: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, expiresAt := utils.GenerateStreamToken()
+	response := models.EventsStreamTokenResponse{
+		Token:     token,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+		URL:       fmt.Sprintf("%s?%s=%s", utils.StreamTokenPath, utils.StreamTokenParam, url.QueryEscape(token)),
+	}
+
+	log.Printf("✅ IssueEventsToken: Issued events stream token expiring at %s", response.ExpiresAt)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ IssueEventsToken: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// StreamEvents handles GET /admin/reserved-orders/events
+// Server-Sent Events stream with one event per committed reserved order change, so the UI can
+// refresh just the affected cart. Browsers open it with the token from IssueEventsToken:
+// GET /admin/reserved-orders/events?streamToken=...
+// Example event:
+// data: {"orderId": 1, "type": "items_added", "at": "2026-01-04T10:30:00Z"}
+func (c *ReservedOrderController) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 StreamEvents: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ StreamEvents: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("❌ StreamEvents: Streaming not supported")
+		writeError(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	eventsCh, unsubscribe := events.GetHub().Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Printf("✅ StreamEvents: Client disconnected")
+			return
+		case event, ok := <-eventsCh:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("❌ StreamEvents: Error encoding event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+				log.Printf("❌ StreamEvents: Error writing event: %v", err)
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				log.Printf("❌ StreamEvents: Error writing keep-alive: %v", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// MergeOrders handles POST /admin/reserved-orders/:id/merge
+// Moves all lines of the source order into this order and cancels the source. Stock stays reserved.
+// Both orders must be in "reserved" status and assigned (409 otherwise).
+// Example request:
+// POST /admin/reserved-orders/3/merge
+// {
+//   "sourceOrderId": 7
+// }
+// Returns the merged target order (same shape as GET /admin/reserved-orders/:id)
+func (c *ReservedOrderController) MergeOrders(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 MergeOrders: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ MergeOrders: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/merge
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/merge")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.MergeOrdersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ MergeOrders: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := requestContext(r)
+	order, err := c.repository.MergeOrders(ctx, orderID, req.SourceOrderID)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Error merging orders: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "not in reserved status") || strings.Contains(errMsg, "not assigned") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		if strings.Contains(errMsg, "required") || strings.Contains(errMsg, "cannot") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to merge orders: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ MergeOrders: Successfully merged order id=%d into order id=%d", req.SourceOrderID, orderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		log.Printf("❌ MergeOrders: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetPickingList handles GET /admin/reserved-orders/picking-list?assignedTo=Erika&status=reserved
+// Aggregates quantities per item (and custom variant) across matching orders so everything can be
+// pulled in a single warehouse pass. status defaults to "reserved"; assignedTo is optional.
+// Example response: See PickingListResponse structure
+func (c *ReservedOrderController) GetPickingList(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetPickingList: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetPickingList: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+	if status == "" {
+		status = "reserved"
+	}
+	if status != "reserved" && status != "completed" && status != "canceled" {
+		log.Printf("❌ GetPickingList: Invalid status: %s", status)
+		writeError(w, "status must be 'reserved', 'completed' or 'canceled'", http.StatusBadRequest)
+		return
+	}
+
+	var assignedTo *string
+	if assignedToStr := strings.TrimSpace(r.URL.Query().Get("assignedTo")); assignedToStr != "" {
+		assignedTo = &assignedToStr
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.GetPickingList(ctx, assignedTo, status)
+	if err != nil {
+		log.Printf("❌ GetPickingList: Error building picking list: %v", err)
+		writeError(w, fmt.Sprintf("Failed to build picking list: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Build image endpoints and apply mappings for readable labels (same rules as GetSeparatedCarts)
+	for i := range response.Items {
+		entry := &response.Items[i]
+		item := &entry.Item
+
+		item.ImageUrlThumb = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=thumb", item.DesignAssetID)
+		item.ImageUrlMedium = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=medium", item.DesignAssetID)
+
+		// Format: primaryColor_secondaryColor_hoodieType (e.g., "CSM_NG_BE")
+		if entry.CustomCode != nil {
+			customCodeParts := strings.Split(*entry.CustomCode, "_")
+			if len(customCodeParts) == 3 {
+				item.ColorPrimary = customCodeParts[0]
+				item.ColorSecondary = customCodeParts[1]
+				item.HoodieType = customCodeParts[2]
+			} else {
+				log.Printf("⚠️ GetPickingList: Invalid customCode format: %s (expected format: primaryColor_secondaryColor_hoodieType)", *entry.CustomCode)
+			}
+		}
+
+		item.ColorPrimaryLabel = utils.MapCodeToColor(item.ColorPrimary)
+		item.ColorSecondaryLabel = utils.MapCodeToColor(item.ColorSecondary)
+		item.HoodieTypeLabel = utils.MapCodeToHoodieType(item.HoodieType)
+		item.ImageTypeLabel = utils.MapCodeToImageType(item.ImageType)
+		item.DecoBaseLabel = utils.MapCodeToDecoBase(item.DecoBase)
+	}
+
+	log.Printf("✅ GetPickingList: Successfully built picking list with %d items", len(response.Items))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ GetPickingList: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetCompletionImpact handles GET /admin/reserved-orders/:id/completion-impact?threshold=5
+// Read-only preview of which items would go low (stock_total at or below threshold) or hit zero
+// if the order were completed. threshold defaults to 5.
+// Example response: See CompletionImpactResponse structure
+func (c *ReservedOrderController) GetCompletionImpact(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetCompletionImpact: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetCompletionImpact: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/completion-impact
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/completion-impact")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ GetCompletionImpact: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	threshold, err := utils.ParseLowStockThreshold(r.URL.Query().Get("threshold"))
+	if err != nil {
+		log.Printf("❌ GetCompletionImpact: %v", err)
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.GetCompletionImpact(ctx, orderID, threshold)
+	if err != nil {
+		log.Printf("❌ GetCompletionImpact: Error computing impact: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "not in reserved status") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to compute completion impact: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Build image endpoints and apply mappings for readable labels
+	for i := range response.Lines {
+		item := &response.Lines[i].Item
+		item.ImageUrlThumb = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=thumb", item.DesignAssetID)
+		item.ImageUrlMedium = fmt.Sprintf("/admin/design-assets/pending/%d/image?size=medium", item.DesignAssetID)
+		item.ColorPrimaryLabel = utils.MapCodeToColor(item.ColorPrimary)
+		item.ColorSecondaryLabel = utils.MapCodeToColor(item.ColorSecondary)
+		item.HoodieTypeLabel = utils.MapCodeToHoodieType(item.HoodieType)
+		item.ImageTypeLabel = utils.MapCodeToImageType(item.ImageType)
+		item.DecoBaseLabel = utils.MapCodeToDecoBase(item.DecoBase)
+	}
+
+	log.Printf("✅ GetCompletionImpact: order id=%d, %d items", orderID, len(response.Lines))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ GetCompletionImpact: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetSummaryBySeller handles GET /admin/reserved-orders/summary-by-seller
+// Returns each seller's count of reserved orders and their combined total, so dashboards do not
+// need every cart line (see GetSeparatedCarts) just to add them up.
+// Example response: See SellerCartSummaryResponse structure
+func (c *ReservedOrderController) GetSummaryBySeller(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetSummaryBySeller: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetSummaryBySeller: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.SummaryBySeller(ctx)
+	if err != nil {
+		log.Printf("❌ GetSummaryBySeller: Error summarizing orders: %v", err)
+		writeError(w, fmt.Sprintf("Failed to summarize orders by seller: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ GetSummaryBySeller: %d orders across %d sellers", response.OrderCount, len(response.Sellers))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ GetSummaryBySeller: Error encoding response: %v", err)
+	}
+}
+
+// RecalculateOrderTypes handles POST /admin/reserved-orders/recalculate-types
+// Prices every reserved order with the current pricing config and stores the resulting order_type
+// where it changed. Run it after a pricing config change so list views are accurate.
+// Example response: See RecalculateOrderTypesResponse structure
+func (c *ReservedOrderController) RecalculateOrderTypes(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 RecalculateOrderTypes: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ RecalculateOrderTypes: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.RecalculateOrderTypes(ctx)
+	if err != nil {
+		log.Printf("❌ RecalculateOrderTypes: Error recalculating order types: %v", err)
+		if strings.Contains(err.Error(), "pricing engine not initialized") {
+			writeError(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to recalculate order types: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ RecalculateOrderTypes: %d of %d orders changed", response.Changed, response.Checked)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ RecalculateOrderTypes: Error encoding response: %v", err)
+	}
+}
@@ -11,24 +11,38 @@ import (
 	"time"
 
 	"armario-mascota-me/models"
+	"armario-mascota-me/pricing"
 	"armario-mascota-me/repository"
+	"armario-mascota-me/service"
+	"armario-mascota-me/utils"
 )
 
 // SaleController handles HTTP requests for sales
 type SaleController struct {
-	repository repository.SaleRepositoryInterface
+	repository     repository.SaleRepositoryInterface
+	webhookService *service.SaleWebhookService
+	invoiceService *service.InvoiceService
 }
 
 // NewSaleController creates a new SaleController
-func NewSaleController(repo repository.SaleRepositoryInterface) *SaleController {
+func NewSaleController(repo repository.SaleRepositoryInterface, webhookService *service.SaleWebhookService) *SaleController {
 	return &SaleController{
-		repository: repo,
+		repository:     repo,
+		webhookService: webhookService,
+		invoiceService: service.NewInvoiceService(),
 	}
 }
 
 // Sell handles POST /admin/reserved-orders/:id/sell
+// Non-blocking issues (e.g. pricing engine not initialized) are returned in "warnings" with HTTP 200
+// With "saleType": "gift" (and a required "reason") stock is deducted but no income is recorded
+// An optional Idempotency-Key header makes the request safely retryable: replaying the same key
+// returns the original sale with 200 and "idempotentReplay": true. Reusing a key for another order is a 409.
+// Split payments ("payments": [{"method", "destination", "amount"}, ...]) record one income per split and
+// must sum to the calculated total; the sale keeps the largest split as its paymentMethod/paymentDestination
 // Example request:
 // POST /admin/reserved-orders/3/sell
+// Idempotency-Key: 7f3c2a9e-sell-3
 // {
 //   "amountPaid": 100000,
 //   "paymentMethod": "transfer",
@@ -49,11 +63,12 @@ func NewSaleController(repo repository.SaleRepositoryInterface) *SaleController
 //   "createdAt": "2026-01-04T10:30:00Z"
 // }
 func (c *SaleController) Sell(w http.ResponseWriter, r *http.Request) {
-	log.Printf("📥 Sell: Received %s request to %s", r.Method, r.URL.Path)
+	ctx := requestContext(r)
+	utils.Logf(ctx, "📥 Sell: Received %s request to %s", r.Method, r.URL.Path)
 
 	if r.Method != http.MethodPost {
-		log.Printf("❌ Sell: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		utils.Logf(ctx, "❌ Sell: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -61,82 +76,363 @@ func (c *SaleController) Sell(w http.ResponseWriter, r *http.Request) {
 	// Path format: /admin/reserved-orders/{id}/sell
 	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
 	if path == "" {
-		http.Error(w, "order id parameter is required", http.StatusBadRequest)
+		writeError(w, "order id parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	// Extract ID (remove /sell suffix)
 	idStr := strings.TrimSuffix(path, "/sell")
 	if idStr == path {
-		http.Error(w, "invalid path format", http.StatusBadRequest)
+		writeError(w, "invalid path format", http.StatusBadRequest)
 		return
 	}
 
 	orderID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		log.Printf("❌ Sell: Invalid order id: %s", idStr)
-		http.Error(w, "invalid order id parameter", http.StatusBadRequest)
+		utils.Logf(ctx, "❌ Sell: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
 		return
 	}
 
 	var req models.SellRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("❌ Sell: Failed to decode request body: %v", err)
-		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		utils.Logf(ctx, "❌ Sell: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Validate required fields
-	if req.AmountPaid <= 0 {
-		log.Printf("❌ Sell: amountPaid must be greater than 0: %d", req.AmountPaid)
-		http.Error(w, "amountPaid must be greater than 0", http.StatusBadRequest)
+	req.IdempotencyKey = strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+	if len(req.IdempotencyKey) > 255 {
+		utils.Logf(ctx, "❌ Sell: Idempotency-Key too long: %d chars", len(req.IdempotencyKey))
+		writeError(w, "Idempotency-Key must be at most 255 characters", http.StatusBadRequest)
 		return
 	}
 
-	if strings.TrimSpace(req.PaymentMethod) == "" {
-		log.Printf("❌ Sell: paymentMethod is required")
-		http.Error(w, "paymentMethod is required", http.StatusBadRequest)
-		return
-	}
+	// Validate required fields
+	// Gifts/samples record no money, so they only need a reason
+	isGift := strings.EqualFold(strings.TrimSpace(req.SaleType), "gift")
+	if isGift {
+		if strings.TrimSpace(req.Reason) == "" {
+			utils.Logf(ctx, "❌ Sell: reason is required for gift sales")
+			writeError(w, "reason is required for gift sales", http.StatusBadRequest)
+			return
+		}
+	} else if len(req.Payments) > 0 {
+		// Split payment: the single-payment fields are ignored, amountPaid defaults to the sum of the splits
+		var sum int64
+		for i, payment := range req.Payments {
+			if strings.TrimSpace(payment.Method) == "" || strings.TrimSpace(payment.Destination) == "" || payment.Amount <= 0 {
+				utils.Logf(ctx, "❌ Sell: Invalid payment split %d: %+v", i, payment)
+				writeError(w, fmt.Sprintf("payments[%d] requires method, destination and an amount greater than 0", i), http.StatusBadRequest)
+				return
+			}
+			sum += payment.Amount
+		}
+		if req.AmountPaid == 0 {
+			req.AmountPaid = sum
+		}
+	} else {
+		if req.AmountPaid <= 0 {
+			utils.Logf(ctx, "❌ Sell: amountPaid must be greater than 0: %d", req.AmountPaid)
+			writeError(w, "amountPaid must be greater than 0", http.StatusBadRequest)
+			return
+		}
 
-	if strings.TrimSpace(req.PaymentDestination) == "" {
-		log.Printf("❌ Sell: paymentDestination is required")
-		http.Error(w, "paymentDestination is required", http.StatusBadRequest)
-		return
+		if strings.TrimSpace(req.PaymentMethod) == "" {
+			utils.Logf(ctx, "❌ Sell: paymentMethod is required")
+			writeError(w, "paymentMethod is required", http.StatusBadRequest)
+			return
+		}
+
+		if strings.TrimSpace(req.PaymentDestination) == "" {
+			utils.Logf(ctx, "❌ Sell: paymentDestination is required")
+			writeError(w, "paymentDestination is required", http.StatusBadRequest)
+			return
+		}
 	}
 
-	ctx := context.Background()
 	sale, err := c.repository.Sell(ctx, orderID, &req)
 	if err != nil {
-		log.Printf("❌ Sell: Error selling order: %v", err)
+		utils.Logf(ctx, "❌ Sell: Error selling order: %v", err)
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "order not found") {
-			http.Error(w, errMsg, http.StatusNotFound)
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "idempotency key already used") {
+			writeError(w, errMsg, http.StatusConflict)
 			return
 		}
 		if strings.Contains(errMsg, "not in reserved status") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
 		if strings.Contains(errMsg, "already has a sale") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(errMsg, "saleType must be") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(errMsg, "already completed without a sale") {
+			writeError(w, errMsg, http.StatusConflict)
 			return
 		}
 		if strings.Contains(errMsg, "insufficient reserved stock") {
-			http.Error(w, errMsg, http.StatusBadRequest)
+			writeError(w, errMsg, http.StatusBadRequest)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to sell order: %v", err), http.StatusInternalServerError)
+		if strings.Contains(errMsg, "payments") || strings.Contains(errMsg, "order discount") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to sell order: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("✅ Sell: Successfully sold order id=%d, sale id=%d", orderID, sale.ID)
+	if sale.IdempotentReplay {
+		// Already notified when the sale was first created
+		utils.Logf(ctx, "🔁 Sell: Replayed idempotency key for order id=%d, sale id=%d", orderID, sale.ID)
+	} else {
+		utils.Logf(ctx, "✅ Sell: Successfully sold order id=%d, sale id=%d", orderID, sale.ID)
+
+		// Notify downstream systems (async, failures end up in webhook_failures)
+		c.webhookService.NotifySale(sale)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(sale); err != nil {
-		log.Printf("❌ Sell: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		utils.Logf(ctx, "❌ Sell: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SalesReport handles GET /admin/sales/report?from=YYYY-MM-DD&to=YYYY-MM-DD&groupBy=day|week|month
+// groupBy defaults to day. Gift sales are excluded.
+// Example response:
+// {
+//   "from": "2026-01-01",
+//   "to": "2026-01-31",
+//   "groupBy": "week",
+//   "totalCount": 12,
+//   "totalAmountPaid": 640000,
+//   "periods": [
+//     { "period": "2025-12-29", "count": 3, "amountPaid": 150000 },
+//     { "period": "2026-01-05", "count": 9, "amountPaid": 490000 }
+//   ],
+//   "byPaymentMethod": [{ "key": "transfer", "count": 10, "amountPaid": 560000 }, { "key": "cash", "count": 2, "amountPaid": 80000 }],
+//   "byPaymentDestination": [{ "key": "Nequi", "count": 12, "amountPaid": 640000 }]
+// }
+func (c *SaleController) SalesReport(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SalesReport: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ SalesReport: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fromStr := r.URL.Query().Get("from")
+	toStr := r.URL.Query().Get("to")
+
+	var from, to *string
+	if fromStr != "" {
+		if _, err := time.Parse("2006-01-02", fromStr); err != nil {
+			log.Printf("❌ SalesReport: Invalid from date format: %s", fromStr)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = &fromStr
+	}
+	if toStr != "" {
+		if _, err := time.Parse("2006-01-02", toStr); err != nil {
+			log.Printf("❌ SalesReport: Invalid to date format: %s", toStr)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = &toStr
+	}
+
+	groupBy := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("groupBy")))
+	if groupBy == "" {
+		groupBy = "day"
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.Report(ctx, from, to, groupBy)
+	if err != nil {
+		log.Printf("❌ SalesReport: Error building report: %v", err)
+		if strings.Contains(err.Error(), "invalid") {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to build sales report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ SalesReport: %d sales in %d periods", response.TotalCount, len(response.Periods))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ SalesReport: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SalesBySeller handles GET /admin/sales/by-seller?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Count and total amountPaid per seller, highest total first. Gift sales are excluded.
+// Example response:
+// {
+//   "from": "2026-01-01",
+//   "to": "2026-01-31",
+//   "totalCount": 12,
+//   "totalAmountPaid": 640000,
+//   "sellers": [
+//     { "seller": "Erika", "count": 8, "amountPaid": 450000 },
+//     { "seller": "Laura", "count": 4, "amountPaid": 190000 }
+//   ]
+// }
+func (c *SaleController) SalesBySeller(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SalesBySeller: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ SalesBySeller: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fromStr := r.URL.Query().Get("from")
+	toStr := r.URL.Query().Get("to")
+
+	var from, to *string
+	if fromStr != "" {
+		if _, err := time.Parse("2006-01-02", fromStr); err != nil {
+			log.Printf("❌ SalesBySeller: Invalid from date format: %s", fromStr)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = &fromStr
+	}
+	if toStr != "" {
+		if _, err := time.Parse("2006-01-02", toStr); err != nil {
+			log.Printf("❌ SalesBySeller: Invalid to date format: %s", toStr)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = &toStr
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.BySeller(ctx, from, to)
+	if err != nil {
+		log.Printf("❌ SalesBySeller: Error building report: %v", err)
+		if strings.Contains(err.Error(), "invalid") {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to build sales by seller report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ SalesBySeller: %d sales across %d sellers", response.TotalCount, len(response.Sellers))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ SalesBySeller: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// defaultTopCustomersLimit and maxTopCustomersLimit bound the limit of SalesTopCustomers
+const (
+	defaultTopCustomersLimit = 10
+	maxTopCustomersLimit     = 100
+)
+
+// SalesTopCustomers handles GET /admin/sales/top-customers?from=YYYY-MM-DD&to=YYYY-MM-DD&limit=10
+// Count and total amountPaid per customer name of the reserved order, highest total first, keeping
+// the top limit customers (default 10, max 100). Orders without a customer name are grouped under
+// "(sin nombre)". Gift sales are excluded.
+// Example response:
+// {
+//   "from": "2026-01-01",
+//   "to": "2026-01-31",
+//   "limit": 10,
+//   "customers": [
+//     { "customerName": "María Pérez", "count": 3, "amountPaid": 210000 },
+//     { "customerName": "(sin nombre)", "count": 2, "amountPaid": 95000 }
+//   ]
+// }
+func (c *SaleController) SalesTopCustomers(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SalesTopCustomers: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ SalesTopCustomers: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fromStr := r.URL.Query().Get("from")
+	toStr := r.URL.Query().Get("to")
+
+	var from, to *string
+	if fromStr != "" {
+		if _, err := time.Parse("2006-01-02", fromStr); err != nil {
+			log.Printf("❌ SalesTopCustomers: Invalid from date format: %s", fromStr)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = &fromStr
+	}
+	if toStr != "" {
+		if _, err := time.Parse("2006-01-02", toStr); err != nil {
+			log.Printf("❌ SalesTopCustomers: Invalid to date format: %s", toStr)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = &toStr
+	}
+
+	limit := defaultTopCustomersLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			log.Printf("❌ SalesTopCustomers: Invalid limit: %s", limitStr)
+			writeError(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if parsed > maxTopCustomersLimit {
+			parsed = maxTopCustomersLimit
+		}
+		limit = parsed
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.TopCustomers(ctx, from, to, limit)
+	if err != nil {
+		log.Printf("❌ SalesTopCustomers: Error building report: %v", err)
+		if strings.Contains(err.Error(), "invalid") {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to build top customers report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ SalesTopCustomers: Returning %d customers", len(response.Customers))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ SalesTopCustomers: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
@@ -161,7 +457,7 @@ func (c *SaleController) ListSales(w http.ResponseWriter, r *http.Request) {
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ ListSales: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -175,7 +471,7 @@ func (c *SaleController) ListSales(w http.ResponseWriter, r *http.Request) {
 		_, err := time.Parse("2006-01-02", fromStr)
 		if err != nil {
 			log.Printf("❌ ListSales: Invalid from date format: %s", fromStr)
-			http.Error(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid from date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		from = &fromStr
@@ -186,17 +482,17 @@ func (c *SaleController) ListSales(w http.ResponseWriter, r *http.Request) {
 		_, err := time.Parse("2006-01-02", toStr)
 		if err != nil {
 			log.Printf("❌ ListSales: Invalid to date format: %s", toStr)
-			http.Error(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			writeError(w, "Invalid to date format. Use YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
 		to = &toStr
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	sales, err := c.repository.List(ctx, from, to)
 	if err != nil {
 		log.Printf("❌ ListSales: Error fetching sales: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to fetch sales: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to fetch sales: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -209,7 +505,7 @@ func (c *SaleController) ListSales(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("❌ ListSales: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
@@ -224,6 +520,9 @@ func (c *SaleController) ListSales(w http.ResponseWriter, r *http.Request) {
 //   "amountPaid": 100000,
 //   "paymentMethod": "transfer",
 //   "paymentDestination": "Nequi",
+//   "payments": [
+//     {"method": "transfer", "destination": "Nequi", "amount": 100000}
+//   ],
 //   "status": "paid",
 //   "notes": "Pago completo",
 //   "createdAt": "2026-01-04T10:30:00Z",
@@ -238,7 +537,7 @@ func (c *SaleController) GetSale(w http.ResponseWriter, r *http.Request) {
 
 	if r.Method != http.MethodGet {
 		log.Printf("❌ GetSale: Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -246,32 +545,32 @@ func (c *SaleController) GetSale(w http.ResponseWriter, r *http.Request) {
 	// Path format: /admin/sales/{id}
 	path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
 	if path == "" {
-		http.Error(w, "sale id parameter is required", http.StatusBadRequest)
+		writeError(w, "sale id parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	// Check if path contains sub-paths
 	if strings.Contains(path, "/") {
-		http.Error(w, "invalid path format", http.StatusBadRequest)
+		writeError(w, "invalid path format", http.StatusBadRequest)
 		return
 	}
 
 	saleID, err := strconv.ParseInt(path, 10, 64)
 	if err != nil {
 		log.Printf("❌ GetSale: Invalid sale id: %s", path)
-		http.Error(w, "invalid sale id parameter", http.StatusBadRequest)
+		writeError(w, "invalid sale id parameter", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := requestContext(r)
 	sale, err := c.repository.GetByID(ctx, saleID)
 	if err != nil {
 		log.Printf("❌ GetSale: Error fetching sale: %v", err)
 		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeError(w, err.Error(), http.StatusNotFound)
 			return
 		}
-		http.Error(w, fmt.Sprintf("Failed to fetch sale: %v", err), http.StatusInternalServerError)
+		writeError(w, fmt.Sprintf("Failed to fetch sale: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -280,9 +579,673 @@ func (c *SaleController) GetSale(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(sale); err != nil {
 		log.Printf("❌ GetSale: Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetSaleInvoice handles GET /admin/sales/:id/invoice
+// Returns a customer receipt: store metadata, the sale, each order line with readable labels and its
+// line total, plus subtotal, discount and grand total. See SaleInvoice for the response structure
+func (c *SaleController) GetSaleInvoice(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetSaleInvoice: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetSaleInvoice: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/sales/{id}/invoice
+	path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
+	idStr := strings.TrimSuffix(path, "/invoice")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	saleID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ GetSaleInvoice: Invalid sale id: %s", idStr)
+		writeError(w, "invalid sale id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	invoice, err := c.getSaleInvoice(ctx, saleID)
+	if err != nil {
+		log.Printf("❌ GetSaleInvoice: Error building invoice: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to build invoice: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ GetSaleInvoice: Built invoice %s with %d lines", invoice.InvoiceNumber, len(invoice.Lines))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(invoice); err != nil {
+		log.Printf("❌ GetSaleInvoice: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetSaleInvoicePDF handles GET /admin/sales/:id/invoice.pdf
+// Renders the same invoice as GetSaleInvoice to a letter-size PDF (lines with description, size, qty,
+// unit price and line total, totals and payment method/destination)
+func (c *SaleController) GetSaleInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetSaleInvoicePDF: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetSaleInvoicePDF: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/sales/{id}/invoice.pdf
+	path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
+	idStr := strings.TrimSuffix(path, "/invoice.pdf")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	saleID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ GetSaleInvoicePDF: Invalid sale id: %s", idStr)
+		writeError(w, "invalid sale id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	invoice, err := c.getSaleInvoice(ctx, saleID)
+	if err != nil {
+		log.Printf("❌ GetSaleInvoicePDF: Error building invoice: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to build invoice: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	pdfData, err := c.invoiceService.GenerateInvoicePDF(ctx, invoice)
+	if err != nil {
+		log.Printf("❌ GetSaleInvoicePDF: Error generating PDF: %v", err)
+		writeError(w, fmt.Sprintf("Failed to generate PDF: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ GetSaleInvoicePDF: Generated invoice %s (%d bytes)", invoice.InvoiceNumber, len(pdfData))
+
+	filename := fmt.Sprintf("factura_%s.pdf", invoice.InvoiceNumber)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(pdfData); err != nil {
+		log.Printf("❌ GetSaleInvoicePDF: Error writing PDF response: %v", err)
+	}
+}
+
+// getSaleInvoice loads a sale with its order and turns it into a SaleInvoice
+func (c *SaleController) getSaleInvoice(ctx context.Context, saleID int64) (*models.SaleInvoice, error) {
+	sale, err := c.repository.GetByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	return buildSaleInvoice(sale), nil
+}
+
+// buildSaleInvoice builds the invoice payload for a sale. Line labels use the same mappings as the
+// order views (custom lines show their custom colors), and totals are computed from the frozen line prices
+// and amount_paid
+func buildSaleInvoice(sale *models.SaleDetailResponse) *models.SaleInvoice {
+	currency := utils.DefaultCurrency
+	if engine := pricing.GetEngine(); engine != nil {
+		if engineCurrency, _ := engine.ConfigInfo(); engineCurrency != "" {
+			currency = engineCurrency
+		}
+	}
+
+	invoice := &models.SaleInvoice{
+		InvoiceNumber:      fmt.Sprintf("V-%06d", sale.ID),
+		Store:              models.InvoiceStore{Name: utils.StoreName(), Currency: currency},
+		SaleID:             sale.ID,
+		ReservedOrderID:    sale.ReservedOrderID,
+		SoldAt:             sale.SoldAt,
+		CustomerName:       sale.CustomerName,
+		SaleType:           sale.SaleType,
+		Status:             sale.Status,
+		PaymentMethod:      sale.PaymentMethod,
+		PaymentDestination: sale.PaymentDestination,
+		Notes:              sale.Notes,
+		Lines:              []models.SaleInvoiceLine{},
+		AmountPaid:         sale.AmountPaid,
+	}
+
+	if sale.Order != nil {
+		decorateOrderLines(sale.Order)
+		invoice.CustomerPhone = sale.Order.CustomerPhone
+		invoice.OrderType = sale.Order.OrderType
+
+		for _, line := range sale.Order.Lines {
+			item := line.Item
+			description := strings.TrimSpace(item.Description)
+			if description == "" {
+				description = strings.TrimSpace(fmt.Sprintf("%s %s / %s", item.HoodieTypeLabel, item.ColorPrimaryLabel, item.ColorSecondaryLabel))
+			}
+			lineTotal := int64(line.Qty) * line.UnitPrice
+			invoice.Lines = append(invoice.Lines, models.SaleInvoiceLine{
+				ItemID:              line.ItemID,
+				SKU:                 item.SKU,
+				Description:         description,
+				Size:                item.Size,
+				ColorPrimaryLabel:   item.ColorPrimaryLabel,
+				ColorSecondaryLabel: item.ColorSecondaryLabel,
+				HoodieTypeLabel:     item.HoodieTypeLabel,
+				Qty:                 line.Qty,
+				UnitPrice:           line.UnitPrice,
+				LineTotal:           lineTotal,
+			})
+			invoice.Subtotal += lineTotal
+		}
+	}
+
+	// Sell spreads order discounts and coupons over the frozen line prices; whatever the lines still
+	// exceed amount_paid by (e.g. a sale recorded without the pricing engine) is shown as the discount
+	if invoice.Subtotal > sale.AmountPaid {
+		invoice.Discount = invoice.Subtotal - sale.AmountPaid
+	}
+	invoice.Total = invoice.Subtotal - invoice.Discount
+	return invoice
+}
+
+// RepriceSale handles POST /admin/sales/:id/reprice
+// Recomputes the order pricing with the current engine, re-freezes line prices,
+// updates amount_paid and posts a finance adjustment for the difference.
+// Sales with refunds cannot be repriced (400).
+// Example request:
+// POST /admin/sales/10/reprice
+// {
+//   "reason": "Bundle rule misconfigured at time of sale"
+// }
+// Example response:
+// {
+//   "sale": { "id": 10, "amountPaid": 90000, ... },
+//   "previousAmount": 100000,
+//   "newAmount": 90000,
+//   "difference": -10000,
+//   "adjustmentTransactionId": 57,
+//   "reason": "Bundle rule misconfigured at time of sale"
+// }
+func (c *SaleController) RepriceSale(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 RepriceSale: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ RepriceSale: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract sale ID from URL path
+	// Path format: /admin/sales/{id}/reprice
+	path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
+	idStr := strings.TrimSuffix(path, "/reprice")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	saleID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ RepriceSale: Invalid sale id: %s", idStr)
+		writeError(w, "invalid sale id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RepriceSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ RepriceSale: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		log.Printf("❌ RepriceSale: reason is required")
+		writeError(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.Reprice(ctx, saleID, strings.TrimSpace(req.Reason))
+	if err != nil {
+		log.Printf("❌ RepriceSale: Error repricing sale: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "not in paid status") || strings.Contains(errMsg, "not in completed status") ||
+			strings.Contains(errMsg, "must be greater than 0") || strings.Contains(errMsg, "required") ||
+			strings.Contains(errMsg, "gift sales") || strings.Contains(errMsg, "payment split") ||
+			strings.Contains(errMsg, "already has refunds") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(errMsg, "pricing engine not initialized") {
+			writeError(w, errMsg, http.StatusServiceUnavailable)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to reprice sale: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ RepriceSale: Successfully repriced sale id=%d (difference=%d)", saleID, response.Difference)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ RepriceSale: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RefundSale handles POST /admin/sales/:id/refund
+// Refunds part (or all) of a sale. Refunded units return to stock_total only.
+// Example request:
+// POST /admin/sales/10/refund
+// {
+//   "lines": [{ "lineId": 5, "qty": 1 }],
+//   "reason": "Talla equivocada",
+//   "destination": "Nequi"
+// }
+// Lines may use "itemId" instead of "lineId". An optional "amount" overrides the refunded money
+// (partial refund); omitting both lines and amount refunds every unit not refunded yet.
+// Example response:
+// {
+//   "id": 1,
+//   "saleId": 10,
+//   "amount": 25000,
+//   "destination": "Nequi",
+//   "reason": "Talla equivocada",
+//   "createdAt": "2026-01-05T09:00:00Z",
+//   "lines": [{ "id": 1, "reservedOrderLineId": 5, "itemId": 12, "qty": 1, "unitPrice": 25000, "amount": 25000 }]
+// }
+func (c *SaleController) RefundSale(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 RefundSale: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ RefundSale: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract sale ID from URL path
+	// Path format: /admin/sales/{id}/refund
+	path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
+	idStr := strings.TrimSuffix(path, "/refund")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	saleID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ RefundSale: Invalid sale id: %s", idStr)
+		writeError(w, "invalid sale id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RefundSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ RefundSale: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		log.Printf("❌ RefundSale: reason is required")
+		writeError(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	refund, err := c.repository.Refund(ctx, saleID, &req)
+	if err != nil {
+		log.Printf("❌ RefundSale: Error refunding sale: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "already refunded") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		if strings.Contains(errMsg, "not in paid status") || strings.Contains(errMsg, "exceeds sold quantity") ||
+			strings.Contains(errMsg, "exceeds amount paid") ||
+			strings.Contains(errMsg, "must be greater than 0") || strings.Contains(errMsg, "duplicate line") ||
+			strings.Contains(errMsg, "required") || strings.Contains(errMsg, "gift sales") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to refund sale: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ RefundSale: Successfully refunded sale id=%d, refund id=%d", saleID, refund.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(refund); err != nil {
+		log.Printf("❌ RefundSale: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// VoidSale handles POST /admin/sales/:id/void
+// Voids a sale recorded in error (wrong order), unlike a refund which records a customer return:
+// the sale is kept with status "voided", its finance transactions are reversed, stock is restored
+// and the order goes back to "reserved" so it can be corrected and sold again. A sale.voided webhook
+// is sent. Only same-day sales can be voided unless ?force=true is passed; sales with refunds cannot
+// be voided. The reason is required.
+// Example request:
+// POST /admin/sales/10/void
+// {"reason": "Venta registrada en el pedido equivocado"}
+// Example response: See VoidSaleResponse structure
+func (c *SaleController) VoidSale(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 VoidSale: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ VoidSale: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract sale ID from URL path
+	// Path format: /admin/sales/{id}/void
+	path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
+	idStr := strings.TrimSuffix(path, "/void")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	saleID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ VoidSale: Invalid sale id: %s", idStr)
+		writeError(w, "invalid sale id parameter", http.StatusBadRequest)
+		return
+	}
+
+	force := false
+	if forceStr := strings.TrimSpace(r.URL.Query().Get("force")); forceStr != "" {
+		force, err = strconv.ParseBool(forceStr)
+		if err != nil {
+			log.Printf("❌ VoidSale: Invalid force flag: %s", forceStr)
+			writeError(w, "force must be 'true' or 'false'", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var req models.VoidSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("❌ VoidSale: Failed to decode request body: %v", err)
+		writeError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.VoidSale(ctx, saleID, force, req.Reason)
+	if err != nil {
+		log.Printf("❌ VoidSale: Error voiding sale: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			writeError(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "reason is required") {
+			writeError(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(errMsg, "already has refunds") || strings.Contains(errMsg, "only be voided") ||
+			strings.Contains(errMsg, "already voided") || strings.Contains(errMsg, "not in paid status") ||
+			strings.Contains(errMsg, "not in completed status") {
+			writeError(w, errMsg, http.StatusConflict)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to void sale: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ VoidSale: Successfully voided sale id=%d, order id=%d is reserved again", saleID, response.ReservedOrderID)
+
+	// Notify downstream systems (async, failures end up in webhook_failures)
+	c.webhookService.NotifySaleVoided(response.Sale)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ VoidSale: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
+// ListSaleRefunds handles GET /admin/sales/:id/refunds
+// Example response:
+// {
+//   "saleId": 10,
+//   "refundedAmount": 25000,
+//   "refunds": [
+//     {
+//       "id": 1,
+//       "saleId": 10,
+//       "amount": 25000,
+//       "destination": "Nequi",
+//       "reason": "Talla equivocada",
+//       "createdAt": "2026-01-05T09:00:00Z",
+//       "lines": [{ "id": 1, "reservedOrderLineId": 5, "itemId": 12, "qty": 1, "unitPrice": 25000, "amount": 25000 }]
+//     }
+//   ]
+// }
+func (c *SaleController) ListSaleRefunds(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ListSaleRefunds: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ ListSaleRefunds: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract sale ID from URL path
+	// Path format: /admin/sales/{id}/refunds
+	path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
+	idStr := strings.TrimSuffix(path, "/refunds")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	saleID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ ListSaleRefunds: Invalid sale id: %s", idStr)
+		writeError(w, "invalid sale id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.ListRefunds(ctx, saleID)
+	if err != nil {
+		log.Printf("❌ ListSaleRefunds: Error fetching refunds: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to fetch refunds: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ListSaleRefunds: Successfully fetched %d refunds for sale id=%d", len(response.Refunds), saleID)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ ListSaleRefunds: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ListOrderSales handles GET /admin/reserved-orders/:id/sales
+// Returns every sale ever associated with the reserved order (including refunded ones),
+// ordered by soldAt, so staff can see the full financial history of a cart
+func (c *SaleController) ListOrderSales(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ListOrderSales: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ ListOrderSales: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/sales
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/sales")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ ListOrderSales: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.ListByReservedOrder(ctx, orderID)
+	if err != nil {
+		log.Printf("❌ ListOrderSales: Error fetching sales: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to fetch sales: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ListOrderSales: Successfully fetched %d sales for order id=%d", len(response.Sales), orderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ ListOrderSales: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetOrderSale handles GET /admin/reserved-orders/:id/sale
+// Returns the current sale of the reserved order (same shape as GET /admin/sales/:id), or 404 when the
+// order has not been sold or its sale was voided. Use /sales for the full history including refunds and voids
+func (c *SaleController) GetOrderSale(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 GetOrderSale: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ GetOrderSale: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/sale
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/sale")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ GetOrderSale: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
 
+	ctx := requestContext(r)
+	sale, err := c.repository.GetByReservedOrder(ctx, orderID)
+	if err != nil {
+		log.Printf("❌ GetOrderSale: Error fetching sale: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to fetch sale: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ GetOrderSale: Successfully fetched sale id=%d for order id=%d", sale.ID, orderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(sale); err != nil {
+		log.Printf("❌ GetOrderSale: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SellCheck handles GET /admin/reserved-orders/:id/sell-check
+// Read-only check of everything Sell validates, so the UI can disable the Sell button with a reason.
+// Blockers are listed in issues; sellable is true only when there are none.
+// Example response: See SellCheckResponse structure
+func (c *SaleController) SellCheck(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 SellCheck: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ SellCheck: Method not allowed: %s", r.Method)
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract order ID from URL path
+	// Path format: /admin/reserved-orders/{id}/sell-check
+	path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
+	idStr := strings.TrimSuffix(path, "/sell-check")
+	if idStr == path || idStr == "" {
+		writeError(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ SellCheck: Invalid order id: %s", idStr)
+		writeError(w, "invalid order id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	response, err := c.repository.SellCheck(ctx, orderID)
+	if err != nil {
+		log.Printf("❌ SellCheck: Error checking order: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, fmt.Sprintf("Failed to check order: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ SellCheck: Error encoding response: %v", err)
+		writeError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
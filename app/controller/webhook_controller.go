@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/repository"
+	"armario-mascota-me/service"
+)
+
+// WebhookController handles HTTP requests for webhook dead-letter entries
+type WebhookController struct {
+	repository     repository.WebhookFailureRepositoryInterface
+	webhookService *service.SaleWebhookService
+}
+
+// NewWebhookController creates a new WebhookController
+func NewWebhookController(repo repository.WebhookFailureRepositoryInterface, webhookService *service.SaleWebhookService) *WebhookController {
+	return &WebhookController{
+		repository:     repo,
+		webhookService: webhookService,
+	}
+}
+
+// ListFailures handles GET /admin/webhooks/failures?includeResolved=true
+// Returns unresolved sale webhooks whose retries were exhausted (resolved ones with includeResolved=true)
+// Example response: See WebhookFailureListResponse structure
+func (c *WebhookController) ListFailures(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 ListWebhookFailures: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		log.Printf("❌ ListWebhookFailures: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	includeResolved := false
+	if value := r.URL.Query().Get("includeResolved"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "includeResolved must be 'true' or 'false'", http.StatusBadRequest)
+			return
+		}
+		includeResolved = parsed
+	}
+
+	ctx := requestContext(r)
+	failures, err := c.repository.List(ctx, includeResolved)
+	if err != nil {
+		log.Printf("❌ ListWebhookFailures: Error fetching webhook failures: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to fetch webhook failures: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ ListWebhookFailures: Successfully fetched %d webhook failures", len(failures))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(models.WebhookFailureListResponse{Failures: failures}); err != nil {
+		log.Printf("❌ ListWebhookFailures: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RetryFailure handles POST /admin/webhooks/failures/:id/retry
+// Replays the stored payload once. On success the entry is marked resolved; on failure
+// attempts and lastError are updated. Returns the updated entry in both cases.
+func (c *WebhookController) RetryFailure(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 RetryWebhookFailure: Received %s request to %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		log.Printf("❌ RetryWebhookFailure: Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /admin/webhooks/failures/{id}/retry
+	path := strings.TrimPrefix(r.URL.Path, "/admin/webhooks/failures/")
+	idStr := strings.TrimSuffix(path, "/retry")
+	if idStr == path || idStr == "" {
+		http.Error(w, "invalid path format", http.StatusBadRequest)
+		return
+	}
+
+	failureID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Printf("❌ RetryWebhookFailure: Invalid id: %s", idStr)
+		http.Error(w, "invalid webhook failure id parameter", http.StatusBadRequest)
+		return
+	}
+
+	ctx := requestContext(r)
+	failure, err := c.webhookService.RetryFailure(ctx, failureID)
+	if err != nil {
+		log.Printf("❌ RetryWebhookFailure: Error retrying webhook failure: %v", err)
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			http.Error(w, errMsg, http.StatusNotFound)
+			return
+		}
+		if strings.Contains(errMsg, "already resolved") {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(errMsg, "not configured") {
+			http.Error(w, errMsg, http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to retry webhook: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("✅ RetryWebhookFailure: id=%d resolved=%v attempts=%d", failure.ID, failure.ResolvedAt != nil, failure.Attempts)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(failure); err != nil {
+		log.Printf("❌ RetryWebhookFailure: Error encoding response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
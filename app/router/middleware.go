@@ -0,0 +1,165 @@
+package router
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"armario-mascota-me/metrics"
+	"armario-mascota-me/utils"
+)
+
+// maxRequestIDLength caps incoming X-Request-ID values so clients cannot flood the logs
+const maxRequestIDLength = 128
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (rec *statusRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write records an implicit 200 when the handler writes without calling WriteHeader
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+// Flush keeps streaming handlers (Server-Sent Events) working through the recorder
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// WithRequestLogging wraps a handler so every request gets an X-Request-ID (an incoming one is honored),
+// carried in the request context for utils.Logf and echoed in the response, and logs method, path,
+// status and duration once the request finishes
+func WithRequestLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := strings.TrimSpace(r.Header.Get(utils.RequestIDHeader))
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = utils.NewRequestID()
+		}
+		w.Header().Set(utils.RequestIDHeader, requestID)
+
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(utils.WithRequestID(r.Context(), requestID)))
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		log.Printf("[req=%s] %s %s -> %d (%s)", requestID, r.Method, r.URL.Path, status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// WithMetrics wraps a handler so every request is counted in http_requests_total and timed in
+// http_request_duration_seconds, labeled with the registered route pattern (e.g. "/admin/reserved-orders/")
+// rather than the raw path so IDs do not create a series each. Returns next unchanged when metrics are disabled
+func WithMetrics(next http.Handler) http.Handler {
+	if !metrics.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		// ServeMux sets Pattern on the request it routes; auth rejections never reach it
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequests.Inc(route, r.Method, strconv.Itoa(status))
+		metrics.HTTPRequestDuration.ObserveSince(start, route)
+	})
+}
+
+// AdminKeyHeader is the request header carrying the admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// WithAdminAuth wraps a handler so every /admin request must carry the ADMIN_API_KEY value in the
+// X-Admin-Key header or as "Authorization: Bearer <key>", otherwise it gets a 401. The key is never
+// accepted in the query string, where it would end up in logs and browser history.
+// Internal catalog render requests signed with a render token (utils.IsAuthorizedRenderRequest) are
+// let through so chromedp can load the catalog, and so is the events stream opened with a short-lived
+// stream token (utils.IsAuthorizedStreamRequest), since EventSource cannot set headers.
+// /ping, /healthz, /readyz and /static are never checked.
+// When ADMIN_API_KEY is not set, auth is disabled outside production and every /admin request is
+// rejected in production (fail closed)
+func WithAdminAuth(next http.Handler) http.Handler {
+	apiKey := os.Getenv("ADMIN_API_KEY")
+	if apiKey == "" {
+		if os.Getenv("ENV") != "production" {
+			log.Printf("⚠️ WithAdminAuth: ADMIN_API_KEY is not set, /admin routes are NOT protected (development only)")
+			return next
+		}
+		log.Printf("⚠️ WithAdminAuth: ADMIN_API_KEY is not set in production, every /admin request will be rejected")
+	}
+	expected := sha256.Sum256([]byte(apiKey))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin" && !strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if utils.IsAuthorizedRenderRequest(r) || utils.IsAuthorizedStreamRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		provided := r.Header.Get(AdminKeyHeader)
+		if provided == "" {
+			provided = bearerToken(r.Header.Get("Authorization"))
+		}
+		// Hashing both sides keeps the comparison constant-time regardless of the key length
+		digest := sha256.Sum256([]byte(provided))
+		if apiKey == "" || provided == "" || subtle.ConstantTimeCompare(digest[:], expected[:]) != 1 {
+			log.Printf("❌ WithAdminAuth: Unauthorized %s %s", r.Method, r.URL.Path)
+			// Same envelope as controller errors: {"error":{"code":...,"message":...}}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("WWW-Authenticate", `ApiKey header="`+AdminKeyHeader+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"unauthorized","message":"missing or invalid admin API key"}}` + "\n"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header value, or "" for any other scheme
+func bearerToken(authorization string) string {
+	scheme, token, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
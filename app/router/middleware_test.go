@@ -0,0 +1,135 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"armario-mascota-me/utils"
+)
+
+func TestWithAdminAuth(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", "secret-key")
+	t.Setenv("ENV", "production")
+
+	handler := WithAdminAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	streamToken, _ := utils.GenerateStreamToken()
+
+	tests := []struct {
+		name          string
+		method        string
+		target        string
+		header        string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:       "valid admin key header",
+			method:     http.MethodGet,
+			target:     "/admin/sales",
+			header:     "secret-key",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing admin key",
+			method:     http.MethodGet,
+			target:     "/admin/sales",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong admin key",
+			method:     http.MethodPost,
+			target:     "/admin/sales",
+			header:     "other-key",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "valid admin key as bearer token",
+			method:        http.MethodGet,
+			target:        "/admin/sales",
+			authorization: "Bearer secret-key",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "wrong authorization scheme",
+			method:        http.MethodGet,
+			target:        "/admin/sales",
+			authorization: "Basic secret-key",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:       "admin key in the query string",
+			method:     http.MethodGet,
+			target:     "/admin/sales?adminKey=secret-key",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "events stream with a stream token",
+			method:     http.MethodGet,
+			target:     "/admin/reserved-orders/events?" + utils.StreamTokenParam + "=" + streamToken,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "stream token outside the events stream",
+			method:     http.MethodGet,
+			target:     "/admin/sales?" + utils.StreamTokenParam + "=" + streamToken,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "events stream with a render token",
+			method:     http.MethodGet,
+			target:     "/admin/reserved-orders/events?" + utils.StreamTokenParam + "=" + utils.GenerateRenderToken("XS"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "public route",
+			method:     http.MethodGet,
+			target:     "/ping",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "catalog render signed with a render token",
+			method:     http.MethodGet,
+			target:     "/admin/catalog/render?size=XS&perPage=9&" + utils.RenderTokenParam + "=" + utils.GenerateRenderToken("XS"),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "design asset image signed for the catalog size",
+			method:     http.MethodGet,
+			target:     utils.SignRenderImageURL("/admin/design-assets/pending/7/image?size=medium", "XS"),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "catalog render token for another size",
+			method:     http.MethodGet,
+			target:     "/admin/catalog/render?size=L&" + utils.RenderTokenParam + "=" + utils.GenerateRenderToken("XS"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "render token outside the render routes",
+			method:     http.MethodGet,
+			target:     "/admin/sales?size=XS&" + utils.RenderTokenParam + "=" + utils.GenerateRenderToken("XS"),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.header != "" {
+				r.Header.Set(AdminKeyHeader, tt.header)
+			}
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("%s %s: status = %d, want %d", tt.method, tt.target, w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,22 +1,35 @@
 package router
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"armario-mascota-me/app/controller"
+	"armario-mascota-me/db"
+	"armario-mascota-me/metrics"
 )
 
 type Controllers struct {
-	DesignAsset        *controller.DesignAssetController
-	Item               *controller.ItemController
-	ReservedOrder      *controller.ReservedOrderController
-	Sale               *controller.SaleController
-	FinanceTransaction *controller.FinanceTransactionController
-	Catalog            *controller.CatalogController
-	Download           *controller.DownloadController
+	DesignAsset          *controller.DesignAssetController
+	Item                 *controller.ItemController
+	ReservedOrder        *controller.ReservedOrderController
+	Sale                 *controller.SaleController
+	FinanceTransaction   *controller.FinanceTransactionController
+	FinanceTemplate      *controller.FinanceTemplateController
+	RecurringTransaction *controller.RecurringTransactionController
+	FinanceBudget        *controller.FinanceBudgetController
+	FinanceDestination   *controller.FinanceReferenceController
+	FinanceCategory      *controller.FinanceReferenceController
+	Catalog              *controller.CatalogController
+	Download             *controller.DownloadController
+	Webhook              *controller.WebhookController
+	Report               *controller.ReportController
+	Pricing              *controller.PricingController
 }
 
 // pingHandler handles GET /ping
@@ -30,6 +43,49 @@ func pingHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"status":"ok"}`))
 }
 
+// readyzTimeout bounds the database ping so readiness probes stay cheap
+const readyzTimeout = 2 * time.Second
+
+// healthzHandler handles GET /healthz
+// Liveness probe: returns 200 as long as the process is serving requests
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// readyzHandler handles GET /readyz
+// Readiness probe: returns 200 when the database answers a ping, 503 otherwise
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if db.DB == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"unavailable","database":"not initialized"}`))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readyzTimeout)
+	defer cancel()
+	if err := db.DB.PingContext(ctx); err != nil {
+		log.Printf("❌ Readyz: Database ping failed: %v", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"unavailable","database":"unreachable"}`))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ready"}`))
+}
+
 // serveStaticFiles serves static files from the static directory
 func serveStaticFiles(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -74,6 +130,13 @@ func SetupRoutes(controllers *Controllers) {
 	// Ping endpoint
 	http.HandleFunc("/ping", pingHandler)
 
+	// Health and readiness probes (unauthenticated, for load balancers and orchestrators)
+	http.HandleFunc("/healthz", healthzHandler)
+	http.HandleFunc("/readyz", readyzHandler)
+
+	// Prometheus metrics (404 unless METRICS_ENABLED=true)
+	http.HandleFunc("/metrics", metrics.Handler)
+
 	// Static files
 	http.HandleFunc("/static/", serveStaticFiles)
 
@@ -92,8 +155,30 @@ func SetupRoutes(controllers *Controllers) {
 	// Filter design assets
 	http.HandleFunc("/admin/design-assets/filter", controllers.DesignAsset.FilterDesignAssets)
 
+	// Auto-tag pending design assets from their filename/code (dryRun=true to preview)
+	http.HandleFunc("/admin/design-assets/auto-tag", controllers.DesignAsset.AutoTag)
+
+	// Design assets sharing the same original image hash
+	http.HandleFunc("/admin/design-assets/duplicates", controllers.DesignAsset.GetDuplicateDesignAssets)
+
+	// Bulk status update (e.g. approve a batch of pending assets)
+	http.HandleFunc("/admin/design-assets/status", controllers.DesignAsset.BulkUpdateStatus)
+
+	// Clear the whole optimized image cache
+	http.HandleFunc("/admin/design-assets/cache/clear", controllers.DesignAsset.ClearImageCache)
+
 	// Get optimized image for pending asset
 	http.HandleFunc("/admin/design-assets/pending/", func(w http.ResponseWriter, r *http.Request) {
+		// Invalidate the cached thumb/medium images of one asset
+		if strings.HasSuffix(r.URL.Path, "/image/cache") {
+			controllers.DesignAsset.InvalidateImageCache(w, r)
+			return
+		}
+		// Store the manual rotation of one asset's images
+		if strings.HasSuffix(r.URL.Path, "/image/rotation") {
+			controllers.DesignAsset.SetImageRotation(w, r)
+			return
+		}
 		// Check if this is the image endpoint
 		if strings.HasSuffix(r.URL.Path, "/image") {
 			controllers.DesignAsset.GetOptimizedImage(w, r)
@@ -105,26 +190,103 @@ func SetupRoutes(controllers *Controllers) {
 
 	// Design asset by code - handles both GET (get) and PUT (update)
 	http.HandleFunc("/admin/design-assets/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/active") {
+			controllers.DesignAsset.SetDesignAssetActive(w, r)
+			return
+		}
+		// Handle POST /admin/design-assets/:id/publish
+		if strings.HasSuffix(r.URL.Path, "/publish") {
+			controllers.DesignAsset.PublishDesignAsset(w, r)
+			return
+		}
+		// Handle GET /admin/design-assets/:id/items
+		if strings.HasSuffix(r.URL.Path, "/items") {
+			controllers.Item.GetDesignAssetItems(w, r)
+			return
+		}
 		// Route to appropriate handler based on HTTP method
 		if r.Method == http.MethodGet {
 			controllers.DesignAsset.GetDesignAssetByCode(w, r)
 		} else if r.Method == http.MethodPut {
 			controllers.DesignAsset.UpdateDesignAsset(w, r)
+		} else if r.Method == http.MethodDelete {
+			controllers.DesignAsset.DeleteDesignAsset(w, r)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
 
 	// Items routes
+	// Create item
+	http.HandleFunc("/admin/items", controllers.Item.CreateItem)
+
 	// Add stock to item
 	http.HandleFunc("/admin/items/stock", controllers.Item.AddStock)
 
 	// Filter items
 	http.HandleFunc("/admin/items/filter", controllers.Item.FilterItems)
 
+	// Dry-run stock check (no reservation)
+	http.HandleFunc("/admin/items/availability", controllers.Item.CheckAvailability)
+
+	// Low-stock items
+	http.HandleFunc("/admin/items/low-stock", controllers.Item.LowStock)
+
+	// Type-ahead item search
+	http.HandleFunc("/admin/items/search", controllers.Item.SearchItems)
+
+	// Item actions
+	http.HandleFunc("/admin/items/", func(w http.ResponseWriter, r *http.Request) {
+		// Handle PATCH /admin/items/:id/design-asset
+		if strings.HasSuffix(r.URL.Path, "/design-asset") {
+			controllers.Item.UpdateDesignAsset(w, r)
+			return
+		}
+		// Handle GET /admin/items/:id/price-quote
+		if strings.HasSuffix(r.URL.Path, "/price-quote") {
+			controllers.Item.PriceQuote(w, r)
+			return
+		}
+		// Handle POST /admin/items/:id/adjust
+		if strings.HasSuffix(r.URL.Path, "/adjust") {
+			controllers.Item.AdjustStock(w, r)
+			return
+		}
+		// Handle GET /admin/items/:id/adjustments
+		if strings.HasSuffix(r.URL.Path, "/adjustments") {
+			controllers.Item.GetAdjustments(w, r)
+			return
+		}
+		// Handle PATCH /admin/items/:id/active
+		if strings.HasSuffix(r.URL.Path, "/active") {
+			controllers.Item.SetItemActive(w, r)
+			return
+		}
+		// Handle GET / PUT / PATCH /admin/items/:id
+		switch r.Method {
+		case http.MethodGet:
+			controllers.Item.GetItem(w, r)
+		case http.MethodPut, http.MethodPatch:
+			controllers.Item.UpdateItem(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
 	// Catalog routes - IMPORTANT: More specific routes must come BEFORE general ones
 	http.HandleFunc("/admin/catalog/png-page", controllers.Catalog.DownloadPNGPage)
 	http.HandleFunc("/admin/catalog/render", controllers.Catalog.RenderCatalog)
+	http.HandleFunc("/admin/catalog/export.json", controllers.Catalog.ExportCatalogJSON)
+	http.HandleFunc("/admin/catalog/jobs", controllers.Catalog.CreateCatalogJob)
+	http.HandleFunc("/admin/catalog/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		// Handle GET /admin/catalog/jobs/:id/download
+		if strings.HasSuffix(r.URL.Path, "/download") {
+			controllers.Catalog.DownloadCatalogJobPDF(w, r)
+			return
+		}
+		// Handle GET /admin/catalog/jobs/:id
+		controllers.Catalog.GetCatalogJob(w, r)
+	})
 	http.HandleFunc("/admin/catalog", controllers.Catalog.GenerateCatalog)
 
 	// Download routes
@@ -145,6 +307,21 @@ func SetupRoutes(controllers *Controllers) {
 	// Get separated carts with full item information
 	http.HandleFunc("/admin/reserved-orders/separated", controllers.ReservedOrder.GetSeparatedCarts)
 
+	// Server-Sent Events stream of reserved order changes
+	http.HandleFunc("/admin/reserved-orders/events", controllers.ReservedOrder.StreamEvents)
+
+	// Short-lived token for opening the events stream from EventSource (no headers)
+	http.HandleFunc("/admin/reserved-orders/events/token", controllers.ReservedOrder.IssueEventsToken)
+
+	// Count and combined total of open carts per seller
+	http.HandleFunc("/admin/reserved-orders/summary-by-seller", controllers.ReservedOrder.GetSummaryBySeller)
+
+	// Consolidated picking list across matching orders
+	http.HandleFunc("/admin/reserved-orders/picking-list", controllers.ReservedOrder.GetPickingList)
+
+	// Maintenance: persist the pricing engine's order_type for every reserved order
+	http.HandleFunc("/admin/reserved-orders/recalculate-types", controllers.ReservedOrder.RecalculateOrderTypes)
+
 	// Reserved order actions (must be before the generic /:id route)
 	http.HandleFunc("/admin/reserved-orders/", func(w http.ResponseWriter, r *http.Request) {
 		path := strings.TrimPrefix(r.URL.Path, "/admin/reserved-orders/")
@@ -154,6 +331,14 @@ func SetupRoutes(controllers *Controllers) {
 			controllers.ReservedOrder.CancelOrder(w, r)
 			return
 		}
+		if strings.HasSuffix(path, "/reopen") {
+			controllers.ReservedOrder.ReopenOrder(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/extend-hold") {
+			controllers.ReservedOrder.ExtendHold(w, r)
+			return
+		}
 		if strings.HasSuffix(path, "/complete") {
 			controllers.ReservedOrder.CompleteOrder(w, r)
 			return
@@ -162,6 +347,39 @@ func SetupRoutes(controllers *Controllers) {
 			controllers.Sale.Sell(w, r)
 			return
 		}
+		if strings.HasSuffix(path, "/claim-stock") {
+			controllers.ReservedOrder.ClaimStock(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/merge") {
+			controllers.ReservedOrder.MergeOrders(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/summary.txt") {
+			controllers.ReservedOrder.GetOrderSummaryText(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/completion-impact") {
+			controllers.ReservedOrder.GetCompletionImpact(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/sales") {
+			controllers.Sale.ListOrderSales(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/sale") {
+			controllers.Sale.GetOrderSale(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/sell-check") {
+			controllers.Sale.SellCheck(w, r)
+			return
+		}
+		// Handle POST /admin/reserved-orders/:id/items/bulk
+		if strings.HasSuffix(path, "/items/bulk") && r.Method == http.MethodPost {
+			controllers.ReservedOrder.BulkAddItems(w, r)
+			return
+		}
 		// Handle DELETE /admin/reserved-orders/:orderId/items/:itemId
 		if strings.Contains(path, "/items/") && r.Method == http.MethodDelete {
 			controllers.ReservedOrder.RemoveItem(w, r)
@@ -184,6 +402,12 @@ func SetupRoutes(controllers *Controllers) {
 			return
 		}
 
+		// Handle PATCH /admin/reserved-orders/:id (update only the provided fields, never lines)
+		if r.Method == http.MethodPatch && !strings.Contains(path, "/") {
+			controllers.ReservedOrder.PatchOrder(w, r)
+			return
+		}
+
 		// Otherwise, treat as GET /admin/reserved-orders/:id
 		if r.Method == http.MethodGet {
 			controllers.ReservedOrder.GetOrder(w, r)
@@ -204,8 +428,44 @@ func SetupRoutes(controllers *Controllers) {
 		}
 	})
 
-	// Get sale by ID
+	// Sales report aggregated by period
+	http.HandleFunc("/admin/sales/report", controllers.Sale.SalesReport)
+
+	// Sales totals per seller
+	http.HandleFunc("/admin/sales/by-seller", controllers.Sale.SalesBySeller)
+
+	// Top customers by spend
+	http.HandleFunc("/admin/sales/top-customers", controllers.Sale.SalesTopCustomers)
+
+	// Sale actions and get sale by ID
 	http.HandleFunc("/admin/sales/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/admin/sales/")
+
+		if strings.HasSuffix(path, "/reprice") {
+			controllers.Sale.RepriceSale(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/refund") {
+			controllers.Sale.RefundSale(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/refunds") {
+			controllers.Sale.ListSaleRefunds(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/void") {
+			controllers.Sale.VoidSale(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/invoice.pdf") {
+			controllers.Sale.GetSaleInvoicePDF(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/invoice") {
+			controllers.Sale.GetSaleInvoice(w, r)
+			return
+		}
+
 		if r.Method == http.MethodGet {
 			controllers.Sale.GetSale(w, r)
 		} else {
@@ -225,6 +485,117 @@ func SetupRoutes(controllers *Controllers) {
 		}
 	})
 
+	// Finance transactions CSV import
+	http.HandleFunc("/admin/finance/transactions/import", controllers.FinanceTransaction.Import)
+
+	// Finance transaction by ID - handles PUT (update) and DELETE
+	http.HandleFunc("/admin/finance/transactions/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			controllers.FinanceTransaction.Update(w, r)
+		case http.MethodDelete:
+			controllers.FinanceTransaction.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Pricing preview for an arbitrary cart (no order created)
+	http.HandleFunc("/admin/pricing/preview", controllers.Pricing.Preview)
+
+	// Pricing config hot reload
+	http.HandleFunc("/admin/pricing/reload", controllers.Pricing.Reload)
+
+	// Pricing config validation against active items
+	http.HandleFunc("/admin/pricing/validate", controllers.Pricing.Validate)
+
+	// Finance transfer between destinations (linked expense + income pair)
+	http.HandleFunc("/admin/finance/transfer", controllers.FinanceTransaction.Transfer)
+
+	// Finance templates - handles both POST (create) and GET (list)
+	http.HandleFunc("/admin/finance/templates", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			controllers.FinanceTemplate.Create(w, r)
+		} else if r.Method == http.MethodGet {
+			controllers.FinanceTemplate.List(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Finance template by ID - handles GET, PUT and DELETE
+	http.HandleFunc("/admin/finance/templates/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			controllers.FinanceTemplate.Get(w, r)
+		case http.MethodPut:
+			controllers.FinanceTemplate.Update(w, r)
+		case http.MethodDelete:
+			controllers.FinanceTemplate.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Recurring finance transactions - handles both POST (create) and GET (list)
+	http.HandleFunc("/admin/finance/recurring", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			controllers.RecurringTransaction.Create(w, r)
+		} else if r.Method == http.MethodGet {
+			controllers.RecurringTransaction.List(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Recurring finance transaction by ID - handles GET, PUT and DELETE
+	http.HandleFunc("/admin/finance/recurring/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			controllers.RecurringTransaction.Get(w, r)
+		case http.MethodPut:
+			controllers.RecurringTransaction.Update(w, r)
+		case http.MethodDelete:
+			controllers.RecurringTransaction.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Finance budgets - handles both POST (create) and GET (list, optional ?month=YYYY-MM)
+	http.HandleFunc("/admin/finance/budgets", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			controllers.FinanceBudget.Create(w, r)
+		} else if r.Method == http.MethodGet {
+			controllers.FinanceBudget.List(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Finance budgets vs actual expenses for a month
+	http.HandleFunc("/admin/finance/budgets/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			controllers.FinanceBudget.Status(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Finance budget by ID - handles GET, PUT and DELETE
+	http.HandleFunc("/admin/finance/budgets/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			controllers.FinanceBudget.Get(w, r)
+		case http.MethodPut:
+			controllers.FinanceBudget.Update(w, r)
+		case http.MethodDelete:
+			controllers.FinanceBudget.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
 	// Finance summary
 	http.HandleFunc("/admin/finance/summary", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
@@ -242,4 +613,80 @@ func SetupRoutes(controllers *Controllers) {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
+
+	// Finance integrity check (money invariants)
+	http.HandleFunc("/admin/finance/integrity", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			controllers.FinanceTransaction.Integrity(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Managed finance destinations - handles both POST (create) and GET (list)
+	http.HandleFunc("/admin/finance/destinations", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			controllers.FinanceDestination.Create(w, r)
+		} else if r.Method == http.MethodGet {
+			controllers.FinanceDestination.List(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Finance destination ledger with running balance, and managed destination by ID (PUT, DELETE)
+	http.HandleFunc("/admin/finance/destinations/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/ledger") {
+			controllers.FinanceTransaction.Ledger(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			controllers.FinanceDestination.Update(w, r)
+		case http.MethodDelete:
+			controllers.FinanceDestination.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Managed finance categories - handles both POST (create) and GET (list)
+	http.HandleFunc("/admin/finance/categories", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			controllers.FinanceCategory.Create(w, r)
+		} else if r.Method == http.MethodGet {
+			controllers.FinanceCategory.List(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Managed finance category by ID - handles PUT (rename) and DELETE
+	http.HandleFunc("/admin/finance/categories/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			controllers.FinanceCategory.Update(w, r)
+		case http.MethodDelete:
+			controllers.FinanceCategory.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Webhook dead-letter routes
+	http.HandleFunc("/admin/webhooks/failures", controllers.Webhook.ListFailures)
+	http.HandleFunc("/admin/webhooks/failures/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/retry") {
+			controllers.Webhook.RetryFailure(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	})
+
+	// Report routes
+	http.HandleFunc("/admin/reports/groups-coverage", controllers.Report.GroupsCoverage)
+	http.HandleFunc("/admin/reports/time-to-sell", controllers.Report.TimeToSell)
+
+	// Inventory health: reserved vs available stock
+	http.HandleFunc("/admin/inventory/dashboard", controllers.Report.InventoryDashboard)
 }
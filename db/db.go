@@ -7,7 +7,10 @@ import (
 	"log"
 	"os"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"armario-mascota-me/metrics"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 // DB holds the database connection
@@ -42,11 +45,23 @@ func InitDB() error {
 	}
 
 	var err error
-	DB, err = sql.Open("pgx", connStr)
-	if err != nil {
-		return fmt.Errorf("failed to open database connection: %w", err)
+	if metrics.Enabled() {
+		// Trace queries so /metrics exposes DB latency and errors
+		config, parseErr := pgx.ParseConfig(connStr)
+		if parseErr != nil {
+			return fmt.Errorf("failed to open database connection: %w", parseErr)
+		}
+		config.Tracer = metricsTracer{}
+		DB = stdlib.OpenDB(*config)
+	} else {
+		DB, err = sql.Open("pgx", connStr)
+		if err != nil {
+			return fmt.Errorf("failed to open database connection: %w", err)
+		}
 	}
 
+	configurePool(DB)
+
 	// Test the connection
 	ctx := context.Background()
 	if err := DB.PingContext(ctx); err != nil {
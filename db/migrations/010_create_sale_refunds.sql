@@ -0,0 +1,34 @@
+-- Migration: Create sale_refunds and sale_refund_lines tables
+-- Description: Audit trail for (partial) refunds of completed sales
+
+-- Table: sale_refunds
+-- Stores one row per refund operation performed on a sale
+CREATE TABLE IF NOT EXISTS sale_refunds (
+    id BIGSERIAL PRIMARY KEY,
+    sale_id BIGINT NOT NULL REFERENCES sales(id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    destination TEXT NOT NULL CHECK (destination != ''),
+    reason TEXT NOT NULL CHECK (reason != ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for sale_refunds
+CREATE INDEX IF NOT EXISTS idx_sale_refunds_sale_id ON sale_refunds(sale_id);
+CREATE INDEX IF NOT EXISTS idx_sale_refunds_created_at ON sale_refunds(created_at DESC);
+
+-- Table: sale_refund_lines
+-- Stores the refunded quantity per order line for each refund
+CREATE TABLE IF NOT EXISTS sale_refund_lines (
+    id BIGSERIAL PRIMARY KEY,
+    sale_refund_id BIGINT NOT NULL REFERENCES sale_refunds(id) ON DELETE CASCADE,
+    reserved_order_line_id BIGINT NOT NULL REFERENCES reserved_order_lines(id) ON DELETE RESTRICT,
+    item_id BIGINT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    qty INT NOT NULL CHECK (qty > 0),
+    unit_price BIGINT NOT NULL CHECK (unit_price >= 0),
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for sale_refund_lines
+CREATE INDEX IF NOT EXISTS idx_sale_refund_lines_refund_id ON sale_refund_lines(sale_refund_id);
+CREATE INDEX IF NOT EXISTS idx_sale_refund_lines_line_id ON sale_refund_lines(reserved_order_line_id);
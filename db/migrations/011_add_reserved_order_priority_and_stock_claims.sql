@@ -0,0 +1,23 @@
+-- Migration: Add priority to reserved_orders and create reserved_order_stock_claims table
+-- Description: High-priority orders can claim reserved units from normal-priority orders, with an audit trail
+
+-- Add priority column to reserved_orders table
+ALTER TABLE reserved_orders
+ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'normal' CHECK (priority IN ('normal', 'high'));
+
+-- Table: reserved_order_stock_claims
+-- Stores one row per stock claim (units moved from a lower-priority order to a higher-priority one)
+CREATE TABLE IF NOT EXISTS reserved_order_stock_claims (
+    id BIGSERIAL PRIMARY KEY,
+    claimant_order_id BIGINT NOT NULL REFERENCES reserved_orders(id) ON DELETE CASCADE,
+    donor_order_id BIGINT NOT NULL REFERENCES reserved_orders(id) ON DELETE CASCADE,
+    item_id BIGINT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    qty INT NOT NULL CHECK (qty > 0),
+    reason TEXT NOT NULL CHECK (reason != ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (claimant_order_id != donor_order_id)
+);
+
+-- Indexes for reserved_order_stock_claims
+CREATE INDEX IF NOT EXISTS idx_reserved_order_stock_claims_claimant ON reserved_order_stock_claims(claimant_order_id);
+CREATE INDEX IF NOT EXISTS idx_reserved_order_stock_claims_donor ON reserved_order_stock_claims(donor_order_id);
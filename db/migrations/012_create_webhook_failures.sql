@@ -0,0 +1,20 @@
+-- Migration: Create webhook_failures table
+-- Description: Dead-letter storage for sale webhooks whose delivery retries were exhausted
+
+-- Table: webhook_failures
+-- Stores the undelivered payload so it can be inspected and replayed manually
+CREATE TABLE IF NOT EXISTS webhook_failures (
+    id BIGSERIAL PRIMARY KEY,
+    sale_id BIGINT NOT NULL REFERENCES sales(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    last_error TEXT NOT NULL,
+    attempts INT NOT NULL CHECK (attempts > 0),
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for webhook_failures
+CREATE INDEX IF NOT EXISTS idx_webhook_failures_sale_id ON webhook_failures(sale_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_failures_created_at ON webhook_failures(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_failures_unresolved ON webhook_failures(created_at DESC) WHERE resolved_at IS NULL;
@@ -0,0 +1,5 @@
+-- Migration: Add image rejection flag to design_assets
+-- Description: Records why an original image was rejected (too large / too many pixels)
+-- so sync and image optimization do not keep downloading and retrying it
+
+ALTER TABLE design_assets ADD COLUMN IF NOT EXISTS image_rejected_reason TEXT;
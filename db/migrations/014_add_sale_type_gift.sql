@@ -0,0 +1,21 @@
+-- Migration: Add sale_type to sales
+-- Description: Allows recording gifts/samples ('gift') that deduct stock without income.
+-- Gift sales have amount_paid = 0, require a reason and have no finance transaction.
+
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS sale_type TEXT NOT NULL DEFAULT 'sale';
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS gift_reason TEXT;
+
+ALTER TABLE sales DROP CONSTRAINT IF EXISTS sales_sale_type_check;
+ALTER TABLE sales ADD CONSTRAINT sales_sale_type_check
+    CHECK (sale_type IN ('sale', 'gift'));
+
+-- Replace the amount_paid > 0 check: gifts are recorded with amount_paid = 0
+ALTER TABLE sales DROP CONSTRAINT IF EXISTS sales_amount_paid_check;
+ALTER TABLE sales ADD CONSTRAINT sales_amount_paid_check
+    CHECK ((sale_type = 'sale' AND amount_paid > 0) OR (sale_type = 'gift' AND amount_paid = 0));
+
+ALTER TABLE sales DROP CONSTRAINT IF EXISTS sales_gift_reason_check;
+ALTER TABLE sales ADD CONSTRAINT sales_gift_reason_check
+    CHECK (sale_type <> 'gift' OR COALESCE(gift_reason, '') <> '');
+
+CREATE INDEX IF NOT EXISTS idx_sales_sale_type ON sales(sale_type);
@@ -0,0 +1,19 @@
+-- Migration: Create finance_templates table
+-- Description: Reusable presets (type, category, counterparty, destination, notes) for routine finance entries
+
+-- Table: finance_templates
+-- Applied by POST /admin/finance/transactions when the body includes templateId
+CREATE TABLE IF NOT EXISTS finance_templates (
+    id BIGSERIAL PRIMARY KEY,
+    label TEXT NOT NULL UNIQUE CHECK (label != ''),
+    type TEXT CHECK (type IN ('income', 'expense')),
+    category TEXT,
+    counterparty TEXT,
+    destination TEXT,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for finance_templates
+CREATE INDEX IF NOT EXISTS idx_finance_templates_label ON finance_templates(label);
@@ -0,0 +1,5 @@
+-- Migration: Add source filename to design_assets
+-- Description: Keeps the original Google Drive filename so batches that follow a naming
+-- convention can be auto-tagged from it
+
+ALTER TABLE design_assets ADD COLUMN IF NOT EXISTS source_filename TEXT;
@@ -0,0 +1,10 @@
+-- Migration: Link transfer legs in finance_transactions
+-- Description: A transfer between destinations is stored as an expense on the source and an
+-- income on the target (category 'transferencia') sharing the same transfer_group_id
+
+CREATE SEQUENCE IF NOT EXISTS finance_transfer_group_seq;
+
+ALTER TABLE finance_transactions ADD COLUMN IF NOT EXISTS transfer_group_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_finance_transactions_transfer_group_id ON finance_transactions(transfer_group_id)
+    WHERE transfer_group_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Migration: Add idempotency_key to sales
+-- Description: Stores the Idempotency-Key header sent with POST /admin/reserved-orders/:id/sell.
+-- Replaying the same key returns the original sale instead of an error.
+
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS idempotency_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sales_idempotency_key
+    ON sales(idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Migration: Add order-level discount to reserved_orders
+-- Description: Flat or percentage discount applied to the whole order after the pricing engine runs.
+-- discount_value is an amount for 'flat' and a percentage (0-100) for 'percent'.
+
+ALTER TABLE reserved_orders
+ADD COLUMN IF NOT EXISTS discount_type TEXT NOT NULL DEFAULT 'none' CHECK (discount_type IN ('none', 'flat', 'percent'));
+
+ALTER TABLE reserved_orders
+ADD COLUMN IF NOT EXISTS discount_value BIGINT NOT NULL DEFAULT 0 CHECK (discount_value >= 0);
@@ -0,0 +1,31 @@
+-- Migration: Create recurring_transactions table
+-- Description: Schedules (rent, salaries, subscriptions) that a background worker materializes into
+-- finance_transactions. last_generated_date is the last day already generated, so a recurrence is
+-- never generated twice for the same date.
+
+-- Table: recurring_transactions
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    label TEXT NOT NULL CHECK (label != ''),
+    type TEXT NOT NULL CHECK (type IN ('income', 'expense')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    destination TEXT NOT NULL CHECK (destination != ''),
+    category TEXT,
+    counterparty TEXT,
+    notes TEXT,
+    frequency TEXT NOT NULL CHECK (frequency IN ('monthly', 'weekly')),
+    day_of_month INT CHECK (day_of_month BETWEEN 1 AND 31),
+    day_of_week INT CHECK (day_of_week BETWEEN 0 AND 6),
+    start_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_generated_date DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (
+        (frequency = 'monthly' AND day_of_month IS NOT NULL) OR
+        (frequency = 'weekly' AND day_of_week IS NOT NULL)
+    )
+);
+
+-- Indexes for recurring_transactions
+CREATE INDEX IF NOT EXISTS idx_recurring_transactions_is_active ON recurring_transactions(is_active);
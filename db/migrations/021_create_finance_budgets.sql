@@ -0,0 +1,17 @@
+-- Migration: Create finance_budgets table
+-- Description: Monthly expense limit per finance category, compared against actual expenses by
+-- GET /admin/finance/budgets/status. period_month is always the first day of the month.
+
+-- Table: finance_budgets
+CREATE TABLE IF NOT EXISTS finance_budgets (
+    id BIGSERIAL PRIMARY KEY,
+    category TEXT NOT NULL CHECK (category != ''),
+    period_month DATE NOT NULL CHECK (EXTRACT(DAY FROM period_month) = 1),
+    limit_amount BIGINT NOT NULL CHECK (limit_amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (category, period_month)
+);
+
+-- Indexes for finance_budgets
+CREATE INDEX IF NOT EXISTS idx_finance_budgets_period_month ON finance_budgets(period_month);
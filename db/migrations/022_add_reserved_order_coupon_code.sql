@@ -0,0 +1,6 @@
+-- Migration: Add coupon code to reserved_orders
+-- Description: Optional promotion code resolved against the coupons in the pricing config.
+-- Unknown, inactive or expired codes are kept but ignored when pricing the order.
+
+ALTER TABLE reserved_orders
+ADD COLUMN IF NOT EXISTS coupon_code TEXT;
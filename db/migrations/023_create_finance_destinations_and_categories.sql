@@ -0,0 +1,38 @@
+-- Migration: Create finance_destinations and finance_categories tables
+-- Description: Managed lists of destination and category names. Manual finance transactions must use
+-- a listed value (matched case-insensitively) unless the request sets allowFreeText.
+
+-- Table: finance_destinations
+CREATE TABLE IF NOT EXISTS finance_destinations (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL CHECK (name != ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_finance_destinations_name ON finance_destinations(LOWER(name));
+
+-- Table: finance_categories
+CREATE TABLE IF NOT EXISTS finance_categories (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL CHECK (name != ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_finance_categories_name ON finance_categories(LOWER(name));
+
+-- Seed both lists with the values already in use so existing clients keep working
+INSERT INTO finance_destinations (name)
+SELECT DISTINCT ON (LOWER(TRIM(destination))) TRIM(destination)
+FROM finance_transactions
+WHERE TRIM(destination) != ''
+ORDER BY LOWER(TRIM(destination)), TRIM(destination)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO finance_categories (name)
+SELECT DISTINCT ON (LOWER(TRIM(category))) TRIM(category)
+FROM finance_transactions
+WHERE category IS NOT NULL AND TRIM(category) != ''
+ORDER BY LOWER(TRIM(category)), TRIM(category)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+-- Migration: Add image hash to design_assets
+-- Description: Stores the SHA-256 of the original image downloaded during sync so
+-- re-synced duplicates with different Drive file ids can be grouped for review
+
+ALTER TABLE design_assets ADD COLUMN IF NOT EXISTS image_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_design_assets_image_hash ON design_assets(image_hash) WHERE image_hash IS NOT NULL;
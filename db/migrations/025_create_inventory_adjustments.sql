@@ -0,0 +1,18 @@
+-- Migration: Create inventory_adjustments table
+-- Description: Audit log for manual corrections of items.stock_total after physical counts
+
+-- Table: inventory_adjustments
+-- Each row records one correction with the stock before/after, why it was made and who made it
+CREATE TABLE IF NOT EXISTS inventory_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    item_id BIGINT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    delta INT NOT NULL CHECK (delta <> 0),
+    stock_total_before INT NOT NULL,
+    stock_total_after INT NOT NULL,
+    reason TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for inventory_adjustments
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustments_item_id ON inventory_adjustments(item_id, created_at DESC);
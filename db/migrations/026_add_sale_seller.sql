@@ -0,0 +1,13 @@
+-- Migration: Add seller to sales
+-- Description: Records who rang up each sale, for per-seller reports and commissions.
+-- Sell defaults it to the order's assigned_to; existing sales are backfilled the same way.
+
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS seller TEXT;
+
+UPDATE sales s
+SET seller = ro.assigned_to
+FROM reserved_orders ro
+WHERE ro.id = s.reserved_order_id
+  AND s.seller IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sales_seller ON sales(seller);
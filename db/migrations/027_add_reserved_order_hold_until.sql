@@ -0,0 +1,11 @@
+-- Migration: Add per-order stock hold expiry to reserved orders
+-- Description: hold_until is set when an order is created (now + ORDER_HOLD_HOURS) and pushed forward
+-- when items are added or via POST /admin/reserved-orders/:id/extend-hold. The hold worker cancels
+-- reserved orders whose hold has passed, releasing their stock.
+-- NULL means the order never expires: existing orders are left NULL so nothing is canceled on deploy.
+
+ALTER TABLE reserved_orders ADD COLUMN IF NOT EXISTS hold_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_reserved_orders_hold_until
+    ON reserved_orders(hold_until)
+    WHERE status = 'reserved' AND hold_until IS NOT NULL;
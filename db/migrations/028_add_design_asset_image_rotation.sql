@@ -0,0 +1,10 @@
+-- Migration: Add manual image rotation to design assets
+-- Description: Clockwise rotation (0, 90, 180, 270) applied to the optimized images after the EXIF
+-- orientation, for photos that still come out sideways. Set with PATCH .../image/rotation.
+
+ALTER TABLE design_assets ADD COLUMN IF NOT EXISTS image_rotation INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE design_assets DROP CONSTRAINT IF EXISTS design_assets_image_rotation_check;
+
+ALTER TABLE design_assets ADD CONSTRAINT design_assets_image_rotation_check
+    CHECK (image_rotation IN (0, 90, 180, 270));
@@ -0,0 +1,38 @@
+-- Migration: Create sale_payments table
+-- Description: Payment splits of a sale (e.g. part cash, part transfer). sales.payment_method and
+-- payment_destination keep the largest split; this table keeps every split.
+
+-- Table: sale_payments
+-- One row per split; the amounts of a sale's rows add up to its amount_paid (gift sales have none)
+CREATE TABLE IF NOT EXISTS sale_payments (
+    id BIGSERIAL PRIMARY KEY,
+    sale_id BIGINT NOT NULL REFERENCES sales(id) ON DELETE RESTRICT,
+    method TEXT NOT NULL CHECK (method != ''),
+    destination TEXT NOT NULL CHECK (destination != ''),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for sale_payments
+CREATE INDEX IF NOT EXISTS idx_sale_payments_sale_id ON sale_payments(sale_id);
+
+-- Backfill existing sales from their sale income transactions (one per split). The method of each
+-- split was not stored before, so backfilled splits take the sale's payment_method
+INSERT INTO sale_payments (sale_id, method, destination, amount, created_at)
+SELECT s.id, s.payment_method, ft.destination, ft.amount, s.created_at
+FROM sales s
+INNER JOIN finance_transactions ft ON ft.source = 'sale' AND ft.source_id = s.id AND ft.type = 'income'
+WHERE s.sale_type <> 'gift'
+  AND NOT EXISTS (SELECT 1 FROM sale_payments sp WHERE sp.sale_id = s.id);
+
+-- Repriced sales changed amount_paid through a separate adjustment transaction: move the difference
+-- onto their largest split, as Reprice does from now on, so the splits add up to amount_paid
+UPDATE sale_payments sp
+SET amount = sp.amount + (s.amount_paid - totals.total)
+FROM sales s,
+     (SELECT sale_id, SUM(amount) as total FROM sale_payments GROUP BY sale_id) totals
+WHERE totals.sale_id = s.id
+  AND sp.sale_id = s.id
+  AND totals.total <> s.amount_paid
+  AND sp.id = (SELECT id FROM sale_payments WHERE sale_id = s.id ORDER BY amount DESC, id ASC LIMIT 1)
+  AND sp.amount + (s.amount_paid - totals.total) > 0;
@@ -0,0 +1,27 @@
+-- Migration: Add voided status to sales
+-- Description: Voiding a sale recorded in error keeps the sale row (status 'voided', voided_at and
+-- void_reason) instead of deleting it; its money is reversed with 'sale_void' finance transactions.
+-- The order can be sold again, so only one non-voided sale per reserved order is unique.
+
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ;
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS void_reason TEXT;
+
+ALTER TABLE sales DROP CONSTRAINT IF EXISTS sales_status_check;
+ALTER TABLE sales ADD CONSTRAINT sales_status_check
+    CHECK (status IN ('paid', 'refunded', 'pending', 'voided'));
+
+ALTER TABLE sales DROP CONSTRAINT IF EXISTS sales_void_check;
+ALTER TABLE sales ADD CONSTRAINT sales_void_check
+    CHECK ((status = 'voided') = (voided_at IS NOT NULL AND COALESCE(void_reason, '') <> ''));
+
+-- Replace UNIQUE(reserved_order_id): voided sales stay next to the sale that replaces them
+ALTER TABLE sales DROP CONSTRAINT IF EXISTS sales_reserved_order_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sales_reserved_order_id_active
+    ON sales(reserved_order_id)
+    WHERE status <> 'voided';
+
+-- Dead-lettered webhooks are an audit trail too: sales are no longer deleted, and must not be while
+-- webhook failures reference them
+ALTER TABLE webhook_failures DROP CONSTRAINT IF EXISTS webhook_failures_sale_id_fkey;
+ALTER TABLE webhook_failures ADD CONSTRAINT webhook_failures_sale_id_fkey
+    FOREIGN KEY (sale_id) REFERENCES sales(id) ON DELETE RESTRICT;
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"armario-mascota-me/metrics"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// queryStartKey carries the query start time between TraceQueryStart and TraceQueryEnd
+type queryStartKey struct{}
+
+// metricsTracer records query latency and errors for /metrics
+type metricsTracer struct{}
+
+// TraceQueryStart remembers when the query started
+func (metricsTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, time.Now())
+}
+
+// TraceQueryEnd observes the query duration and counts failed queries
+func (metricsTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	if start, ok := ctx.Value(queryStartKey{}).(time.Time); ok {
+		metrics.DBQueryDuration.ObserveSince(start)
+	}
+	if data.Err != nil {
+		metrics.DBQueryErrors.Inc()
+	}
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"armario-mascota-me/models"
+)
+
+// Reserved order change types
+const (
+	OrderCreated      = "created"
+	OrderItemsAdded   = "items_added"
+	OrderItemsRemoved = "items_removed"
+	OrderItemsUpdated = "items_updated"
+	OrderUpdated      = "updated"
+	OrderCanceled     = "canceled"
+	OrderReopened     = "reopened"
+	OrderCompleted    = "completed"
+	OrderSold         = "sold"
+	OrderSaleVoided   = "sale_voided"
+	OrderHoldExtended = "hold_extended"
+	OrderHoldExpired  = "hold_expired"
+)
+
+// subscriberBuffer is how many events a slow subscriber can fall behind before events are dropped
+const subscriberBuffer = 32
+
+// Hub is a simple in-process pub/sub for reserved order changes.
+// Publish never blocks: a subscriber whose buffer is full misses the event.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[chan models.ReservedOrderEvent]struct{}
+}
+
+// NewHub creates a new Hub
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[chan models.ReservedOrderEvent]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber and returns its channel and an unsubscribe function
+func (h *Hub) Subscribe() (<-chan models.ReservedOrderEvent, func()) {
+	ch := make(chan models.ReservedOrderEvent, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// CloseAll closes every subscriber channel so open streams end, e.g. during server shutdown
+func (h *Hub) CloseAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+	log.Printf("✅ CloseAll: Closed all reserved order event subscribers")
+}
+
+// Publish sends an event to every subscriber without blocking
+func (h *Hub) Publish(event models.ReservedOrderEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("⚠️ Publish: Subscriber buffer full, dropping %s event for order_id=%d", event.Type, event.OrderID)
+		}
+	}
+}
+
+// hub is the process-wide hub used by the repositories
+var hub = NewHub()
+
+// GetHub returns the process-wide reserved order hub
+func GetHub() *Hub {
+	return hub
+}
+
+// PublishOrderChange notifies subscribers that a reserved order changed.
+// Call it only after the change has been committed.
+func PublishOrderChange(orderID int64, changeType string) {
+	hub.Publish(models.ReservedOrderEvent{
+		OrderID: orderID,
+		Type:    changeType,
+		At:      time.Now().UTC().Format(time.RFC3339),
+	})
+}
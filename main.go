@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 
 	"armario-mascota-me/app"
+	"armario-mascota-me/app/router"
 	"armario-mascota-me/db"
+	"armario-mascota-me/events"
+	"armario-mascota-me/utils"
 )
 
+// shutdownTimeout is how long in-flight requests (catalog renders, sale transactions) get to finish
+// after SIGINT/SIGTERM before the server stops waiting for them
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load .env file in development (ignores error if file doesn't exist)
 	// In production, variables should be set directly
@@ -48,24 +60,51 @@ func main() {
 	if err := app.Initialize(); err != nil {
 		log.Fatal(err)
 	}
-	defer db.CloseDB()
 
 	// Start server
 	// Listen on 0.0.0.0 to accept connections from all interfaces (required for Docker/Render)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	addr := utils.ServerAddr()
+	log.Printf("Server starting on %s (base URL %s)", addr, utils.BaseURL())
+	log.Printf("Load images endpoint: GET %s/admin/design-assets/load?folderId=YOUR_FOLDER_ID", utils.BaseURL())
+
+	// Every request gets an X-Request-ID and a summary log line (method, path, status, duration)
+	// and is counted in /metrics when METRICS_ENABLED=true; /admin routes additionally require the
+	// ADMIN_API_KEY (rejections are logged and counted too)
+	handler := router.WithRequestLogging(router.WithMetrics(router.WithAdminAuth(http.DefaultServeMux)))
+	server := &http.Server{Addr: addr, Handler: handler}
+	// Server-Sent Events streams never finish on their own; end them so Shutdown does not wait on them
+	server.RegisterOnShutdown(events.GetHub().CloseAll)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErr:
+		db.CloseDB()
+		log.Fatalf("Server failed to start: %v", err)
+	case sig := <-quit:
+		log.Printf("Received %s, shutting down (waiting up to %s for in-flight requests)", sig, shutdownTimeout)
 	}
-	// Remove leading colon if present (PORT from Render doesn't include it)
-	if len(port) > 0 && port[0] == ':' {
-		port = port[1:]
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Warning: Server shutdown did not complete cleanly: %v", err)
+	} else {
+		log.Printf("Server stopped, all in-flight requests finished")
 	}
-	addr := "0.0.0.0:" + port
-	log.Printf("Server starting on %s", addr)
-	log.Printf("Load images endpoint: GET http://localhost:%s/admin/design-assets/load?folderId=YOUR_FOLDER_ID", port)
 
-	if err := http.ListenAndServe(addr, nil); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	// Close the database only after in-flight requests are done with it
+	if err := db.CloseDB(); err != nil {
+		log.Printf("Warning: Error closing database: %v", err)
 	}
+	log.Printf("Shutdown complete")
 }
 
@@ -0,0 +1,35 @@
+package metrics
+
+// catalogRenderBuckets cover catalog PDF/PNG renders, which take seconds to minutes
+var catalogRenderBuckets = []float64{1, 2, 5, 10, 20, 30, 60, 90, 120, 180}
+
+var (
+	// HTTPRequests counts finished requests by registered route pattern, method and status code
+	HTTPRequests = NewCounter("http_requests_total", "HTTP requests by route, method and status.", "route", "method", "status")
+
+	// HTTPRequestDuration observes request latency by registered route pattern
+	HTTPRequestDuration = NewHistogram("http_request_duration_seconds", "HTTP request latency by route.", DefaultBuckets, "route")
+
+	// CatalogRenderDuration observes catalog PDF/PNG generation time; result is "ok" or "error"
+	CatalogRenderDuration = NewHistogram("catalog_render_duration_seconds", "Catalog PDF/PNG render duration.", catalogRenderBuckets, "format", "result")
+
+	// PricingCalculationDuration observes pricing engine runs by operation (order, preview, simulate)
+	PricingCalculationDuration = NewHistogram("pricing_calculation_duration_seconds", "Pricing engine calculation duration.", DefaultBuckets, "operation")
+
+	// SellDuration observes the sell transaction; result is "ok" or "error"
+	SellDuration = NewHistogram("sale_sell_duration_seconds", "Duration of selling a reserved order.", DefaultBuckets, "result")
+
+	// DBQueryDuration observes every database query and exec
+	DBQueryDuration = NewHistogram("db_query_duration_seconds", "Database query latency.", DefaultBuckets)
+
+	// DBQueryErrors counts database queries and execs that returned an error
+	DBQueryErrors = NewCounter("db_query_errors_total", "Database queries that returned an error.")
+)
+
+// Result returns the result label for an operation outcome
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
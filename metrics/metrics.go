@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the histogram buckets (seconds) used for request, query and pricing latencies
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var (
+	enabled     bool
+	enabledOnce sync.Once
+)
+
+// Enabled reports whether metrics are collected and served on /metrics (METRICS_ENABLED=true).
+// When disabled every Inc/Observe is a no-op
+func Enabled() bool {
+	enabledOnce.Do(func() {
+		enabled = strings.EqualFold(strings.TrimSpace(os.Getenv("METRICS_ENABLED")), "true")
+	})
+	return enabled
+}
+
+// collector is a metric that can write itself in the Prometheus text exposition format
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	registry      []collector
+	registryMutex sync.Mutex
+)
+
+// register adds a collector to the set written by WriteText
+func register(c collector) {
+	registryMutex.Lock()
+	registry = append(registry, c)
+	registryMutex.Unlock()
+}
+
+// labelKeySeparator joins label values into a series key; it cannot appear in valid UTF-8 text
+const labelKeySeparator = "\xff"
+
+// Counter is a monotonically increasing count, one series per combination of label values
+type Counter struct {
+	name   string
+	help   string
+	labels []string
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter creates and registers a counter with the given label names
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels, values: make(map[string]float64)}
+	register(c)
+	return c
+}
+
+// Inc adds one to the series for labelValues (given in the order of the counter's label names)
+func (c *Counter) Inc(labelValues ...string) {
+	if !Enabled() {
+		return
+	}
+	key := strings.Join(labelValues, labelKeySeparator)
+	c.mu.Lock()
+	c.values[key]++
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, key, ""), formatFloat(c.values[key]))
+	}
+}
+
+// histogramSeries holds the observations of one label combination
+type histogramSeries struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Histogram counts observations (durations in seconds) into buckets, one series per combination of label values
+type Histogram struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramSeries
+}
+
+// NewHistogram creates and registers a histogram with the given upper bounds (ascending) and label names
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, labels: labels, buckets: buckets, values: make(map[string]*histogramSeries)}
+	register(h)
+	return h
+}
+
+// Observe records value in the series for labelValues
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if !Enabled() {
+		return
+	}
+	key := strings.Join(labelValues, labelKeySeparator)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	series, ok := h.values[key]
+	if !ok {
+		series = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = series
+	}
+	for i, upper := range h.buckets {
+		if value <= upper {
+			series.counts[i]++
+			break
+		}
+	}
+	series.sum += value
+	series.count++
+}
+
+// ObserveSince records the seconds elapsed since start
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	keys := make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		series := h.values[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += series.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, "+Inf"), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, key, ""), formatFloat(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, key, ""), series.count)
+	}
+}
+
+// WriteText writes every registered metric in the Prometheus text exposition format
+func WriteText(w io.Writer) {
+	registryMutex.Lock()
+	collectors := append([]collector(nil), registry...)
+	registryMutex.Unlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Handler serves GET /metrics in the Prometheus text format, or 404 when metrics are disabled
+func Handler(w http.ResponseWriter, r *http.Request) {
+	if !Enabled() {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteText(w)
+}
+
+// sortedKeys returns the series keys of a counter in a stable order
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatLabels renders {name="value",...} for a series key, adding le for histogram buckets when set
+func formatLabels(names []string, key string, le string) string {
+	var pairs []string
+	if len(names) > 0 {
+		values := strings.Split(key, labelKeySeparator)
+		for i, name := range names {
+			value := ""
+			if i < len(values) {
+				value = values[i]
+			}
+			pairs = append(pairs, name+`="`+labelValueEscaper.Replace(value)+`"`)
+		}
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelValueEscaper escapes label values as the text exposition format requires
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatFloat renders a sample value the way Prometheus expects (shortest representation)
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
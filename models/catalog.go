@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // CatalogItem represents a single item in the catalog
 type CatalogItem struct {
 	ID               int    `json:"id"`
@@ -23,3 +25,59 @@ type CatalogData struct {
 	Items     []CatalogItem `json:"items"`
 	PageCount int           `json:"pageCount"`
 }
+
+// CatalogExportItem represents a catalog item in the JSON export (no base64 image payload)
+type CatalogExportItem struct {
+	ID                 int    `json:"id"`
+	DesignAssetID      int    `json:"designAssetId"`
+	SKU                string `json:"sku"`
+	Code               string `json:"code"`
+	ColorPrimary       string `json:"colorPrimary"`
+	ColorPrimaryName   string `json:"colorPrimaryName"`
+	ColorSecondary     string `json:"colorSecondary"`
+	ColorSecondaryName string `json:"colorSecondaryName"`
+	HoodieType         string `json:"hoodieType"`
+	HoodieTypeName     string `json:"hoodieTypeName"`
+	AvailableQty       int    `json:"availableQty"`
+	IsCustom           bool   `json:"isCustom"`
+	ImageUrlThumb      string `json:"imageUrlThumb"`
+	ImageUrlMedium     string `json:"imageUrlMedium"`
+}
+
+// CatalogExportSize represents all exported items of a single size with its catalog prices
+// Prices come from the BUSOS pricebook and are omitted when the pricing engine is not available
+type CatalogExportSize struct {
+	Size           string              `json:"size"`
+	RetailPrice    *int64              `json:"retailPrice,omitempty"`
+	WholesalePrice *int64              `json:"wholesalePrice,omitempty"`
+	ItemCount      int                 `json:"itemCount"`
+	Items          []CatalogExportItem `json:"items"`
+}
+
+// CatalogPageLink represents a download link for a generated catalog page
+type CatalogPageLink struct {
+	Page     int    `json:"page"`
+	URL      string `json:"url"`
+	Filename string `json:"filename"`
+}
+
+// Catalog job statuses
+const (
+	CatalogJobPending = "pending"
+	CatalogJobRunning = "running"
+	CatalogJobDone    = "done"
+	CatalogJobFailed  = "failed"
+)
+
+// CatalogJob represents an asynchronous PDF/PNG catalog generation job
+// Links are only set once Status is "done"
+type CatalogJob struct {
+	ID         string            `json:"jobId"`
+	Status     string            `json:"status"`
+	Size       string            `json:"size"`
+	Format     string            `json:"format"`
+	Error      string            `json:"error,omitempty"`
+	Links      []CatalogPageLink `json:"links,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	FinishedAt *time.Time        `json:"finishedAt,omitempty"`
+}
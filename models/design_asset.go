@@ -4,7 +4,36 @@ package models
 type DesignAsset struct {
 	DriveFileID string `json:"driveFileId"`
 	ImageURL    string `json:"imageUrl"`
+	// Source metadata reported by Drive (used for size validation on sync)
+	SizeBytes int64 `json:"-"`
+	Width     int   `json:"-"`
+	Height    int   `json:"-"`
+	// FileName is the original filename in Drive (used for auto-tagging)
+	FileName string `json:"-"`
+	// Note is a per-file sync note (e.g. why the original was rejected)
+	Note string `json:"note,omitempty"`
 }
 
 
 
+
+// SetImageRotationRequest represents the request body to store the manual rotation of an asset's images
+// Example: {"rotation": 90} (0 clears it)
+type SetImageRotationRequest struct {
+	Rotation int `json:"rotation"`
+}
+
+// ImageRotationResponse represents the stored rotation and the cached sizes it invalidated
+// Example: {"assetId": 45, "rotation": 90, "removed": 2}
+type ImageRotationResponse struct {
+	AssetID  int `json:"assetId"`
+	Rotation int `json:"rotation"`
+	Removed  int `json:"removed"`
+}
+
+// ImageCacheClearResponse represents the result of invalidating optimized image cache files
+// Example: {"assetId": 45, "removed": 2} or {"removed": 120} for a full clear
+type ImageCacheClearResponse struct {
+	AssetID int `json:"assetId,omitempty"`
+	Removed int `json:"removed"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// AutoTagFields represents the design attributes parsed from a pending asset's filename or code
+type AutoTagFields struct {
+	ColorPrimary   string `json:"colorPrimary,omitempty"`
+	ColorSecondary string `json:"colorSecondary,omitempty"`
+	HoodieType     string `json:"hoodieType,omitempty"`
+	ImageType      string `json:"imageType,omitempty"`
+	DecoBase       string `json:"decoBase,omitempty"`
+}
+
+// IsEmpty reports whether no attribute was parsed
+func (f AutoTagFields) IsEmpty() bool {
+	return f.ColorPrimary == "" && f.ColorSecondary == "" && f.HoodieType == "" && f.ImageType == "" && f.DecoBase == ""
+}
+
+// AutoTagResult represents the outcome of auto-tagging a single pending asset
+// Status is one of: tagged, would-tag (dry run), unmatched, failed
+type AutoTagResult struct {
+	ID             int            `json:"id"`
+	SourceFilename string         `json:"sourceFilename,omitempty"`
+	Rule           string         `json:"rule,omitempty"`
+	Parsed         *AutoTagFields `json:"parsed,omitempty"`
+	Status         string         `json:"status"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// AutoTagResponse represents the response for POST /admin/design-assets/auto-tag
+// Example response:
+// {
+//   "dryRun": true,
+//   "scanned": 2,
+//   "matched": 1,
+//   "tagged": 0,
+//   "results": [
+//     {
+//       "id": 41,
+//       "sourceFilename": "RO_NG-BE-IT0041-C.png",
+//       "rule": "naming-convention",
+//       "parsed": { "colorPrimary": "RO", "colorSecondary": "NG", "hoodieType": "BE", "imageType": "IT", "decoBase": "C" },
+//       "status": "would-tag"
+//     },
+//     { "id": 42, "sourceFilename": "foto final.png", "status": "unmatched" }
+//   ]
+// }
+type AutoTagResponse struct {
+	DryRun  bool            `json:"dryRun"`
+	Scanned int             `json:"scanned"`
+	Matched int             `json:"matched"`
+	Tagged  int             `json:"tagged"`
+	Results []AutoTagResult `json:"results"`
+}
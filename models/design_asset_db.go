@@ -15,6 +15,12 @@ type DesignAssetDB struct {
 	CreatedAt      string // RFC3339 format from Google Drive
 	IsActive       bool
 	HasHiglights   bool
+	// ImageRejectedReason is set when the original image exceeds the source limits
+	ImageRejectedReason string
+	// SourceFilename is the original filename in Drive
+	SourceFilename string
+	// ImageHash is the SHA-256 (hex) of the original image, when it could be downloaded during sync
+	ImageHash string
 }
 
 
@@ -1,9 +1,16 @@
 package models
 
 // DesignAssetUpdateRequest represents the request body for updating a design asset
+// Classification fields are codes (e.g. "NG", "BE", "MnSML", "C"); empty values keep the current value
+// Example: {"description": "Buso negro", "hasHighlights": true, "colorPrimary": "NG", "hoodieType": "BE"}
 type DesignAssetUpdateRequest struct {
-	Description  string `json:"description"`
-	HasHighlights bool  `json:"hasHighlights"`
+	Description    string `json:"description"`
+	HasHighlights  bool   `json:"hasHighlights"`
+	ColorPrimary   string `json:"colorPrimary,omitempty"`
+	ColorSecondary string `json:"colorSecondary,omitempty"`
+	HoodieType     string `json:"hoodieType,omitempty"`
+	ImageType      string `json:"imageType,omitempty"`
+	DecoBase       string `json:"decoBase,omitempty"`
 }
 
 // DesignAssetDetail represents a design asset with all details for editing
@@ -21,6 +28,12 @@ type DesignAssetDetail struct {
 	DecoBase       string `json:"decoBase"`
 	IsActive       bool   `json:"isActive"`
 	HasHighlights  bool   `json:"hasHighlights"`
+	// ImageRejectedReason is set when the original image was rejected for exceeding source limits
+	ImageRejectedReason string `json:"imageRejectedReason,omitempty"`
+	// SourceFilename is the original filename in Drive, when known
+	SourceFilename string `json:"sourceFilename,omitempty"`
+	// ImageRotation is the manual clockwise rotation applied to the optimized images, when set
+	ImageRotation int `json:"imageRotation,omitempty"`
 }
 
 // DesignAssetDetailWithOptimizedURL extends DesignAssetDetail with optimized image URL
@@ -30,3 +43,81 @@ type DesignAssetDetailWithOptimizedURL struct {
 }
 
 
+
+// SetDesignAssetActiveRequest represents the request body for activating or deactivating a design asset
+// Example: {"isActive": false}
+type SetDesignAssetActiveRequest struct {
+	IsActive bool `json:"isActive"`
+}
+
+// BulkUpdateDesignAssetStatusRequest represents the request body for PATCH /admin/design-assets/status
+// Assets can be referenced by code, by id, or both. status must be pending, ready, custom-pending or custom-ready
+// Example: {"codes": ["ABC123", "DEF456"], "ids": [45], "status": "ready"}
+type BulkUpdateDesignAssetStatusRequest struct {
+	Codes  []string `json:"codes"`
+	IDs    []int    `json:"ids"`
+	Status string   `json:"status"`
+}
+
+// BulkUpdateDesignAssetStatusResponse represents the result of a bulk status update
+// Assets already in the target status count as requested but not as updated
+// Example: {"status": "ready", "requested": 3, "updated": 2}
+type BulkUpdateDesignAssetStatusResponse struct {
+	Status    string `json:"status"`
+	Requested int    `json:"requested"`
+	Updated   int    `json:"updated"`
+}
+
+// PublishDesignAssetItem is one size to create when publishing a design asset
+type PublishDesignAssetItem struct {
+	Size       string `json:"size"`       // required, one of XS, S, M, L, XL, MN, IT
+	Price      int64  `json:"price"`      // required, must be > 0
+	StockTotal int    `json:"stockTotal"` // optional, defaults to 0
+}
+
+// PublishDesignAssetRequest represents the request body for POST /admin/design-assets/:id/publish
+// Example: {"items": [{"size": "M", "price": 12000, "stockTotal": 3}, {"size": "L", "price": 13000}]}
+type PublishDesignAssetRequest struct {
+	Items []PublishDesignAssetItem `json:"items"`
+}
+
+// PublishDesignAssetResponse represents the result of publishing a design asset
+// Sizes that already had an item are reported in skipped and left untouched
+// Example: {"designAssetId": 45, "status": "ready", "created": [...], "skipped": ["L"]}
+type PublishDesignAssetResponse struct {
+	DesignAssetID int      `json:"designAssetId"`
+	Status        string   `json:"status"`
+	Created       []Item   `json:"created"`
+	Skipped       []string `json:"skipped"`
+}
+
+// DesignAssetDeleteConflictResponse is returned when a design asset cannot be deleted because items reference it
+// Example: {"error": "design asset is referenced by items", "code": "ABC123", "itemCount": 3}
+type DesignAssetDeleteConflictResponse struct {
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	ItemCount int    `json:"itemCount"`
+}
+
+// PendingDesignAssetsResponse represents a page of pending design assets, oldest first
+// Example: {"assets": [...], "totalPending": 42, "limit": 10, "offset": 0}
+type PendingDesignAssetsResponse struct {
+	Assets       []DesignAssetDetailWithOptimizedURL `json:"assets"`
+	TotalPending int                                 `json:"totalPending"` // Pending assets matching the filter, across all pages
+	Limit        int                                 `json:"limit"`
+	Offset       int                                 `json:"offset"`
+}
+
+// DesignAssetDuplicateGroup groups design assets whose original images share the same hash
+type DesignAssetDuplicateGroup struct {
+	ImageHash string                              `json:"imageHash"`
+	Count     int                                 `json:"count"`
+	Assets    []DesignAssetDetailWithOptimizedURL `json:"assets"` // Oldest first
+}
+
+// DesignAssetDuplicatesResponse represents the response of GET /admin/design-assets/duplicates
+// Example: {"groups": [{"imageHash": "9f86d0...", "count": 2, "assets": [...]}], "totalGroups": 1}
+type DesignAssetDuplicatesResponse struct {
+	Groups      []DesignAssetDuplicateGroup `json:"groups"`
+	TotalGroups int                         `json:"totalGroups"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// FinanceBudget represents the expense limit of a finance category for one month
+// Example: {"id": 1, "category": "publicidad", "month": "2026-02", "limitAmount": 300000}
+type FinanceBudget struct {
+	ID          int64  `json:"id"`
+	Category    string `json:"category"`
+	Month       string `json:"month"` // YYYY-MM
+	LimitAmount int64  `json:"limitAmount"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// FinanceBudgetRequest represents the request body for creating or replacing a budget
+// Example: {"category": "publicidad", "month": "2026-02", "limitAmount": 300000}
+type FinanceBudgetRequest struct {
+	Category    string `json:"category"`    // required
+	Month       string `json:"month"`       // required, YYYY-MM
+	LimitAmount int64  `json:"limitAmount"` // required, must be > 0
+}
+
+// FinanceBudgetListResponse represents the response for listing budgets
+type FinanceBudgetListResponse struct {
+	Budgets []FinanceBudget `json:"budgets"`
+}
+
+// BudgetCategoryStatus compares a category's actual expense for the month against its budget
+// PercentUsed is actual/limit*100; OverBudget is true above 100%
+type BudgetCategoryStatus struct {
+	BudgetID         int64   `json:"budgetId"`
+	Category         string  `json:"category"`
+	LimitAmount      int64   `json:"limitAmount"`
+	ActualExpense    int64   `json:"actualExpense"`
+	Remaining        int64   `json:"remaining"` // negative when over budget
+	PercentUsed      float64 `json:"percentUsed"`
+	OverBudget       bool    `json:"overBudget"`
+	TransactionCount int     `json:"transactionCount"`
+}
+
+// FinanceBudgetStatusResponse represents the budget vs actual comparison for one month
+// Unbudgeted lists expense categories of the month that have no budget
+// Example: {"currency": "COP", "month": "2026-02", "totalLimit": 500000, "totalActual": 620000, "totalRemaining": -120000, "overBudgetCount": 1, "categories": [...], "unbudgeted": [...]}
+type FinanceBudgetStatusResponse struct {
+	Currency        string                 `json:"currency"`
+	Month           string                 `json:"month"` // YYYY-MM
+	TotalLimit      int64                  `json:"totalLimit"`
+	TotalActual     int64                  `json:"totalActual"`
+	TotalRemaining  int64                  `json:"totalRemaining"`
+	OverBudgetCount int                    `json:"overBudgetCount"`
+	Categories      []BudgetCategoryStatus `json:"categories"`
+	Unbudgeted      []CategoryAmount       `json:"unbudgeted"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// FinanceImportRow is one parsed row of a finance transaction CSV import
+// A row with Error set could not be parsed and is rejected without further validation
+type FinanceImportRow struct {
+	Line    int                             `json:"line"` // Line number in the CSV file (header is line 1)
+	Request CreateFinanceTransactionRequest `json:"request"`
+	Error   string                          `json:"error,omitempty"`
+}
+
+// FinanceImportRowResult reports what happened to one CSV row
+type FinanceImportRowResult struct {
+	Line          int    `json:"line"`
+	Status        string `json:"status"` // "imported", "rejected" or "skipped" (valid, but another row was rejected)
+	TransactionID *int64 `json:"transactionId,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// FinanceImportResponse represents the result of a finance transaction CSV import
+// Without allowPartial a single rejected row imports nothing: imported is 0 and valid rows are "skipped"
+// Example response with allowPartial:
+// {
+//   "imported": 2,
+//   "rejected": 1,
+//   "rows": [
+//     { "line": 2, "status": "imported", "transactionId": 120 },
+//     { "line": 3, "status": "rejected", "error": "amount must be greater than 0" },
+//     { "line": 4, "status": "imported", "transactionId": 121 }
+//   ]
+// }
+type FinanceImportResponse struct {
+	Imported int                      `json:"imported"`
+	Rejected int                      `json:"rejected"`
+	Rows     []FinanceImportRowResult `json:"rows"`
+}
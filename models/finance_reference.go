@@ -0,0 +1,22 @@
+package models
+
+// FinanceReferenceValue represents a managed destination or category name
+// Example: {"id": 1, "name": "Nequi", "createdAt": "2026-01-04T15:20:00Z", "updatedAt": "2026-01-04T15:20:00Z"}
+type FinanceReferenceValue struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// FinanceReferenceValueRequest represents the request body for creating or renaming a destination or category
+// Example: {"name": "Nequi"}
+type FinanceReferenceValueRequest struct {
+	Name string `json:"name"` // required, unique (case-insensitive)
+}
+
+// FinanceReferenceListResponse represents the response for listing destinations or categories
+// Example: {"values": [{"id": 1, "name": "Caja", ...}, {"id": 2, "name": "Nequi", ...}]}
+type FinanceReferenceListResponse struct {
+	Values []FinanceReferenceValue `json:"values"`
+}
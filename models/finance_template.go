@@ -0,0 +1,31 @@
+package models
+
+// FinanceTemplate represents a reusable preset for manual finance entries
+// Example: {"id": 1, "label": "Compra telas", "type": "expense", "category": "materiales", "notes": "compra telas"}
+type FinanceTemplate struct {
+	ID           int64  `json:"id"`
+	Label        string `json:"label"`
+	Type         string `json:"type,omitempty"` // 'income' or 'expense'
+	Category     string `json:"category,omitempty"`
+	Counterparty string `json:"counterparty,omitempty"`
+	Destination  string `json:"destination,omitempty"`
+	Notes        string `json:"notes,omitempty"`
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+// FinanceTemplateRequest represents the request body for creating or replacing a finance template
+// Example: {"label": "Compra telas", "type": "expense", "category": "materiales", "counterparty": "Proveedor telas", "destination": "Caja", "notes": "compra telas"}
+type FinanceTemplateRequest struct {
+	Label        string `json:"label"`                  // required, unique
+	Type         string `json:"type,omitempty"`         // optional, 'income' or 'expense'
+	Category     string `json:"category,omitempty"`     // optional
+	Counterparty string `json:"counterparty,omitempty"` // optional
+	Destination  string `json:"destination,omitempty"`  // optional
+	Notes        string `json:"notes,omitempty"`        // optional
+}
+
+// FinanceTemplateListResponse represents the response for listing finance templates
+type FinanceTemplateListResponse struct {
+	Templates []FinanceTemplate `json:"templates"`
+}
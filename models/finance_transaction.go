@@ -13,6 +13,11 @@ type FinanceTransaction struct {
 	Counterparty string `json:"counterparty,omitempty"`
 	Notes       string `json:"notes,omitempty"`
 	CreatedAt   string `json:"createdAt"`
+	// Template applied on creation (only returned by Create when templateId was sent)
+	AppliedTemplateID    *int64 `json:"appliedTemplateId,omitempty"`
+	AppliedTemplateLabel string `json:"appliedTemplateLabel,omitempty"`
+	// Shared by both legs of a transfer between destinations
+	TransferGroupID *int64 `json:"transferGroupId,omitempty"`
 }
 
 // CreateFinanceTransactionRequest represents the request body for creating a finance transaction
@@ -32,6 +37,40 @@ type CreateFinanceTransactionRequest struct {
 	Counterparty string `json:"counterparty,omitempty"` // optional
 	Notes       string `json:"notes,omitempty"`       // optional
 	OccurredAt  string `json:"occurredAt,omitempty"`  // optional, defaults to now
+	TemplateID  *int64 `json:"templateId,omitempty"`  // optional, pre-fills empty fields from a finance template
+	AllowFreeText bool `json:"allowFreeText,omitempty"` // optional, skips the managed destination/category check
+}
+
+// TransferRequest represents the request body for moving money between two destinations
+// Example: {"fromDestination": "Nequi", "toDestination": "Caja", "amount": 50000, "notes": "Retiro efectivo"}
+type TransferRequest struct {
+	FromDestination string `json:"fromDestination"`      // required
+	ToDestination   string `json:"toDestination"`        // required, different from fromDestination
+	Amount          int64  `json:"amount"`               // required, must be > 0
+	OccurredAt      string `json:"occurredAt,omitempty"` // optional RFC3339, defaults to now
+	Notes           string `json:"notes,omitempty"`      // optional
+}
+
+// TransferResponse represents the two linked transactions created by a transfer
+type TransferResponse struct {
+	TransferGroupID int64              `json:"transferGroupId"`
+	Expense         FinanceTransaction `json:"expense"` // leg on fromDestination
+	Income          FinanceTransaction `json:"income"`  // leg on toDestination
+}
+
+// UpdateFinanceTransactionRequest represents the request body for editing a manual finance transaction
+// Omitted fields are left unchanged; an empty category, counterparty or notes clears it.
+// type cannot be changed: when sent it must match the stored type
+// Example: {"amount": 54000, "category": "materiales", "notes": "Franela 12m"}
+type UpdateFinanceTransactionRequest struct {
+	Type         *string `json:"type,omitempty"`
+	Amount       *int64  `json:"amount,omitempty"`
+	Destination  *string `json:"destination,omitempty"`
+	Category     *string `json:"category,omitempty"`
+	Counterparty *string `json:"counterparty,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+	OccurredAt   *string `json:"occurredAt,omitempty"` // RFC3339
+	AllowFreeText bool   `json:"allowFreeText,omitempty"` // skips the managed destination/category check
 }
 
 // FinanceTransactionListRequest represents query parameters for listing transactions
@@ -43,8 +82,11 @@ type FinanceTransactionListRequest struct {
 	Destination *string `json:"destination,omitempty"` // account name
 	Category   *string `json:"category,omitempty"` // category name
 	Q          *string `json:"q,omitempty"`         // text search in notes and counterparty
+	MinAmount  *int64  `json:"minAmount,omitempty"` // amount >= minAmount
+	MaxAmount  *int64  `json:"maxAmount,omitempty"` // amount <= maxAmount
 	Limit      int     `json:"limit,omitempty"`     // default 50, max 200
 	Cursor     *string `json:"cursor,omitempty"`    // pagination cursor
+	ExcludeTransfers bool `json:"excludeTransfers,omitempty"` // hide transfer-category rows
 }
 
 // FinanceTransactionListResponse represents the response for listing transactions
@@ -66,6 +108,7 @@ type FinanceSummaryResponse struct {
 	ByDestinationAllTime []DestinationBalance    `json:"byDestinationAllTime"`
 	Range              *SummaryRange             `json:"range,omitempty"`
 	ByDestinationRange []DestinationRangeBalance `json:"byDestinationRange,omitempty"`
+	ExcludedCategories []string                  `json:"excludedCategories"` // Categories left out of range income/expense/net
 }
 
 // DestinationBalance represents balance for a destination
@@ -99,6 +142,7 @@ type FinanceDashboardRequest struct {
 	From        *string `json:"from,omitempty"`         // YYYY-MM-DD
 	To          *string `json:"to,omitempty"`           // YYYY-MM-DD
 	CompareWith *string `json:"compareWith,omitempty"`  // 'previous', 'last_year'
+	ExcludeCategories []string `json:"excludeCategories,omitempty"` // categories left out of income/expense metrics
 }
 
 // FinanceDashboardResponse represents the dashboard response
@@ -114,6 +158,7 @@ type FinanceDashboardResponse struct {
 	TopTransactions TopTransactions `json:"topTransactions"`
 	KPIs          KPIs            `json:"kpis"`
 	Trends        Trends          `json:"trends"`
+	ExcludedCategories []string   `json:"excludedCategories"` // Categories left out of income/expense metrics
 }
 
 // PeriodInfo represents period information
@@ -257,3 +302,70 @@ type Trends struct {
 	ProfitMarginTrend string `json:"profitMarginTrend"`  // 'improving', 'declining', 'stable'
 }
 
+
+// FinanceIntegrityViolation represents a single failed invariant found by the integrity check
+// check values: "balance_by_destination", "sale_amount_paid"
+type FinanceIntegrityViolation struct {
+	Check      string `json:"check"`
+	SaleID     *int64 `json:"saleId,omitempty"`
+	Expected   int64  `json:"expected"`
+	Actual     int64  `json:"actual"`
+	Difference int64  `json:"difference"` // actual - expected
+	Message    string `json:"message"`
+}
+
+// FinanceIntegrityResponse represents the response of the finance integrity check
+// Example response:
+// {
+//   "consistent": false,
+//   "balanceAllTime": 150000,
+//   "sumByDestinationAllTime": 150000,
+//   "salesChecked": 42,
+//   "violations": [
+//     {
+//       "check": "sale_amount_paid",
+//       "saleId": 10,
+//       "expected": 99999,
+//       "actual": 100000,
+//       "difference": 1,
+//       "message": "amount_paid 100000 differs from sum of frozen line totals 99999"
+//     }
+//   ]
+// }
+type FinanceIntegrityResponse struct {
+	Consistent              bool                        `json:"consistent"`
+	BalanceAllTime          int64                       `json:"balanceAllTime"`
+	SumByDestinationAllTime int64                       `json:"sumByDestinationAllTime"`
+	SalesChecked            int                         `json:"salesChecked"`
+	Violations              []FinanceIntegrityViolation `json:"violations"`
+}
+
+// DestinationLedgerEntry represents a transaction in a destination ledger with the balance after it
+type DestinationLedgerEntry struct {
+	FinanceTransaction
+	RunningBalance int64 `json:"runningBalance"`
+}
+
+// DestinationLedgerResponse represents the ledger of a single destination (account)
+// Example response:
+// {
+//   "destination": "Nequi",
+//   "currency": "COP",
+//   "from": "2026-01-01",
+//   "to": "2026-01-31",
+//   "openingBalance": 50000,
+//   "closingBalance": 130000,
+//   "entries": [
+//     { "id": 7, "type": "income", "source": "sale", "sourceId": 10, "occurredAt": "2026-01-04T10:30:00Z", "amount": 100000, "destination": "Nequi", "createdAt": "2026-01-04T10:30:00Z", "runningBalance": 150000 },
+//     { "id": 8, "type": "expense", "source": "manual", "occurredAt": "2026-01-05T09:00:00Z", "amount": 20000, "destination": "Nequi", "category": "materiales", "createdAt": "2026-01-05T09:00:00Z", "runningBalance": 130000 }
+//   ]
+// }
+type DestinationLedgerResponse struct {
+	Destination    string                   `json:"destination"`
+	Currency       string                   `json:"currency"`
+	From           string                   `json:"from,omitempty"`
+	To             string                   `json:"to,omitempty"`
+	OpeningBalance int64                    `json:"openingBalance"`
+	ClosingBalance int64                    `json:"closingBalance"`
+	Entries        []DestinationLedgerEntry `json:"entries"`
+}
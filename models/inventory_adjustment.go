@@ -0,0 +1,33 @@
+package models
+
+// AdjustItemStockRequest represents the request body for correcting an item's stock_total
+// Example: {"delta": -2, "reason": "conteo físico", "user": "erika"}
+type AdjustItemStockRequest struct {
+	Delta  int    `json:"delta"`  // positive or negative, cannot be 0
+	Reason string `json:"reason"` // required
+	User   string `json:"user"`   // required
+}
+
+// InventoryAdjustment represents one audited stock correction
+type InventoryAdjustment struct {
+	ID               int64  `json:"id"`
+	ItemID           int64  `json:"itemId"`
+	Delta            int    `json:"delta"`
+	StockTotalBefore int    `json:"stockTotalBefore"`
+	StockTotalAfter  int    `json:"stockTotalAfter"`
+	Reason           string `json:"reason"`
+	User             string `json:"user"`
+	CreatedAt        string `json:"createdAt"`
+}
+
+// AdjustItemStockResponse represents the response of POST /admin/items/:id/adjust
+type AdjustItemStockResponse struct {
+	Item       *Item               `json:"item"`
+	Adjustment InventoryAdjustment `json:"adjustment"`
+}
+
+// ItemAdjustmentsResponse represents the adjustment history of an item, newest first
+type ItemAdjustmentsResponse struct {
+	ItemID      int64                 `json:"itemId"`
+	Adjustments []InventoryAdjustment `json:"adjustments"`
+}
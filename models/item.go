@@ -43,3 +43,136 @@ type ItemCard struct {
 	ImageUrl      string `json:"imageUrl"`
 }
 
+
+// LowStockItem represents an active item whose available stock is at or below the low-stock threshold
+type LowStockItem struct {
+	ID             int64  `json:"id"`
+	SKU            string `json:"sku"`
+	Size           string `json:"size"`
+	StockTotal     int    `json:"stockTotal"`
+	StockReserved  int    `json:"stockReserved"`
+	Available      int    `json:"available"` // stockTotal - stockReserved
+	DesignAssetID  int64  `json:"designAssetId"`
+	Description    string `json:"description"`
+	ColorPrimary   string `json:"colorPrimary"`
+	ColorSecondary string `json:"colorSecondary"`
+	HoodieType     string `json:"hoodieType"`
+	// Readable labels
+	ColorPrimaryLabel   string `json:"colorPrimaryLabel"`
+	ColorSecondaryLabel string `json:"colorSecondaryLabel"`
+	HoodieTypeLabel     string `json:"hoodieTypeLabel"`
+	ImageUrlThumb       string `json:"imageUrlThumb"`
+}
+
+// LowStockResponse represents the low-stock items, most urgent (lowest availability) first
+type LowStockResponse struct {
+	Threshold int            `json:"threshold"`
+	Count     int            `json:"count"`
+	Items     []LowStockItem `json:"items"`
+}
+
+// ItemSearchResult represents an active item matching a search, with stock and image URLs
+type ItemSearchResult struct {
+	ID             int64  `json:"id"`
+	SKU            string `json:"sku"`
+	Size           string `json:"size"`
+	Price          int64  `json:"price"`
+	StockTotal     int    `json:"stockTotal"`
+	StockReserved  int    `json:"stockReserved"`
+	StockAvailable int    `json:"stockAvailable"`
+	DesignAssetID  int64  `json:"designAssetId"`
+	Description    string `json:"description"`
+	ColorPrimary   string `json:"colorPrimary"`
+	ColorSecondary string `json:"colorSecondary"`
+	HoodieType     string `json:"hoodieType"`
+	// Readable labels
+	ColorPrimaryLabel   string `json:"colorPrimaryLabel"`
+	ColorSecondaryLabel string `json:"colorSecondaryLabel"`
+	HoodieTypeLabel     string `json:"hoodieTypeLabel"`
+	ImageUrlThumb       string `json:"imageUrlThumb"`
+	ImageUrlMedium      string `json:"imageUrlMedium"`
+}
+
+// ItemSearchResponse represents the response for GET /admin/items/search?q=...
+// Example: {"query": "negro", "count": 1, "items": [{"id": 12, "sku": "M_ABC123", "size": "M", "stockAvailable": 2, ...}]}
+type ItemSearchResponse struct {
+	Query string             `json:"query"`
+	Count int                `json:"count"`
+	Items []ItemSearchResult `json:"items"`
+}
+
+// CreateItemRequest represents the request body for creating an item
+// Example: {"designAssetId": 45, "size": "M", "price": 12000, "stockTotal": 3}
+// sku is optional and defaults to "<size>_<design asset code>"
+type CreateItemRequest struct {
+	DesignAssetID int64  `json:"designAssetId"` // required, must exist
+	Size          string `json:"size"`          // required, one of XS, S, M, L, XL, MN, IT
+	SKU           string `json:"sku,omitempty"` // optional
+	Price         int64  `json:"price"`         // required, must be > 0
+	StockTotal    int    `json:"stockTotal"`    // optional, defaults to 0
+}
+
+// UpdateItemRequest represents the request body for editing an item
+// Only provided fields are updated
+// Example: {"price": 13000, "stockTotal": 5}
+type UpdateItemRequest struct {
+	SKU        *string `json:"sku,omitempty"`
+	Price      *int64  `json:"price,omitempty"`      // must be > 0
+	StockTotal *int    `json:"stockTotal,omitempty"` // cannot be lower than stockReserved
+}
+
+// SetItemActiveRequest represents the request body for activating or deactivating an item
+// Example: {"isActive": false}
+type SetItemActiveRequest struct {
+	IsActive bool `json:"isActive"`
+}
+
+// UpdateItemDesignAssetRequest represents the request body for relinking an item to another design asset
+// Example: {"designAssetId": 45, "recompute": true}
+type UpdateItemDesignAssetRequest struct {
+	DesignAssetID int64 `json:"designAssetId"`
+	Recompute     bool  `json:"recompute"` // optional; true recalculates sku and price from the new design asset
+}
+
+// ItemAvailabilityLine represents one requested item in an availability check
+// Example: {"itemId": 123, "qty": 10}
+type ItemAvailabilityLine struct {
+	ItemID int64 `json:"itemId"`
+	Qty    int   `json:"qty"`
+}
+
+// ItemAvailability represents whether the requested quantity of an item can be reserved right now.
+// Lines repeating the same item are summed into requestedQty
+type ItemAvailability struct {
+	ItemID       int64 `json:"itemId"`
+	RequestedQty int   `json:"requestedQty"`
+	Available    int   `json:"available"` // stockTotal - stockReserved (0 when the item is missing or inactive)
+	IsAvailable  bool  `json:"isAvailable"`
+	Found        bool  `json:"found"`
+	IsActive     bool  `json:"isActive"`
+}
+
+// ItemAvailabilityResponse represents the result of a dry-run stock check
+type ItemAvailabilityResponse struct {
+	AllAvailable bool               `json:"allAvailable"`
+	Items        []ItemAvailability `json:"items"`
+}
+
+// DesignAssetItem represents one SKU (size) generated from a design asset
+type DesignAssetItem struct {
+	ID             int64  `json:"id"`
+	SKU            string `json:"sku"`
+	Size           string `json:"size"`
+	Price          int64  `json:"price"`
+	StockTotal     int    `json:"stockTotal"`
+	StockReserved  int    `json:"stockReserved"`
+	StockAvailable int    `json:"stockAvailable"` // max(0, stockTotal - stockReserved)
+	IsActive       bool   `json:"isActive"`
+}
+
+// DesignAssetItemsResponse represents the full size run of a design asset, ordered MN, IT, XS, S, M, L, XL
+type DesignAssetItemsResponse struct {
+	DesignAssetID int               `json:"designAssetId"`
+	Count         int               `json:"count"`
+	Items         []DesignAssetItem `json:"items"`
+}
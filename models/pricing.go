@@ -10,6 +10,7 @@ type PricingLine struct {
 	UnitPrice   int64    `json:"unitPrice"`   // Unit price applied (retail or wholesale)
 	LineTotal   int64    `json:"lineTotal"`   // Total for this line
 	RuleIDs     []string `json:"ruleIds"`     // IDs of rules applied to this line
+	Explanation string   `json:"explanation"` // Human-readable reason for the price (base retail, bundle, wholesale)
 }
 
 // PricingBreakdown represents the complete pricing calculation result
@@ -18,5 +19,74 @@ type PricingBreakdown struct {
 	Lines       []PricingLine `json:"lines"`       // Pricing breakdown per line
 	AppliedRules []string     `json:"appliedRules"` // List of rule IDs applied
 	OrderType   string        `json:"orderType"`   // Calculated order type: "mayorista" or "detal"
+	Coupon      *PricingCoupon `json:"coupon,omitempty"` // Coupon requested for this calculation (if any)
 }
 
+// PricingCoupon reports how a coupon code was resolved. Invalid or expired codes never fail the
+// calculation: they are returned with applied=false and the reason they were ignored.
+// Example: {"code": "VERANO10", "applied": true, "discount": 5000}
+// Example: {"code": "VIEJO", "applied": false, "reason": "coupon VIEJO expired on 2026-01-31"}
+type PricingCoupon struct {
+	Code     string `json:"code"`
+	Applied  bool   `json:"applied"`
+	Discount int64  `json:"discount"`         // Amount taken off the total (0 when not applied)
+	Reason   string `json:"reason,omitempty"` // Why the coupon was ignored
+}
+
+// PricingPreviewLine represents one cart entry in a pricing preview request
+// Example: [{"itemId": 12, "qty": 3}, {"itemId": 40, "qty": 2}]
+type PricingPreviewLine struct {
+	ItemID int64 `json:"itemId"`
+	Qty    int   `json:"qty"`
+}
+
+// PricingPreviewRequest represents a pricing preview request with an optional coupon code
+// Example: {"lines": [{"itemId": 12, "qty": 3}], "couponCode": "VERANO10"}
+type PricingPreviewRequest struct {
+	Lines      []PricingPreviewLine `json:"lines"`
+	CouponCode string               `json:"couponCode,omitempty"`
+}
+
+// PricingReloadResponse represents the active pricing config after a hot reload
+type PricingReloadResponse struct {
+	Currency  string `json:"currency"`
+	RuleCount int    `json:"ruleCount"`
+}
+
+// PricingUnmappedCombination represents a (hoodie type, size) pair present in active items that
+// the pricing config does not cover, so its items are priced with hardcoded fallback prices
+type PricingUnmappedCombination struct {
+	HoodieType    string `json:"hoodieType"`
+	Size          string `json:"size"`
+	Group         string `json:"group,omitempty"` // Empty when the hoodie type is in no group
+	SizeBucket    string `json:"sizeBucket"`
+	HasSizeBucket bool   `json:"hasSizeBucket"` // False when the size is missing from sizeBuckets
+	Reason        string `json:"reason"`
+	ItemCount     int    `json:"itemCount"` // Active items with this hoodie type and size
+}
+
+// PricingValidationResponse represents the result of validating the pricing config against active items
+// Example: GET /admin/pricing/validate
+// {"valid": false, "checkedCombinations": 12, "unmapped": [{"hoodieType": "PO", "size": "XL", "sizeBucket": "XL", "hasSizeBucket": false, "reason": "hoodie type PO does not belong to any pricing group", "itemCount": 3}]}
+type PricingValidationResponse struct {
+	Valid               bool                         `json:"valid"`
+	CheckedCombinations int                          `json:"checkedCombinations"`
+	Unmapped            []PricingUnmappedCombination `json:"unmapped"`
+}
+
+// ItemPriceQuote represents the price of a single item at a given quantity, without creating an order
+type ItemPriceQuote struct {
+	ItemID             int64            `json:"itemId"`
+	SKU                string           `json:"sku"`
+	Size               string           `json:"size"`
+	HoodieType         string           `json:"hoodieType"`
+	Qty                int              `json:"qty"`
+	OrderType          string           `json:"orderType"`          // "mayorista" or "detal" (forced or calculated)
+	EffectiveUnitPrice int64            `json:"effectiveUnitPrice"` // Total / qty, rounded down
+	Total              int64            `json:"total"`
+	QtyInBundle        int              `json:"qtyInBundle"`
+	AppliedRules       []string         `json:"appliedRules"`
+	WholesaleApplies   bool             `json:"wholesaleApplies"`
+	WholesaleMinQty    int              `json:"wholesaleMinQty,omitempty"` // Eligible units needed for wholesale pricing
+	Breakdown          PricingBreakdown `json:"breakdown"`
+}
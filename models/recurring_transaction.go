@@ -0,0 +1,46 @@
+package models
+
+// RecurringTransaction represents a finance transaction that repeats on a monthly or weekly schedule
+// Monthly recurrences use dayOfMonth (1-31, clamped to the last day of shorter months);
+// weekly recurrences use dayOfWeek (0 = Sunday ... 6 = Saturday)
+// Example: {"id": 1, "label": "Arriendo", "type": "expense", "amount": 1200000, "destination": "Bancolombia", "category": "arriendo", "frequency": "monthly", "dayOfMonth": 5, "startDate": "2026-01-01", "isActive": true, "lastGeneratedDate": "2026-02-05"}
+type RecurringTransaction struct {
+	ID                int64   `json:"id"`
+	Label             string  `json:"label"`
+	Type              string  `json:"type"` // 'income' or 'expense'
+	Amount            int64   `json:"amount"`
+	Destination       string  `json:"destination"`
+	Category          string  `json:"category,omitempty"`
+	Counterparty      string  `json:"counterparty,omitempty"`
+	Notes             string  `json:"notes,omitempty"`
+	Frequency         string  `json:"frequency"` // 'monthly' or 'weekly'
+	DayOfMonth        *int    `json:"dayOfMonth,omitempty"`
+	DayOfWeek         *int    `json:"dayOfWeek,omitempty"`
+	StartDate         string  `json:"startDate"` // YYYY-MM-DD
+	IsActive          bool    `json:"isActive"`
+	LastGeneratedDate *string `json:"lastGeneratedDate,omitempty"` // YYYY-MM-DD, last day already generated
+	CreatedAt         string  `json:"createdAt"`
+	UpdatedAt         string  `json:"updatedAt"`
+}
+
+// RecurringTransactionRequest represents the request body for creating or replacing a recurring transaction
+// Example: {"label": "Arriendo", "type": "expense", "amount": 1200000, "destination": "Bancolombia", "category": "arriendo", "frequency": "monthly", "dayOfMonth": 5}
+type RecurringTransactionRequest struct {
+	Label        string `json:"label"`                  // required
+	Type         string `json:"type"`                   // required, 'income' or 'expense'
+	Amount       int64  `json:"amount"`                 // required, must be > 0
+	Destination  string `json:"destination"`            // required
+	Category     string `json:"category,omitempty"`     // optional
+	Counterparty string `json:"counterparty,omitempty"` // optional
+	Notes        string `json:"notes,omitempty"`        // optional
+	Frequency    string `json:"frequency"`              // required, 'monthly' or 'weekly'
+	DayOfMonth   *int   `json:"dayOfMonth,omitempty"`   // required for monthly (1-31)
+	DayOfWeek    *int   `json:"dayOfWeek,omitempty"`    // required for weekly (0 = Sunday ... 6 = Saturday)
+	StartDate    string `json:"startDate,omitempty"`    // optional, YYYY-MM-DD, defaults to today
+	IsActive     *bool  `json:"isActive,omitempty"`     // optional, defaults to true
+}
+
+// RecurringTransactionListResponse represents the response for listing recurring transactions
+type RecurringTransactionListResponse struct {
+	Recurring []RecurringTransaction `json:"recurring"`
+}
@@ -0,0 +1,83 @@
+package models
+
+// GroupCoverageSize represents pricebook coverage for one size of a hoodie type
+type GroupCoverageSize struct {
+	Size              string `json:"size"`
+	SizeBucket        string `json:"sizeBucket"`
+	HasPricebookEntry bool   `json:"hasPricebookEntry"`
+	ItemCount         int    `json:"itemCount"` // Active items with this hoodie type and size
+}
+
+// GroupCoverageEntry represents the resolved pricing group for a hoodie type present in active items
+type GroupCoverageEntry struct {
+	HoodieType      string              `json:"hoodieType"`
+	HoodieTypeLabel string              `json:"hoodieTypeLabel"`
+	Group           string              `json:"group"`     // Empty when ungrouped
+	Ungrouped       bool                `json:"ungrouped"` // True when the type falls through to fallback pricing
+	ItemCount       int                 `json:"itemCount"`
+	Sizes           []GroupCoverageSize `json:"sizes"`
+}
+
+// GroupsCoverageResponse represents the pricing config vs. data coverage report
+// Example: GET /admin/reports/groups-coverage
+type GroupsCoverageResponse struct {
+	Entries            []GroupCoverageEntry `json:"entries"`
+	UngroupedTypes     int                  `json:"ungroupedTypes"`
+	SizesWithoutPrices int                  `json:"sizesWithoutPrices"` // Hoodie type + size pairs with no pricebook entry
+}
+
+// TimeToSellEntry represents sales velocity for one design: the average days between item creation
+// (and first reservation) and sale, weighted by units sold
+type TimeToSellEntry struct {
+	DesignAssetID               int64   `json:"designAssetId"`
+	Code                        string  `json:"code"`
+	Description                 string  `json:"description"`
+	ColorPrimaryLabel           string  `json:"colorPrimaryLabel"`
+	ColorSecondaryLabel         string  `json:"colorSecondaryLabel"`
+	HoodieTypeLabel             string  `json:"hoodieTypeLabel"`
+	SalesCount                  int     `json:"salesCount"` // Distinct sales containing the design
+	UnitsSold                   int     `json:"unitsSold"`
+	AvgDaysFromItemCreation     float64 `json:"avgDaysFromItemCreation"`
+	AvgDaysFromFirstReservation float64 `json:"avgDaysFromFirstReservation"`
+}
+
+// TimeToSellResponse represents the time-to-sell report. Designs without sales in the range are excluded.
+// Example: GET /admin/reports/time-to-sell?from=2026-01-01&to=2026-01-31
+type TimeToSellResponse struct {
+	From    string            `json:"from,omitempty"`
+	To      string            `json:"to,omitempty"`
+	Designs []TimeToSellEntry `json:"designs"`
+}
+
+// InventoryStockSplit represents on-hand stock split into units held by open carts and free units
+type InventoryStockSplit struct {
+	ItemCount          int     `json:"itemCount"`          // Active items
+	OnHand             int     `json:"onHand"`             // Sum of stock_total
+	Reserved           int     `json:"reserved"`           // Sum of stock_reserved
+	Available          int     `json:"available"`          // Sum of stock_total - stock_reserved (never negative per item)
+	ReservedPercent    float64 `json:"reservedPercent"`    // reserved / onHand * 100
+	FullyReservedItems int     `json:"fullyReservedItems"` // Items with stock all held by carts (reserved > 0, available == 0)
+}
+
+// InventorySizeSplit represents the stock split for one size
+type InventorySizeSplit struct {
+	Size string `json:"size"`
+	InventoryStockSplit
+}
+
+// InventoryGroupSplit represents the stock split for one pricing group (hoodie types grouped as in pricing)
+type InventoryGroupSplit struct {
+	Group       string   `json:"group"`     // Empty when ungrouped
+	Ungrouped   bool     `json:"ungrouped"` // Hoodie types in no pricing group (or pricing engine unavailable)
+	HoodieTypes []string `json:"hoodieTypes"`
+	InventoryStockSplit
+}
+
+// InventoryDashboardResponse represents the reserved vs available inventory health dashboard
+// Example: GET /admin/inventory/dashboard
+type InventoryDashboardResponse struct {
+	InventoryStockSplit
+	BySize   []InventorySizeSplit  `json:"bySize"`  // Ordered MN, IT, XS, S, M, L, XL
+	ByGroup  []InventoryGroupSplit `json:"byGroup"` // Ordered by group, ungrouped last
+	Warnings []string              `json:"warnings,omitempty"`
+}
@@ -6,9 +6,14 @@ type ReservedOrder struct {
 	Status       string `json:"status"` // reserved, completed, canceled
 	AssignedTo   string `json:"assignedTo"`
 	OrderType    string `json:"orderType"`
+	Priority     string `json:"priority"` // normal, high
 	CustomerName string `json:"customerName,omitempty"`
 	CustomerPhone string `json:"customerPhone,omitempty"`
 	Notes        string `json:"notes,omitempty"`
+	DiscountType  string `json:"discountType,omitempty"`  // none, flat, percent
+	DiscountValue int64  `json:"discountValue,omitempty"` // Amount for flat, percentage (0-99) for percent
+	CouponCode    string `json:"couponCode,omitempty"`    // Promotion code from the pricing config
+	HoldUntil     *string `json:"holdUntil,omitempty"`    // When the stock hold expires and the order is auto-canceled; null never expires
 	CreatedAt    string `json:"createdAt"`
 	UpdatedAt    string `json:"updatedAt"`
 }
@@ -26,17 +31,24 @@ type ReservedOrderLine struct {
 	ItemSKU   string `json:"itemSku,omitempty"`
 	ItemSize  string `json:"itemSize,omitempty"`
 	ItemPrice int64  `json:"itemPrice,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"` // Non-blocking issues detected while adding the item
 }
 
 // CreateReservedOrderRequest represents the request body for creating a reserved order
 // Example: {"assignedTo": "Erika", "orderType": "detal", "customerName": "Juan Pérez", "customerPhone": "+1234567890", "notes": "Cliente VIP"}
 // orderType values: "detal" (retail) or "mayorista" (wholesale) - case-insensitive, will be normalized to lowercase
+// priority values: "normal" (default) or "high" - high-priority orders can claim stock from normal ones
+// discountType values: "none" (default), "flat" (discountValue is an amount) or "percent" (discountValue is 0-99)
 type CreateReservedOrderRequest struct {
 	AssignedTo    string `json:"assignedTo"`
 	OrderType     string `json:"orderType"` // "detal" or "mayorista" (case-insensitive)
+	Priority      string `json:"priority,omitempty"` // "normal" or "high" (optional, defaults to "normal")
 	CustomerName  string `json:"customerName,omitempty"`
 	CustomerPhone string `json:"customerPhone,omitempty"`
 	Notes         string `json:"notes,omitempty"`
+	DiscountType  string `json:"discountType,omitempty"`  // "none", "flat" or "percent" (optional, defaults to "none")
+	DiscountValue int64  `json:"discountValue,omitempty"` // Amount for flat, percentage for percent
+	CouponCode    string `json:"couponCode,omitempty"`    // Optional, invalid codes are ignored when pricing
 }
 
 // AddItemToOrderRequest represents the request body for adding an item to a reserved order
@@ -51,6 +63,28 @@ type AddItemToOrderRequest struct {
 	HoodieType     string `json:"hoodieType,omitempty"`
 }
 
+// BulkAddItemError represents an item of a bulk add request that could not be added
+// index is the position of the item in the request array
+type BulkAddItemError struct {
+	Index  int    `json:"index"`
+	ItemID int64  `json:"itemId"`
+	Error  string `json:"error"`
+}
+
+// BulkAddItemsResponse represents the response for POST /admin/reserved-orders/:id/items/bulk
+// The batch is all-or-nothing: when errors is not empty nothing was added and lines is empty
+// Example response:
+// {
+//   "orderId": 1,
+//   "lines": [{ "id": 10, "reservedOrderId": 1, "itemId": 123, "qty": 2, "unitPrice": 0, "createdAt": "2026-01-04T10:30:00Z" }],
+//   "errors": []
+// }
+type BulkAddItemsResponse struct {
+	OrderID int64               `json:"orderId"`
+	Lines   []ReservedOrderLine `json:"lines"`
+	Errors  []BulkAddItemError  `json:"errors"`
+}
+
 // UpdateItemQuantityRequest represents the request body for updating item quantity in a reserved order
 // Example: {"qty": 3}
 type UpdateItemQuantityRequest struct {
@@ -89,10 +123,31 @@ type UpdateReservedOrderRequest struct {
 	Status        string                           `json:"status"`
 	AssignedTo    string                           `json:"assignedTo"`
 	OrderType     string                           `json:"orderType"`
+	Priority      string                           `json:"priority,omitempty"` // Optional, keeps current priority when empty
 	CustomerName  string                           `json:"customerName,omitempty"`
 	CustomerPhone string                           `json:"customerPhone,omitempty"`
 	Notes         string                           `json:"notes,omitempty"`
+	DiscountType  string                           `json:"discountType,omitempty"`  // Optional, keeps current discount when empty
+	DiscountValue int64                            `json:"discountValue,omitempty"` // Amount for flat, percentage for percent
+	CouponCode    *string                          `json:"couponCode,omitempty"`    // Optional, keeps current coupon when omitted, "" removes it
 	Lines         []UpdateReservedOrderLineRequest `json:"lines"`
+	// ExpectedUpdatedAt is the updatedAt the client last read (RFC3339). When set, the update fails
+	// with "order changed" if the order was modified since, instead of overwriting those changes
+	ExpectedUpdatedAt *string `json:"expectedUpdatedAt,omitempty"`
+}
+
+// PatchReservedOrderRequest represents the request body for PATCH /admin/reserved-orders/:id
+// Only provided fields are updated and lines are never touched. An empty customerName, customerPhone
+// or notes clears it; assignedTo and orderType cannot be empty
+// Example: {"customerPhone": "3152956953"}
+type PatchReservedOrderRequest struct {
+	AssignedTo    *string `json:"assignedTo,omitempty"`
+	CustomerName  *string `json:"customerName,omitempty"`
+	CustomerPhone *string `json:"customerPhone,omitempty"`
+	Notes         *string `json:"notes,omitempty"`
+	OrderType     *string `json:"orderType,omitempty"`
+	// ExpectedUpdatedAt works as in UpdateReservedOrderRequest
+	ExpectedUpdatedAt *string `json:"expectedUpdatedAt,omitempty"`
 }
 
 // ReservedOrderResponse represents the response for a single reserved order with its lines
@@ -122,6 +177,7 @@ type UpdateReservedOrderRequest struct {
 //         "price": 50000,
 //         "stockTotal": 10,
 //         "stockReserved": 2,
+//         "stockAvailable": 8,
 //         "designAssetId": 45,
 //         "description": "Hoodie con diseño especial",
 //         "colorPrimary": "BL",
@@ -140,12 +196,21 @@ type UpdateReservedOrderRequest struct {
 //       }
 //     }
 //   ],
+//   "subtotal": 100000,
+//   "discount": 0,
 //   "total": 100000
 // }
 type ReservedOrderResponse struct {
 	ReservedOrder
-	Lines []ReservedOrderLineWithItem `json:"lines"`
-	Total int64                       `json:"total"` // Sum of qty * unit_price for all lines
+	Lines    []ReservedOrderLineWithItem `json:"lines"`
+	Subtotal int64                       `json:"subtotal"`           // Sum of qty * unit_price for all lines
+	Discount int64                       `json:"discount"`           // Order-level discount taken off the subtotal
+	Total    int64                       `json:"total"`              // Subtotal minus discount (never negative)
+	Warnings []string                    `json:"warnings,omitempty"` // Non-blocking issues (e.g. fallback pricing)
+	Coupon   *PricingCoupon              `json:"coupon,omitempty"`   // Coupon resolution for reserved orders with a couponCode
+	// Full pricing engine result (per-line qtyInBundle/qtyRetail/appliedRules), only with ?breakdown=1 on reserved
+	// orders. Its total is before the order-level discount
+	Breakdown *PricingBreakdown `json:"breakdown,omitempty"`
 }
 
 // ReservedOrderListItem represents a reserved order in a list response
@@ -165,15 +230,29 @@ type ReservedOrderListItem struct {
 //       "assignedTo": "Erika",
 //       "orderType": "detal",
 //       "customerName": "Juan Pérez",
+//       "holdUntil": "2024-01-16T10:30:00Z",
 //       "createdAt": "2024-01-15T10:30:00Z",
 //       "updatedAt": "2024-01-15T10:30:00Z",
 //       "lineCount": 2,
 //       "total": 100000
 //     }
-//   ]
+//   ],
+//   "totalCount": 1,
+//   "pagination": { "limit": 50 }
 // }
 type ReservedOrderListResponse struct {
-	Orders []ReservedOrderListItem `json:"orders"`
+	Orders     []ReservedOrderListItem `json:"orders"`
+	TotalCount int                     `json:"totalCount"` // Orders matching the filters, across all pages
+	Pagination PaginationInfo          `json:"pagination"`
+}
+
+// ReservedOrderListRequest represents query parameters for listing reserved orders
+type ReservedOrderListRequest struct {
+	Status     *string `json:"status,omitempty"`     // reserved, completed, canceled
+	Q          *string `json:"q,omitempty"`          // case-insensitive search in customer name and phone
+	AssignedTo *string `json:"assignedTo,omitempty"` // exact match on assigned_to
+	Limit      int     `json:"limit,omitempty"`      // default 50, max 200
+	Cursor     *string `json:"cursor,omitempty"`     // pagination cursor
 }
 
 // ItemFullInfo represents complete item information with design asset details
@@ -184,6 +263,7 @@ type ItemFullInfo struct {
 	Price         int64  `json:"price"`
 	StockTotal    int    `json:"stockTotal"`
 	StockReserved int    `json:"stockReserved"`
+	StockAvailable int   `json:"stockAvailable"` // max(0, stockTotal - stockReserved)
 	DesignAssetID int    `json:"designAssetId"`
 	// Design asset information (codes)
 	Description    string `json:"description"`
@@ -219,8 +299,10 @@ type ReservedOrderLineWithItem struct {
 // ReservedOrderWithFullItems represents a reserved order with complete item information
 type ReservedOrderWithFullItems struct {
 	ReservedOrder
-	Lines []ReservedOrderLineWithItem `json:"lines"`
-	Total int64                        `json:"total"` // Sum of qty * unit_price for all lines
+	Lines    []ReservedOrderLineWithItem `json:"lines"`
+	Subtotal int64                       `json:"subtotal"` // Sum of qty * unit_price for all lines
+	Discount int64                       `json:"discount"` // Order-level discount taken off the subtotal
+	Total    int64                       `json:"total"`    // Subtotal minus discount (never negative)
 }
 
 // SeparatedCartsResponse represents the response for separated carts endpoint
@@ -252,6 +334,7 @@ type ReservedOrderWithFullItems struct {
 //             "price": 50000,
 //             "stockTotal": 10,
 //             "stockReserved": 2,
+//             "stockAvailable": 8,
 //             "designAssetId": 45,
 //             "description": "Hoodie con diseño especial",
 //             "colorPrimary": "BL",
@@ -278,3 +361,200 @@ type SeparatedCartsResponse struct {
 	Carts []ReservedOrderWithFullItems `json:"carts"`
 }
 
+
+// OrderTypeChange represents a reserved order whose stored order_type was corrected
+type OrderTypeChange struct {
+	OrderID int64  `json:"orderId"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// RecalculateOrderTypesResponse represents the response for POST /admin/reserved-orders/recalculate-types
+// Example response:
+// {
+//   "checked": 12,
+//   "changed": 1,
+//   "failed": 0,
+//   "changes": [{ "orderId": 7, "from": "detal", "to": "mayorista" }]
+// }
+type RecalculateOrderTypesResponse struct {
+	Checked int               `json:"checked"` // Reserved orders priced
+	Changed int               `json:"changed"` // Orders whose order_type was updated
+	Failed  int               `json:"failed"`  // Orders that could not be priced or updated (see logs)
+	Changes []OrderTypeChange `json:"changes"`
+}
+
+// ExtendHoldRequest represents the request body for POST /admin/reserved-orders/:id/extend-hold
+// Exactly one of hours (added to the later of now and the current hold) or holdUntil (RFC3339) is required
+// Example: {"hours": 48}
+// Example: {"holdUntil": "2026-01-10T18:00:00-05:00"}
+type ExtendHoldRequest struct {
+	Hours     int    `json:"hours,omitempty"`
+	HoldUntil string `json:"holdUntil,omitempty"`
+}
+
+// ReservedOrderHold represents the response for POST /admin/reserved-orders/:id/extend-hold
+// Example response:
+// {
+//   "orderId": 1,
+//   "previousHoldUntil": "2026-01-05T10:30:00Z",
+//   "holdUntil": "2026-01-07T10:30:00Z"
+// }
+type ReservedOrderHold struct {
+	OrderID           int64   `json:"orderId"`
+	PreviousHoldUntil *string `json:"previousHoldUntil"` // null when the order had no hold
+	HoldUntil         string  `json:"holdUntil"`
+}
+
+// ClaimStockRequest represents the request body for claiming reserved stock from another order
+// The donor order must be named twice (fromOrderId + confirmFromOrderId) so the order losing stock
+// is always an explicit decision
+// Example: {"fromOrderId": 7, "confirmFromOrderId": 7, "itemId": 123, "qty": 1, "reason": "Pedido mayorista pagado"}
+type ClaimStockRequest struct {
+	FromOrderID        int64  `json:"fromOrderId"`
+	ConfirmFromOrderID int64  `json:"confirmFromOrderId"`
+	ItemID             int64  `json:"itemId"`
+	Qty                int    `json:"qty"`
+	Reason             string `json:"reason"`
+}
+
+// ReservedOrderStockClaim represents an audit entry of units moved between reserved orders
+// Example response:
+// {
+//   "id": 1,
+//   "claimantOrderId": 3,
+//   "donorOrderId": 7,
+//   "itemId": 123,
+//   "qty": 1,
+//   "reason": "Pedido mayorista pagado",
+//   "createdAt": "2024-01-15T10:30:00Z"
+// }
+type ReservedOrderStockClaim struct {
+	ID              int64  `json:"id"`
+	ClaimantOrderID int64  `json:"claimantOrderId"`
+	DonorOrderID    int64  `json:"donorOrderId"`
+	ItemID          int64  `json:"itemId"`
+	Qty             int    `json:"qty"`
+	Reason          string `json:"reason"`
+	CreatedAt       string `json:"createdAt"`
+}
+
+// ReservedOrderEvent represents a change to a reserved order pushed by GET /admin/reserved-orders/events
+// type values: created, items_added, items_removed, items_updated, updated, canceled, reopened, completed, sold, sale_voided,
+// hold_extended, hold_expired
+// Example: {"orderId": 1, "type": "items_added", "at": "2026-01-04T10:30:00Z"}
+type ReservedOrderEvent struct {
+	OrderID int64  `json:"orderId"`
+	Type    string `json:"type"`
+	At      string `json:"at"`
+}
+
+// EventsStreamTokenResponse represents a short-lived token that opens the reserved order events stream
+// Example response:
+// {
+//   "token": "1767520800.3f9a...",
+//   "expiresAt": "2026-01-04T10:35:00Z",
+//   "url": "/admin/reserved-orders/events?streamToken=1767520800.3f9a..."
+// }
+type EventsStreamTokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt"`
+	URL       string `json:"url"` // Events stream URL with the token, ready for new EventSource(url)
+}
+
+// MergeOrdersRequest represents the request body for merging another reserved order into this one
+// Example: {"sourceOrderId": 7}
+type MergeOrdersRequest struct {
+	SourceOrderID int64 `json:"sourceOrderId"`
+}
+
+// PickingListItem represents the aggregated quantity of one item (and custom variant) across orders
+type PickingListItem struct {
+	Item       ItemFullInfo `json:"item"`
+	CustomCode *string      `json:"customCode,omitempty"` // Custom variants are picked separately
+	Qty        int          `json:"qty"`                  // Total units to pick across all orders
+	OrderIDs   []int64      `json:"orderIds"`             // Orders contributing to this quantity
+}
+
+// PickingListResponse represents the consolidated picking list for matching orders
+// Example response:
+// {
+//   "assignedTo": "Erika",
+//   "status": "reserved",
+//   "orderCount": 2,
+//   "totalUnits": 3,
+//   "items": [
+//     {
+//       "item": {
+//         "id": 123,
+//         "sku": "MN_ABC123",
+//         "size": "MN",
+//         "designAssetId": 45,
+//         "colorPrimaryLabel": "negro",
+//         "hoodieTypeLabel": "buso tipo esqueleto",
+//         "imageUrlThumb": "/admin/design-assets/pending/45/image?size=thumb"
+//       },
+//       "qty": 3,
+//       "orderIds": [1, 4]
+//     }
+//   ]
+// }
+type PickingListResponse struct {
+	AssignedTo string            `json:"assignedTo,omitempty"`
+	Status     string            `json:"status"`
+	OrderCount int               `json:"orderCount"`
+	TotalUnits int               `json:"totalUnits"`
+	Items      []PickingListItem `json:"items"`
+}
+
+// CompletionImpactLine represents the stock effect of completing an order on one item
+// Completion deducts qty from both stock_total and stock_reserved, so "available" does not change
+type CompletionImpactLine struct {
+	Item                     ItemFullInfo `json:"item"`
+	Qty                      int          `json:"qty"` // Units of this item in the order (all lines)
+	CurrentStockTotal        int          `json:"currentStockTotal"`
+	CurrentStockReserved     int          `json:"currentStockReserved"`
+	CurrentAvailable         int          `json:"currentAvailable"`
+	PostCompletionStockTotal int          `json:"postCompletionStockTotal"`
+	CrossesLowStock          bool         `json:"crossesLowStock"` // Above the threshold now, at or below it after completion
+	IsLowAfterCompletion     bool         `json:"isLowAfterCompletion"`
+	HitsZero                 bool         `json:"hitsZero"`             // stock_total reaches 0 after completion
+	InsufficientReserved     bool         `json:"insufficientReserved"` // Completion would fail for this item
+}
+
+// CompletionImpactResponse represents a read-only preview of the stock impact of completing an order
+// Example: GET /admin/reserved-orders/3/completion-impact?threshold=5
+type CompletionImpactResponse struct {
+	OrderID           int64                  `json:"orderId"`
+	Status            string                 `json:"status"`
+	LowStockThreshold int                    `json:"lowStockThreshold"`
+	Lines             []CompletionImpactLine `json:"lines"`
+	ItemsGoingLow     int                    `json:"itemsGoingLow"`
+	ItemsHittingZero  int                    `json:"itemsHittingZero"`
+	CanComplete       bool                   `json:"canComplete"` // False when any item lacks reserved stock
+}
+
+// SellerCartSummary represents one seller's open (reserved) carts
+type SellerCartSummary struct {
+	AssignedTo string `json:"assignedTo"`
+	OrderCount int    `json:"orderCount"`
+	TotalValue int64  `json:"totalValue"` // Sum of the orders' totals (after order discounts)
+}
+
+// SellerCartSummaryResponse represents the response for GET /admin/reserved-orders/summary-by-seller
+// Example response:
+// {
+//   "orderCount": 3,
+//   "totalValue": 152000,
+//   "sellers": [
+//     { "assignedTo": "Erika", "orderCount": 2, "totalValue": 110000 },
+//     { "assignedTo": "Maria", "orderCount": 1, "totalValue": 42000 }
+//   ],
+//   "warnings": ["pricing failed for order 9: total uses stored prices"]
+// }
+type SellerCartSummaryResponse struct {
+	OrderCount int                 `json:"orderCount"`
+	TotalValue int64               `json:"totalValue"`
+	Sellers    []SellerCartSummary `json:"sellers"` // Highest totalValue first
+	Warnings   []string            `json:"warnings,omitempty"`
+}
@@ -10,17 +10,46 @@ type Sale struct {
 	PaymentMethod     string `json:"paymentMethod"`
 	PaymentDestination string `json:"paymentDestination"`
 	Status            string `json:"status"`
+	SaleType          string `json:"saleType"`             // "sale" or "gift" (no income recorded)
+	GiftReason        string `json:"giftReason,omitempty"` // Required when saleType is "gift"
+	Seller            string `json:"seller,omitempty"`     // Who rang up the sale
 	Notes             string `json:"notes,omitempty"`
 	CreatedAt         string `json:"createdAt"`
+	VoidedAt          string `json:"voidedAt,omitempty"`   // Set when status is "voided"
+	VoidReason        string `json:"voidReason,omitempty"` // Why the sale was voided
+	Warnings          []string `json:"warnings,omitempty"` // Non-blocking issues detected while selling
+	// Payments lists how the sale was paid (sale_payments), one finance transaction per entry.
+	// Returned by Sell and the sale detail endpoints; empty for gift sales
+	Payments []SalePayment `json:"payments,omitempty"`
+	// True when the request replayed an Idempotency-Key and the original sale was returned
+	IdempotentReplay bool `json:"idempotentReplay,omitempty"`
+}
+
+// SalePayment represents one part of a split payment
+// Example: {"method": "cash", "destination": "Caja", "amount": 40000}
+type SalePayment struct {
+	Method      string `json:"method"`
+	Destination string `json:"destination"`
+	Amount      int64  `json:"amount"`
 }
 
 // SellRequest represents the request body for selling a reserved order
 // Example: {"amountPaid": 100000, "paymentMethod": "transfer", "paymentDestination": "Nequi", "notes": "Pago completo"}
+// Split payment example: {"payments": [{"method": "cash", "destination": "Caja", "amount": 40000}, {"method": "transfer", "destination": "Nequi", "amount": 60000}]}
+// When payments is set, the single-payment fields are ignored and the amounts must sum to the calculated total
+// Gift/sample example: {"saleType": "gift", "reason": "Muestra para influencer"}
+// Gifts deduct stock but record no income, so amountPaid/paymentMethod/paymentDestination/payments are ignored
+// seller is optional and defaults to the order's assignedTo
 type SellRequest struct {
 	AmountPaid         int64  `json:"amountPaid"`
 	PaymentMethod      string `json:"paymentMethod"`
 	PaymentDestination string `json:"paymentDestination"`
+	Payments           []SalePayment `json:"payments,omitempty"`
 	Notes              string `json:"notes,omitempty"`
+	SaleType           string `json:"saleType,omitempty"` // "sale" (default) or "gift"
+	Reason             string `json:"reason,omitempty"`   // Required for gift sales
+	Seller             string `json:"seller,omitempty"`   // Who rang up the sale (defaults to the order's assignedTo)
+	IdempotencyKey     string `json:"-"`                  // From the Idempotency-Key header
 }
 
 // SaleResponse represents the response for a sale
@@ -41,6 +70,66 @@ type SaleResponse struct {
 	Sale
 }
 
+// SalesReportPeriod represents the sales rolled up for one day, week or month
+type SalesReportPeriod struct {
+	Period     string `json:"period"` // Start of the period (YYYY-MM-DD); weeks start on Monday
+	Count      int    `json:"count"`
+	AmountPaid int64  `json:"amountPaid"`
+}
+
+// SalesReportBreakdown represents the sales rolled up for one payment method or destination
+type SalesReportBreakdown struct {
+	Key        string `json:"key"`
+	Count      int    `json:"count"`
+	AmountPaid int64  `json:"amountPaid"`
+}
+
+// SalesReportResponse represents sales totals grouped by period, payment method and payment destination
+// Gift sales record no money and are left out
+type SalesReportResponse struct {
+	From                 string                 `json:"from,omitempty"`
+	To                   string                 `json:"to,omitempty"`
+	GroupBy              string                 `json:"groupBy"`
+	TotalCount           int                    `json:"totalCount"`
+	TotalAmountPaid      int64                  `json:"totalAmountPaid"`
+	Periods              []SalesReportPeriod    `json:"periods"`
+	ByPaymentMethod      []SalesReportBreakdown `json:"byPaymentMethod"`
+	ByPaymentDestination []SalesReportBreakdown `json:"byPaymentDestination"`
+}
+
+// SellerSales represents the sales rung up by one seller
+type SellerSales struct {
+	Seller     string `json:"seller"` // Empty for sales recorded without a seller
+	Count      int    `json:"count"`
+	AmountPaid int64  `json:"amountPaid"`
+}
+
+// SalesBySellerResponse represents sales totals per seller
+// Gift sales record no money and are left out
+type SalesBySellerResponse struct {
+	From            string        `json:"from,omitempty"`
+	To              string        `json:"to,omitempty"`
+	TotalCount      int           `json:"totalCount"`
+	TotalAmountPaid int64         `json:"totalAmountPaid"`
+	Sellers         []SellerSales `json:"sellers"`
+}
+
+// CustomerSales represents the sales made to one customer
+type CustomerSales struct {
+	CustomerName string `json:"customerName"` // "(sin nombre)" for orders without a customer name
+	Count        int    `json:"count"`
+	AmountPaid   int64  `json:"amountPaid"`
+}
+
+// TopCustomersResponse represents the customers with the highest spend, highest total first
+// Gift sales record no money and are left out
+type TopCustomersResponse struct {
+	From      string          `json:"from,omitempty"`
+	To        string          `json:"to,omitempty"`
+	Limit     int             `json:"limit"`
+	Customers []CustomerSales `json:"customers"`
+}
+
 // SaleListItem represents a sale in a list response
 type SaleListItem struct {
 	ID                int64  `json:"id"`
@@ -50,6 +139,7 @@ type SaleListItem struct {
 	AmountPaid        int64  `json:"amountPaid"`
 	PaymentDestination string `json:"paymentDestination"`
 	PaymentMethod     string `json:"paymentMethod"`
+	SaleType          string `json:"saleType"`
 }
 
 // SaleListResponse represents the response for listing sales
@@ -71,6 +161,38 @@ type SaleListResponse struct {
 	Sales []SaleListItem `json:"sales"`
 }
 
+// OrderSaleItem represents a sale linked to a reserved order, with its refund totals
+type OrderSaleItem struct {
+	SaleListItem
+	Status         string `json:"status"` // "voided" sales are kept in the history with a net amount of 0
+	RefundedAmount int64  `json:"refundedAmount"`
+	NetAmount      int64  `json:"netAmount"`
+}
+
+// OrderSalesResponse represents the financial history of a reserved order
+// Example response:
+// {
+//   "reservedOrderId": 3,
+//   "sales": [
+//     {
+//       "id": 10,
+//       "soldAt": "2026-01-04T10:30:00Z",
+//       "reservedOrderId": 3,
+//       "amountPaid": 100000,
+//       "paymentDestination": "Nequi",
+//       "paymentMethod": "transfer",
+//       "saleType": "sale",
+//       "status": "paid",
+//       "refundedAmount": 25000,
+//       "netAmount": 75000
+//     }
+//   ]
+// }
+type OrderSalesResponse struct {
+	ReservedOrderID int64           `json:"reservedOrderId"`
+	Sales           []OrderSaleItem `json:"sales"`
+}
+
 // SaleDetailResponse represents the response for a sale detail with order information
 // Example response:
 // {
@@ -96,3 +218,56 @@ type SaleDetailResponse struct {
 }
 
 
+// RepriceSaleRequest represents the request body for repricing a completed sale
+// Example: {"reason": "Bundle rule misconfigured at time of sale"}
+type RepriceSaleRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RepriceSaleResponse represents the result of repricing a completed sale
+// Example response:
+// {
+//   "sale": { "id": 10, "amountPaid": 90000, ... },
+//   "previousAmount": 100000,
+//   "newAmount": 90000,
+//   "difference": -10000,
+//   "adjustmentTransactionId": 57,
+//   "reason": "Bundle rule misconfigured at time of sale"
+// }
+type RepriceSaleResponse struct {
+	Sale                    Sale   `json:"sale"`
+	PreviousAmount          int64  `json:"previousAmount"`
+	NewAmount               int64  `json:"newAmount"`
+	Difference              int64  `json:"difference"`
+	AdjustmentTransactionID *int64 `json:"adjustmentTransactionId,omitempty"`
+	Reason                  string `json:"reason"`
+}
+
+// SellCheckIssue represents a single blocker that would make Sell fail for an order
+// code values: already_sold, already_completed, not_reserved, no_lines, item_inactive,
+// insufficient_reserved_stock, pricing_unavailable, pricing_failed, line_not_priceable
+type SellCheckIssue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	ItemID  *int64 `json:"itemId,omitempty"`
+	LineID  *int64 `json:"lineId,omitempty"`
+}
+
+// SellCheckResponse represents the read-only sellability check of a reserved order
+// Example response:
+// {
+//   "orderId": 3,
+//   "status": "reserved",
+//   "sellable": false,
+//   "expectedTotal": 85000,
+//   "issues": [
+//     { "code": "insufficient_reserved_stock", "message": "item SKU-12 has 1 reserved, order needs 2", "itemId": 12 }
+//   ]
+// }
+type SellCheckResponse struct {
+	OrderID       int64            `json:"orderId"`
+	Status        string           `json:"status"`
+	Sellable      bool             `json:"sellable"`
+	ExpectedTotal int64            `json:"expectedTotal"`
+	Issues        []SellCheckIssue `json:"issues"`
+}
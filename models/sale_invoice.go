@@ -0,0 +1,69 @@
+package models
+
+// InvoiceStore represents the store metadata printed on an invoice
+type InvoiceStore struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// SaleInvoiceLine represents one order line on an invoice
+type SaleInvoiceLine struct {
+	ItemID              int64  `json:"itemId"`
+	SKU                 string `json:"sku"`
+	Description         string `json:"description"`
+	Size                string `json:"size"`
+	ColorPrimaryLabel   string `json:"colorPrimaryLabel"`
+	ColorSecondaryLabel string `json:"colorSecondaryLabel"`
+	HoodieTypeLabel     string `json:"hoodieTypeLabel"`
+	Qty                 int    `json:"qty"`
+	UnitPrice           int64  `json:"unitPrice"`
+	LineTotal           int64  `json:"lineTotal"` // qty * unitPrice
+}
+
+// SaleInvoice represents a customer receipt for a sale
+// Example response:
+// {
+//   "invoiceNumber": "V-000010",
+//   "store": { "name": "Armario Mascota", "currency": "COP" },
+//   "saleId": 10,
+//   "reservedOrderId": 3,
+//   "soldAt": "2026-01-04T10:30:00Z",
+//   "customerName": "Juan Pérez",
+//   "customerPhone": "3152956953",
+//   "orderType": "detal",
+//   "saleType": "sale",
+//   "status": "paid",
+//   "paymentMethod": "transfer",
+//   "paymentDestination": "Nequi",
+//   "lines": [
+//     {
+//       "itemId": 123, "sku": "MN_ABC123", "description": "Hoodie con diseño especial", "size": "MN",
+//       "colorPrimaryLabel": "negro", "colorSecondaryLabel": "azul cielo", "hoodieTypeLabel": "buso tipo esqueleto",
+//       "qty": 2, "unitPrice": 50000, "lineTotal": 100000
+//     }
+//   ],
+//   "subtotal": 100000,
+//   "discount": 10000,
+//   "total": 90000,
+//   "amountPaid": 90000
+// }
+type SaleInvoice struct {
+	InvoiceNumber      string            `json:"invoiceNumber"`
+	Store              InvoiceStore      `json:"store"`
+	SaleID             int64             `json:"saleId"`
+	ReservedOrderID    int64             `json:"reservedOrderId"`
+	SoldAt             string            `json:"soldAt"`
+	CustomerName       string            `json:"customerName,omitempty"`
+	CustomerPhone      string            `json:"customerPhone,omitempty"`
+	OrderType          string            `json:"orderType"`
+	SaleType           string            `json:"saleType"`
+	Status             string            `json:"status"`
+	PaymentMethod      string            `json:"paymentMethod"`
+	PaymentDestination string            `json:"paymentDestination"`
+	Notes              string            `json:"notes,omitempty"`
+	Lines              []SaleInvoiceLine `json:"lines"`
+	Subtotal           int64             `json:"subtotal"`   // Sum of line totals
+	Discount           int64             `json:"discount"`   // Discount not already in the line prices (subtotal above amountPaid)
+	Total              int64             `json:"total"`      // Subtotal minus discount (never negative)
+	AmountPaid         int64             `json:"amountPaid"` // Amount recorded on the sale
+}
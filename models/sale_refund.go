@@ -0,0 +1,65 @@
+package models
+
+// SaleRefund represents a refund performed on a sale
+type SaleRefund struct {
+	ID          int64            `json:"id"`
+	SaleID      int64            `json:"saleId"`
+	Amount      int64            `json:"amount"`
+	Destination string           `json:"destination"`
+	Reason      string           `json:"reason"`
+	CreatedAt   string           `json:"createdAt"`
+	Lines       []SaleRefundLine `json:"lines"`
+}
+
+// SaleRefundLine represents the refunded quantity of a single order line
+type SaleRefundLine struct {
+	ID                  int64 `json:"id"`
+	ReservedOrderLineID int64 `json:"reservedOrderLineId"`
+	ItemID              int64 `json:"itemId"`
+	Qty                 int   `json:"qty"`
+	UnitPrice           int64 `json:"unitPrice"`
+	Amount              int64 `json:"amount"`
+}
+
+// RefundLineRequest represents a line to refund within a RefundSaleRequest
+// The line is identified by lineId, or by itemId when lineId is omitted
+type RefundLineRequest struct {
+	LineID int64 `json:"lineId,omitempty"`
+	ItemID int64 `json:"itemId,omitempty"`
+	Qty    int   `json:"qty"`
+}
+
+// RefundSaleRequest represents the request body for refunding (part of) a sale
+// Example: {"lines": [{"itemId": 12, "qty": 1}], "reason": "Talla equivocada", "destination": "Nequi"}
+// destination is optional and defaults to the sale's payment destination.
+// amount is optional and overrides the refunded money (defaults to qty * unit price of the lines);
+// with amount and no lines nothing is restocked. With neither, every unit not yet refunded is refunded.
+type RefundSaleRequest struct {
+	Lines       []RefundLineRequest `json:"lines"`
+	Reason      string              `json:"reason"`
+	Destination string              `json:"destination,omitempty"`
+	Amount      *int64              `json:"amount,omitempty"`
+}
+
+// SaleRefundListResponse represents the refund history of a sale
+// Example response:
+// {
+//   "saleId": 10,
+//   "refundedAmount": 25000,
+//   "refunds": [
+//     {
+//       "id": 1,
+//       "saleId": 10,
+//       "amount": 25000,
+//       "destination": "Nequi",
+//       "reason": "Talla equivocada",
+//       "createdAt": "2026-01-05T09:00:00Z",
+//       "lines": [{"id": 1, "reservedOrderLineId": 5, "itemId": 12, "qty": 1, "unitPrice": 25000, "amount": 25000}]
+//     }
+//   ]
+// }
+type SaleRefundListResponse struct {
+	SaleID         int64        `json:"saleId"`
+	RefundedAmount int64        `json:"refundedAmount"`
+	Refunds        []SaleRefund `json:"refunds"`
+}
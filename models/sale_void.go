@@ -0,0 +1,28 @@
+package models
+
+// VoidSaleRequest represents the request body for voiding a sale recorded in error
+// Example: {"reason": "Venta registrada en el pedido equivocado"}
+type VoidSaleRequest struct {
+	Reason string `json:"reason"` // required, stored as the sale's void_reason
+}
+
+// VoidSaleResponse represents the result of voiding a sale recorded in error
+// Example response:
+// {
+//   "saleId": 10,
+//   "reservedOrderId": 3,
+//   "amountPaid": 100000,
+//   "reversedTransactions": 1,
+//   "restoredUnits": 2,
+//   "orderStatus": "reserved",
+//   "sale": { "id": 10, "status": "voided", "voidedAt": "2026-01-04T11:00:00Z", "voidReason": "Venta registrada en el pedido equivocado", ... }
+// }
+type VoidSaleResponse struct {
+	SaleID               int64  `json:"saleId"`
+	ReservedOrderID      int64  `json:"reservedOrderId"`
+	AmountPaid           int64  `json:"amountPaid"`
+	ReversedTransactions int    `json:"reversedTransactions"` // Sale incomes and reprice adjustments reversed by a "sale_void" transaction
+	RestoredUnits        int    `json:"restoredUnits"`        // Units put back into stock_total and stock_reserved
+	OrderStatus          string `json:"orderStatus"`          // Always "reserved"
+	Sale                 *Sale  `json:"sale"`                 // The sale, now with status "voided"
+}
@@ -0,0 +1,42 @@
+package models
+
+import "encoding/json"
+
+// SaleWebhookPayload represents the body POSTed to the sale webhook URL
+// Example: {"event": "sale.completed", "sale": {"id": 10, "reservedOrderId": 3, "amountPaid": 100000, ...}}
+// Event is "sale.completed" when the sale is recorded and "sale.voided" when it is voided
+type SaleWebhookPayload struct {
+	Event string `json:"event"`
+	Sale  Sale   `json:"sale"`
+}
+
+// WebhookFailure represents a sale webhook whose delivery retries were exhausted
+type WebhookFailure struct {
+	ID         int64           `json:"id"`
+	SaleID     int64           `json:"saleId"`
+	Payload    json.RawMessage `json:"payload"`
+	LastError  string          `json:"lastError"`
+	Attempts   int             `json:"attempts"`
+	ResolvedAt *string         `json:"resolvedAt,omitempty"` // Set once a manual retry succeeds
+	CreatedAt  string          `json:"createdAt"`
+	UpdatedAt  string          `json:"updatedAt"`
+}
+
+// WebhookFailureListResponse represents the response for listing webhook failures
+// Example response:
+// {
+//   "failures": [
+//     {
+//       "id": 1,
+//       "saleId": 10,
+//       "payload": {"event": "sale.completed", "sale": {"id": 10}},
+//       "lastError": "unexpected status 503",
+//       "attempts": 3,
+//       "createdAt": "2026-01-04T10:30:00Z",
+//       "updatedAt": "2026-01-04T10:30:00Z"
+//     }
+//   ]
+// }
+type WebhookFailureListResponse struct {
+	Failures []WebhookFailure `json:"failures"`
+}
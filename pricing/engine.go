@@ -2,6 +2,7 @@ package pricing
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,8 +10,11 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"armario-mascota-me/db"
+	"armario-mascota-me/metrics"
 	"armario-mascota-me/models"
 	"armario-mascota-me/utils"
 )
@@ -22,6 +26,18 @@ type PricingConfig struct {
 	SizeBuckets map[string]string                `json:"sizeBuckets"`
 	Pricebook   map[string]map[string]PriceEntry `json:"pricebook"`
 	Rules       []Rule                           `json:"rules"`
+	Coupons     map[string]Coupon                `json:"coupons,omitempty"` // Keyed by code (case-insensitive)
+	Rounding    RoundingConfig                   `json:"rounding,omitempty"`
+}
+
+// RoundingConfig controls how computed prices are rounded.
+// BundleRemainder is "distribute" (default): the remainder of bundleTotalPrice/requiredQty is spread
+// over the bundle's lines so they add up to the bundle total exactly; "truncate" drops it.
+// RetailNearest rounds retail unit prices to the nearest multiple (e.g. 100 COP); 0 disables it.
+// Example: "rounding": {"bundleRemainder": "distribute", "retailNearest": 100}
+type RoundingConfig struct {
+	BundleRemainder string `json:"bundleRemainder,omitempty"`
+	RetailNearest   int64  `json:"retailNearest,omitempty"`
 }
 
 type GroupConfig struct {
@@ -44,6 +60,18 @@ type Rule struct {
 	Action     map[string]interface{} `json:"action,omitempty"`
 }
 
+// Coupon is a promotion code applied to the order total after bundle/wholesale pricing.
+// Type is "percent_off" (Value is 1-100) or "amount_off" (Value is an amount). MinQty is the minimum
+// number of wholesale-eligible units (BUSOS+CAMISETAS) and ExpiresAt the last valid day (YYYY-MM-DD);
+// both are optional.
+type Coupon struct {
+	Active    bool   `json:"active"`
+	Type      string `json:"type"`
+	Value     int64  `json:"value"`
+	MinQty    int    `json:"minQty,omitempty"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
 // OrderLineInput represents input data for pricing calculation
 type OrderLineInput struct {
 	LineID     int64
@@ -56,6 +84,7 @@ type OrderLineInput struct {
 
 // Engine handles pricing calculations based on JSON configuration
 type Engine struct {
+	mu     sync.RWMutex
 	config *PricingConfig
 }
 
@@ -67,6 +96,53 @@ func NewEngine(configPath string) (*Engine, error) {
 		return engineInstance, nil
 	}
 
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := &Engine{
+		config: config,
+	}
+
+	engineInstance = engine
+	log.Printf("✅ PricingEngine: Successfully loaded pricing config from %s", configPath)
+	return engine, nil
+}
+
+// Reload re-reads and re-validates the pricing config and swaps it in atomically.
+// If the new config cannot be read or is invalid, the previous config stays active.
+func (e *Engine) Reload(configPath string) error {
+	config, err := loadConfig(configPath)
+	if err != nil {
+		log.Printf("❌ PricingEngine: Reload failed, keeping previous config: %v", err)
+		return err
+	}
+
+	e.mu.Lock()
+	e.config = config
+	e.mu.Unlock()
+
+	log.Printf("✅ PricingEngine: Reloaded pricing config from %s (%d rules)", configPath, len(config.Rules))
+	return nil
+}
+
+// ConfigInfo returns the currency and number of rules of the active config
+func (e *Engine) ConfigInfo() (currency string, ruleCount int) {
+	e = e.snapshot()
+	return e.config.Currency, len(e.config.Rules)
+}
+
+// snapshot returns an engine bound to the config active right now, so a calculation that spans
+// several lookups is not affected by a concurrent Reload
+func (e *Engine) snapshot() *Engine {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return &Engine{config: e.config}
+}
+
+// loadConfig reads, validates and sorts a pricing config file
+func loadConfig(configPath string) (*PricingConfig, error) {
 	// Resolve config path
 	if !filepath.IsAbs(configPath) {
 		wd, err := os.Getwd()
@@ -93,18 +169,19 @@ func NewEngine(configPath string) (*Engine, error) {
 		return nil, fmt.Errorf("invalid pricing config: %w", err)
 	}
 
+	// Coupon codes are matched case-insensitively
+	coupons := make(map[string]Coupon, len(config.Coupons))
+	for code, coupon := range config.Coupons {
+		coupons[normalizeCouponCode(code)] = coupon
+	}
+	config.Coupons = coupons
+
 	// Sort rules by priority (highest first)
 	sort.Slice(config.Rules, func(i, j int) bool {
 		return config.Rules[i].Priority > config.Rules[j].Priority
 	})
 
-	engine := &Engine{
-		config: &config,
-	}
-
-	engineInstance = engine
-	log.Printf("✅ PricingEngine: Successfully loaded pricing config from %s", configPath)
-	return engine, nil
+	return &config, nil
 }
 
 func validateConfig(config *PricingConfig) error {
@@ -117,9 +194,66 @@ func validateConfig(config *PricingConfig) error {
 	if len(config.Pricebook) == 0 {
 		return fmt.Errorf("pricebook is required")
 	}
+	for code, coupon := range config.Coupons {
+		if normalizeCouponCode(code) == "" {
+			return fmt.Errorf("coupon code cannot be empty")
+		}
+		switch coupon.Type {
+		case "percent_off":
+			if coupon.Value <= 0 || coupon.Value > 100 {
+				return fmt.Errorf("coupon %s: percent_off value must be between 1 and 100", code)
+			}
+		case "amount_off":
+			if coupon.Value <= 0 {
+				return fmt.Errorf("coupon %s: amount_off value must be greater than 0", code)
+			}
+		default:
+			return fmt.Errorf("coupon %s: type must be percent_off or amount_off", code)
+		}
+		if coupon.MinQty < 0 {
+			return fmt.Errorf("coupon %s: minQty cannot be negative", code)
+		}
+		if coupon.ExpiresAt != "" {
+			if _, err := time.Parse("2006-01-02", coupon.ExpiresAt); err != nil {
+				return fmt.Errorf("coupon %s: expiresAt must be YYYY-MM-DD", code)
+			}
+		}
+	}
+	switch config.Rounding.BundleRemainder {
+	case "", "distribute", "truncate":
+	default:
+		return fmt.Errorf("rounding.bundleRemainder must be distribute or truncate")
+	}
+	if config.Rounding.RetailNearest < 0 {
+		return fmt.Errorf("rounding.retailNearest cannot be negative")
+	}
+	for _, rule := range config.Rules {
+		switch rule.Type {
+		case "bundle_fixed_total", "wholesale_override":
+		case "percent_discount":
+			percent, _ := rule.Action["percent"].(float64)
+			if percent <= 0 || percent > 100 {
+				return fmt.Errorf("rule %s: percent_discount action.percent must be between 1 and 100", rule.ID)
+			}
+			group, _ := rule.Conditions["group"].(string)
+			if _, exists := config.Groups[group]; !exists {
+				return fmt.Errorf("rule %s: percent_discount conditions.group %q is not a configured group", rule.ID, group)
+			}
+			if minQty, ok := rule.Conditions["minQty"].(float64); ok && minQty < 0 {
+				return fmt.Errorf("rule %s: minQty cannot be negative", rule.ID)
+			}
+		default:
+			return fmt.Errorf("rule %s: unknown rule type %q", rule.ID, rule.Type)
+		}
+	}
 	return nil
 }
 
+// normalizeCouponCode trims and upper-cases a coupon code
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
 // GetEngine returns the singleton pricing engine instance
 func GetEngine() *Engine {
 	return engineInstance
@@ -128,7 +262,11 @@ func GetEngine() *Engine {
 // GetCatalogBusoPrices returns retail and wholesale prices for BUSOS for a given size.
 // It uses the configured sizeBuckets mapping (e.g., XS/S/M -> XS_S_M, MN/IT -> MINI_INTERMEDIO).
 func (e *Engine) GetCatalogBusoPrices(size string) (retail int64, wholesale int64, ok bool) {
-	if e == nil || e.config == nil {
+	if e == nil {
+		return 0, 0, false
+	}
+	e = e.snapshot()
+	if e.config == nil {
 		return 0, 0, false
 	}
 	bucket := e.getSizeBucket(size)
@@ -189,21 +327,174 @@ func (e *Engine) isEligibleForWholesaleCount(productType string) bool {
 	return group == "BUSOS" || group == "CAMISETAS"
 }
 
+// FallbackPriceReason returns why a product would be priced with a default fallback price instead of
+// a pricebook entry, or an empty string when a pricebook entry exists for its group and size
+func (e *Engine) FallbackPriceReason(productType, size string) string {
+	e = e.snapshot()
+	return e.fallbackPriceReason(productType, size)
+}
+
+// fallbackPriceReason is FallbackPriceReason on an already snapshotted engine
+func (e *Engine) fallbackPriceReason(productType, size string) string {
+	if productType == "" {
+		return "design asset has no hoodie type"
+	}
+	group := e.getGroupForProductType(productType)
+	if group == "" {
+		return fmt.Sprintf("hoodie type %s does not belong to any pricing group", productType)
+	}
+	sizeBucket := e.getSizeBucket(size)
+	if pricebook, exists := e.config.Pricebook[group]; exists {
+		if _, exists := pricebook[sizeBucket]; exists {
+			return ""
+		}
+	}
+	return fmt.Sprintf("no %s pricebook entry for size %s", group, size)
+}
+
+// GroupCoverage resolves the pricing group and size bucket for a product type and size, and reports
+// whether the pricebook has an entry for them. group is empty when the product type is in no group.
+func (e *Engine) GroupCoverage(productType, size string) (group string, sizeBucket string, hasPricebookEntry bool) {
+	e = e.snapshot()
+	group = e.getGroupForProductType(productType)
+	sizeBucket = e.getSizeBucket(size)
+	if group == "" {
+		return group, sizeBucket, false
+	}
+	if pricebook, exists := e.config.Pricebook[group]; exists {
+		_, hasPricebookEntry = pricebook[sizeBucket]
+	}
+	return group, sizeBucket, hasPricebookEntry
+}
+
+// ValidateAgainstCatalog cross-checks every distinct (hoodie_type, size) combination present in active
+// items against the configured groups, size buckets and pricebook, and returns the combinations that
+// would be priced with fallback prices
+func (e *Engine) ValidateAgainstCatalog(ctx context.Context) (*models.PricingValidationResponse, error) {
+	e = e.snapshot()
+
+	query := `
+		SELECT COALESCE(da.hoodie_type, '') as hoodie_type, i.size, COUNT(*) as item_count
+		FROM items i
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.is_active = true
+		GROUP BY COALESCE(da.hoodie_type, ''), i.size
+		ORDER BY hoodie_type ASC, i.size ASC
+	`
+	rows, err := db.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query items: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.PricingValidationResponse{
+		Unmapped: []models.PricingUnmappedCombination{},
+	}
+	for rows.Next() {
+		var hoodieType, size string
+		var itemCount int
+		if err := rows.Scan(&hoodieType, &size, &itemCount); err != nil {
+			return nil, fmt.Errorf("failed to scan item combination: %w", err)
+		}
+		response.CheckedCombinations++
+
+		reason := e.fallbackPriceReason(hoodieType, size)
+		if reason == "" {
+			continue
+		}
+		_, hasSizeBucket := e.config.SizeBuckets[utils.NormalizeSize(size)]
+		response.Unmapped = append(response.Unmapped, models.PricingUnmappedCombination{
+			HoodieType:    hoodieType,
+			Size:          size,
+			Group:         e.getGroupForProductType(hoodieType),
+			SizeBucket:    e.getSizeBucket(size),
+			HasSizeBucket: hasSizeBucket,
+			Reason:        reason,
+			ItemCount:     itemCount,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate item combinations: %w", err)
+	}
+
+	response.Valid = len(response.Unmapped) == 0
+	log.Printf("💰 ValidateAgainstCatalog: %d combinations checked, %d unmapped", response.CheckedCombinations, len(response.Unmapped))
+	return response, nil
+}
+
 // CalculateOrderPricing calculates pricing for an order based on its lines
 func (e *Engine) CalculateOrderPricing(ctx context.Context, orderID int64) (*models.PricingBreakdown, error) {
+	defer metrics.PricingCalculationDuration.ObserveSince(time.Now(), "order")
+
+	// Pin the active config for the whole calculation
+	e = e.snapshot()
+
 	// Get order lines with product information
 	lines, err := e.getOrderLines(ctx, orderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order lines: %w", err)
 	}
 
+	couponCode, err := e.getOrderCouponCode(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order coupon code: %w", err)
+	}
+
+	utils.Logf(ctx, "💰 CalculateOrderPricing: Order %d has %d lines", orderID, len(lines))
+	breakdown := e.simulatePricing(lines, "", couponCode)
+
+	utils.Logf(ctx, "✅ CalculateOrderPricing: Order %d total = %d, orderType = %s", orderID, breakdown.Total, breakdown.OrderType)
+	return breakdown, nil
+}
+
+// PreviewPricing runs the same bundle/wholesale logic as CalculateOrderPricing on lines supplied
+// by the caller instead of reading them from reserved_order_lines. Each line must already carry
+// its HoodieType and Size. Lines without a LineID are numbered by position (1-based), since bundle
+// allocation tracks remaining quantity per line. couponCode is optional; an invalid or expired code
+// is reported in the breakdown instead of failing the preview.
+func (e *Engine) PreviewPricing(ctx context.Context, lines []OrderLineInput, couponCode string) (*models.PricingBreakdown, error) {
+	numbered := make([]OrderLineInput, len(lines))
+	for i, line := range lines {
+		if line.Qty <= 0 {
+			return nil, fmt.Errorf("qty must be greater than 0 for item %d", line.ItemID)
+		}
+		if line.LineID == 0 {
+			line.LineID = int64(i + 1)
+		}
+		numbered[i] = line
+	}
+	lines = numbered
+
+	log.Printf("💰 PreviewPricing: Cart has %d lines", len(lines))
+	start := time.Now()
+	breakdown := e.snapshot().simulatePricing(lines, "", couponCode)
+	metrics.PricingCalculationDuration.ObserveSince(start, "preview")
+
+	log.Printf("✅ PreviewPricing: total = %d, orderType = %s", breakdown.Total, breakdown.OrderType)
+	return breakdown, nil
+}
+
+// SimulatePricing prices an in-memory set of lines without reading or writing any order.
+// orderType forces "mayorista" or "detal" pricing; when empty, the wholesale override rule decides.
+func (e *Engine) SimulatePricing(lines []OrderLineInput, orderType string) *models.PricingBreakdown {
+	defer metrics.PricingCalculationDuration.ObserveSince(time.Now(), "simulate")
+
+	// Pin the active config for the whole calculation
+	return e.snapshot().simulatePricing(lines, orderType, "")
+}
+
+// simulatePricing is SimulatePricing on an already snapshotted engine, with an optional coupon code
+// applied after bundle/wholesale pricing
+func (e *Engine) simulatePricing(lines []OrderLineInput, orderType string, couponCode string) *models.PricingBreakdown {
 	if len(lines) == 0 {
-		return &models.PricingBreakdown{
+		breakdown := &models.PricingBreakdown{
 			Total:        0,
 			Lines:        []models.PricingLine{},
 			AppliedRules: []string{},
 			OrderType:    "detal",
-		}, nil
+		}
+		e.applyCoupon(breakdown, couponCode, 0)
+		return breakdown
 	}
 
 	// Calculate global eligible quantity (BUSOS + CAMISETAS only)
@@ -214,22 +505,19 @@ func (e *Engine) CalculateOrderPricing(ctx context.Context, orderID int64) (*mod
 		}
 	}
 
-	log.Printf("💰 CalculateOrderPricing: Order %d has %d eligible units (BUSOS+CAMISETAS)", orderID, globalQtyEligible)
-
-	// Check if wholesale override applies (priority 1000)
-	wholesaleOverride := false
-	for _, rule := range e.config.Rules {
-		if !rule.Active {
-			continue
-		}
-		if rule.Type == "wholesale_override" && rule.Priority == 1000 {
-			if minQty, ok := rule.Conditions["minQty"].(float64); ok {
-				if globalQtyEligible >= int(minQty) {
-					wholesaleOverride = true
-					log.Printf("💰 Wholesale override applies: %d >= %d", globalQtyEligible, int(minQty))
-					break
-				}
-			}
+	log.Printf("💰 SimulatePricing: %d lines, %d eligible units (BUSOS+CAMISETAS)", len(lines), globalQtyEligible)
+
+	var wholesaleOverride bool
+	switch strings.ToLower(orderType) {
+	case "mayorista":
+		wholesaleOverride = true
+	case "detal":
+		wholesaleOverride = false
+	default:
+		// Check if wholesale override applies (priority 1000)
+		if minQty, ok := e.wholesaleMinQty(); ok && globalQtyEligible >= minQty {
+			wholesaleOverride = true
+			log.Printf("💰 Wholesale override applies: %d >= %d", globalQtyEligible, minQty)
 		}
 	}
 
@@ -243,8 +531,75 @@ func (e *Engine) CalculateOrderPricing(ctx context.Context, orderID int64) (*mod
 		breakdown.OrderType = "detal"
 	}
 
-	log.Printf("✅ CalculateOrderPricing: Order %d total = %d, orderType = %s", orderID, breakdown.Total, breakdown.OrderType)
-	return breakdown, nil
+	e.applyCoupon(breakdown, couponCode, globalQtyEligible)
+
+	return breakdown
+}
+
+// applyCoupon takes a coupon off the breakdown total and records it in AppliedRules as COUPON_<code>.
+// The discount is spread over the line totals so they keep adding up to the total (and prices frozen
+// from them by Sell match what was paid). Unknown, inactive, expired or ineligible codes leave the
+// total untouched and are reported with applied=false.
+func (e *Engine) applyCoupon(breakdown *models.PricingBreakdown, couponCode string, globalQtyEligible int) {
+	code := normalizeCouponCode(couponCode)
+	if code == "" {
+		return
+	}
+
+	result := &models.PricingCoupon{Code: code}
+	breakdown.Coupon = result
+
+	coupon, exists := e.config.Coupons[code]
+	if !exists {
+		result.Reason = fmt.Sprintf("coupon %s does not exist", code)
+	} else if !coupon.Active {
+		result.Reason = fmt.Sprintf("coupon %s is not active", code)
+	} else if coupon.ExpiresAt != "" && time.Now().Format("2006-01-02") > coupon.ExpiresAt {
+		result.Reason = fmt.Sprintf("coupon %s expired on %s", code, coupon.ExpiresAt)
+	} else if globalQtyEligible < coupon.MinQty {
+		result.Reason = fmt.Sprintf("coupon %s requires at least %d eligible units (BUSOS+CAMISETAS), order has %d", code, coupon.MinQty, globalQtyEligible)
+	}
+	if result.Reason != "" {
+		log.Printf("⚠️ applyCoupon: Ignoring coupon: %s", result.Reason)
+		return
+	}
+
+	var discount int64
+	switch coupon.Type {
+	case "percent_off":
+		discount = breakdown.Total * coupon.Value / 100
+	case "amount_off":
+		discount = coupon.Value
+	}
+	if discount > breakdown.Total {
+		discount = breakdown.Total
+	}
+
+	breakdown.Total -= discount
+	SpreadDiscount(breakdown.Lines, discount)
+	breakdown.AppliedRules = append(breakdown.AppliedRules, "COUPON_"+code)
+	result.Applied = true
+	result.Discount = discount
+	log.Printf("💰 applyCoupon: Coupon %s applied, discount=%d, total=%d", code, discount, breakdown.Total)
+}
+
+// WholesaleMinQty returns the eligible-unit threshold of the active wholesale override rule (priority 1000)
+func (e *Engine) WholesaleMinQty() (int, bool) {
+	return e.snapshot().wholesaleMinQty()
+}
+
+func (e *Engine) wholesaleMinQty() (int, bool) {
+	for _, rule := range e.config.Rules {
+		if !rule.Active {
+			continue
+		}
+		if rule.Type == "wholesale_override" && rule.Priority == 1000 {
+			if minQty, ok := rule.Conditions["minQty"].(float64); ok {
+				return int(minQty), true
+			}
+		}
+	}
+	return 0, false
 }
 
 // getOrderLines retrieves order lines with product information
@@ -288,6 +643,17 @@ func (e *Engine) getOrderLines(ctx context.Context, orderID int64) ([]OrderLineI
 	return lines, rows.Err()
 }
 
+// getOrderCouponCode returns the coupon code stored on a reserved order (empty when none)
+func (e *Engine) getOrderCouponCode(ctx context.Context, orderID int64) (string, error) {
+	var couponCode string
+	query := `SELECT COALESCE(coupon_code, '') FROM reserved_orders WHERE id = $1`
+	err := db.DB.QueryRowContext(ctx, query, orderID).Scan(&couponCode)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return couponCode, err
+}
+
 // calculateWholesalePricing calculates wholesale pricing for all eligible items
 func (e *Engine) calculateWholesalePricing(lines []OrderLineInput) *models.PricingBreakdown {
 	breakdown := &models.PricingBreakdown{
@@ -329,6 +695,15 @@ func (e *Engine) calculateWholesalePricing(lines []OrderLineInput) *models.Prici
 		lineTotal := int64(line.Qty) * unitPrice
 		breakdown.Total += lineTotal
 
+		var explanation string
+		if group == "BUSOS" || group == "CAMISETAS" {
+			explanation = fmt.Sprintf("%s Global wholesale applied (WHOLESALE_GLOBAL_6PLUS): %d x %s wholesale = %s.",
+				e.describeRetailBase(group, sizeBucket), line.Qty, utils.FormatCOP(unitPrice), utils.FormatCOP(lineTotal))
+		} else {
+			explanation = fmt.Sprintf("Base retail price %s (BUSOS %s pricebook). Global wholesale applied to the order, but %s does not get wholesale prices: %d x %s retail = %s.",
+				utils.FormatCOP(unitPrice), sizeBucket, line.HoodieType, line.Qty, utils.FormatCOP(unitPrice), utils.FormatCOP(lineTotal))
+		}
+
 		breakdown.Lines = append(breakdown.Lines, models.PricingLine{
 			LineID:      line.LineID,
 			ItemID:      line.ItemID,
@@ -338,6 +713,7 @@ func (e *Engine) calculateWholesalePricing(lines []OrderLineInput) *models.Prici
 			UnitPrice:   unitPrice,
 			LineTotal:   lineTotal,
 			RuleIDs:     []string{"WHOLESALE_GLOBAL_6PLUS"},
+			Explanation: explanation,
 		})
 	}
 
@@ -561,6 +937,61 @@ func (e *Engine) calculateRetailWithBundles(lines []OrderLineInput, globalQtyEli
 		}
 	}
 
+	// bundleTotalPrice/requiredQty truncates, so unless the rounding policy is "truncate" the remainder
+	// is handed out one unit of currency per bundle unit (lowest line IDs first) so the bundle's lines
+	// add up to the bundle total exactly
+	bundleRemainders := make(map[int64]int64) // lineID -> amount on top of qtyInBundle * bundle unit price
+	if e.config.Rounding.BundleRemainder != "truncate" {
+		sortedLines := append([]OrderLineInput(nil), lines...)
+		sort.Slice(sortedLines, func(i, j int) bool {
+			return sortedLines[i].LineID < sortedLines[j].LineID
+		})
+		// Line totals price bundle units with the line's first bundle rule
+		inRule := func(line OrderLineInput, ruleID string) bool {
+			ids := bundleRuleIDs[line.LineID]
+			return len(ids) > 0 && ids[0] == ruleID
+		}
+		for _, rule := range bundleRules {
+			remainder, ok := bundleTotalsByRule[rule.ID]
+			if !ok {
+				continue
+			}
+			bundleTotalPrice, _ := rule.Action["bundleTotalPrice"].(float64)
+			requiredQty, _ := rule.Conditions["requiredQty"].(float64)
+			unitPrice := int64(bundleTotalPrice) / int64(requiredQty)
+			for _, line := range sortedLines {
+				if inRule(line, rule.ID) {
+					remainder -= int64(bundleApplications[line.LineID]) * unitPrice
+				}
+			}
+			for _, line := range sortedLines {
+				if remainder <= 0 {
+					break
+				}
+				if !inRule(line, rule.ID) {
+					continue
+				}
+				extra := int64(bundleApplications[line.LineID])
+				if extra > remainder {
+					extra = remainder
+				}
+				bundleRemainders[line.LineID] += extra
+				remainder -= extra
+			}
+		}
+	}
+
+	// Percentage discounts apply after bundles, to the units still at retail price
+	percentRules := e.getPercentDiscountRules()
+	percentRuleQty := make(map[string]int) // ruleID -> cart qty of matching lines (for minQty)
+	for _, rule := range percentRules {
+		for _, line := range lines {
+			if e.percentDiscountMatches(rule, line) {
+				percentRuleQty[rule.ID] += line.Qty
+			}
+		}
+	}
+
 	// Calculate retail pricing for remaining quantities and bundle pricing
 	for _, line := range lines {
 		group := e.getGroupForProductType(line.HoodieType)
@@ -600,6 +1031,8 @@ func (e *Engine) calculateRetailWithBundles(lines []OrderLineInput, globalQtyEli
 
 		// Calculate bundle unit price if this line is in a bundle
 		var bundleUnitPrice int64
+		var bundleRule *Rule
+		var bundleRequiredQty int64
 		ruleIDs := bundleRuleIDs[line.LineID]
 		if len(ruleIDs) == 0 {
 			ruleIDs = []string{}
@@ -614,6 +1047,8 @@ func (e *Engine) calculateRetailWithBundles(lines []OrderLineInput, globalQtyEli
 						if requiredQty, ok := rule.Conditions["requiredQty"].(float64); ok {
 							// Bundle unit price = bundleTotalPrice / requiredQty
 							bundleUnitPrice = int64(bundleTotalPrice) / int64(requiredQty)
+							bundleRule = &rule
+							bundleRequiredQty = int64(requiredQty)
 							log.Printf("💰 Bundle unit price for line %d: %d (bundleTotal=%d, requiredQty=%d)",
 								line.LineID, bundleUnitPrice, int64(bundleTotalPrice), int64(requiredQty))
 							break
@@ -623,9 +1058,34 @@ func (e *Engine) calculateRetailWithBundles(lines []OrderLineInput, globalQtyEli
 			}
 		}
 
+		// Apply the highest-priority matching percent_discount rule to the retail units
+		var percentRule *Rule
+		discountedRetailPrice := retailPrice
+		if qtyRetail > 0 {
+			for i, rule := range percentRules {
+				minQty, _ := rule.Conditions["minQty"].(float64)
+				if !e.percentDiscountMatches(rule, line) || percentRuleQty[rule.ID] < int(minQty) {
+					continue
+				}
+				percent, _ := rule.Action["percent"].(float64)
+				percentRule = &percentRules[i]
+				discountedRetailPrice = retailPrice * (100 - int64(percent)) / 100
+				ruleIDs = append(ruleIDs, rule.ID)
+				if !contains(breakdown.AppliedRules, rule.ID) {
+					breakdown.AppliedRules = append(breakdown.AppliedRules, rule.ID)
+				}
+				log.Printf("💰 Percent discount %s for line %d: %d%% off, retail %d -> %d",
+					rule.ID, line.LineID, int64(percent), retailPrice, discountedRetailPrice)
+				break
+			}
+		}
+
+		// Round the charged retail price (e.g. after a percent discount) per the rounding policy
+		discountedRetailPrice = roundToNearest(discountedRetailPrice, e.config.Rounding.RetailNearest)
+
 		// Calculate totals
-		retailTotal := int64(qtyRetail) * retailPrice
-		bundleTotal := int64(qtyInBundle) * bundleUnitPrice
+		retailTotal := int64(qtyRetail) * discountedRetailPrice
+		bundleTotal := int64(qtyInBundle)*bundleUnitPrice + bundleRemainders[line.LineID]
 		lineTotal := retailTotal + bundleTotal
 		breakdown.Total += lineTotal
 
@@ -642,8 +1102,8 @@ func (e *Engine) calculateRetailWithBundles(lines []OrderLineInput, globalQtyEli
 				effectiveUnitPrice = retailPrice
 			}
 		} else {
-			// No units in bundle - use retail price
-			effectiveUnitPrice = retailPrice
+			// No units in bundle - use retail price (after any percent discount)
+			effectiveUnitPrice = discountedRetailPrice
 		}
 
 		breakdown.Lines = append(breakdown.Lines, models.PricingLine{
@@ -655,12 +1115,63 @@ func (e *Engine) calculateRetailWithBundles(lines []OrderLineInput, globalQtyEli
 			UnitPrice:   effectiveUnitPrice, // Effective unit price (bundle price for bundle units, retail for others)
 			LineTotal:   lineTotal,
 			RuleIDs:     ruleIDs,
+			Explanation: e.explainRetailLine(group, sizeBucket, retailPrice, qtyInBundle, qtyRetail, bundleRule, bundleRequiredQty, bundleUnitPrice, percentRule, discountedRetailPrice, globalQtyEligible),
 		})
 	}
 
 	return breakdown
 }
 
+// describeRetailBase describes the pre-discount retail price of a group/size bucket
+func (e *Engine) describeRetailBase(group, sizeBucket string) string {
+	if pricebook, exists := e.config.Pricebook[group]; exists {
+		if priceEntry, exists := pricebook[sizeBucket]; exists && priceEntry.Retail > 0 {
+			return fmt.Sprintf("Base retail price %s (%s %s).", utils.FormatCOP(priceEntry.Retail), group, sizeBucket)
+		}
+	}
+	return fmt.Sprintf("No retail price in the pricebook for %s %s.", group, sizeBucket)
+}
+
+// explainRetailLine describes how a retail line was priced: base retail price, the bundle and
+// percent discount applied (if any) and why global wholesale did not kick in
+func (e *Engine) explainRetailLine(group, sizeBucket string, retailPrice int64, qtyInBundle, qtyRetail int, bundleRule *Rule, bundleRequiredQty, bundleUnitPrice int64, percentRule *Rule, discountedRetailPrice int64, globalQtyEligible int) string {
+	var parts []string
+
+	base := fmt.Sprintf("Base retail price %s", utils.FormatCOP(retailPrice))
+	if group != "" {
+		base += fmt.Sprintf(" (%s %s)", group, sizeBucket)
+	}
+	parts = append(parts, base+".")
+
+	if qtyInBundle > 0 && bundleRule != nil {
+		name := bundleRule.ID
+		if bundleRule.Name != "" {
+			name = fmt.Sprintf("%s (%s)", bundleRule.ID, bundleRule.Name)
+		}
+		parts = append(parts, fmt.Sprintf("Bundle %s applied to %d unit(s): %d units for %s, %s per unit.",
+			name, qtyInBundle, bundleRequiredQty, utils.FormatCOP(bundleRequiredQty*bundleUnitPrice), utils.FormatCOP(bundleUnitPrice)))
+	}
+	if qtyRetail > 0 && percentRule != nil {
+		percent, _ := percentRule.Action["percent"].(float64)
+		parts = append(parts, fmt.Sprintf("%d unit(s) at retail %s with %d%% off (%s) = %s per unit.",
+			qtyRetail, utils.FormatCOP(retailPrice), int64(percent), percentRule.ID, utils.FormatCOP(discountedRetailPrice)))
+	} else if qtyRetail > 0 {
+		parts = append(parts, fmt.Sprintf("%d unit(s) at retail %s.", qtyRetail, utils.FormatCOP(retailPrice)))
+	}
+
+	if minQty, ok := e.wholesaleMinQty(); ok {
+		if globalQtyEligible < minQty {
+			parts = append(parts, fmt.Sprintf("Global wholesale not applied: %d eligible unit(s) (BUSOS+CAMISETAS), needs %d.", globalQtyEligible, minQty))
+		} else {
+			parts = append(parts, "Global wholesale not applied: order priced as detal.")
+		}
+	} else {
+		parts = append(parts, "Global wholesale not applied.")
+	}
+
+	return strings.Join(parts, " ")
+}
+
 // getBundleRules returns active bundle rules sorted by priority
 func (e *Engine) getBundleRules() []Rule {
 	var bundleRules []Rule
@@ -672,6 +1183,94 @@ func (e *Engine) getBundleRules() []Rule {
 	return bundleRules
 }
 
+// getPercentDiscountRules returns active percent_discount rules, highest priority first
+// (loadConfig keeps rules sorted by priority)
+func (e *Engine) getPercentDiscountRules() []Rule {
+	var percentRules []Rule
+	for _, rule := range e.config.Rules {
+		if rule.Active && rule.Type == "percent_discount" {
+			percentRules = append(percentRules, rule)
+		}
+	}
+	return percentRules
+}
+
+// percentDiscountMatches reports whether a line is in the rule's group and sizes
+// An empty sizes list matches every size of the group
+func (e *Engine) percentDiscountMatches(rule Rule, line OrderLineInput) bool {
+	group, _ := rule.Conditions["group"].(string)
+	if e.getGroupForProductType(line.HoodieType) != group {
+		return false
+	}
+	sizes, _ := rule.Conditions["sizes"].([]interface{})
+	if len(sizes) == 0 {
+		return true
+	}
+	normalizedLineSize := utils.NormalizeSize(line.Size)
+	for _, size := range sizes {
+		if sizeStr, ok := size.(string); ok && utils.NormalizeSize(sizeStr) == normalizedLineSize {
+			return true
+		}
+	}
+	return false
+}
+
+// roundToNearest rounds amount to the nearest multiple of nearest (halves round up)
+// nearest <= 1 leaves the amount unchanged
+func roundToNearest(amount, nearest int64) int64 {
+	if nearest <= 1 {
+		return amount
+	}
+	return (amount + nearest/2) / nearest * nearest
+}
+
+// SpreadDiscount takes discount off the line totals in proportion to each line's total, so the
+// lines keep adding up to the discounted order total. Rounding leftovers go to the largest lines
+// first, and no line total is driven below 0.
+func SpreadDiscount(lines []models.PricingLine, discount int64) {
+	var total int64
+	for _, line := range lines {
+		total += line.LineTotal
+	}
+	if discount <= 0 || total <= 0 {
+		return
+	}
+	if discount > total {
+		discount = total
+	}
+
+	shares := make([]int64, len(lines))
+	var spread int64
+	for i, line := range lines {
+		shares[i] = discount * line.LineTotal / total
+		spread += shares[i]
+	}
+
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return lines[order[a]].LineTotal > lines[order[b]].LineTotal
+	})
+	for _, i := range order {
+		leftover := discount - spread
+		if leftover == 0 {
+			break
+		}
+		take := lines[i].LineTotal - shares[i]
+		if take > leftover {
+			take = leftover
+		}
+		shares[i] += take
+		spread += take
+	}
+
+	for i := range lines {
+		lines[i].LineTotal -= shares[i]
+	}
+}
+
 // contains checks if a string slice contains a value
 func contains(slice []string, value string) bool {
 	for _, v := range slice {
@@ -25,6 +25,29 @@ func capitalizeWords(s string) string {
 	return strings.Join(words, " ")
 }
 
+// CatalogFilterParams represents optional catalog filters (codes, empty means no filter)
+type CatalogFilterParams struct {
+	ColorPrimary string // design_assets.color_primary code (e.g., "NG")
+	HoodieType   string // design_assets.hoodie_type code (e.g., "BU")
+	Sort         string // one of catalogSortOrders (e.g., "newest"), empty keeps the default order by design code
+}
+
+// catalogSortOrders maps the supported catalog sort options to their ORDER BY clause.
+// Design code is always the tie-breaker so the order is stable between renders
+var catalogSortOrders = map[string]string{
+	"":           "da.code ASC",
+	"newest":     "i.created_at DESC, da.code ASC",
+	"price_asc":  "i.price ASC, da.code ASC",
+	"price_desc": "i.price DESC, da.code ASC",
+	"color":      "da.color_primary ASC, da.code ASC",
+}
+
+// IsValidCatalogSort reports whether sort is a supported catalog sort option (empty is the default order)
+func IsValidCatalogSort(sort string) bool {
+	_, ok := catalogSortOrders[sort]
+	return ok
+}
+
 // CatalogRepository handles database operations for catalog generation
 type CatalogRepository struct{}
 
@@ -37,8 +60,9 @@ func NewCatalogRepository() *CatalogRepository {
 var _ CatalogRepositoryInterface = (*CatalogRepository)(nil)
 
 // GetItemsBySizeForCatalog retrieves all active items for a specific size with design asset information
-func (r *CatalogRepository) GetItemsBySizeForCatalog(ctx context.Context, size string) ([]models.CatalogItem, error) {
-	log.Printf("🔍 GetItemsBySizeForCatalog: Fetching items for size=%s", size)
+// Non-empty filters are ANDed against design_assets.color_primary and hoodie_type; filters.Sort picks the ORDER BY
+func (r *CatalogRepository) GetItemsBySizeForCatalog(ctx context.Context, size string, filters CatalogFilterParams) ([]models.CatalogItem, error) {
+	log.Printf("🔍 GetItemsBySizeForCatalog: Fetching items for size=%s (color=%q, hoodieType=%q, sort=%q)", size, filters.ColorPrimary, filters.HoodieType, filters.Sort)
 
 	// Normalize size
 	normalizedSize := utils.NormalizeSize(size)
@@ -64,10 +88,25 @@ func (r *CatalogRepository) GetItemsBySizeForCatalog(ctx context.Context, size s
 		  AND da.is_active = true
 		  AND da.status IN ('ready', 'custom-ready')
 		  AND (i.stock_total - i.stock_reserved) > 0
-		ORDER BY da.code ASC
 	`
+	args := []interface{}{normalizedSize}
+
+	if filters.ColorPrimary != "" {
+		args = append(args, filters.ColorPrimary)
+		query += fmt.Sprintf(" AND da.color_primary = $%d", len(args))
+	}
+	if filters.HoodieType != "" {
+		args = append(args, filters.HoodieType)
+		query += fmt.Sprintf(" AND da.hoodie_type = $%d", len(args))
+	}
+
+	orderBy, ok := catalogSortOrders[filters.Sort]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort: %s", filters.Sort)
+	}
+	query += " ORDER BY " + orderBy
 
-	rows, err := db.DB.QueryContext(ctx, query, normalizedSize)
+	rows, err := db.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("❌ Error querying items for catalog: %v", err)
 		return nil, fmt.Errorf("failed to query items: %w", err)
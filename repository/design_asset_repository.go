@@ -10,6 +10,7 @@ import (
 
 	"armario-mascota-me/db"
 	"armario-mascota-me/models"
+	"armario-mascota-me/utils"
 )
 
 // DesignAssetRepository handles database operations for design assets
@@ -83,14 +84,24 @@ func (r *DesignAssetRepository) Insert(ctx context.Context, asset *models.Design
 		return fmt.Errorf("failed to get max deco_id: %w", err)
 	}
 
+	// Warn (without blocking) when the same image was already imported under another Drive file id
+	if asset.ImageHash != "" {
+		duplicates, err := r.findCodesByImageHash(ctx, asset.ImageHash)
+		if err != nil {
+			log.Printf("⚠️  Warning: Could not check image hash for drive_file_id %s: %v", asset.DriveFileID, err)
+		} else if len(duplicates) > 0 {
+			log.Printf("⚠️  Possible duplicate: drive_file_id %s has the same image as design assets %s", asset.DriveFileID, strings.Join(duplicates, ", "))
+		}
+	}
+
 	nextDecoID := maxDecoID + 1
 	nextDecoIDStr := fmt.Sprintf("%d", nextDecoID)
 	log.Printf("🔢 Next deco_id will be: %s", nextDecoIDStr)
 
 	query := `
 		INSERT INTO design_assets (
-			code, drive_file_id, image_url, deco_id, status, created_at, is_active
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			code, drive_file_id, image_url, deco_id, status, created_at, is_active, image_rejected_reason, source_filename, image_hash
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''))
 		ON CONFLICT (drive_file_id) DO NOTHING
 	`
 
@@ -115,6 +126,9 @@ func (r *DesignAssetRepository) Insert(ctx context.Context, asset *models.Design
 		status,        // Use provided status or default to "pending"
 		createdAt,
 		true, // is_active defaults to true
+		asset.ImageRejectedReason,
+		asset.SourceFilename,
+		asset.ImageHash,
 	)
 
 	if err != nil {
@@ -138,6 +152,111 @@ func (r *DesignAssetRepository) Insert(ctx context.Context, asset *models.Design
 	return nil
 }
 
+// findCodesByImageHash returns the codes of the design assets whose original image has the given hash
+func (r *DesignAssetRepository) findCodesByImageHash(ctx context.Context, imageHash string) ([]string, error) {
+	rows, err := db.DB.QueryContext(ctx, `SELECT code FROM design_assets WHERE image_hash = $1 ORDER BY created_at ASC, id ASC`, imageHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query design assets by image hash: %w", err)
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan design asset code: %w", err)
+		}
+		codes = append(codes, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate design assets by image hash: %w", err)
+	}
+	return codes, nil
+}
+
+// DuplicateGroup holds the design assets sharing the same original image hash, oldest first
+type DuplicateGroup struct {
+	ImageHash string
+	Assets    []models.DesignAssetDetail
+}
+
+// GetDuplicates groups design assets whose original images share the same hash.
+// Groups are ordered by their oldest asset so the original import comes first
+func (r *DesignAssetRepository) GetDuplicates(ctx context.Context) ([]DuplicateGroup, error) {
+	log.Printf("🔍 Fetching duplicate design assets by image hash")
+
+	query := `
+		SELECT id, code, 
+		       COALESCE(description, '') as description, 
+		       drive_file_id, 
+		       image_url,
+		       COALESCE(color_primary, '') as color_primary, 
+		       COALESCE(color_secondary, '') as color_secondary, 
+		       COALESCE(hoodie_type, '') as hoodie_type, 
+		       COALESCE(image_type, '') as image_type,
+		       COALESCE(deco_id, '') as deco_id, 
+		       COALESCE(deco_base, '') as deco_base, 
+		       is_active, 
+		       has_highlights,
+		       COALESCE(source_filename, '') as source_filename,
+		       image_hash
+		FROM (
+			SELECT *,
+			       COUNT(*) OVER (PARTITION BY image_hash) as hash_count,
+			       MIN(created_at) OVER (PARTITION BY image_hash) as first_created_at
+			FROM design_assets
+			WHERE image_hash IS NOT NULL
+		) da
+		WHERE hash_count > 1
+		ORDER BY first_created_at ASC, image_hash ASC, created_at ASC, id ASC
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("❌ Error fetching duplicate design assets: %v", err)
+		return nil, fmt.Errorf("failed to get duplicate design assets: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []DuplicateGroup{}
+	for rows.Next() {
+		var asset models.DesignAssetDetail
+		var imageHash string
+		if err := rows.Scan(
+			&asset.ID,
+			&asset.Code,
+			&asset.Description,
+			&asset.DriveFileID,
+			&asset.ImageURL,
+			&asset.ColorPrimary,
+			&asset.ColorSecondary,
+			&asset.HoodieType,
+			&asset.ImageType,
+			&asset.DecoID,
+			&asset.DecoBase,
+			&asset.IsActive,
+			&asset.HasHighlights,
+			&asset.SourceFilename,
+			&imageHash,
+		); err != nil {
+			log.Printf("❌ Error scanning duplicate design asset: %v", err)
+			return nil, fmt.Errorf("failed to scan duplicate design asset: %w", err)
+		}
+		if len(groups) == 0 || groups[len(groups)-1].ImageHash != imageHash {
+			groups = append(groups, DuplicateGroup{ImageHash: imageHash})
+		}
+		groups[len(groups)-1].Assets = append(groups[len(groups)-1].Assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ Error iterating duplicate design assets: %v", err)
+		return nil, fmt.Errorf("failed to iterate duplicate design assets: %w", err)
+	}
+
+	log.Printf("✓ Successfully fetched %d duplicate groups", len(groups))
+	return groups, nil
+}
+
 // GetByCode retrieves a design asset by its code
 func (r *DesignAssetRepository) GetByCode(ctx context.Context, code string) (*models.DesignAssetDetail, error) {
 	log.Printf("🔍 Fetching design asset by code: %s", code)
@@ -185,17 +304,25 @@ func (r *DesignAssetRepository) GetByCode(ctx context.Context, code string) (*mo
 	return &asset, nil
 }
 
-// UpdateDescriptionAndHighlights updates the description and has_highlights fields of a design asset
-func (r *DesignAssetRepository) UpdateDescriptionAndHighlights(ctx context.Context, code string, description string, hasHighlights bool) error {
-	log.Printf("🔄 Updating design asset: code=%s, description=%s, hasHighlights=%v", code, description, hasHighlights)
+// Update sets the description and has_highlights of a design asset, and its classification codes
+// (color_primary, color_secondary, hoodie_type, image_type, deco_base). Empty codes keep the current value
+func (r *DesignAssetRepository) Update(ctx context.Context, code string, req *models.DesignAssetUpdateRequest) error {
+	log.Printf("🔄 Updating design asset: code=%s, description=%s, hasHighlights=%v, colorPrimary=%s, colorSecondary=%s, hoodieType=%s, imageType=%s, decoBase=%s",
+		code, req.Description, req.HasHighlights, req.ColorPrimary, req.ColorSecondary, req.HoodieType, req.ImageType, req.DecoBase)
 
 	query := `
 		UPDATE design_assets
-		SET description = $1, has_highlights = $2
+		SET description = $1, has_highlights = $2,
+		    color_primary = COALESCE(NULLIF($4, ''), color_primary),
+		    color_secondary = COALESCE(NULLIF($5, ''), color_secondary),
+		    hoodie_type = COALESCE(NULLIF($6, ''), hoodie_type),
+		    image_type = COALESCE(NULLIF($7, ''), image_type),
+		    deco_base = COALESCE(NULLIF($8, ''), deco_base)
 		WHERE code = $3
 	`
 
-	result, err := db.DB.ExecContext(ctx, query, description, hasHighlights, code)
+	result, err := db.DB.ExecContext(ctx, query, req.Description, req.HasHighlights, code,
+		req.ColorPrimary, req.ColorSecondary, req.HoodieType, req.ImageType, req.DecoBase)
 	if err != nil {
 		log.Printf("❌ Error updating design asset %s: %v", code, err)
 		return fmt.Errorf("failed to update design asset: %w", err)
@@ -215,10 +342,306 @@ func (r *DesignAssetRepository) UpdateDescriptionAndHighlights(ctx context.Conte
 	return nil
 }
 
+// DesignAssetInUseError is returned by Delete when items still reference the design asset
+type DesignAssetInUseError struct {
+	Code      string
+	ItemCount int
+}
+
+func (e *DesignAssetInUseError) Error() string {
+	return fmt.Sprintf("design asset %s is referenced by %d items", e.Code, e.ItemCount)
+}
+
+// SetActive activates or deactivates a design asset (soft delete). Items and orders that already
+// reference the asset are left untouched
+func (r *DesignAssetRepository) SetActive(ctx context.Context, code string, active bool) (*models.DesignAssetDetail, error) {
+	log.Printf("🔄 SetDesignAssetActive: code=%s, is_active=%t", code, active)
+
+	result, err := db.DB.ExecContext(ctx, `UPDATE design_assets SET is_active = $1 WHERE code = $2`, active, code)
+	if err != nil {
+		log.Printf("❌ SetDesignAssetActive: Error updating design asset %s: %v", code, err)
+		return nil, fmt.Errorf("failed to update design asset: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Printf("❌ SetDesignAssetActive: Design asset not found: code=%s", code)
+		return nil, fmt.Errorf("design asset with code %s not found", code)
+	}
+
+	log.Printf("✅ SetDesignAssetActive: code=%s is_active=%t", code, active)
+	return r.GetByCode(ctx, code)
+}
+
+// designAssetStatuses lists the statuses allowed by the design_assets_status_check constraint
+var designAssetStatuses = map[string]bool{
+	"pending":        true,
+	"ready":          true,
+	"custom-pending": true,
+	"custom-ready":   true,
+}
+
+// IsValidDesignAssetStatus reports whether status is an allowed design asset status
+func IsValidDesignAssetStatus(status string) bool {
+	return designAssetStatuses[status]
+}
+
+// BulkUpdateStatus sets the status of every design asset referenced by code or id in a single
+// transaction. If any reference does not exist nothing is updated
+func (r *DesignAssetRepository) BulkUpdateStatus(ctx context.Context, codes []string, ids []int, status string) (*models.BulkUpdateDesignAssetStatusResponse, error) {
+	log.Printf("🔄 BulkUpdateDesignAssetStatus: %d codes, %d ids, status=%s", len(codes), len(ids), status)
+
+	if !IsValidDesignAssetStatus(status) {
+		return nil, fmt.Errorf("invalid status %q: must be pending, ready, custom-pending or custom-ready", status)
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Resolve every reference to an id first, so an asset listed by code and id is updated once
+	assetIDs := make(map[int]bool)
+	var notFound []string
+	for _, code := range codes {
+		var id int
+		err := tx.QueryRowContext(ctx, `SELECT id FROM design_assets WHERE code = $1 FOR UPDATE`, code).Scan(&id)
+		if err == sql.ErrNoRows {
+			notFound = append(notFound, code)
+			continue
+		}
+		if err != nil {
+			log.Printf("❌ BulkUpdateDesignAssetStatus: Error fetching design asset %s: %v", code, err)
+			return nil, fmt.Errorf("failed to get design asset: %w", err)
+		}
+		assetIDs[id] = true
+	}
+	for _, id := range ids {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `SELECT true FROM design_assets WHERE id = $1 FOR UPDATE`, id).Scan(&exists)
+		if err == sql.ErrNoRows {
+			notFound = append(notFound, fmt.Sprintf("id %d", id))
+			continue
+		}
+		if err != nil {
+			log.Printf("❌ BulkUpdateDesignAssetStatus: Error fetching design asset id=%d: %v", id, err)
+			return nil, fmt.Errorf("failed to get design asset: %w", err)
+		}
+		assetIDs[id] = true
+	}
+	if len(notFound) > 0 {
+		log.Printf("❌ BulkUpdateDesignAssetStatus: Design assets not found: %v", notFound)
+		return nil, fmt.Errorf("design assets not found: %s", strings.Join(notFound, ", "))
+	}
+
+	response := &models.BulkUpdateDesignAssetStatusResponse{
+		Status:    status,
+		Requested: len(assetIDs),
+	}
+	for id := range assetIDs {
+		result, err := tx.ExecContext(ctx, `UPDATE design_assets SET status = $1 WHERE id = $2 AND status <> $1`, status, id)
+		if err != nil {
+			log.Printf("❌ BulkUpdateDesignAssetStatus: Error updating design asset id=%d: %v", id, err)
+			return nil, fmt.Errorf("failed to update design asset status: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		response.Updated += int(rowsAffected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ BulkUpdateDesignAssetStatus: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ BulkUpdateDesignAssetStatus: %d of %d design assets moved to %s", response.Updated, response.Requested, status)
+	return response, nil
+}
+
+// publishedStatuses maps a pending status to the status a design asset gets when published
+var publishedStatuses = map[string]string{
+	"pending":        "ready",
+	"custom-pending": "custom-ready",
+}
+
+// Publish activates a design asset and creates its items in a single transaction: the status moves
+// from pending to ready (custom-pending to custom-ready) and one item is created per requested size,
+// with the "<size>_<design asset code>" SKU. Sizes that already have an item are skipped, so
+// publishing again is safe
+func (r *DesignAssetRepository) Publish(ctx context.Context, id int, req *models.PublishDesignAssetRequest) (*models.PublishDesignAssetResponse, error) {
+	log.Printf("📦 PublishDesignAsset: id=%d, %d sizes", id, len(req.Items))
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("items are required")
+	}
+	seenSizes := make(map[string]bool)
+	for i := range req.Items {
+		entry := &req.Items[i]
+		size := utils.NormalizeSize(entry.Size)
+		if !utils.IsKnownSize(size) {
+			return nil, fmt.Errorf("invalid size %s: must be one of XS, S, M, L, XL, MN, IT", entry.Size)
+		}
+		if seenSizes[size] {
+			return nil, fmt.Errorf("invalid items: size %s is listed more than once", size)
+		}
+		seenSizes[size] = true
+		if entry.Price <= 0 {
+			return nil, fmt.Errorf("price for size %s must be greater than 0", size)
+		}
+		if entry.StockTotal < 0 {
+			return nil, fmt.Errorf("stockTotal for size %s must be greater than or equal to 0", size)
+		}
+		entry.Size = size
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var code, status string
+	err = tx.QueryRowContext(ctx, `SELECT code, status FROM design_assets WHERE id = $1 FOR UPDATE`, id).Scan(&code, &status)
+	if err == sql.ErrNoRows {
+		log.Printf("❌ PublishDesignAsset: Design asset not found: id=%d", id)
+		return nil, fmt.Errorf("design asset with id %d not found", id)
+	}
+	if err != nil {
+		log.Printf("❌ PublishDesignAsset: Error fetching design asset id=%d: %v", id, err)
+		return nil, fmt.Errorf("failed to get design asset: %w", err)
+	}
+
+	if published, ok := publishedStatuses[status]; ok {
+		status = published
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE design_assets SET status = $1, is_active = true WHERE id = $2`, status, id); err != nil {
+		log.Printf("❌ PublishDesignAsset: Error updating design asset id=%d: %v", id, err)
+		return nil, fmt.Errorf("failed to update design asset status: %w", err)
+	}
+
+	response := &models.PublishDesignAssetResponse{
+		DesignAssetID: id,
+		Status:        status,
+		Created:       []models.Item{},
+		Skipped:       []string{},
+	}
+	for _, entry := range req.Items {
+		// items has UNIQUE(design_asset_id, size)
+		var existingItemID int64
+		err := tx.QueryRowContext(ctx, `SELECT id FROM items WHERE design_asset_id = $1 AND size = $2`, id, entry.Size).Scan(&existingItemID)
+		if err == nil {
+			log.Printf("⚠️ PublishDesignAsset: Skipping size %s, item %d already exists", entry.Size, existingItemID)
+			response.Skipped = append(response.Skipped, entry.Size)
+			continue
+		}
+		if err != sql.ErrNoRows {
+			log.Printf("❌ PublishDesignAsset: Error checking existing item: %v", err)
+			return nil, fmt.Errorf("failed to check existing item: %w", err)
+		}
+
+		sku := fmt.Sprintf("%s_%s", entry.Size, code)
+		if err := checkSKUAvailable(ctx, tx, sku, 0); err != nil {
+			log.Printf("❌ PublishDesignAsset: %v", err)
+			return nil, err
+		}
+
+		var itemID int64
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO items (design_asset_id, size, sku, price, stock_total, stock_reserved, is_active, created_at)
+			VALUES ($1, $2, $3, $4, $5, 0, true, NOW())
+			RETURNING id
+		`, id, entry.Size, sku, entry.Price, entry.StockTotal).Scan(&itemID)
+		if err != nil {
+			log.Printf("❌ PublishDesignAsset: Error inserting item for size %s: %v", entry.Size, err)
+			return nil, fmt.Errorf("failed to insert item: %w", err)
+		}
+
+		item, err := scanItem(tx.QueryRowContext(ctx, queryItemByID, itemID))
+		if err != nil {
+			log.Printf("❌ PublishDesignAsset: Error fetching created item: %v", err)
+			return nil, fmt.Errorf("failed to fetch created item: %w", err)
+		}
+		response.Created = append(response.Created, *item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ PublishDesignAsset: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ PublishDesignAsset: id=%d status=%s, created %d items, skipped %d", id, status, len(response.Created), len(response.Skipped))
+	return response, nil
+}
+
+// Delete permanently removes a design asset. It refuses with *DesignAssetInUseError when any item
+// references the asset, so existing orders keep their history
+func (r *DesignAssetRepository) Delete(ctx context.Context, code string) error {
+	log.Printf("🗑️  DeleteDesignAsset: code=%s", code)
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM design_assets WHERE code = $1 FOR UPDATE`, code).Scan(&id)
+	if err == sql.ErrNoRows {
+		log.Printf("❌ DeleteDesignAsset: Design asset not found: code=%s", code)
+		return fmt.Errorf("design asset with code %s not found", code)
+	}
+	if err != nil {
+		log.Printf("❌ DeleteDesignAsset: Error fetching design asset %s: %v", code, err)
+		return fmt.Errorf("failed to get design asset: %w", err)
+	}
+
+	var itemCount int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM items WHERE design_asset_id = $1`, id).Scan(&itemCount); err != nil {
+		log.Printf("❌ DeleteDesignAsset: Error counting items for design asset %s: %v", code, err)
+		return fmt.Errorf("failed to count referencing items: %w", err)
+	}
+	if itemCount > 0 {
+		log.Printf("⚠️  DeleteDesignAsset: code=%s is referenced by %d items", code, itemCount)
+		return &DesignAssetInUseError{Code: code, ItemCount: itemCount}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM design_assets WHERE id = $1`, id); err != nil {
+		log.Printf("❌ DeleteDesignAsset: Error deleting design asset %s: %v", code, err)
+		return fmt.Errorf("failed to delete design asset: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ DeleteDesignAsset: code=%s (id=%d) deleted", code, id)
+	return nil
+}
+
+// incompleteClassificationCondition matches design assets missing any field required to build their code
+const incompleteClassificationCondition = `(
+		COALESCE(color_primary, '') = '' OR
+		COALESCE(color_secondary, '') = '' OR
+		COALESCE(hoodie_type, '') = '' OR
+		COALESCE(image_type, '') = '' OR
+		COALESCE(deco_base, '') = ''
+	)`
+
 // getByStatus is a generic helper method that retrieves design assets by status
 // This method contains the common SQL query logic used by GetPending and GetCustomPending
-func (r *DesignAssetRepository) getByStatus(ctx context.Context, status string, limit int) ([]models.DesignAssetDetail, error) {
-	log.Printf("🔍 Fetching design assets with status = '%s' (limit: %d)", status, limit)
+// When incompleteOnly is true, only assets missing classification fields are returned
+func (r *DesignAssetRepository) getByStatus(ctx context.Context, status string, limit, offset int, incompleteOnly bool) ([]models.DesignAssetDetail, error) {
+	log.Printf("🔍 Fetching design assets with status = '%s' (limit: %d, offset: %d, incompleteOnly: %t)", status, limit, offset, incompleteOnly)
+
+	whereClause := "WHERE status = $1"
+	if incompleteOnly {
+		whereClause += " AND " + incompleteClassificationCondition
+	}
 
 	query := `
 		SELECT id, code, 
@@ -232,14 +655,15 @@ func (r *DesignAssetRepository) getByStatus(ctx context.Context, status string,
 		       COALESCE(deco_id, '') as deco_id, 
 		       COALESCE(deco_base, '') as deco_base, 
 		       is_active, 
-		       has_highlights
+		       has_highlights,
+		       COALESCE(source_filename, '') as source_filename
 		FROM design_assets
-		WHERE status = $1
-		ORDER BY created_at ASC
-		LIMIT $2
+		` + whereClause + `
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := db.DB.QueryContext(ctx, query, status, limit)
+	rows, err := db.DB.QueryContext(ctx, query, status, limit, offset)
 	if err != nil {
 		log.Printf("❌ Error fetching design assets with status '%s': %v", status, err)
 		return nil, fmt.Errorf("failed to get design assets with status '%s': %w", status, err)
@@ -263,6 +687,7 @@ func (r *DesignAssetRepository) getByStatus(ctx context.Context, status string,
 			&asset.DecoBase,
 			&asset.IsActive,
 			&asset.HasHighlights,
+			&asset.SourceFilename,
 		)
 		if err != nil {
 			log.Printf("❌ Error scanning design asset with status '%s': %v", status, err)
@@ -280,14 +705,31 @@ func (r *DesignAssetRepository) getByStatus(ctx context.Context, status string,
 	return assets, nil
 }
 
-// GetPending retrieves all design assets with status = 'pending' (limited to 10 rows)
-func (r *DesignAssetRepository) GetPending(ctx context.Context) ([]models.DesignAssetDetail, error) {
-	return r.getByStatus(ctx, "pending", 10)
+// GetPending retrieves a page of design assets with status = 'pending', oldest first, together with
+// the total number of pending assets matching the same filter
+func (r *DesignAssetRepository) GetPending(ctx context.Context, limit, offset int, incompleteOnly bool) ([]models.DesignAssetDetail, int, error) {
+	assets, err := r.getByStatus(ctx, "pending", limit, offset, incompleteOnly)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	countQuery := `SELECT COUNT(*) FROM design_assets WHERE status = 'pending'`
+	if incompleteOnly {
+		countQuery += " AND " + incompleteClassificationCondition
+	}
+
+	var totalPending int
+	if err := db.DB.QueryRowContext(ctx, countQuery).Scan(&totalPending); err != nil {
+		log.Printf("❌ Error counting pending design assets: %v", err)
+		return nil, 0, fmt.Errorf("failed to count pending design assets: %w", err)
+	}
+
+	return assets, totalPending, nil
 }
 
 // GetCustomPending retrieves all design assets with status = 'custom-pending' (limited to 10 rows)
 func (r *DesignAssetRepository) GetCustomPending(ctx context.Context) ([]models.DesignAssetDetail, error) {
-	return r.getByStatus(ctx, "custom-pending", 10)
+	return r.getByStatus(ctx, "custom-pending", 10, 0, false)
 }
 
 // GetByID retrieves a design asset by its ID
@@ -306,7 +748,9 @@ func (r *DesignAssetRepository) GetByID(ctx context.Context, id int) (*models.De
 		       COALESCE(deco_id, '') as deco_id, 
 		       COALESCE(deco_base, '') as deco_base, 
 		       is_active, 
-		       has_highlights
+		       has_highlights,
+		       COALESCE(image_rejected_reason, '') as image_rejected_reason,
+		       image_rotation
 		FROM design_assets
 		WHERE id = $1
 	`
@@ -326,6 +770,8 @@ func (r *DesignAssetRepository) GetByID(ctx context.Context, id int) (*models.De
 		&asset.DecoBase,
 		&asset.IsActive,
 		&asset.HasHighlights,
+		&asset.ImageRejectedReason,
+		&asset.ImageRotation,
 	)
 
 	if err != nil {
@@ -337,6 +783,89 @@ func (r *DesignAssetRepository) GetByID(ctx context.Context, id int) (*models.De
 	return &asset, nil
 }
 
+// MarkImageRejected flags a design asset whose original image exceeds the source limits,
+// so the image is not downloaded and optimized again on every request
+func (r *DesignAssetRepository) MarkImageRejected(ctx context.Context, id int, reason string) error {
+	log.Printf("📦 MarkImageRejected: id=%d, reason=%s", id, reason)
+
+	result, err := db.DB.ExecContext(ctx, `
+		UPDATE design_assets
+		SET image_rejected_reason = $2
+		WHERE id = $1
+	`, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark image as rejected: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("design asset not found: id=%d", id)
+	}
+
+	return nil
+}
+
+// SetImageRotation stores the manual clockwise rotation (0, 90, 180 or 270) of a design asset's images
+func (r *DesignAssetRepository) SetImageRotation(ctx context.Context, id int, rotation int) error {
+	log.Printf("📦 SetImageRotation: id=%d, rotation=%d", id, rotation)
+
+	result, err := db.DB.ExecContext(ctx, `UPDATE design_assets SET image_rotation = $2 WHERE id = $1`, id, rotation)
+	if err != nil {
+		return fmt.Errorf("failed to set image rotation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("design asset not found: id=%d", id)
+	}
+
+	return nil
+}
+
+// autoTagBatchLimit caps how many pending assets a single auto-tag run inspects
+const autoTagBatchLimit = 500
+
+// GetPendingForAutoTag retrieves pending design assets (oldest first) for auto-tagging
+func (r *DesignAssetRepository) GetPendingForAutoTag(ctx context.Context) ([]models.DesignAssetDetail, error) {
+	return r.getByStatus(ctx, "pending", autoTagBatchLimit, 0, false)
+}
+
+// ApplyAutoTags sets the parsed attributes on a pending design asset.
+// Empty values keep the current column value and the status stays 'pending',
+// so staff still review and confirm the asset through the full update
+func (r *DesignAssetRepository) ApplyAutoTags(ctx context.Context, id int, tags models.AutoTagFields) error {
+	log.Printf("📦 ApplyAutoTags: id=%d, tags=%+v", id, tags)
+
+	result, err := db.DB.ExecContext(ctx, `
+		UPDATE design_assets
+		SET color_primary = COALESCE(NULLIF($2, ''), color_primary),
+		    color_secondary = COALESCE(NULLIF($3, ''), color_secondary),
+		    hoodie_type = COALESCE(NULLIF($4, ''), hoodie_type),
+		    image_type = COALESCE(NULLIF($5, ''), image_type),
+		    deco_base = COALESCE(NULLIF($6, ''), deco_base)
+		WHERE id = $1 AND status = 'pending'
+	`, id, tags.ColorPrimary, tags.ColorSecondary, tags.HoodieType, tags.ImageType, tags.DecoBase)
+	if err != nil {
+		return fmt.Errorf("failed to apply auto tags: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pending design asset not found: id=%d", id)
+	}
+
+	return nil
+}
+
 // UpdateFullDesignAsset updates all fields of a design asset by ID
 func (r *DesignAssetRepository) UpdateFullDesignAsset(ctx context.Context, id int, code, description, colorPrimary, colorSecondary, hoodieType, imageType, decoID, decoBase string, hasHighlights bool, status string) error {
 	log.Printf("🔄 Updating full design asset: id=%d, code=%s, description=%s, colorPrimary=%s, colorSecondary=%s, hoodieType=%s, imageType=%s, decoID=%s, decoBase=%s, hasHighlights=%v, status=%s",
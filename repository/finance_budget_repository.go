@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"armario-mascota-me/db"
+	"armario-mascota-me/models"
+	"armario-mascota-me/utils"
+)
+
+// FinanceBudgetRepository handles database operations for monthly finance budgets
+type FinanceBudgetRepository struct {
+	finance *FinanceTransactionRepository
+}
+
+// NewFinanceBudgetRepository creates a new FinanceBudgetRepository
+func NewFinanceBudgetRepository() *FinanceBudgetRepository {
+	return &FinanceBudgetRepository{finance: NewFinanceTransactionRepository()}
+}
+
+// Ensure FinanceBudgetRepository implements FinanceBudgetRepositoryInterface
+var _ FinanceBudgetRepositoryInterface = (*FinanceBudgetRepository)(nil)
+
+const financeBudgetColumns = `id, category, period_month, limit_amount, created_at, updated_at`
+
+// budgetMonthLayout is the format of budget months in requests and responses
+const budgetMonthLayout = "2006-01"
+
+// parseBudgetMonth parses a YYYY-MM month into the first day of that month
+func parseBudgetMonth(month string) (time.Time, error) {
+	parsed, err := time.Parse(budgetMonthLayout, strings.TrimSpace(month))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month format, use YYYY-MM")
+	}
+	return parsed, nil
+}
+
+// scanFinanceBudget scans a row selected with financeBudgetColumns
+func scanFinanceBudget(scanner interface{ Scan(dest ...any) error }) (*models.FinanceBudget, error) {
+	var budget models.FinanceBudget
+	var periodMonth time.Time
+	err := scanner.Scan(
+		&budget.ID,
+		&budget.Category,
+		&periodMonth,
+		&budget.LimitAmount,
+		&budget.CreatedAt,
+		&budget.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	budget.Month = periodMonth.Format(budgetMonthLayout)
+	return &budget, nil
+}
+
+// validateFinanceBudget validates and trims a budget request in place and returns its month
+func validateFinanceBudget(req *models.FinanceBudgetRequest) (time.Time, error) {
+	req.Category = strings.TrimSpace(req.Category)
+	if req.Category == "" {
+		return time.Time{}, fmt.Errorf("category is required")
+	}
+	if req.Category == TransferCategory {
+		return time.Time{}, fmt.Errorf("category must not be %q, transfers are not expenses", TransferCategory)
+	}
+	if req.LimitAmount <= 0 {
+		return time.Time{}, fmt.Errorf("limitAmount must be greater than 0")
+	}
+	if strings.TrimSpace(req.Month) == "" {
+		return time.Time{}, fmt.Errorf("month is required")
+	}
+	return parseBudgetMonth(req.Month)
+}
+
+// List retrieves budgets ordered by month (newest first) and category, optionally for one YYYY-MM month
+func (r *FinanceBudgetRepository) List(ctx context.Context, month *string) ([]models.FinanceBudget, error) {
+	log.Printf("📦 ListFinanceBudgets: Fetching budgets")
+
+	query := `SELECT ` + financeBudgetColumns + ` FROM finance_budgets`
+	var args []interface{}
+	if month != nil && strings.TrimSpace(*month) != "" {
+		periodMonth, err := parseBudgetMonth(*month)
+		if err != nil {
+			return nil, err
+		}
+		query += ` WHERE period_month = $1`
+		args = append(args, periodMonth)
+	}
+	query += ` ORDER BY period_month DESC, category ASC`
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("❌ ListFinanceBudgets: Error fetching budgets: %v", err)
+		return nil, fmt.Errorf("failed to fetch budgets: %w", err)
+	}
+	defer rows.Close()
+
+	budgets := []models.FinanceBudget{}
+	for rows.Next() {
+		budget, err := scanFinanceBudget(rows)
+		if err != nil {
+			log.Printf("❌ ListFinanceBudgets: Error scanning budget: %v", err)
+			return nil, fmt.Errorf("failed to scan budget: %w", err)
+		}
+		budgets = append(budgets, *budget)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ ListFinanceBudgets: Error iterating budgets: %v", err)
+		return nil, fmt.Errorf("failed to iterate budgets: %w", err)
+	}
+
+	log.Printf("✅ ListFinanceBudgets: Successfully fetched %d budgets", len(budgets))
+	return budgets, nil
+}
+
+// GetByID retrieves a budget by ID
+func (r *FinanceBudgetRepository) GetByID(ctx context.Context, id int64) (*models.FinanceBudget, error) {
+	log.Printf("📦 GetFinanceBudget: Fetching budget id=%d", id)
+
+	query := `SELECT ` + financeBudgetColumns + ` FROM finance_budgets WHERE id = $1`
+	budget, err := scanFinanceBudget(db.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ GetFinanceBudget: Budget not found: id=%d", id)
+			return nil, fmt.Errorf("budget not found")
+		}
+		log.Printf("❌ GetFinanceBudget: Error fetching budget: %v", err)
+		return nil, fmt.Errorf("failed to fetch budget: %w", err)
+	}
+	return budget, nil
+}
+
+// Create inserts the budget of a category for a month. Each category has at most one budget per month
+func (r *FinanceBudgetRepository) Create(ctx context.Context, req *models.FinanceBudgetRequest) (*models.FinanceBudget, error) {
+	log.Printf("📦 CreateFinanceBudget: category=%q, month=%s", req.Category, req.Month)
+
+	periodMonth, err := validateFinanceBudget(req)
+	if err != nil {
+		log.Printf("❌ CreateFinanceBudget: %v", err)
+		return nil, err
+	}
+
+	var exists bool
+	err = db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM finance_budgets WHERE category = $1 AND period_month = $2)`,
+		req.Category, periodMonth).Scan(&exists)
+	if err != nil {
+		log.Printf("❌ CreateFinanceBudget: Error checking budget: %v", err)
+		return nil, fmt.Errorf("failed to check budget: %w", err)
+	}
+	if exists {
+		log.Printf("❌ CreateFinanceBudget: Budget already exists: category=%q, month=%s", req.Category, req.Month)
+		return nil, fmt.Errorf("a budget for category %q in %s already exists", req.Category, periodMonth.Format(budgetMonthLayout))
+	}
+
+	query := `
+		INSERT INTO finance_budgets (category, period_month, limit_amount)
+		VALUES ($1, $2, $3)
+		RETURNING ` + financeBudgetColumns
+	budget, err := scanFinanceBudget(db.DB.QueryRowContext(ctx, query, req.Category, periodMonth, req.LimitAmount))
+	if err != nil {
+		log.Printf("❌ CreateFinanceBudget: Error inserting budget: %v", err)
+		return nil, fmt.Errorf("failed to insert budget: %w", err)
+	}
+
+	log.Printf("✅ CreateFinanceBudget: Created budget id=%d", budget.ID)
+	return budget, nil
+}
+
+// Update replaces the category, month and limit of a budget
+func (r *FinanceBudgetRepository) Update(ctx context.Context, id int64, req *models.FinanceBudgetRequest) (*models.FinanceBudget, error) {
+	log.Printf("📦 UpdateFinanceBudget: id=%d, category=%q, month=%s", id, req.Category, req.Month)
+
+	periodMonth, err := validateFinanceBudget(req)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceBudget: %v", err)
+		return nil, err
+	}
+
+	var exists bool
+	err = db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM finance_budgets WHERE category = $1 AND period_month = $2 AND id <> $3)`,
+		req.Category, periodMonth, id).Scan(&exists)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceBudget: Error checking budget: %v", err)
+		return nil, fmt.Errorf("failed to check budget: %w", err)
+	}
+	if exists {
+		log.Printf("❌ UpdateFinanceBudget: Budget already exists: category=%q, month=%s", req.Category, req.Month)
+		return nil, fmt.Errorf("a budget for category %q in %s already exists", req.Category, periodMonth.Format(budgetMonthLayout))
+	}
+
+	query := `
+		UPDATE finance_budgets
+		SET category = $2, period_month = $3, limit_amount = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + financeBudgetColumns
+	budget, err := scanFinanceBudget(db.DB.QueryRowContext(ctx, query, id, req.Category, periodMonth, req.LimitAmount))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateFinanceBudget: Budget not found: id=%d", id)
+			return nil, fmt.Errorf("budget not found")
+		}
+		log.Printf("❌ UpdateFinanceBudget: Error updating budget: %v", err)
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+
+	log.Printf("✅ UpdateFinanceBudget: Updated budget id=%d", id)
+	return budget, nil
+}
+
+// Delete removes a budget
+func (r *FinanceBudgetRepository) Delete(ctx context.Context, id int64) error {
+	log.Printf("📦 DeleteFinanceBudget: id=%d", id)
+
+	result, err := db.DB.ExecContext(ctx, `DELETE FROM finance_budgets WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("❌ DeleteFinanceBudget: Error deleting budget: %v", err)
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Printf("❌ DeleteFinanceBudget: Budget not found: id=%d", id)
+		return fmt.Errorf("budget not found")
+	}
+
+	log.Printf("✅ DeleteFinanceBudget: Deleted budget id=%d", id)
+	return nil
+}
+
+// Status compares each budgeted category's actual expense for a YYYY-MM month against its limit.
+// Actual expenses come from the same category breakdown as the finance dashboard, without transfers.
+// Budgets are ordered by percent used (highest first); expense categories without a budget are
+// returned separately in Unbudgeted
+func (r *FinanceBudgetRepository) Status(ctx context.Context, month string) (*models.FinanceBudgetStatusResponse, error) {
+	log.Printf("📦 FinanceBudgetStatus: month=%s", month)
+
+	periodMonth, err := parseBudgetMonth(month)
+	if err != nil {
+		log.Printf("❌ FinanceBudgetStatus: %v", err)
+		return nil, err
+	}
+
+	budgets, err := r.List(ctx, &month)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same month bounds as the dashboard's month period, in the store timezone
+	loc := utils.AppLocation()
+	from := time.Date(periodMonth.Year(), periodMonth.Month(), 1, 0, 0, 0, 0, loc)
+	to := time.Date(periodMonth.Year(), periodMonth.Month()+1, 0, 23, 59, 59, 999999999, loc)
+	breakdown, err := r.finance.calculateCategoryBreakdown(ctx, from, to, []string{TransferCategory})
+	if err != nil {
+		log.Printf("❌ FinanceBudgetStatus: Error calculating category breakdown: %v", err)
+		return nil, fmt.Errorf("failed to calculate category breakdown: %w", err)
+	}
+
+	actualByCategory := make(map[string]models.CategoryAmount, len(breakdown.Expense))
+	for _, expense := range breakdown.Expense {
+		actualByCategory[expense.Category] = expense
+	}
+
+	response := &models.FinanceBudgetStatusResponse{
+		Currency:   "COP",
+		Month:      periodMonth.Format(budgetMonthLayout),
+		Categories: make([]models.BudgetCategoryStatus, 0, len(budgets)),
+		Unbudgeted: []models.CategoryAmount{},
+	}
+	budgeted := make(map[string]bool, len(budgets))
+	for _, budget := range budgets {
+		budgeted[budget.Category] = true
+		actual := actualByCategory[budget.Category]
+		status := models.BudgetCategoryStatus{
+			BudgetID:         budget.ID,
+			Category:         budget.Category,
+			LimitAmount:      budget.LimitAmount,
+			ActualExpense:    actual.Amount,
+			Remaining:        budget.LimitAmount - actual.Amount,
+			PercentUsed:      float64(actual.Amount) / float64(budget.LimitAmount) * 100,
+			TransactionCount: actual.Count,
+		}
+		status.OverBudget = actual.Amount > budget.LimitAmount
+		if status.OverBudget {
+			response.OverBudgetCount++
+		}
+		response.TotalLimit += budget.LimitAmount
+		response.TotalActual += actual.Amount
+		response.Categories = append(response.Categories, status)
+	}
+	response.TotalRemaining = response.TotalLimit - response.TotalActual
+
+	sort.SliceStable(response.Categories, func(i, j int) bool {
+		return response.Categories[i].PercentUsed > response.Categories[j].PercentUsed
+	})
+
+	// Breakdown expenses are already ordered by amount (highest first)
+	for _, expense := range breakdown.Expense {
+		if !budgeted[expense.Category] {
+			response.Unbudgeted = append(response.Unbudgeted, expense)
+		}
+	}
+
+	log.Printf("✅ FinanceBudgetStatus: month=%s budgets=%d overBudget=%d", response.Month, len(response.Categories), response.OverBudgetCount)
+	return response, nil
+}
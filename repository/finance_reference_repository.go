@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"armario-mascota-me/db"
+	"armario-mascota-me/models"
+)
+
+// Finance reference kinds (managed lists of allowed values)
+const (
+	FinanceReferenceDestination = "destination"
+	FinanceReferenceCategory    = "category"
+)
+
+// financeReferenceTables maps each reference kind to its table
+var financeReferenceTables = map[string]string{
+	FinanceReferenceDestination: "finance_destinations",
+	FinanceReferenceCategory:    "finance_categories",
+}
+
+// FinanceReferenceRepository handles database operations for the managed destination and category lists
+type FinanceReferenceRepository struct{}
+
+// NewFinanceReferenceRepository creates a new FinanceReferenceRepository
+func NewFinanceReferenceRepository() *FinanceReferenceRepository {
+	return &FinanceReferenceRepository{}
+}
+
+// Ensure FinanceReferenceRepository implements FinanceReferenceRepositoryInterface
+var _ FinanceReferenceRepositoryInterface = (*FinanceReferenceRepository)(nil)
+
+// financeReferenceTable returns the table for a reference kind
+func financeReferenceTable(kind string) (string, error) {
+	table, ok := financeReferenceTables[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown finance reference kind: %s", kind)
+	}
+	return table, nil
+}
+
+// scanFinanceReferenceValue scans a row selected as id, name, created_at, updated_at
+func scanFinanceReferenceValue(scanner interface{ Scan(dest ...any) error }) (*models.FinanceReferenceValue, error) {
+	var value models.FinanceReferenceValue
+	if err := scanner.Scan(&value.ID, &value.Name, &value.CreatedAt, &value.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// List retrieves all values of a reference kind ordered by name
+func (r *FinanceReferenceRepository) List(ctx context.Context, kind string) ([]models.FinanceReferenceValue, error) {
+	log.Printf("📦 ListFinanceReferences: Fetching %s values", kind)
+
+	table, err := financeReferenceTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.DB.QueryContext(ctx, `SELECT id, name, created_at, updated_at FROM `+table+` ORDER BY LOWER(name) ASC`)
+	if err != nil {
+		log.Printf("❌ ListFinanceReferences: Error fetching %s values: %v", kind, err)
+		return nil, fmt.Errorf("failed to fetch %s values: %w", kind, err)
+	}
+	defer rows.Close()
+
+	values := []models.FinanceReferenceValue{}
+	for rows.Next() {
+		value, err := scanFinanceReferenceValue(rows)
+		if err != nil {
+			log.Printf("❌ ListFinanceReferences: Error scanning %s value: %v", kind, err)
+			return nil, fmt.Errorf("failed to scan %s value: %w", kind, err)
+		}
+		values = append(values, *value)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ ListFinanceReferences: Error iterating %s values: %v", kind, err)
+		return nil, fmt.Errorf("failed to iterate %s values: %w", kind, err)
+	}
+
+	log.Printf("✅ ListFinanceReferences: Successfully fetched %d %s values", len(values), kind)
+	return values, nil
+}
+
+// Create adds a value to a reference kind. Names are unique case-insensitively
+func (r *FinanceReferenceRepository) Create(ctx context.Context, kind string, req *models.FinanceReferenceValueRequest) (*models.FinanceReferenceValue, error) {
+	log.Printf("📦 CreateFinanceReference: kind=%s, name=%q", kind, req.Name)
+
+	table, err := financeReferenceTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	if err := checkFinanceReferenceNameFree(ctx, table, kind, name, 0); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO ` + table + ` (name) VALUES ($1) RETURNING id, name, created_at, updated_at`
+	value, err := scanFinanceReferenceValue(db.DB.QueryRowContext(ctx, query, name))
+	if err != nil {
+		log.Printf("❌ CreateFinanceReference: Error inserting %s %q: %v", kind, name, err)
+		return nil, fmt.Errorf("failed to create %s: %w", kind, err)
+	}
+
+	log.Printf("✅ CreateFinanceReference: Successfully created %s id=%d (%s)", kind, value.ID, value.Name)
+	return value, nil
+}
+
+// Update renames a value of a reference kind. Existing transactions keep the name they were saved with
+func (r *FinanceReferenceRepository) Update(ctx context.Context, kind string, id int64, req *models.FinanceReferenceValueRequest) (*models.FinanceReferenceValue, error) {
+	log.Printf("📦 UpdateFinanceReference: kind=%s, id=%d, name=%q", kind, id, req.Name)
+
+	table, err := financeReferenceTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	if err := checkFinanceReferenceNameFree(ctx, table, kind, name, id); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE ` + table + ` SET name = $2, updated_at = NOW() WHERE id = $1 RETURNING id, name, created_at, updated_at`
+	value, err := scanFinanceReferenceValue(db.DB.QueryRowContext(ctx, query, id, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateFinanceReference: %s not found: id=%d", kind, id)
+			return nil, fmt.Errorf("%s not found", kind)
+		}
+		log.Printf("❌ UpdateFinanceReference: Error updating %s id=%d: %v", kind, id, err)
+		return nil, fmt.Errorf("failed to update %s: %w", kind, err)
+	}
+
+	log.Printf("✅ UpdateFinanceReference: Successfully updated %s id=%d (%s)", kind, value.ID, value.Name)
+	return value, nil
+}
+
+// Delete removes a value from a reference kind. Existing transactions are not touched
+func (r *FinanceReferenceRepository) Delete(ctx context.Context, kind string, id int64) error {
+	log.Printf("📦 DeleteFinanceReference: kind=%s, id=%d", kind, id)
+
+	table, err := financeReferenceTable(kind)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.DB.ExecContext(ctx, `DELETE FROM `+table+` WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("❌ DeleteFinanceReference: Error deleting %s id=%d: %v", kind, id, err)
+		return fmt.Errorf("failed to delete %s: %w", kind, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Printf("❌ DeleteFinanceReference: %s not found: id=%d", kind, id)
+		return fmt.Errorf("%s not found", kind)
+	}
+
+	log.Printf("✅ DeleteFinanceReference: Successfully deleted %s id=%d", kind, id)
+	return nil
+}
+
+// checkFinanceReferenceNameFree returns a conflict error when another row (other than excludeID) already uses name
+func checkFinanceReferenceNameFree(ctx context.Context, table, kind, name string, excludeID int64) error {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM ` + table + ` WHERE LOWER(name) = LOWER($1) AND id != $2)`
+	if err := db.DB.QueryRowContext(ctx, query, name, excludeID).Scan(&exists); err != nil {
+		log.Printf("❌ FinanceReference: Error checking %s name: %v", kind, err)
+		return fmt.Errorf("failed to check %s name: %w", kind, err)
+	}
+	if exists {
+		return fmt.Errorf("a %s named %q already exists", kind, name)
+	}
+	return nil
+}
+
+// resolveFinanceReference returns the managed spelling of value for a reference kind (matched
+// case-insensitively), or an error when the value is not in the list
+func resolveFinanceReference(ctx context.Context, kind, value string) (string, error) {
+	table, err := financeReferenceTable(kind)
+	if err != nil {
+		return "", err
+	}
+
+	var name string
+	query := `SELECT name FROM ` + table + ` WHERE LOWER(name) = LOWER($1)`
+	err = db.DB.QueryRowContext(ctx, query, strings.TrimSpace(value)).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("unknown %s %q: add it to the managed list or set allowFreeText", kind, value)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check %s: %w", kind, err)
+	}
+	return name, nil
+}
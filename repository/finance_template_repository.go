@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"armario-mascota-me/db"
+	"armario-mascota-me/models"
+)
+
+// FinanceTemplateRepository handles database operations for finance templates
+type FinanceTemplateRepository struct{}
+
+// NewFinanceTemplateRepository creates a new FinanceTemplateRepository
+func NewFinanceTemplateRepository() *FinanceTemplateRepository {
+	return &FinanceTemplateRepository{}
+}
+
+// Ensure FinanceTemplateRepository implements FinanceTemplateRepositoryInterface
+var _ FinanceTemplateRepositoryInterface = (*FinanceTemplateRepository)(nil)
+
+const financeTemplateColumns = `id, label, type, category, counterparty, destination, notes, created_at, updated_at`
+
+// scanFinanceTemplate scans a row selected with financeTemplateColumns
+func scanFinanceTemplate(scanner interface{ Scan(dest ...any) error }) (*models.FinanceTemplate, error) {
+	var template models.FinanceTemplate
+	var templateType, category, counterparty, destination, notes sql.NullString
+	err := scanner.Scan(
+		&template.ID,
+		&template.Label,
+		&templateType,
+		&category,
+		&counterparty,
+		&destination,
+		&notes,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	template.Type = templateType.String
+	template.Category = category.String
+	template.Counterparty = counterparty.String
+	template.Destination = destination.String
+	template.Notes = notes.String
+	return &template, nil
+}
+
+// validateFinanceTemplate validates and trims a template request in place
+func validateFinanceTemplate(req *models.FinanceTemplateRequest) error {
+	req.Label = strings.TrimSpace(req.Label)
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	if req.Label == "" {
+		return fmt.Errorf("label is required")
+	}
+	if req.Type != "" && req.Type != "income" && req.Type != "expense" {
+		return fmt.Errorf("type must be 'income' or 'expense'")
+	}
+	return nil
+}
+
+// nullIfEmpty converts an empty string to SQL NULL
+func nullIfEmpty(value string) sql.NullString {
+	value = strings.TrimSpace(value)
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// List retrieves all finance templates ordered by label
+func (r *FinanceTemplateRepository) List(ctx context.Context) ([]models.FinanceTemplate, error) {
+	log.Printf("📦 ListFinanceTemplates: Fetching finance templates")
+
+	rows, err := db.DB.QueryContext(ctx, `SELECT `+financeTemplateColumns+` FROM finance_templates ORDER BY label ASC`)
+	if err != nil {
+		log.Printf("❌ ListFinanceTemplates: Error fetching templates: %v", err)
+		return nil, fmt.Errorf("failed to fetch finance templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []models.FinanceTemplate{}
+	for rows.Next() {
+		template, err := scanFinanceTemplate(rows)
+		if err != nil {
+			log.Printf("❌ ListFinanceTemplates: Error scanning template: %v", err)
+			return nil, fmt.Errorf("failed to scan finance template: %w", err)
+		}
+		templates = append(templates, *template)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ ListFinanceTemplates: Error iterating templates: %v", err)
+		return nil, fmt.Errorf("failed to iterate finance templates: %w", err)
+	}
+
+	log.Printf("✅ ListFinanceTemplates: Successfully fetched %d templates", len(templates))
+	return templates, nil
+}
+
+// GetByID retrieves a finance template by ID
+func (r *FinanceTemplateRepository) GetByID(ctx context.Context, id int64) (*models.FinanceTemplate, error) {
+	log.Printf("📦 GetFinanceTemplate: Fetching finance template id=%d", id)
+
+	query := `SELECT ` + financeTemplateColumns + ` FROM finance_templates WHERE id = $1`
+	template, err := scanFinanceTemplate(db.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ GetFinanceTemplate: Template not found: id=%d", id)
+			return nil, fmt.Errorf("finance template not found")
+		}
+		log.Printf("❌ GetFinanceTemplate: Error fetching template: %v", err)
+		return nil, fmt.Errorf("failed to fetch finance template: %w", err)
+	}
+	return template, nil
+}
+
+// Create inserts a new finance template
+func (r *FinanceTemplateRepository) Create(ctx context.Context, req *models.FinanceTemplateRequest) (*models.FinanceTemplate, error) {
+	log.Printf("📦 CreateFinanceTemplate: label=%q", req.Label)
+
+	if err := validateFinanceTemplate(req); err != nil {
+		log.Printf("❌ CreateFinanceTemplate: %v", err)
+		return nil, err
+	}
+
+	var exists bool
+	err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM finance_templates WHERE label = $1)`, req.Label).Scan(&exists)
+	if err != nil {
+		log.Printf("❌ CreateFinanceTemplate: Error checking label: %v", err)
+		return nil, fmt.Errorf("failed to check template label: %w", err)
+	}
+	if exists {
+		log.Printf("❌ CreateFinanceTemplate: Label already exists: %q", req.Label)
+		return nil, fmt.Errorf("a template with label %q already exists", req.Label)
+	}
+
+	query := `
+		INSERT INTO finance_templates (label, type, category, counterparty, destination, notes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + financeTemplateColumns
+	template, err := scanFinanceTemplate(db.DB.QueryRowContext(ctx, query,
+		req.Label,
+		nullIfEmpty(req.Type),
+		nullIfEmpty(req.Category),
+		nullIfEmpty(req.Counterparty),
+		nullIfEmpty(req.Destination),
+		nullIfEmpty(req.Notes),
+	))
+	if err != nil {
+		log.Printf("❌ CreateFinanceTemplate: Error inserting template: %v", err)
+		return nil, fmt.Errorf("failed to insert finance template: %w", err)
+	}
+
+	log.Printf("✅ CreateFinanceTemplate: Created template id=%d", template.ID)
+	return template, nil
+}
+
+// Update replaces all fields of a finance template
+func (r *FinanceTemplateRepository) Update(ctx context.Context, id int64, req *models.FinanceTemplateRequest) (*models.FinanceTemplate, error) {
+	log.Printf("📦 UpdateFinanceTemplate: id=%d, label=%q", id, req.Label)
+
+	if err := validateFinanceTemplate(req); err != nil {
+		log.Printf("❌ UpdateFinanceTemplate: %v", err)
+		return nil, err
+	}
+
+	var exists bool
+	err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM finance_templates WHERE label = $1 AND id <> $2)`, req.Label, id).Scan(&exists)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceTemplate: Error checking label: %v", err)
+		return nil, fmt.Errorf("failed to check template label: %w", err)
+	}
+	if exists {
+		log.Printf("❌ UpdateFinanceTemplate: Label already exists: %q", req.Label)
+		return nil, fmt.Errorf("a template with label %q already exists", req.Label)
+	}
+
+	query := `
+		UPDATE finance_templates
+		SET label = $2, type = $3, category = $4, counterparty = $5, destination = $6, notes = $7, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + financeTemplateColumns
+	template, err := scanFinanceTemplate(db.DB.QueryRowContext(ctx, query,
+		id,
+		req.Label,
+		nullIfEmpty(req.Type),
+		nullIfEmpty(req.Category),
+		nullIfEmpty(req.Counterparty),
+		nullIfEmpty(req.Destination),
+		nullIfEmpty(req.Notes),
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateFinanceTemplate: Template not found: id=%d", id)
+			return nil, fmt.Errorf("finance template not found")
+		}
+		log.Printf("❌ UpdateFinanceTemplate: Error updating template: %v", err)
+		return nil, fmt.Errorf("failed to update finance template: %w", err)
+	}
+
+	log.Printf("✅ UpdateFinanceTemplate: Updated template id=%d", id)
+	return template, nil
+}
+
+// Delete removes a finance template. Transactions created from it are not affected.
+func (r *FinanceTemplateRepository) Delete(ctx context.Context, id int64) error {
+	log.Printf("📦 DeleteFinanceTemplate: id=%d", id)
+
+	result, err := db.DB.ExecContext(ctx, `DELETE FROM finance_templates WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("❌ DeleteFinanceTemplate: Error deleting template: %v", err)
+		return fmt.Errorf("failed to delete finance template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Printf("❌ DeleteFinanceTemplate: Template not found: id=%d", id)
+		return fmt.Errorf("finance template not found")
+	}
+
+	log.Printf("✅ DeleteFinanceTemplate: Deleted template id=%d", id)
+	return nil
+}
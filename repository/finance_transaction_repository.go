@@ -12,6 +12,7 @@ import (
 
 	"armario-mascota-me/db"
 	"armario-mascota-me/models"
+	"armario-mascota-me/utils"
 )
 
 // FinanceTransactionRepository handles database operations for finance transactions
@@ -25,41 +26,31 @@ func NewFinanceTransactionRepository() *FinanceTransactionRepository {
 // Ensure FinanceTransactionRepository implements FinanceTransactionRepositoryInterface
 var _ FinanceTransactionRepositoryInterface = (*FinanceTransactionRepository)(nil)
 
+// TransferCategory is the category used for both legs of a transfer between destinations.
+// Transfers only move money between destinations and net to zero overall
+const TransferCategory = "transferencia"
+
+// categoryFilter returns the SQL condition that excludes rows in any of the given categories, and
+// args with the categories appended as the array parameter the condition refers to. args are the
+// query's positional parameters so far. Rows without a category are kept
+func categoryFilter(excludeCategories []string, args ...interface{}) (string, []interface{}) {
+	if len(excludeCategories) == 0 {
+		return "", args
+	}
+	args = append(args, excludeCategories)
+	return fmt.Sprintf(" AND (category IS NULL OR category <> ALL($%d))", len(args)), args
+}
+
 // Create creates a new finance transaction
 // For manual transactions, source='manual' and source_id=NULL
 // For sale transactions, source='sale' and source_id must be provided
 func (r *FinanceTransactionRepository) Create(ctx context.Context, req *models.CreateFinanceTransactionRequest) (*models.FinanceTransaction, error) {
 	log.Printf("💰 CreateFinanceTransaction: type=%s, amount=%d", req.Type, req.Amount)
 
-	// Validate type
-	if req.Type != "income" && req.Type != "expense" {
-		log.Printf("❌ CreateFinanceTransaction: Invalid type: %s", req.Type)
-		return nil, fmt.Errorf("type must be 'income' or 'expense'")
-	}
-
-	// Validate amount
-	if req.Amount <= 0 {
-		log.Printf("❌ CreateFinanceTransaction: Invalid amount: %d", req.Amount)
-		return nil, fmt.Errorf("amount must be greater than 0")
-	}
-
-	// Validate destination
-	if strings.TrimSpace(req.Destination) == "" {
-		log.Printf("❌ CreateFinanceTransaction: Destination is required")
-		return nil, fmt.Errorf("destination is required")
-	}
-
-	// Parse occurredAt or use current time
-	var occurredAt time.Time
-	if req.OccurredAt != "" {
-		var err error
-		occurredAt, err = time.Parse(time.RFC3339, req.OccurredAt)
-		if err != nil {
-			log.Printf("❌ CreateFinanceTransaction: Invalid occurredAt format: %s", req.OccurredAt)
-			return nil, fmt.Errorf("invalid occurredAt format, use RFC3339 (e.g., 2006-01-02T15:04:05Z07:00): %w", err)
-		}
-	} else {
-		occurredAt = time.Now()
+	occurredAt, err := validateFinanceTransaction(ctx, req)
+	if err != nil {
+		log.Printf("❌ CreateFinanceTransaction: %v", err)
+		return nil, err
 	}
 
 	// For manual transactions, source='manual' and source_id=NULL
@@ -77,7 +68,7 @@ func (r *FinanceTransactionRepository) Create(ctx context.Context, req *models.C
 	var category, counterparty, notes sql.NullString
 	var sourceIDScan sql.NullInt64
 
-	err := db.DB.QueryRowContext(ctx, queryInsert,
+	err = db.DB.QueryRowContext(ctx, queryInsert,
 		req.Type,
 		source,
 		sourceID,
@@ -124,6 +115,420 @@ func (r *FinanceTransactionRepository) Create(ctx context.Context, req *models.C
 	return &transaction, nil
 }
 
+// validateFinanceTransaction checks a manual transaction request (type, amount, destination,
+// managed destination/category unless AllowFreeText) and returns its occurredAt (now when empty).
+// Destination and category are rewritten with their managed spelling
+func validateFinanceTransaction(ctx context.Context, req *models.CreateFinanceTransactionRequest) (time.Time, error) {
+	if req.Type != "income" && req.Type != "expense" {
+		return time.Time{}, fmt.Errorf("type must be 'income' or 'expense'")
+	}
+
+	if req.Amount <= 0 {
+		return time.Time{}, fmt.Errorf("amount must be greater than 0")
+	}
+
+	if strings.TrimSpace(req.Destination) == "" {
+		return time.Time{}, fmt.Errorf("destination is required")
+	}
+
+	// Destination and category must come from the managed lists unless free text is allowed
+	if !req.AllowFreeText {
+		destination, err := resolveFinanceReference(ctx, FinanceReferenceDestination, req.Destination)
+		if err != nil {
+			return time.Time{}, err
+		}
+		req.Destination = destination
+
+		if strings.TrimSpace(req.Category) != "" {
+			category, err := resolveFinanceReference(ctx, FinanceReferenceCategory, req.Category)
+			if err != nil {
+				return time.Time{}, err
+			}
+			req.Category = category
+		}
+	}
+
+	// Parse occurredAt or use current time
+	if req.OccurredAt == "" {
+		return time.Now(), nil
+	}
+	occurredAt, err := time.Parse(time.RFC3339, req.OccurredAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid occurredAt format, use RFC3339 (e.g., 2006-01-02T15:04:05Z07:00): %w", err)
+	}
+	return occurredAt, nil
+}
+
+// Import validates imported rows like Create and inserts them as manual transactions in a single
+// transaction. Rejected rows are reported with their line number and error. The import is all or
+// nothing: if any row is rejected nothing is inserted and the valid rows are reported as 'skipped',
+// unless allowPartial is set, in which case the valid rows are still inserted
+func (r *FinanceTransactionRepository) Import(ctx context.Context, rows []models.FinanceImportRow, allowPartial bool) (*models.FinanceImportResponse, error) {
+	log.Printf("💰 ImportFinanceTransactions: Importing %d rows (allowPartial=%v)", len(rows), allowPartial)
+
+	response := &models.FinanceImportResponse{
+		Rows: make([]models.FinanceImportRowResult, len(rows)),
+	}
+
+	// Validate every row before touching the database
+	occurredAts := make([]time.Time, len(rows))
+	for i := range rows {
+		response.Rows[i].Line = rows[i].Line
+		if rows[i].Error != "" {
+			response.Rows[i].Status = "rejected"
+			response.Rows[i].Error = rows[i].Error
+			continue
+		}
+		occurredAt, err := validateFinanceTransaction(ctx, &rows[i].Request)
+		if err != nil {
+			response.Rows[i].Status = "rejected"
+			response.Rows[i].Error = err.Error()
+			continue
+		}
+		occurredAts[i] = occurredAt
+	}
+
+	for i := range response.Rows {
+		if response.Rows[i].Status == "rejected" {
+			response.Rejected++
+		}
+	}
+	if response.Rejected > 0 && !allowPartial {
+		for i := range response.Rows {
+			if response.Rows[i].Status != "rejected" {
+				response.Rows[i].Status = "skipped"
+			}
+		}
+		log.Printf("❌ ImportFinanceTransactions: %d rows rejected, nothing imported", response.Rejected)
+		return response, nil
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ ImportFinanceTransactions: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queryInsert := `
+		INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes)
+		VALUES ($1, 'manual', NULL, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	for i := range rows {
+		if response.Rows[i].Status == "rejected" {
+			continue
+		}
+		req := rows[i].Request
+		var id int64
+		err := tx.QueryRowContext(ctx, queryInsert,
+			req.Type,
+			occurredAts[i],
+			req.Amount,
+			req.Destination,
+			sql.NullString{String: req.Category, Valid: req.Category != ""},
+			sql.NullString{String: req.Counterparty, Valid: req.Counterparty != ""},
+			sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		).Scan(&id)
+		if err != nil {
+			log.Printf("❌ ImportFinanceTransactions: Error inserting line %d: %v", rows[i].Line, err)
+			return nil, fmt.Errorf("failed to insert line %d: %w", rows[i].Line, err)
+		}
+		response.Rows[i].Status = "imported"
+		response.Rows[i].TransactionID = &id
+		response.Imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ ImportFinanceTransactions: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ ImportFinanceTransactions: Imported %d rows, rejected %d", response.Imported, response.Rejected)
+	return response, nil
+}
+
+// Update edits a manual finance transaction. Only amount, destination, category, counterparty,
+// notes and occurredAt can change; the type must stay the same. Transactions generated by the
+// system (source other than 'manual', e.g. sales, refunds and reprice adjustments) are rejected
+func (r *FinanceTransactionRepository) Update(ctx context.Context, id int64, req *models.UpdateFinanceTransactionRequest) (*models.FinanceTransaction, error) {
+	log.Printf("💰 UpdateFinanceTransaction: id=%d", id)
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceTransaction: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current models.FinanceTransaction
+	var category, counterparty, notes sql.NullString
+	var occurredAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		SELECT type, source, occurred_at, amount, destination, category, counterparty, notes
+		FROM finance_transactions
+		WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&current.Type, &current.Source, &occurredAt, &current.Amount, &current.Destination, &category, &counterparty, &notes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateFinanceTransaction: Transaction not found: id=%d", id)
+			return nil, fmt.Errorf("finance transaction not found")
+		}
+		log.Printf("❌ UpdateFinanceTransaction: Error fetching transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch finance transaction: %w", err)
+	}
+
+	if current.Source != "manual" {
+		log.Printf("❌ UpdateFinanceTransaction: Transaction id=%d is system-generated (source=%s)", id, current.Source)
+		return nil, fmt.Errorf("system-generated transactions cannot be edited (source=%s)", current.Source)
+	}
+
+	if req.Type != nil && *req.Type != current.Type {
+		log.Printf("❌ UpdateFinanceTransaction: Type change not allowed: %s -> %s", current.Type, *req.Type)
+		return nil, fmt.Errorf("type cannot be changed")
+	}
+
+	amount := current.Amount
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			log.Printf("❌ UpdateFinanceTransaction: Invalid amount: %d", *req.Amount)
+			return nil, fmt.Errorf("amount must be greater than 0")
+		}
+		amount = *req.Amount
+	}
+
+	destination := current.Destination
+	if req.Destination != nil {
+		if strings.TrimSpace(*req.Destination) == "" {
+			log.Printf("❌ UpdateFinanceTransaction: Destination is required")
+			return nil, fmt.Errorf("destination is required")
+		}
+		destination = *req.Destination
+		if !req.AllowFreeText {
+			destination, err = resolveFinanceReference(ctx, FinanceReferenceDestination, destination)
+			if err != nil {
+				log.Printf("❌ UpdateFinanceTransaction: %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	if req.OccurredAt != nil {
+		occurredAt, err = time.Parse(time.RFC3339, *req.OccurredAt)
+		if err != nil {
+			log.Printf("❌ UpdateFinanceTransaction: Invalid occurredAt format: %s", *req.OccurredAt)
+			return nil, fmt.Errorf("invalid occurredAt format, use RFC3339 (e.g., 2006-01-02T15:04:05Z07:00): %w", err)
+		}
+	}
+
+	if req.Category != nil {
+		category = sql.NullString{String: *req.Category, Valid: *req.Category != ""}
+		if category.Valid && !req.AllowFreeText {
+			category.String, err = resolveFinanceReference(ctx, FinanceReferenceCategory, category.String)
+			if err != nil {
+				log.Printf("❌ UpdateFinanceTransaction: %v", err)
+				return nil, err
+			}
+		}
+	}
+	if req.Counterparty != nil {
+		counterparty = sql.NullString{String: *req.Counterparty, Valid: *req.Counterparty != ""}
+	}
+	if req.Notes != nil {
+		notes = sql.NullString{String: *req.Notes, Valid: *req.Notes != ""}
+	}
+
+	queryUpdate := `
+		UPDATE finance_transactions
+		SET occurred_at = $2, amount = $3, destination = $4, category = $5, counterparty = $6, notes = $7
+		WHERE id = $1
+		RETURNING id, type, source, source_id, occurred_at, amount, destination, category, counterparty, notes, created_at
+	`
+
+	var transaction models.FinanceTransaction
+	var sourceID sql.NullInt64
+	var occurredAtScan time.Time
+	err = tx.QueryRowContext(ctx, queryUpdate, id, occurredAt, amount, destination, category, counterparty, notes).Scan(
+		&transaction.ID,
+		&transaction.Type,
+		&transaction.Source,
+		&sourceID,
+		&occurredAtScan,
+		&transaction.Amount,
+		&transaction.Destination,
+		&category,
+		&counterparty,
+		&notes,
+		&transaction.CreatedAt,
+	)
+	if err != nil {
+		log.Printf("❌ UpdateFinanceTransaction: Error updating transaction: %v", err)
+		return nil, fmt.Errorf("failed to update finance transaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ UpdateFinanceTransaction: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	transaction.OccurredAt = occurredAtScan.Format(time.RFC3339)
+	if sourceID.Valid {
+		transaction.SourceID = &sourceID.Int64
+	}
+	if category.Valid {
+		transaction.Category = category.String
+	}
+	if counterparty.Valid {
+		transaction.Counterparty = counterparty.String
+	}
+	if notes.Valid {
+		transaction.Notes = notes.String
+	}
+
+	log.Printf("✅ UpdateFinanceTransaction: Successfully updated transaction id=%d", transaction.ID)
+	return &transaction, nil
+}
+
+// Delete removes a manual finance transaction (e.g. a duplicated expense).
+// Transactions generated by sales, refunds or reprice adjustments are rejected:
+// money from a sale must be returned through a refund instead
+func (r *FinanceTransactionRepository) Delete(ctx context.Context, id int64) error {
+	log.Printf("💰 DeleteFinanceTransaction: id=%d", id)
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ DeleteFinanceTransaction: Error starting transaction: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var source string
+	err = tx.QueryRowContext(ctx, `SELECT source FROM finance_transactions WHERE id = $1 FOR UPDATE`, id).Scan(&source)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ DeleteFinanceTransaction: Transaction not found: id=%d", id)
+			return fmt.Errorf("finance transaction not found")
+		}
+		log.Printf("❌ DeleteFinanceTransaction: Error fetching transaction: %v", err)
+		return fmt.Errorf("failed to fetch finance transaction: %w", err)
+	}
+
+	if source == "sale" {
+		log.Printf("❌ DeleteFinanceTransaction: Transaction id=%d belongs to a sale", id)
+		return fmt.Errorf("system-generated transactions cannot be deleted: this transaction belongs to a sale, refund the sale instead")
+	}
+	if source != "manual" {
+		log.Printf("❌ DeleteFinanceTransaction: Transaction id=%d is system-generated (source=%s)", id, source)
+		return fmt.Errorf("system-generated transactions cannot be deleted (source=%s)", source)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM finance_transactions WHERE id = $1`, id); err != nil {
+		log.Printf("❌ DeleteFinanceTransaction: Error deleting transaction: %v", err)
+		return fmt.Errorf("failed to delete finance transaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ DeleteFinanceTransaction: Error committing transaction: %v", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ DeleteFinanceTransaction: Successfully deleted transaction id=%d", id)
+	return nil
+}
+
+// Transfer moves money between two destinations as a linked pair of transactions: an expense on
+// fromDestination and an income on toDestination, both with category TransferCategory, source
+// 'transfer' and the same transfer_group_id. The pair nets to zero, so balanceAllTime is unaffected
+func (r *FinanceTransactionRepository) Transfer(ctx context.Context, req *models.TransferRequest) (*models.TransferResponse, error) {
+	log.Printf("💰 FinanceTransfer: from=%s, to=%s, amount=%d", req.FromDestination, req.ToDestination, req.Amount)
+
+	fromDestination := strings.TrimSpace(req.FromDestination)
+	toDestination := strings.TrimSpace(req.ToDestination)
+	if fromDestination == "" || toDestination == "" {
+		log.Printf("❌ FinanceTransfer: fromDestination and toDestination are required")
+		return nil, fmt.Errorf("fromDestination and toDestination are required")
+	}
+	if fromDestination == toDestination {
+		log.Printf("❌ FinanceTransfer: Same source and target destination: %s", fromDestination)
+		return nil, fmt.Errorf("fromDestination and toDestination must be different")
+	}
+	if req.Amount <= 0 {
+		log.Printf("❌ FinanceTransfer: Invalid amount: %d", req.Amount)
+		return nil, fmt.Errorf("amount must be greater than 0")
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != "" {
+		var err error
+		occurredAt, err = time.Parse(time.RFC3339, req.OccurredAt)
+		if err != nil {
+			log.Printf("❌ FinanceTransfer: Invalid occurredAt format: %s", req.OccurredAt)
+			return nil, fmt.Errorf("invalid occurredAt format, use RFC3339 (e.g., 2006-01-02T15:04:05Z07:00): %w", err)
+		}
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ FinanceTransfer: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	response := &models.TransferResponse{}
+	if err := tx.QueryRowContext(ctx, `SELECT nextval('finance_transfer_group_seq')`).Scan(&response.TransferGroupID); err != nil {
+		log.Printf("❌ FinanceTransfer: Error allocating transfer group: %v", err)
+		return nil, fmt.Errorf("failed to allocate transfer group: %w", err)
+	}
+
+	queryInsert := `
+		INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes, transfer_group_id)
+		VALUES ($1, 'transfer', NULL, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, type, source, occurred_at, amount, destination, category, counterparty, created_at
+	`
+	insertLeg := func(leg *models.FinanceTransaction, txType, destination, counterparty string) error {
+		var occurredAtScan time.Time
+		var category, counterpartyScan string
+		err := tx.QueryRowContext(ctx, queryInsert,
+			txType,
+			occurredAt,
+			req.Amount,
+			destination,
+			TransferCategory,
+			counterparty,
+			sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+			response.TransferGroupID,
+		).Scan(&leg.ID, &leg.Type, &leg.Source, &occurredAtScan, &leg.Amount, &leg.Destination, &category, &counterpartyScan, &leg.CreatedAt)
+		if err != nil {
+			return err
+		}
+		leg.OccurredAt = occurredAtScan.Format(time.RFC3339)
+		leg.Category = category
+		leg.Counterparty = counterpartyScan
+		leg.Notes = req.Notes
+		leg.TransferGroupID = &response.TransferGroupID
+		return nil
+	}
+
+	// Each leg names the other destination as its counterparty
+	if err := insertLeg(&response.Expense, "expense", fromDestination, toDestination); err != nil {
+		log.Printf("❌ FinanceTransfer: Error inserting expense leg: %v", err)
+		return nil, fmt.Errorf("failed to insert transfer expense: %w", err)
+	}
+	if err := insertLeg(&response.Income, "income", toDestination, fromDestination); err != nil {
+		log.Printf("❌ FinanceTransfer: Error inserting income leg: %v", err)
+		return nil, fmt.Errorf("failed to insert transfer income: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ FinanceTransfer: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ FinanceTransfer: Created transfer group=%d (expense id=%d, income id=%d)", response.TransferGroupID, response.Expense.ID, response.Income.ID)
+	return response, nil
+}
+
 // cursorData represents the cursor structure for pagination
 type cursorData struct {
 	OccurredAt string `json:"occurredAt"`
@@ -172,7 +577,7 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 
 	// Build query with filters
 	query := `
-		SELECT id, type, source, source_id, occurred_at, amount, destination, category, counterparty, notes, created_at
+		SELECT id, type, source, source_id, occurred_at, amount, destination, category, counterparty, notes, created_at, transfer_group_id
 		FROM finance_transactions
 		WHERE 1=1
 	`
@@ -181,7 +586,7 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 
 	// Date range filters
 	if req.From != nil && *req.From != "" {
-		fromDate, err := time.Parse("2006-01-02", *req.From)
+		fromDate, err := utils.ParseLocalDate(*req.From)
 		if err != nil {
 			return nil, fmt.Errorf("invalid from date format: %w", err)
 		}
@@ -191,12 +596,12 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 	}
 
 	if req.To != nil && *req.To != "" {
-		toDate, err := time.Parse("2006-01-02", *req.To)
+		toDate, err := utils.ParseLocalDate(*req.To)
 		if err != nil {
 			return nil, fmt.Errorf("invalid to date format: %w", err)
 		}
 		// Set to end of day
-		toDate = time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+		toDate = utils.EndOfDay(toDate)
 		query += fmt.Sprintf(" AND occurred_at <= $%d", argIndex)
 		args = append(args, toDate)
 		argIndex++
@@ -230,6 +635,14 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 		argIndex++
 	}
 
+	// Transfer filter
+	if req.ExcludeTransfers {
+		var filter string
+		filter, args = categoryFilter([]string{TransferCategory}, args...)
+		query += filter
+		argIndex = len(args) + 1
+	}
+
 	// Text search filter (q) - search in notes and counterparty
 	if req.Q != nil && *req.Q != "" {
 		searchTerm := "%" + *req.Q + "%"
@@ -238,6 +651,29 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 		argIndex++
 	}
 
+	// Amount range filters
+	if req.MinAmount != nil && *req.MinAmount < 0 {
+		return nil, fmt.Errorf("invalid minAmount: must be non-negative")
+	}
+	if req.MaxAmount != nil && *req.MaxAmount < 0 {
+		return nil, fmt.Errorf("invalid maxAmount: must be non-negative")
+	}
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		return nil, fmt.Errorf("invalid amount range: minAmount must be less than or equal to maxAmount")
+	}
+
+	if req.MinAmount != nil {
+		query += fmt.Sprintf(" AND amount >= $%d", argIndex)
+		args = append(args, *req.MinAmount)
+		argIndex++
+	}
+
+	if req.MaxAmount != nil {
+		query += fmt.Sprintf(" AND amount <= $%d", argIndex)
+		args = append(args, *req.MaxAmount)
+		argIndex++
+	}
+
 	// Cursor pagination
 	if req.Cursor != nil && *req.Cursor != "" {
 		cursorOccurredAt, cursorID, err := decodeCursor(*req.Cursor)
@@ -267,7 +703,7 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 	for rows.Next() {
 		var transaction models.FinanceTransaction
 		var category, counterparty, notes sql.NullString
-		var sourceID sql.NullInt64
+		var sourceID, transferGroupID sql.NullInt64
 		var occurredAt time.Time
 
 		err := rows.Scan(
@@ -282,6 +718,7 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 			&counterparty,
 			&notes,
 			&transaction.CreatedAt,
+			&transferGroupID,
 		)
 		if err != nil {
 			log.Printf("❌ ListFinanceTransactions: Error scanning transaction: %v", err)
@@ -292,6 +729,9 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 		if sourceID.Valid {
 			transaction.SourceID = &sourceID.Int64
 		}
+		if transferGroupID.Valid {
+			transaction.TransferGroupID = &transferGroupID.Int64
+		}
 		if category.Valid {
 			transaction.Category = category.String
 		}
@@ -332,11 +772,16 @@ func (r *FinanceTransactionRepository) List(ctx context.Context, req *models.Fin
 }
 
 // Summary calculates financial summary and balances
-func (r *FinanceTransactionRepository) Summary(ctx context.Context, from, to *string) (*models.FinanceSummaryResponse, error) {
-	log.Printf("📊 SummaryFinanceTransactions: Calculating summary (from=%v, to=%v)", from, to)
+// Rows in excludeCategories (e.g. transfers) are left out of the range income/expense/net only:
+// balanceAllTime, byDestinationAllTime, opening/closing balances and byDestinationRange always
+// include them. balanceAllTime stays correct with transfers in it since both legs net to zero
+// overall, and per-destination balances need them because they do move money between destinations
+func (r *FinanceTransactionRepository) Summary(ctx context.Context, from, to *string, excludeCategories []string) (*models.FinanceSummaryResponse, error) {
+	log.Printf("📊 SummaryFinanceTransactions: Calculating summary (from=%v, to=%v, excludeCategories=%v)", from, to, excludeCategories)
 
 	response := &models.FinanceSummaryResponse{
-		Currency: "COP",
+		Currency:           "COP",
+		ExcludedCategories: excludeCategories,
 	}
 
 	// Calculate balanceAllTime
@@ -382,15 +827,15 @@ func (r *FinanceTransactionRepository) Summary(ctx context.Context, from, to *st
 
 	// If date range is provided, calculate range-specific metrics
 	if from != nil && *from != "" && to != nil && *to != "" {
-		fromDate, err := time.Parse("2006-01-02", *from)
+		fromDate, err := utils.ParseLocalDate(*from)
 		if err != nil {
 			return nil, fmt.Errorf("invalid from date format: %w", err)
 		}
-		toDate, err := time.Parse("2006-01-02", *to)
+		toDate, err := utils.ParseLocalDate(*to)
 		if err != nil {
 			return nil, fmt.Errorf("invalid to date format: %w", err)
 		}
-		toDate = time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+		toDate = utils.EndOfDay(toDate)
 
 		// Calculate opening balance (before from date)
 		queryOpeningBalance := `
@@ -406,15 +851,16 @@ func (r *FinanceTransactionRepository) Summary(ctx context.Context, from, to *st
 		}
 
 		// Calculate income, expense, and net in range
+		filter, args := categoryFilter(excludeCategories, fromDate, toDate)
 		queryRange := `
 			SELECT 
 				COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
 				COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expense
 			FROM finance_transactions
-			WHERE occurred_at >= $1 AND occurred_at <= $2
+			WHERE occurred_at >= $1 AND occurred_at <= $2` + filter + `
 		`
 		var income, expense int64
-		err = db.DB.QueryRowContext(ctx, queryRange, fromDate, toDate).Scan(&income, &expense)
+		err = db.DB.QueryRowContext(ctx, queryRange, args...).Scan(&income, &expense)
 		if err != nil {
 			log.Printf("❌ SummaryFinanceTransactions: Error calculating range metrics: %v", err)
 			return nil, fmt.Errorf("failed to calculate range metrics: %w", err)
@@ -468,7 +914,261 @@ func (r *FinanceTransactionRepository) Summary(ctx context.Context, from, to *st
 	return response, nil
 }
 
+// Ledger returns a destination's transactions in chronological order, each annotated with the
+// running balance after it. The running sum starts from the opening balance before from (0 when
+// from is not set); transfers are included since they move money in and out of the destination
+func (r *FinanceTransactionRepository) Ledger(ctx context.Context, destination string, from, to *string) (*models.DestinationLedgerResponse, error) {
+	log.Printf("📊 DestinationLedger: Building ledger (destination=%s, from=%v, to=%v)", destination, from, to)
+
+	var exists bool
+	err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM finance_transactions WHERE destination = $1)`, destination).Scan(&exists)
+	if err != nil {
+		log.Printf("❌ DestinationLedger: Error checking destination: %v", err)
+		return nil, fmt.Errorf("failed to check destination: %w", err)
+	}
+	if !exists {
+		log.Printf("❌ DestinationLedger: Destination not found: %s", destination)
+		return nil, fmt.Errorf("destination not found")
+	}
+
+	response := &models.DestinationLedgerResponse{
+		Destination: destination,
+		Currency:    "COP",
+		Entries:     []models.DestinationLedgerEntry{},
+	}
+
+	query := `
+		SELECT id, type, source, source_id, occurred_at, amount, destination, category, counterparty, notes, created_at, transfer_group_id
+		FROM finance_transactions
+		WHERE destination = $1
+	`
+	args := []interface{}{destination}
+
+	if from != nil && *from != "" {
+		fromDate, err := utils.ParseLocalDate(*from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date format: %w", err)
+		}
+
+		queryOpeningBalance := `
+			SELECT COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE -amount END), 0)
+			FROM finance_transactions
+			WHERE destination = $1 AND occurred_at < $2
+		`
+		if err := db.DB.QueryRowContext(ctx, queryOpeningBalance, destination, fromDate).Scan(&response.OpeningBalance); err != nil {
+			log.Printf("❌ DestinationLedger: Error calculating openingBalance: %v", err)
+			return nil, fmt.Errorf("failed to calculate opening balance: %w", err)
+		}
+
+		args = append(args, fromDate)
+		query += fmt.Sprintf(" AND occurred_at >= $%d", len(args))
+		response.From = *from
+	}
+
+	if to != nil && *to != "" {
+		toDate, err := utils.ParseLocalDate(*to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date format: %w", err)
+		}
+		toDate = utils.EndOfDay(toDate)
+
+		args = append(args, toDate)
+		query += fmt.Sprintf(" AND occurred_at <= $%d", len(args))
+		response.To = *to
+	}
+
+	query += " ORDER BY occurred_at ASC, id ASC"
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("❌ DestinationLedger: Error fetching transactions: %v", err)
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+	defer rows.Close()
+
+	balance := response.OpeningBalance
+	for rows.Next() {
+		var entry models.DestinationLedgerEntry
+		var category, counterparty, notes sql.NullString
+		var sourceID, transferGroupID sql.NullInt64
+		var occurredAt time.Time
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Type,
+			&entry.Source,
+			&sourceID,
+			&occurredAt,
+			&entry.Amount,
+			&entry.Destination,
+			&category,
+			&counterparty,
+			&notes,
+			&entry.CreatedAt,
+			&transferGroupID,
+		)
+		if err != nil {
+			log.Printf("❌ DestinationLedger: Error scanning transaction: %v", err)
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+
+		entry.OccurredAt = occurredAt.Format(time.RFC3339)
+		if sourceID.Valid {
+			entry.SourceID = &sourceID.Int64
+		}
+		if transferGroupID.Valid {
+			entry.TransferGroupID = &transferGroupID.Int64
+		}
+		if category.Valid {
+			entry.Category = category.String
+		}
+		if counterparty.Valid {
+			entry.Counterparty = counterparty.String
+		}
+		if notes.Valid {
+			entry.Notes = notes.String
+		}
+
+		if entry.Type == "income" {
+			balance += entry.Amount
+		} else {
+			balance -= entry.Amount
+		}
+		entry.RunningBalance = balance
+
+		response.Entries = append(response.Entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ DestinationLedger: Error iterating transactions: %v", err)
+		return nil, fmt.Errorf("failed to iterate transactions: %w", err)
+	}
+
+	response.ClosingBalance = balance
+
+	log.Printf("✅ DestinationLedger: %d entries for destination=%s (opening=%d, closing=%d)", len(response.Entries), destination, response.OpeningBalance, response.ClosingBalance)
+	return response, nil
+}
+
+// Integrity checks money invariants that should always hold on existing data:
+//   - balanceAllTime equals the sum of byDestinationAllTime balances
+//   - each sale's amount_paid equals the sum of its frozen line totals (qty * unit_price), up to the
+//     rounding of the frozen unit prices (see withinFrozenRounding)
+// All checks run inside a single read-only snapshot so concurrent writes cannot produce false positives
+func (r *FinanceTransactionRepository) Integrity(ctx context.Context) (*models.FinanceIntegrityResponse, error) {
+	log.Printf("📊 FinanceIntegrity: Running integrity checks")
+
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		log.Printf("❌ FinanceIntegrity: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	response := &models.FinanceIntegrityResponse{
+		Violations: []models.FinanceIntegrityViolation{},
+	}
+
+	// Check 1: balanceAllTime == sum(byDestinationAllTime.balance)
+	queryBalances := `
+		SELECT
+			COALESCE((SELECT SUM(CASE WHEN type = 'income' THEN amount ELSE -amount END) FROM finance_transactions), 0),
+			COALESCE((
+				SELECT SUM(balance) FROM (
+					SELECT SUM(CASE WHEN type = 'income' THEN amount ELSE -amount END) as balance
+					FROM finance_transactions
+					GROUP BY destination
+				) by_destination
+			), 0)
+	`
+	err = tx.QueryRowContext(ctx, queryBalances).Scan(&response.BalanceAllTime, &response.SumByDestinationAllTime)
+	if err != nil {
+		log.Printf("❌ FinanceIntegrity: Error calculating balances: %v", err)
+		return nil, fmt.Errorf("failed to calculate balances: %w", err)
+	}
+	if response.BalanceAllTime != response.SumByDestinationAllTime {
+		response.Violations = append(response.Violations, models.FinanceIntegrityViolation{
+			Check:      "balance_by_destination",
+			Expected:   response.BalanceAllTime,
+			Actual:     response.SumByDestinationAllTime,
+			Difference: response.SumByDestinationAllTime - response.BalanceAllTime,
+			Message: fmt.Sprintf("sum of balances by destination %d differs from balance all time %d",
+				response.SumByDestinationAllTime, response.BalanceAllTime),
+		})
+	}
+
+	// Check 2: sales.amount_paid == sum(qty * unit_price) of the frozen order lines, up to rounding.
+	// Voided sales are skipped: their order went back to reserved and its lines may have changed since
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sales WHERE status <> 'voided'`).Scan(&response.SalesChecked)
+	if err != nil {
+		log.Printf("❌ FinanceIntegrity: Error counting sales: %v", err)
+		return nil, fmt.Errorf("failed to count sales: %w", err)
+	}
+
+	querySales := `
+		SELECT s.id, s.amount_paid,
+		       COALESCE(SUM(rol.qty * rol.unit_price), 0) as lines_total,
+		       COALESCE(SUM(GREATEST(rol.qty - 1, 0)), 0) as rounding_tolerance
+		FROM sales s
+		LEFT JOIN reserved_order_lines rol ON rol.reserved_order_id = s.reserved_order_id
+		WHERE s.status <> 'voided'
+		GROUP BY s.id, s.amount_paid
+		HAVING s.amount_paid <> COALESCE(SUM(rol.qty * rol.unit_price), 0)
+		ORDER BY s.id
+	`
+	rows, err := tx.QueryContext(ctx, querySales)
+	if err != nil {
+		log.Printf("❌ FinanceIntegrity: Error checking sales: %v", err)
+		return nil, fmt.Errorf("failed to check sales: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var saleID, amountPaid, linesTotal, tolerance int64
+		if err := rows.Scan(&saleID, &amountPaid, &linesTotal, &tolerance); err != nil {
+			log.Printf("❌ FinanceIntegrity: Error scanning sale: %v", err)
+			return nil, fmt.Errorf("failed to scan sale: %w", err)
+		}
+		if withinFrozenRounding(amountPaid, linesTotal, tolerance) {
+			continue
+		}
+		id := saleID
+		response.Violations = append(response.Violations, models.FinanceIntegrityViolation{
+			Check:      "sale_amount_paid",
+			SaleID:     &id,
+			Expected:   linesTotal,
+			Actual:     amountPaid,
+			Difference: amountPaid - linesTotal,
+			Message: fmt.Sprintf("amount_paid %d differs from sum of frozen line totals %d by more than rounding (%d)",
+				amountPaid, linesTotal, tolerance),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ FinanceIntegrity: Error iterating sales: %v", err)
+		return nil, fmt.Errorf("failed to iterate sales: %w", err)
+	}
+
+	response.Consistent = len(response.Violations) == 0
+	if response.Consistent {
+		log.Printf("✅ FinanceIntegrity: All consistent (%d sales checked)", response.SalesChecked)
+	} else {
+		log.Printf("⚠️ FinanceIntegrity: Found %d violations (%d sales checked)", len(response.Violations), response.SalesChecked)
+	}
+	return response, nil
+}
+
+// withinFrozenRounding reports whether a sale's amount_paid matches the sum of its frozen line totals.
+// Frozen unit prices are lineTotal / qty truncated, so a line with qty units can come up to qty-1 short
+// of its real total: amount_paid may exceed the frozen lines by at most tolerance (the sum of qty-1
+// over the lines), but never fall below them.
+func withinFrozenRounding(amountPaid, linesTotal, tolerance int64) bool {
+	difference := amountPaid - linesTotal
+	return difference >= 0 && difference <= tolerance
+}
+
 // Dashboard calculates comprehensive financial dashboard metrics
+// Rows in req.ExcludeCategories (e.g. transfers) are left out of period metrics, cash flow, category,
+// counterparty and top-transaction breakdowns; byDestination always includes them
 func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *models.FinanceDashboardRequest) (*models.FinanceDashboardResponse, error) {
 	log.Printf("📊 DashboardFinanceTransactions: Calculating dashboard metrics")
 
@@ -480,15 +1180,15 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 	if req.From != nil && *req.From != "" && req.To != nil && *req.To != "" {
 		// Use provided dates
 		var err error
-		fromDate, err = time.Parse("2006-01-02", *req.From)
+		fromDate, err = utils.ParseLocalDate(*req.From)
 		if err != nil {
 			return nil, fmt.Errorf("invalid from date format: %w", err)
 		}
-		toDate, err = time.Parse("2006-01-02", *req.To)
+		toDate, err = utils.ParseLocalDate(*req.To)
 		if err != nil {
 			return nil, fmt.Errorf("invalid to date format: %w", err)
 		}
-		toDate = time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+		toDate = utils.EndOfDay(toDate)
 		periodType = "custom"
 		periodLabel = fmt.Sprintf("%s - %s", *req.From, *req.To)
 	} else {
@@ -498,7 +1198,7 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 			periodTypeStr = *req.Period
 		}
 		periodType = periodTypeStr
-		now := time.Now()
+		now := time.Now().In(utils.AppLocation())
 
 		switch periodTypeStr {
 		case "quarter":
@@ -525,7 +1225,8 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 	}
 
 	response := &models.FinanceDashboardResponse{
-		Currency: "COP",
+		Currency:           "COP",
+		ExcludedCategories: req.ExcludeCategories,
 		Period: models.PeriodInfo{
 			Type:  periodType,
 			From:  fromDate.Format("2006-01-02"),
@@ -535,7 +1236,7 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 	}
 
 	// Calculate current period metrics
-	currentMetrics, err := r.calculatePeriodMetrics(ctx, fromDate, toDate)
+	currentMetrics, err := r.calculatePeriodMetrics(ctx, fromDate, toDate, req.ExcludeCategories)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate current period metrics: %w", err)
 	}
@@ -560,7 +1261,7 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 			compareType = "previous"
 		}
 
-		previousMetrics, err := r.calculatePeriodMetrics(ctx, compareFrom, compareTo)
+		previousMetrics, err := r.calculatePeriodMetrics(ctx, compareFrom, compareTo, req.ExcludeCategories)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate previous period metrics: %w", err)
 		}
@@ -581,21 +1282,21 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 	}
 
 	// Calculate cash flow time series
-	cashFlow, err := r.calculateCashFlow(ctx, fromDate, toDate)
+	cashFlow, err := r.calculateCashFlow(ctx, fromDate, toDate, req.ExcludeCategories)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate cash flow: %w", err)
 	}
 	response.CashFlow = *cashFlow
 
 	// Calculate breakdown by category
-	byCategory, err := r.calculateCategoryBreakdown(ctx, fromDate, toDate)
+	byCategory, err := r.calculateCategoryBreakdown(ctx, fromDate, toDate, req.ExcludeCategories)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate category breakdown: %w", err)
 	}
 	response.ByCategory = *byCategory
 
 	// Calculate breakdown by counterparty
-	byCounterparty, err := r.calculateCounterpartyBreakdown(ctx, fromDate, toDate)
+	byCounterparty, err := r.calculateCounterpartyBreakdown(ctx, fromDate, toDate, req.ExcludeCategories)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate counterparty breakdown: %w", err)
 	}
@@ -609,7 +1310,7 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 	response.ByDestination = *byDestination
 
 	// Get top transactions
-	topTransactions, err := r.getTopTransactions(ctx, fromDate, toDate)
+	topTransactions, err := r.getTopTransactions(ctx, fromDate, toDate, req.ExcludeCategories)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get top transactions: %w", err)
 	}
@@ -638,7 +1339,8 @@ func (r *FinanceTransactionRepository) Dashboard(ctx context.Context, req *model
 }
 
 // Helper function to calculate period metrics
-func (r *FinanceTransactionRepository) calculatePeriodMetrics(ctx context.Context, from, to time.Time) (*models.PeriodMetrics, error) {
+func (r *FinanceTransactionRepository) calculatePeriodMetrics(ctx context.Context, from, to time.Time, excludeCategories []string) (*models.PeriodMetrics, error) {
+	filter, args := categoryFilter(excludeCategories, from, to)
 	query := `
 		SELECT 
 			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
@@ -646,14 +1348,14 @@ func (r *FinanceTransactionRepository) calculatePeriodMetrics(ctx context.Contex
 			COUNT(*) as transaction_count,
 			COALESCE(AVG(amount), 0) as avg_transaction
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2
+		WHERE occurred_at >= $1 AND occurred_at <= $2` + filter + `
 	`
 
 	var income, expense int64
 	var transactionCount int
 	var avgTransaction float64
 
-	err := db.DB.QueryRowContext(ctx, query, from, to).Scan(&income, &expense, &transactionCount, &avgTransaction)
+	err := db.DB.QueryRowContext(ctx, query, args...).Scan(&income, &expense, &transactionCount, &avgTransaction)
 	if err != nil {
 		return nil, err
 	}
@@ -706,22 +1408,26 @@ func abs(x int64) int64 {
 }
 
 // Helper function to calculate cash flow time series
-func (r *FinanceTransactionRepository) calculateCashFlow(ctx context.Context, from, to time.Time) (*models.CashFlowData, error) {
+func (r *FinanceTransactionRepository) calculateCashFlow(ctx context.Context, from, to time.Time, excludeCategories []string) (*models.CashFlowData, error) {
 	cashFlow := &models.CashFlowData{}
 
+	// Bucket by local day/week/month so transactions near midnight land on the store's calendar day
+	timezone := utils.AppLocation().String()
+	filter, args := categoryFilter(excludeCategories, from, to, timezone)
+
 	// Daily cash flow
 	dailyQuery := `
 		SELECT 
-			DATE(occurred_at) as date,
+			DATE(occurred_at AT TIME ZONE $3) as date,
 			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
 			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expense
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2
-		GROUP BY DATE(occurred_at)
+		WHERE occurred_at >= $1 AND occurred_at <= $2` + filter + `
+		GROUP BY DATE(occurred_at AT TIME ZONE $3)
 		ORDER BY date
 	`
 
-	rows, err := db.DB.QueryContext(ctx, dailyQuery, from, to)
+	rows, err := db.DB.QueryContext(ctx, dailyQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -741,16 +1447,16 @@ func (r *FinanceTransactionRepository) calculateCashFlow(ctx context.Context, fr
 	// Weekly cash flow
 	weeklyQuery := `
 		SELECT 
-			TO_CHAR(occurred_at, 'IYYY-"W"IW') as week,
+			TO_CHAR(occurred_at AT TIME ZONE $3, 'IYYY-"W"IW') as week,
 			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
 			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expense
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2
-		GROUP BY TO_CHAR(occurred_at, 'IYYY-"W"IW')
+		WHERE occurred_at >= $1 AND occurred_at <= $2` + filter + `
+		GROUP BY TO_CHAR(occurred_at AT TIME ZONE $3, 'IYYY-"W"IW')
 		ORDER BY week
 	`
 
-	rows, err = db.DB.QueryContext(ctx, weeklyQuery, from, to)
+	rows, err = db.DB.QueryContext(ctx, weeklyQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -768,16 +1474,16 @@ func (r *FinanceTransactionRepository) calculateCashFlow(ctx context.Context, fr
 	// Monthly cash flow
 	monthlyQuery := `
 		SELECT 
-			TO_CHAR(occurred_at, 'YYYY-MM') as month,
+			TO_CHAR(occurred_at AT TIME ZONE $3, 'YYYY-MM') as month,
 			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
 			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expense
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2
-		GROUP BY TO_CHAR(occurred_at, 'YYYY-MM')
+		WHERE occurred_at >= $1 AND occurred_at <= $2` + filter + `
+		GROUP BY TO_CHAR(occurred_at AT TIME ZONE $3, 'YYYY-MM')
 		ORDER BY month
 	`
 
-	rows, err = db.DB.QueryContext(ctx, monthlyQuery, from, to)
+	rows, err = db.DB.QueryContext(ctx, monthlyQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -796,8 +1502,9 @@ func (r *FinanceTransactionRepository) calculateCashFlow(ctx context.Context, fr
 }
 
 // Helper function to calculate category breakdown
-func (r *FinanceTransactionRepository) calculateCategoryBreakdown(ctx context.Context, from, to time.Time) (*models.CategoryBreakdown, error) {
+func (r *FinanceTransactionRepository) calculateCategoryBreakdown(ctx context.Context, from, to time.Time, excludeCategories []string) (*models.CategoryBreakdown, error) {
 	breakdown := &models.CategoryBreakdown{}
+	filter, args := categoryFilter(excludeCategories, from, to)
 
 	// Income by category
 	incomeQuery := `
@@ -806,12 +1513,12 @@ func (r *FinanceTransactionRepository) calculateCategoryBreakdown(ctx context.Co
 			SUM(amount) as amount,
 			COUNT(*) as count
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'income'
+		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'income'` + filter + `
 		GROUP BY category
 		ORDER BY amount DESC
 	`
 
-	rows, err := db.DB.QueryContext(ctx, incomeQuery, from, to)
+	rows, err := db.DB.QueryContext(ctx, incomeQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -843,12 +1550,12 @@ func (r *FinanceTransactionRepository) calculateCategoryBreakdown(ctx context.Co
 			SUM(amount) as amount,
 			COUNT(*) as count
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'expense'
+		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'expense'` + filter + `
 		GROUP BY category
 		ORDER BY amount DESC
 	`
 
-	rows, err = db.DB.QueryContext(ctx, expenseQuery, from, to)
+	rows, err = db.DB.QueryContext(ctx, expenseQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -877,8 +1584,9 @@ func (r *FinanceTransactionRepository) calculateCategoryBreakdown(ctx context.Co
 }
 
 // Helper function to calculate counterparty breakdown
-func (r *FinanceTransactionRepository) calculateCounterpartyBreakdown(ctx context.Context, from, to time.Time) (*models.CounterpartyBreakdown, error) {
+func (r *FinanceTransactionRepository) calculateCounterpartyBreakdown(ctx context.Context, from, to time.Time, excludeCategories []string) (*models.CounterpartyBreakdown, error) {
 	breakdown := &models.CounterpartyBreakdown{}
+	filter, args := categoryFilter(excludeCategories, from, to)
 
 	// Top expenses by counterparty
 	expenseQuery := `
@@ -887,13 +1595,13 @@ func (r *FinanceTransactionRepository) calculateCounterpartyBreakdown(ctx contex
 			SUM(amount) as amount,
 			COUNT(*) as count
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'expense' AND counterparty IS NOT NULL
+		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'expense' AND counterparty IS NOT NULL` + filter + `
 		GROUP BY counterparty
 		ORDER BY amount DESC
 		LIMIT 10
 	`
 
-	rows, err := db.DB.QueryContext(ctx, expenseQuery, from, to)
+	rows, err := db.DB.QueryContext(ctx, expenseQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -914,13 +1622,13 @@ func (r *FinanceTransactionRepository) calculateCounterpartyBreakdown(ctx contex
 			SUM(amount) as amount,
 			COUNT(*) as count
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'income' AND counterparty IS NOT NULL
+		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'income' AND counterparty IS NOT NULL` + filter + `
 		GROUP BY counterparty
 		ORDER BY amount DESC
 		LIMIT 10
 	`
 
-	rows, err = db.DB.QueryContext(ctx, incomeQuery, from, to)
+	rows, err = db.DB.QueryContext(ctx, incomeQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -974,19 +1682,20 @@ func (r *FinanceTransactionRepository) calculateDestinationBreakdown(ctx context
 }
 
 // Helper function to get top transactions
-func (r *FinanceTransactionRepository) getTopTransactions(ctx context.Context, from, to time.Time) (*models.TopTransactions, error) {
+func (r *FinanceTransactionRepository) getTopTransactions(ctx context.Context, from, to time.Time, excludeCategories []string) (*models.TopTransactions, error) {
 	topTransactions := &models.TopTransactions{}
+	filter, args := categoryFilter(excludeCategories, from, to)
 
 	// Largest incomes
 	incomeQuery := `
 		SELECT id, amount, destination, category, occurred_at
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'income'
+		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'income'` + filter + `
 		ORDER BY amount DESC
 		LIMIT 10
 	`
 
-	rows, err := db.DB.QueryContext(ctx, incomeQuery, from, to)
+	rows, err := db.DB.QueryContext(ctx, incomeQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1010,12 +1719,12 @@ func (r *FinanceTransactionRepository) getTopTransactions(ctx context.Context, f
 	expenseQuery := `
 		SELECT id, amount, destination, category, occurred_at
 		FROM finance_transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'expense'
+		WHERE occurred_at >= $1 AND occurred_at <= $2 AND type = 'expense'` + filter + `
 		ORDER BY amount DESC
 		LIMIT 10
 	`
 
-	rows, err = db.DB.QueryContext(ctx, expenseQuery, from, to)
+	rows, err = db.DB.QueryContext(ctx, expenseQuery, args...)
 	if err != nil {
 		return nil, err
 	}
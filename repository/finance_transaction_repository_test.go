@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"armario-mascota-me/models"
+)
+
+// frozenLinesTotal mirrors what Sell stores: each line frozen at lineTotal / qty, summed as qty * unit_price,
+// plus the rounding tolerance the integrity check allows for those lines
+func frozenLinesTotal(lines []models.PricingLine) (int64, int64) {
+	var total, tolerance int64
+	for _, line := range lines {
+		unitPrice := line.LineTotal / int64(line.Qty)
+		total += int64(line.Qty) * unitPrice
+		tolerance += int64(line.Qty - 1)
+	}
+	return total, tolerance
+}
+
+func TestWithinFrozenRounding(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []models.PricingLine
+		amountPaid int64
+		want       bool
+	}{
+		{
+			name:       "divisible line totals",
+			lines:      []models.PricingLine{{Qty: 2, LineTotal: 90000}, {Qty: 1, LineTotal: 45000}},
+			amountPaid: 135000,
+			want:       true,
+		},
+		{
+			name:       "bundle line total not divisible by qty",
+			lines:      []models.PricingLine{{Qty: 3, LineTotal: 100000}},
+			amountPaid: 100000,
+			want:       true,
+		},
+		{
+			name:       "bundle and discount rounding on several lines",
+			lines:      []models.PricingLine{{Qty: 3, LineTotal: 100000}, {Qty: 7, LineTotal: 200005}},
+			amountPaid: 300005,
+			want:       true,
+		},
+		{
+			name:       "amount paid above the rounding tolerance",
+			lines:      []models.PricingLine{{Qty: 3, LineTotal: 100000}},
+			amountPaid: 100003,
+			want:       false,
+		},
+		{
+			name:       "amount paid below the frozen lines",
+			lines:      []models.PricingLine{{Qty: 3, LineTotal: 100000}},
+			amountPaid: 99998,
+			want:       false,
+		},
+		{
+			name:       "single units leave no room for rounding",
+			lines:      []models.PricingLine{{Qty: 1, LineTotal: 45000}, {Qty: 1, LineTotal: 45000}},
+			amountPaid: 90001,
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linesTotal, tolerance := frozenLinesTotal(tt.lines)
+			if got := withinFrozenRounding(tt.amountPaid, linesTotal, tolerance); got != tt.want {
+				t.Errorf("withinFrozenRounding(%d, %d, %d) = %v, want %v", tt.amountPaid, linesTotal, tolerance, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestImportRejectsAllOnRejectedRow checks that without allowPartial a rejected row imports nothing.
+// It returns before touching the database, so no connection is needed
+func TestImportRejectsAllOnRejectedRow(t *testing.T) {
+	rows := []models.FinanceImportRow{
+		{Line: 2, Request: models.CreateFinanceTransactionRequest{Type: "income", Amount: 1000, Destination: "Caja", AllowFreeText: true}},
+		{Line: 3, Request: models.CreateFinanceTransactionRequest{Type: "expense", Amount: 0, Destination: "Caja", AllowFreeText: true}},
+		{Line: 4, Error: "invalid amount 'abc'"},
+	}
+
+	response, err := NewFinanceTransactionRepository().Import(context.Background(), rows, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if response.Imported != 0 || response.Rejected != 2 {
+		t.Errorf("Import() imported=%d rejected=%d, want imported=0 rejected=2", response.Imported, response.Rejected)
+	}
+
+	wantStatuses := []string{"skipped", "rejected", "rejected"}
+	for i, want := range wantStatuses {
+		if got := response.Rows[i]; got.Status != want || got.TransactionID != nil {
+			t.Errorf("row for line %d = %+v, want status %q and no transaction", got.Line, got, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"armario-mascota-me/models"
 )
@@ -12,49 +13,152 @@ type DesignAssetRepositoryInterface interface {
 	Insert(ctx context.Context, asset *models.DesignAssetDB, status string) error
 	GetByCode(ctx context.Context, code string) (*models.DesignAssetDetail, error)
 	GetByID(ctx context.Context, id int) (*models.DesignAssetDetail, error)
-	UpdateDescriptionAndHighlights(ctx context.Context, code string, description string, hasHighlights bool) error
-	GetPending(ctx context.Context) ([]models.DesignAssetDetail, error)
+	Update(ctx context.Context, code string, req *models.DesignAssetUpdateRequest) error
+	GetPending(ctx context.Context, limit, offset int, incompleteOnly bool) ([]models.DesignAssetDetail, int, error)
 	GetCustomPending(ctx context.Context) ([]models.DesignAssetDetail, error)
 	UpdateFullDesignAsset(ctx context.Context, id int, code, description, colorPrimary, colorSecondary, hoodieType, imageType, decoID, decoBase string, hasHighlights bool, status string) error
 	FilterDesignAssets(ctx context.Context, filters FilterParams) ([]models.DesignAssetDetail, error)
+	MarkImageRejected(ctx context.Context, id int, reason string) error
+	SetImageRotation(ctx context.Context, id int, rotation int) error
+	GetPendingForAutoTag(ctx context.Context) ([]models.DesignAssetDetail, error)
+	ApplyAutoTags(ctx context.Context, id int, tags models.AutoTagFields) error
+	SetActive(ctx context.Context, code string, active bool) (*models.DesignAssetDetail, error)
+	BulkUpdateStatus(ctx context.Context, codes []string, ids []int, status string) (*models.BulkUpdateDesignAssetStatusResponse, error)
+	Publish(ctx context.Context, id int, req *models.PublishDesignAssetRequest) (*models.PublishDesignAssetResponse, error)
+	Delete(ctx context.Context, code string) error
+	GetDuplicates(ctx context.Context) ([]DuplicateGroup, error)
 }
 
 // ItemRepositoryInterface defines the contract for item repository operations
 type ItemRepositoryInterface interface {
 	UpsertStock(ctx context.Context, designAssetID int, size string, quantity int) (*models.AddStockResponse, error)
 	FilterItems(ctx context.Context, filters ItemFilterParams) ([]models.ItemCard, error)
+	UpdateDesignAsset(ctx context.Context, itemID int64, designAssetID int64, recompute bool) (*models.ItemFullInfo, error)
+	QuotePrice(ctx context.Context, itemID int64, qty int, orderType string) (*models.ItemPriceQuote, error)
+	PreviewPricing(ctx context.Context, lines []models.PricingPreviewLine, couponCode string) (*models.PricingBreakdown, error)
+	Create(ctx context.Context, req *models.CreateItemRequest) (*models.Item, error)
+	Update(ctx context.Context, itemID int64, req *models.UpdateItemRequest) (*models.Item, error)
+	GetByID(ctx context.Context, itemID int64) (*models.Item, error)
+	SetActive(ctx context.Context, itemID int64, isActive bool) (*models.Item, error)
+	GetLowStock(ctx context.Context, threshold int) (*models.LowStockResponse, error)
+	GetItemsByDesignAsset(ctx context.Context, designAssetID int) (*models.DesignAssetItemsResponse, error)
+	SearchItems(ctx context.Context, query string, limit int) (*models.ItemSearchResponse, error)
+	AdjustStock(ctx context.Context, itemID int64, req *models.AdjustItemStockRequest) (*models.AdjustItemStockResponse, error)
+	GetAdjustments(ctx context.Context, itemID int64) (*models.ItemAdjustmentsResponse, error)
+	CheckAvailability(ctx context.Context, lines []models.ItemAvailabilityLine) (*models.ItemAvailabilityResponse, error)
 }
 
 // ReservedOrderRepositoryInterface defines the contract for reserved order repository operations
 type ReservedOrderRepositoryInterface interface {
 	Create(ctx context.Context, req *models.CreateReservedOrderRequest) (*models.ReservedOrder, error)
 	AddItem(ctx context.Context, orderID int64, itemID int64, qty int, customCode *string) (*models.ReservedOrderLine, error)
+	BulkAddItems(ctx context.Context, orderID int64, items []models.AddItemToOrderRequest) (*models.BulkAddItemsResponse, error)
 	RemoveItem(ctx context.Context, orderID int64, itemID int64) error
 	UpdateItemQuantity(ctx context.Context, orderID int64, itemID int64, newQty int) (*models.ReservedOrderLine, error)
 	UpdateOrder(ctx context.Context, req *models.UpdateReservedOrderRequest) (*models.ReservedOrderResponse, error)
 	GetByID(ctx context.Context, id int64) (*models.ReservedOrderResponse, error)
-	List(ctx context.Context, status *string) ([]models.ReservedOrderListItem, error)
+	GetByIDWithBreakdown(ctx context.Context, id int64) (*models.ReservedOrderResponse, error)
+	List(ctx context.Context, req *models.ReservedOrderListRequest) (*models.ReservedOrderListResponse, error)
 	Cancel(ctx context.Context, id int64) (*models.ReservedOrder, error)
+	ExtendHold(ctx context.Context, orderID int64, req *models.ExtendHoldRequest) (*models.ReservedOrderHold, error)
+	ExpireHolds(ctx context.Context, now time.Time) (int, error)
+	Reopen(ctx context.Context, id int64) (*models.ReservedOrder, error)
 	Complete(ctx context.Context, id int64) (*models.ReservedOrder, error)
 	GetAllWithFullItems(ctx context.Context, status *string) ([]models.ReservedOrderWithFullItems, error)
+	RecalculateOrderTypes(ctx context.Context) (*models.RecalculateOrderTypesResponse, error)
+	ClaimStock(ctx context.Context, orderID int64, req *models.ClaimStockRequest) (*models.ReservedOrderStockClaim, error)
+	MergeOrders(ctx context.Context, targetID, sourceID int64) (*models.ReservedOrderResponse, error)
+	GetPickingList(ctx context.Context, assignedTo *string, status string) (*models.PickingListResponse, error)
+	PatchOrder(ctx context.Context, id int64, req *models.PatchReservedOrderRequest) (*models.ReservedOrderResponse, error)
+	SummaryBySeller(ctx context.Context) (*models.SellerCartSummaryResponse, error)
+	GetCompletionImpact(ctx context.Context, orderID int64, threshold int) (*models.CompletionImpactResponse, error)
 }
 
 // SaleRepositoryInterface defines the contract for sale repository operations
 type SaleRepositoryInterface interface {
 	Sell(ctx context.Context, reservedOrderID int64, req *models.SellRequest) (*models.Sale, error)
+	Report(ctx context.Context, from, to *string, groupBy string) (*models.SalesReportResponse, error)
+	BySeller(ctx context.Context, from, to *string) (*models.SalesBySellerResponse, error)
+	TopCustomers(ctx context.Context, from, to *string, limit int) (*models.TopCustomersResponse, error)
 	GetByID(ctx context.Context, saleID int64) (*models.SaleDetailResponse, error)
 	List(ctx context.Context, from, to *string) ([]models.SaleListItem, error)
+	Reprice(ctx context.Context, saleID int64, reason string) (*models.RepriceSaleResponse, error)
+	Refund(ctx context.Context, saleID int64, req *models.RefundSaleRequest) (*models.SaleRefund, error)
+	VoidSale(ctx context.Context, saleID int64, force bool, reason string) (*models.VoidSaleResponse, error)
+	ListRefunds(ctx context.Context, saleID int64) (*models.SaleRefundListResponse, error)
+	ListByReservedOrder(ctx context.Context, reservedOrderID int64) (*models.OrderSalesResponse, error)
+	GetByReservedOrder(ctx context.Context, reservedOrderID int64) (*models.SaleDetailResponse, error)
+	SellCheck(ctx context.Context, reservedOrderID int64) (*models.SellCheckResponse, error)
 }
 
 // FinanceTransactionRepositoryInterface defines the contract for finance transaction repository operations
 type FinanceTransactionRepositoryInterface interface {
 	Create(ctx context.Context, req *models.CreateFinanceTransactionRequest) (*models.FinanceTransaction, error)
+	Update(ctx context.Context, id int64, req *models.UpdateFinanceTransactionRequest) (*models.FinanceTransaction, error)
+	Delete(ctx context.Context, id int64) error
+	Transfer(ctx context.Context, req *models.TransferRequest) (*models.TransferResponse, error)
+	Import(ctx context.Context, rows []models.FinanceImportRow, allowPartial bool) (*models.FinanceImportResponse, error)
 	List(ctx context.Context, req *models.FinanceTransactionListRequest) (*models.FinanceTransactionListResponse, error)
-	Summary(ctx context.Context, from, to *string) (*models.FinanceSummaryResponse, error)
+	Summary(ctx context.Context, from, to *string, excludeCategories []string) (*models.FinanceSummaryResponse, error)
 	Dashboard(ctx context.Context, req *models.FinanceDashboardRequest) (*models.FinanceDashboardResponse, error)
+	Integrity(ctx context.Context) (*models.FinanceIntegrityResponse, error)
+	Ledger(ctx context.Context, destination string, from, to *string) (*models.DestinationLedgerResponse, error)
+}
+
+// FinanceTemplateRepositoryInterface defines the contract for finance template operations
+type FinanceTemplateRepositoryInterface interface {
+	List(ctx context.Context) ([]models.FinanceTemplate, error)
+	GetByID(ctx context.Context, id int64) (*models.FinanceTemplate, error)
+	Create(ctx context.Context, req *models.FinanceTemplateRequest) (*models.FinanceTemplate, error)
+	Update(ctx context.Context, id int64, req *models.FinanceTemplateRequest) (*models.FinanceTemplate, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// FinanceReferenceRepositoryInterface defines the contract for the managed destination and category lists.
+// kind is FinanceReferenceDestination or FinanceReferenceCategory
+type FinanceReferenceRepositoryInterface interface {
+	List(ctx context.Context, kind string) ([]models.FinanceReferenceValue, error)
+	Create(ctx context.Context, kind string, req *models.FinanceReferenceValueRequest) (*models.FinanceReferenceValue, error)
+	Update(ctx context.Context, kind string, id int64, req *models.FinanceReferenceValueRequest) (*models.FinanceReferenceValue, error)
+	Delete(ctx context.Context, kind string, id int64) error
+}
+
+// RecurringTransactionRepositoryInterface defines the contract for recurring finance transaction operations
+type RecurringTransactionRepositoryInterface interface {
+	List(ctx context.Context) ([]models.RecurringTransaction, error)
+	GetByID(ctx context.Context, id int64) (*models.RecurringTransaction, error)
+	Create(ctx context.Context, req *models.RecurringTransactionRequest) (*models.RecurringTransaction, error)
+	Update(ctx context.Context, id int64, req *models.RecurringTransactionRequest) (*models.RecurringTransaction, error)
+	Delete(ctx context.Context, id int64) error
+	GenerateDue(ctx context.Context, today time.Time) (int, error)
+}
+
+// FinanceBudgetRepositoryInterface defines the contract for monthly finance budget operations
+type FinanceBudgetRepositoryInterface interface {
+	List(ctx context.Context, month *string) ([]models.FinanceBudget, error)
+	GetByID(ctx context.Context, id int64) (*models.FinanceBudget, error)
+	Create(ctx context.Context, req *models.FinanceBudgetRequest) (*models.FinanceBudget, error)
+	Update(ctx context.Context, id int64, req *models.FinanceBudgetRequest) (*models.FinanceBudget, error)
+	Delete(ctx context.Context, id int64) error
+	Status(ctx context.Context, month string) (*models.FinanceBudgetStatusResponse, error)
 }
 
 // CatalogRepositoryInterface defines the contract for catalog repository operations
 type CatalogRepositoryInterface interface {
-	GetItemsBySizeForCatalog(ctx context.Context, size string) ([]models.CatalogItem, error)
+	GetItemsBySizeForCatalog(ctx context.Context, size string, filters CatalogFilterParams) ([]models.CatalogItem, error)
+}
+
+// WebhookFailureRepositoryInterface defines the contract for webhook dead-letter operations
+type WebhookFailureRepositoryInterface interface {
+	Create(ctx context.Context, saleID int64, payload []byte, lastError string, attempts int) (*models.WebhookFailure, error)
+	List(ctx context.Context, includeResolved bool) ([]models.WebhookFailure, error)
+	GetByID(ctx context.Context, id int64) (*models.WebhookFailure, error)
+	RecordRetry(ctx context.Context, id int64, retryErr error) (*models.WebhookFailure, error)
+}
+
+// ReportRepositoryInterface defines the contract for reporting operations
+type ReportRepositoryInterface interface {
+	GroupsCoverage(ctx context.Context) (*models.GroupsCoverageResponse, error)
+	TimeToSell(ctx context.Context, from, to *string) (*models.TimeToSellResponse, error)
+	InventoryDashboard(ctx context.Context) (*models.InventoryDashboardResponse, error)
 }
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"armario-mascota-me/db"
 	"armario-mascota-me/models"
+	"armario-mascota-me/pricing"
 	"armario-mascota-me/utils"
 )
 
@@ -211,3 +213,925 @@ func (r *ItemRepository) FilterItems(ctx context.Context, filters ItemFilterPara
 	return items, nil
 }
 
+
+// UpdateDesignAsset relinks an item to another (active) design asset.
+// The SKU and the stored price are derived from the design asset (code and hoodie_type); they are only
+// recalculated from the new asset when recompute is true, so a relink never overwrites a price implicitly.
+func (r *ItemRepository) UpdateDesignAsset(ctx context.Context, itemID int64, designAssetID int64, recompute bool) (*models.ItemFullInfo, error) {
+	log.Printf("📦 UpdateDesignAsset: item_id=%d -> design_asset_id=%d, recompute=%v", itemID, designAssetID, recompute)
+
+	// Start transaction
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ UpdateDesignAsset: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock item and get its current linkage
+	var size string
+	var oldDesignAssetID int64
+	var oldHoodieType string
+	queryItem := `
+		SELECT i.size, i.design_asset_id, COALESCE(da.hoodie_type, '') as hoodie_type
+		FROM items i
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.id = $1
+		FOR UPDATE OF i
+	`
+	err = tx.QueryRowContext(ctx, queryItem, itemID).Scan(&size, &oldDesignAssetID, &oldHoodieType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateDesignAsset: Item not found: id=%d", itemID)
+			return nil, fmt.Errorf("item not found")
+		}
+		log.Printf("❌ UpdateDesignAsset: Error fetching item: %v", err)
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+
+	if oldDesignAssetID == designAssetID {
+		log.Printf("❌ UpdateDesignAsset: Item %d is already linked to design asset %d", itemID, designAssetID)
+		return nil, fmt.Errorf("item is already linked to design asset %d", designAssetID)
+	}
+
+	// Validate new design asset exists and is active
+	var code, hoodieType string
+	var isActive bool
+	queryDesignAsset := `
+		SELECT code, COALESCE(hoodie_type, '') as hoodie_type, is_active
+		FROM design_assets
+		WHERE id = $1
+	`
+	err = tx.QueryRowContext(ctx, queryDesignAsset, designAssetID).Scan(&code, &hoodieType, &isActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateDesignAsset: Design asset not found: id=%d", designAssetID)
+			return nil, fmt.Errorf("design asset with id %d does not exist", designAssetID)
+		}
+		log.Printf("❌ UpdateDesignAsset: Error fetching design asset: %v", err)
+		return nil, fmt.Errorf("failed to get design asset: %w", err)
+	}
+	if !isActive {
+		log.Printf("❌ UpdateDesignAsset: Design asset is not active: id=%d", designAssetID)
+		return nil, fmt.Errorf("design asset with id %d is not active", designAssetID)
+	}
+
+	// items has UNIQUE(design_asset_id, size)
+	var conflictingItemID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM items WHERE design_asset_id = $1 AND size = $2`, designAssetID, size).Scan(&conflictingItemID)
+	if err == nil {
+		log.Printf("❌ UpdateDesignAsset: Design asset %d already has item %d for size %s", designAssetID, conflictingItemID, size)
+		return nil, fmt.Errorf("design asset %d already has an item for size %s (item %d)", designAssetID, size, conflictingItemID)
+	}
+	if err != sql.ErrNoRows {
+		log.Printf("❌ UpdateDesignAsset: Error checking existing item: %v", err)
+		return nil, fmt.Errorf("failed to check existing item: %w", err)
+	}
+
+	// SKU and price are only recalculated from the new design asset when the caller asks for it;
+	// otherwise the relink keeps them as they are
+	if recompute {
+		price := utils.CalculatePriceLegacy(hoodieType, size)
+		sku := fmt.Sprintf("%s_%s", size, code)
+		log.Printf("💰 UpdateDesignAsset: Recomputing item %d from design asset %d: sku=%s, price=%d", itemID, designAssetID, sku, price)
+		_, err = tx.ExecContext(ctx, `UPDATE items SET design_asset_id = $1, sku = $2, price = $3 WHERE id = $4`, designAssetID, sku, price, itemID)
+	} else {
+		if oldHoodieType != hoodieType {
+			log.Printf("⚠️ UpdateDesignAsset: hoodie_type changed %s -> %s, keeping item %d sku and price (recompute not requested)", oldHoodieType, hoodieType, itemID)
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE items SET design_asset_id = $1 WHERE id = $2`, designAssetID, itemID)
+	}
+	if err != nil {
+		log.Printf("❌ UpdateDesignAsset: Error updating item: %v", err)
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+
+	// Read back the item with the new design asset information
+	var item models.ItemFullInfo
+	queryFull := `
+		SELECT i.id, i.sku, i.size, i.price, i.stock_total, i.stock_reserved, i.design_asset_id,
+		       COALESCE(da.description, '') as description,
+		       COALESCE(da.color_primary, '') as color_primary,
+		       COALESCE(da.color_secondary, '') as color_secondary,
+		       COALESCE(da.hoodie_type, '') as hoodie_type,
+		       COALESCE(da.image_type, '') as image_type,
+		       COALESCE(da.deco_id, '') as deco_id,
+		       COALESCE(da.deco_base, '') as deco_base
+		FROM items i
+		INNER JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.id = $1
+	`
+	err = tx.QueryRowContext(ctx, queryFull, itemID).Scan(
+		&item.ID,
+		&item.SKU,
+		&item.Size,
+		&item.Price,
+		&item.StockTotal,
+		&item.StockReserved,
+		&item.DesignAssetID,
+		&item.Description,
+		&item.ColorPrimary,
+		&item.ColorSecondary,
+		&item.HoodieType,
+		&item.ImageType,
+		&item.DecoID,
+		&item.DecoBase,
+	)
+	if err != nil {
+		log.Printf("❌ UpdateDesignAsset: Error fetching updated item: %v", err)
+		return nil, fmt.Errorf("failed to fetch updated item: %w", err)
+	}
+	item.StockAvailable = stockAvailable(item.StockTotal, item.StockReserved)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ UpdateDesignAsset: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ UpdateDesignAsset: item_id=%d relinked from design_asset_id=%d to %d (sku=%s)", itemID, oldDesignAssetID, designAssetID, item.SKU)
+	return &item, nil
+}
+
+// QuotePrice runs the pricing engine on a single-line synthetic cart (item x qty) without creating an order.
+// orderType optionally forces "mayorista" or "detal" pricing; empty lets the engine decide.
+func (r *ItemRepository) QuotePrice(ctx context.Context, itemID int64, qty int, orderType string) (*models.ItemPriceQuote, error) {
+	log.Printf("📦 QuotePrice: item_id=%d, qty=%d, orderType=%s", itemID, qty, orderType)
+
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		return nil, fmt.Errorf("pricing engine not available")
+	}
+
+	line := pricing.OrderLineInput{ItemID: itemID, Qty: qty}
+	query := `
+		SELECT i.size, i.sku, COALESCE(da.hoodie_type, '') as hoodie_type
+		FROM items i
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.id = $1
+	`
+	err := db.DB.QueryRowContext(ctx, query, itemID).Scan(&line.Size, &line.SKU, &line.HoodieType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("item not found")
+		}
+		log.Printf("❌ QuotePrice: Error fetching item %d: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	breakdown := pricingEngine.SimulatePricing([]pricing.OrderLineInput{line}, orderType)
+
+	quote := &models.ItemPriceQuote{
+		ItemID:             itemID,
+		SKU:                line.SKU,
+		Size:               line.Size,
+		HoodieType:         line.HoodieType,
+		Qty:                qty,
+		OrderType:          breakdown.OrderType,
+		EffectiveUnitPrice: breakdown.Total / int64(qty),
+		Total:              breakdown.Total,
+		AppliedRules:       breakdown.AppliedRules,
+		WholesaleApplies:   breakdown.OrderType == "mayorista",
+		Breakdown:          *breakdown,
+	}
+	for _, pricedLine := range breakdown.Lines {
+		quote.QtyInBundle += pricedLine.QtyInBundle
+	}
+	if minQty, ok := pricingEngine.WholesaleMinQty(); ok {
+		quote.WholesaleMinQty = minQty
+	}
+
+	log.Printf("💰 QuotePrice: item_id=%d, qty=%d -> total=%d, unit=%d, orderType=%s", itemID, qty, quote.Total, quote.EffectiveUnitPrice, quote.OrderType)
+	return quote, nil
+}
+
+// PreviewPricing prices an arbitrary cart of {itemId, qty} entries without creating an order.
+// Each item's hoodieType and size are resolved from items/design_assets before running the engine.
+// couponCode is optional and passed through to the engine.
+func (r *ItemRepository) PreviewPricing(ctx context.Context, lines []models.PricingPreviewLine, couponCode string) (*models.PricingBreakdown, error) {
+	log.Printf("📦 PreviewPricing: %d cart lines", len(lines))
+
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		return nil, fmt.Errorf("pricing engine not available")
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("at least one line is required")
+	}
+
+	query := `
+		SELECT i.size, i.sku, COALESCE(da.hoodie_type, '') as hoodie_type
+		FROM items i
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.id = $1
+	`
+
+	resolved := make(map[int64]pricing.OrderLineInput)
+	inputs := make([]pricing.OrderLineInput, 0, len(lines))
+	for _, previewLine := range lines {
+		if previewLine.ItemID <= 0 {
+			return nil, fmt.Errorf("invalid itemId: %d", previewLine.ItemID)
+		}
+		if previewLine.Qty <= 0 {
+			return nil, fmt.Errorf("qty must be greater than 0 for item %d", previewLine.ItemID)
+		}
+
+		line, ok := resolved[previewLine.ItemID]
+		if !ok {
+			line = pricing.OrderLineInput{ItemID: previewLine.ItemID}
+			err := db.DB.QueryRowContext(ctx, query, previewLine.ItemID).Scan(&line.Size, &line.SKU, &line.HoodieType)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return nil, fmt.Errorf("item %d not found", previewLine.ItemID)
+				}
+				log.Printf("❌ PreviewPricing: Error fetching item %d: %v", previewLine.ItemID, err)
+				return nil, fmt.Errorf("failed to get item %d: %w", previewLine.ItemID, err)
+			}
+			resolved[previewLine.ItemID] = line
+		}
+
+		line.Qty = previewLine.Qty
+		inputs = append(inputs, line)
+	}
+
+	return pricingEngine.PreviewPricing(ctx, inputs, couponCode)
+}
+
+// queryItemByID selects the columns scanned by scanItem
+const queryItemByID = `
+	SELECT id, design_asset_id, size, sku, price, stock_total, stock_reserved, is_active, created_at
+	FROM items
+	WHERE id = $1
+`
+
+// scanItem scans a row selected with queryItemByID into an Item
+func scanItem(row *sql.Row) (*models.Item, error) {
+	var item models.Item
+	var createdAt time.Time
+	err := row.Scan(
+		&item.ID,
+		&item.DesignAssetID,
+		&item.Size,
+		&item.SKU,
+		&item.Price,
+		&item.StockTotal,
+		&item.StockReserved,
+		&item.IsActive,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	return &item, nil
+}
+
+// checkSKUAvailable returns a conflict error when another item already uses sku.
+// excludeItemID skips the item being edited (0 when creating)
+func checkSKUAvailable(ctx context.Context, tx *sql.Tx, sku string, excludeItemID int64) error {
+	var existingID int64
+	err := tx.QueryRowContext(ctx, `SELECT id FROM items WHERE sku = $1 AND id <> $2 LIMIT 1`, sku, excludeItemID).Scan(&existingID)
+	if err == nil {
+		return fmt.Errorf("sku %s already exists (item %d)", sku, existingID)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check sku: %w", err)
+	}
+	return nil
+}
+
+// Create inserts a new item for a design asset and size.
+// The SKU defaults to "<size>_<design asset code>", the same format UpsertStock generates
+func (r *ItemRepository) Create(ctx context.Context, req *models.CreateItemRequest) (*models.Item, error) {
+	log.Printf("📦 CreateItem: design_asset_id=%d, size=%s, sku=%s, price=%d, stock_total=%d",
+		req.DesignAssetID, req.Size, req.SKU, req.Price, req.StockTotal)
+
+	size := utils.NormalizeSize(req.Size)
+	if !utils.IsKnownSize(size) {
+		log.Printf("❌ CreateItem: Invalid size: %s", req.Size)
+		return nil, fmt.Errorf("invalid size %s: must be one of XS, S, M, L, XL, MN, IT", req.Size)
+	}
+	if req.Price <= 0 {
+		log.Printf("❌ CreateItem: Invalid price: %d", req.Price)
+		return nil, fmt.Errorf("price must be greater than 0")
+	}
+	if req.StockTotal < 0 {
+		log.Printf("❌ CreateItem: Invalid stock_total: %d", req.StockTotal)
+		return nil, fmt.Errorf("stockTotal must be greater than or equal to 0")
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ CreateItem: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var code string
+	err = tx.QueryRowContext(ctx, `SELECT code FROM design_assets WHERE id = $1`, req.DesignAssetID).Scan(&code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ CreateItem: Design asset not found: id=%d", req.DesignAssetID)
+			return nil, fmt.Errorf("design asset with id %d does not exist", req.DesignAssetID)
+		}
+		log.Printf("❌ CreateItem: Error fetching design asset: %v", err)
+		return nil, fmt.Errorf("failed to get design asset: %w", err)
+	}
+
+	// items has UNIQUE(design_asset_id, size)
+	var conflictingItemID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM items WHERE design_asset_id = $1 AND size = $2`, req.DesignAssetID, size).Scan(&conflictingItemID)
+	if err == nil {
+		log.Printf("❌ CreateItem: Design asset %d already has item %d for size %s", req.DesignAssetID, conflictingItemID, size)
+		return nil, fmt.Errorf("design asset %d already has an item for size %s (item %d)", req.DesignAssetID, size, conflictingItemID)
+	}
+	if err != sql.ErrNoRows {
+		log.Printf("❌ CreateItem: Error checking existing item: %v", err)
+		return nil, fmt.Errorf("failed to check existing item: %w", err)
+	}
+
+	sku := strings.TrimSpace(req.SKU)
+	if sku == "" {
+		sku = fmt.Sprintf("%s_%s", size, code)
+	}
+	if err := checkSKUAvailable(ctx, tx, sku, 0); err != nil {
+		log.Printf("❌ CreateItem: %v", err)
+		return nil, err
+	}
+
+	var itemID int64
+	queryInsert := `
+		INSERT INTO items (design_asset_id, size, sku, price, stock_total, stock_reserved, is_active, created_at)
+		VALUES ($1, $2, $3, $4, $5, 0, true, NOW())
+		RETURNING id
+	`
+	err = tx.QueryRowContext(ctx, queryInsert, req.DesignAssetID, size, sku, req.Price, req.StockTotal).Scan(&itemID)
+	if err != nil {
+		log.Printf("❌ CreateItem: Error inserting item: %v", err)
+		return nil, fmt.Errorf("failed to insert item: %w", err)
+	}
+
+	item, err := scanItem(tx.QueryRowContext(ctx, queryItemByID, itemID))
+	if err != nil {
+		log.Printf("❌ CreateItem: Error fetching created item: %v", err)
+		return nil, fmt.Errorf("failed to fetch created item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ CreateItem: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ CreateItem: Created item id=%d, sku=%s", item.ID, item.SKU)
+	return item, nil
+}
+
+// Update edits the SKU, price and/or stock_total of an item.
+// stock_total can never go below stock_reserved (units held by open orders)
+func (r *ItemRepository) Update(ctx context.Context, itemID int64, req *models.UpdateItemRequest) (*models.Item, error) {
+	log.Printf("📦 UpdateItem: item_id=%d", itemID)
+
+	if req.SKU == nil && req.Price == nil && req.StockTotal == nil {
+		log.Printf("❌ UpdateItem: No fields to update")
+		return nil, fmt.Errorf("at least one field is required")
+	}
+	if req.SKU != nil && strings.TrimSpace(*req.SKU) == "" {
+		log.Printf("❌ UpdateItem: Empty sku")
+		return nil, fmt.Errorf("sku cannot be empty")
+	}
+	if req.Price != nil && *req.Price <= 0 {
+		log.Printf("❌ UpdateItem: Invalid price: %d", *req.Price)
+		return nil, fmt.Errorf("price must be greater than 0")
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ UpdateItem: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanItem(tx.QueryRowContext(ctx, queryItemByID+" FOR UPDATE", itemID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateItem: Item not found: id=%d", itemID)
+			return nil, fmt.Errorf("item not found")
+		}
+		log.Printf("❌ UpdateItem: Error fetching item: %v", err)
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+
+	sku := item.SKU
+	if req.SKU != nil {
+		sku = strings.TrimSpace(*req.SKU)
+		if sku != item.SKU {
+			if err := checkSKUAvailable(ctx, tx, sku, itemID); err != nil {
+				log.Printf("❌ UpdateItem: %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	price := int64(item.Price)
+	if req.Price != nil {
+		price = *req.Price
+	}
+
+	stockTotal := item.StockTotal
+	if req.StockTotal != nil {
+		stockTotal = *req.StockTotal
+		if stockTotal < item.StockReserved {
+			log.Printf("❌ UpdateItem: stock_total %d below stock_reserved %d for item %d", stockTotal, item.StockReserved, itemID)
+			return nil, fmt.Errorf("stockTotal cannot be lower than stockReserved (%d)", item.StockReserved)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE items SET sku = $1, price = $2, stock_total = $3 WHERE id = $4`, sku, price, stockTotal, itemID)
+	if err != nil {
+		log.Printf("❌ UpdateItem: Error updating item: %v", err)
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+
+	item, err = scanItem(tx.QueryRowContext(ctx, queryItemByID, itemID))
+	if err != nil {
+		log.Printf("❌ UpdateItem: Error fetching updated item: %v", err)
+		return nil, fmt.Errorf("failed to fetch updated item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ UpdateItem: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ UpdateItem: Updated item id=%d (sku=%s, price=%d, stock_total=%d)", itemID, item.SKU, item.Price, item.StockTotal)
+	return item, nil
+}
+
+// GetByID retrieves a single item by ID
+func (r *ItemRepository) GetByID(ctx context.Context, itemID int64) (*models.Item, error) {
+	item, err := scanItem(db.DB.QueryRowContext(ctx, queryItemByID, itemID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ GetItemByID: Item not found: id=%d", itemID)
+			return nil, fmt.Errorf("item not found")
+		}
+		log.Printf("❌ GetItemByID: Error fetching item: %v", err)
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+	return item, nil
+}
+
+// SetActive activates or deactivates an item. Inactive items are hidden from filters and catalogs
+// and cannot be added to orders; existing reservations are left untouched
+func (r *ItemRepository) SetActive(ctx context.Context, itemID int64, isActive bool) (*models.Item, error) {
+	log.Printf("📦 SetItemActive: item_id=%d, is_active=%t", itemID, isActive)
+
+	result, err := db.DB.ExecContext(ctx, `UPDATE items SET is_active = $1 WHERE id = $2`, isActive, itemID)
+	if err != nil {
+		log.Printf("❌ SetItemActive: Error updating item: %v", err)
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Printf("❌ SetItemActive: Item not found: id=%d", itemID)
+		return nil, fmt.Errorf("item not found")
+	}
+
+	log.Printf("✅ SetItemActive: item_id=%d is_active=%t", itemID, isActive)
+	return r.GetByID(ctx, itemID)
+}
+
+// GetLowStock returns every active item whose available stock (stock_total - stock_reserved) is at or
+// below threshold, sorted ascending by availability so the most urgent items come first
+func (r *ItemRepository) GetLowStock(ctx context.Context, threshold int) (*models.LowStockResponse, error) {
+	log.Printf("📦 GetLowStock: threshold=%d", threshold)
+
+	query := `
+		SELECT i.id, i.sku, i.size, i.stock_total, i.stock_reserved,
+		       i.stock_total - i.stock_reserved as available,
+		       i.design_asset_id,
+		       COALESCE(da.description, '') as description,
+		       COALESCE(da.color_primary, '') as color_primary,
+		       COALESCE(da.color_secondary, '') as color_secondary,
+		       COALESCE(da.hoodie_type, '') as hoodie_type
+		FROM items i
+		INNER JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.is_active = true
+		  AND i.stock_total - i.stock_reserved <= $1
+		ORDER BY available ASC, i.id ASC
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, threshold)
+	if err != nil {
+		log.Printf("❌ GetLowStock: Error querying items: %v", err)
+		return nil, fmt.Errorf("failed to get low stock items: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.LowStockResponse{
+		Threshold: threshold,
+		Items:     []models.LowStockItem{},
+	}
+	for rows.Next() {
+		var item models.LowStockItem
+		err := rows.Scan(
+			&item.ID,
+			&item.SKU,
+			&item.Size,
+			&item.StockTotal,
+			&item.StockReserved,
+			&item.Available,
+			&item.DesignAssetID,
+			&item.Description,
+			&item.ColorPrimary,
+			&item.ColorSecondary,
+			&item.HoodieType,
+		)
+		if err != nil {
+			log.Printf("❌ GetLowStock: Error scanning item: %v", err)
+			return nil, fmt.Errorf("failed to scan low stock item: %w", err)
+		}
+		response.Items = append(response.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ GetLowStock: Error iterating items: %v", err)
+		return nil, fmt.Errorf("failed to iterate low stock items: %w", err)
+	}
+
+	response.Count = len(response.Items)
+	log.Printf("✅ GetLowStock: %d items at or below threshold %d", response.Count, threshold)
+	return response, nil
+}
+
+// GetItemsByDesignAsset returns every item (SKU) generated from a design asset, active or not,
+// ordered by size run (MN, IT, XS, S, M, L, XL) so the whole run can be reviewed at a glance
+func (r *ItemRepository) GetItemsByDesignAsset(ctx context.Context, designAssetID int) (*models.DesignAssetItemsResponse, error) {
+	log.Printf("📦 GetItemsByDesignAsset: design_asset_id=%d", designAssetID)
+
+	var exists bool
+	if err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM design_assets WHERE id = $1)`, designAssetID).Scan(&exists); err != nil {
+		log.Printf("❌ GetItemsByDesignAsset: Error checking design asset: %v", err)
+		return nil, fmt.Errorf("failed to check design asset: %w", err)
+	}
+	if !exists {
+		log.Printf("❌ GetItemsByDesignAsset: Design asset not found: id=%d", designAssetID)
+		return nil, fmt.Errorf("design asset not found")
+	}
+
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id, sku, size, price, stock_total, stock_reserved, is_active
+		FROM items
+		WHERE design_asset_id = $1
+		ORDER BY CASE size
+		           WHEN 'MN' THEN 1
+		           WHEN 'IT' THEN 2
+		           WHEN 'XS' THEN 3
+		           WHEN 'S' THEN 4
+		           WHEN 'M' THEN 5
+		           WHEN 'L' THEN 6
+		           WHEN 'XL' THEN 7
+		           ELSE 8
+		         END, size, id
+	`, designAssetID)
+	if err != nil {
+		log.Printf("❌ GetItemsByDesignAsset: Error querying items: %v", err)
+		return nil, fmt.Errorf("failed to get design asset items: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.DesignAssetItemsResponse{
+		DesignAssetID: designAssetID,
+		Items:         []models.DesignAssetItem{},
+	}
+	for rows.Next() {
+		var item models.DesignAssetItem
+		err := rows.Scan(
+			&item.ID,
+			&item.SKU,
+			&item.Size,
+			&item.Price,
+			&item.StockTotal,
+			&item.StockReserved,
+			&item.IsActive,
+		)
+		if err != nil {
+			log.Printf("❌ GetItemsByDesignAsset: Error scanning item: %v", err)
+			return nil, fmt.Errorf("failed to scan design asset item: %w", err)
+		}
+		item.StockAvailable = stockAvailable(item.StockTotal, item.StockReserved)
+		response.Items = append(response.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ GetItemsByDesignAsset: Error iterating items: %v", err)
+		return nil, fmt.Errorf("failed to iterate design asset items: %w", err)
+	}
+
+	response.Count = len(response.Items)
+	log.Printf("✅ GetItemsByDesignAsset: %d items for design_asset_id=%d", response.Count, designAssetID)
+	return response, nil
+}
+
+// SearchItems finds active items whose SKU or design asset description contains query (ILIKE), or whose
+// readable color / hoodie type label does (matched to codes with utils). Exact SKU matches come first,
+// then SKU prefixes, then the rest by available stock
+func (r *ItemRepository) SearchItems(ctx context.Context, query string, limit int) (*models.ItemSearchResponse, error) {
+	log.Printf("📦 SearchItems: q=%q, limit=%d", query, limit)
+
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT i.id, i.sku, i.size, i.price, i.stock_total, i.stock_reserved, i.design_asset_id,
+		       COALESCE(da.description, '') as description,
+		       COALESCE(da.color_primary, '') as color_primary,
+		       COALESCE(da.color_secondary, '') as color_secondary,
+		       COALESCE(da.hoodie_type, '') as hoodie_type
+		FROM items i
+		INNER JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.is_active = true
+		  AND (i.sku ILIKE $2
+		       OR da.description ILIKE $2
+		       OR da.color_primary = ANY($3)
+		       OR da.color_secondary = ANY($3)
+		       OR da.hoodie_type = ANY($4))
+		ORDER BY CASE
+		           WHEN LOWER(i.sku) = LOWER($1) THEN 0
+		           WHEN i.sku ILIKE $1 || '%' THEN 1
+		           ELSE 2
+		         END,
+		         i.stock_total - i.stock_reserved DESC, i.id
+		LIMIT $5
+	`, query, "%"+query+"%", utils.ColorCodesMatching(query), utils.HoodieTypeCodesMatching(query), limit)
+	if err != nil {
+		log.Printf("❌ SearchItems: Error querying items: %v", err)
+		return nil, fmt.Errorf("failed to search items: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.ItemSearchResponse{
+		Query: query,
+		Items: []models.ItemSearchResult{},
+	}
+	for rows.Next() {
+		var item models.ItemSearchResult
+		err := rows.Scan(
+			&item.ID,
+			&item.SKU,
+			&item.Size,
+			&item.Price,
+			&item.StockTotal,
+			&item.StockReserved,
+			&item.DesignAssetID,
+			&item.Description,
+			&item.ColorPrimary,
+			&item.ColorSecondary,
+			&item.HoodieType,
+		)
+		if err != nil {
+			log.Printf("❌ SearchItems: Error scanning item: %v", err)
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		item.StockAvailable = stockAvailable(item.StockTotal, item.StockReserved)
+		response.Items = append(response.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ SearchItems: Error iterating items: %v", err)
+		return nil, fmt.Errorf("failed to iterate items: %w", err)
+	}
+
+	response.Count = len(response.Items)
+	log.Printf("✅ SearchItems: %d items for q=%q", response.Count, query)
+	return response, nil
+}
+
+// AdjustStock applies a manual correction (delta) to an item's stock_total and records it in
+// inventory_adjustments within the same transaction. stock_total can never drop below stock_reserved
+func (r *ItemRepository) AdjustStock(ctx context.Context, itemID int64, req *models.AdjustItemStockRequest) (*models.AdjustItemStockResponse, error) {
+	log.Printf("📦 AdjustStock: item_id=%d, delta=%d", itemID, req.Delta)
+
+	reason := strings.TrimSpace(req.Reason)
+	user := strings.TrimSpace(req.User)
+	if req.Delta == 0 {
+		return nil, fmt.Errorf("delta cannot be 0")
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+	if user == "" {
+		return nil, fmt.Errorf("user is required")
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ AdjustStock: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanItem(tx.QueryRowContext(ctx, queryItemByID+" FOR UPDATE", itemID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ AdjustStock: Item not found: id=%d", itemID)
+			return nil, fmt.Errorf("item not found")
+		}
+		log.Printf("❌ AdjustStock: Error fetching item: %v", err)
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+
+	stockTotalAfter := item.StockTotal + req.Delta
+	if stockTotalAfter < item.StockReserved {
+		log.Printf("❌ AdjustStock: stock_total %d below stock_reserved %d for item %d", stockTotalAfter, item.StockReserved, itemID)
+		return nil, fmt.Errorf("stockTotal cannot be lower than stockReserved (%d)", item.StockReserved)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE items SET stock_total = $1 WHERE id = $2`, stockTotalAfter, itemID); err != nil {
+		log.Printf("❌ AdjustStock: Error updating item: %v", err)
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+
+	adjustment := models.InventoryAdjustment{
+		ItemID:           itemID,
+		Delta:            req.Delta,
+		StockTotalBefore: item.StockTotal,
+		StockTotalAfter:  stockTotalAfter,
+		Reason:           reason,
+		User:             user,
+	}
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO inventory_adjustments (item_id, delta, stock_total_before, stock_total_after, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, itemID, req.Delta, item.StockTotal, stockTotalAfter, reason, user).Scan(&adjustment.ID, &createdAt)
+	if err != nil {
+		log.Printf("❌ AdjustStock: Error inserting adjustment: %v", err)
+		return nil, fmt.Errorf("failed to record adjustment: %w", err)
+	}
+	adjustment.CreatedAt = createdAt.Format(time.RFC3339)
+
+	item, err = scanItem(tx.QueryRowContext(ctx, queryItemByID, itemID))
+	if err != nil {
+		log.Printf("❌ AdjustStock: Error fetching updated item: %v", err)
+		return nil, fmt.Errorf("failed to fetch updated item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ AdjustStock: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ AdjustStock: item_id=%d stock_total %d -> %d (adjustment id=%d, user=%s)", itemID, adjustment.StockTotalBefore, adjustment.StockTotalAfter, adjustment.ID, user)
+	return &models.AdjustItemStockResponse{Item: item, Adjustment: adjustment}, nil
+}
+
+// GetAdjustments returns the stock adjustment history of an item, newest first
+func (r *ItemRepository) GetAdjustments(ctx context.Context, itemID int64) (*models.ItemAdjustmentsResponse, error) {
+	log.Printf("📦 GetAdjustments: item_id=%d", itemID)
+
+	var exists bool
+	if err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM items WHERE id = $1)`, itemID).Scan(&exists); err != nil {
+		log.Printf("❌ GetAdjustments: Error checking item: %v", err)
+		return nil, fmt.Errorf("failed to check item: %w", err)
+	}
+	if !exists {
+		log.Printf("❌ GetAdjustments: Item not found: id=%d", itemID)
+		return nil, fmt.Errorf("item not found")
+	}
+
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id, item_id, delta, stock_total_before, stock_total_after, reason, created_by, created_at
+		FROM inventory_adjustments
+		WHERE item_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, itemID)
+	if err != nil {
+		log.Printf("❌ GetAdjustments: Error querying adjustments: %v", err)
+		return nil, fmt.Errorf("failed to get adjustments: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.ItemAdjustmentsResponse{
+		ItemID:      itemID,
+		Adjustments: []models.InventoryAdjustment{},
+	}
+	for rows.Next() {
+		var adjustment models.InventoryAdjustment
+		var createdAt time.Time
+		if err := rows.Scan(
+			&adjustment.ID,
+			&adjustment.ItemID,
+			&adjustment.Delta,
+			&adjustment.StockTotalBefore,
+			&adjustment.StockTotalAfter,
+			&adjustment.Reason,
+			&adjustment.User,
+			&createdAt,
+		); err != nil {
+			log.Printf("❌ GetAdjustments: Error scanning adjustment: %v", err)
+			return nil, fmt.Errorf("failed to scan adjustment: %w", err)
+		}
+		adjustment.CreatedAt = createdAt.Format(time.RFC3339)
+		response.Adjustments = append(response.Adjustments, adjustment)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ GetAdjustments: Error iterating adjustments: %v", err)
+		return nil, fmt.Errorf("failed to iterate adjustments: %w", err)
+	}
+
+	log.Printf("✅ GetAdjustments: item_id=%d, %d adjustments", itemID, len(response.Adjustments))
+	return response, nil
+}
+
+// maxAvailabilityLines caps the number of lines accepted by a single CheckAvailability call
+const maxAvailabilityLines = 200
+
+// CheckAvailability reports, per item, whether the requested quantity is currently available.
+// It is a read-only dry run: no row locks are taken and no stock is reserved, so the result can
+// change before the items are actually added to an order
+func (r *ItemRepository) CheckAvailability(ctx context.Context, lines []models.ItemAvailabilityLine) (*models.ItemAvailabilityResponse, error) {
+	log.Printf("📦 CheckAvailability: %d lines", len(lines))
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("items must not be empty")
+	}
+	if len(lines) > maxAvailabilityLines {
+		return nil, fmt.Errorf("too many items: max %d per request", maxAvailabilityLines)
+	}
+
+	// Sum quantities per item, keeping the order in which items were first requested
+	requested := make(map[int64]int)
+	var itemIDs []int64
+	for i, line := range lines {
+		if line.ItemID <= 0 {
+			return nil, fmt.Errorf("items[%d]: itemId must be greater than 0", i)
+		}
+		if line.Qty <= 0 {
+			return nil, fmt.Errorf("items[%d]: qty must be greater than 0", i)
+		}
+		if _, ok := requested[line.ItemID]; !ok {
+			itemIDs = append(itemIDs, line.ItemID)
+		}
+		requested[line.ItemID] += line.Qty
+	}
+
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id, stock_total, stock_reserved, is_active
+		FROM items
+		WHERE id = ANY($1)
+	`, itemIDs)
+	if err != nil {
+		log.Printf("❌ CheckAvailability: Error querying items: %v", err)
+		return nil, fmt.Errorf("failed to check availability: %w", err)
+	}
+	defer rows.Close()
+
+	type stockRow struct {
+		stockTotal    int
+		stockReserved int
+		isActive      bool
+	}
+	stock := make(map[int64]stockRow)
+	for rows.Next() {
+		var id int64
+		var row stockRow
+		if err := rows.Scan(&id, &row.stockTotal, &row.stockReserved, &row.isActive); err != nil {
+			log.Printf("❌ CheckAvailability: Error scanning item: %v", err)
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		stock[id] = row
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ CheckAvailability: Error iterating items: %v", err)
+		return nil, fmt.Errorf("failed to iterate items: %w", err)
+	}
+
+	response := &models.ItemAvailabilityResponse{
+		AllAvailable: true,
+		Items:        make([]models.ItemAvailability, 0, len(itemIDs)),
+	}
+	for _, itemID := range itemIDs {
+		availability := models.ItemAvailability{
+			ItemID:       itemID,
+			RequestedQty: requested[itemID],
+		}
+		if row, ok := stock[itemID]; ok {
+			availability.Found = true
+			availability.IsActive = row.isActive
+			if row.isActive {
+				availability.Available = stockAvailable(row.stockTotal, row.stockReserved)
+			}
+		}
+		availability.IsAvailable = availability.IsActive && availability.Available >= availability.RequestedQty
+		if !availability.IsAvailable {
+			response.AllAvailable = false
+		}
+		response.Items = append(response.Items, availability)
+	}
+
+	log.Printf("✅ CheckAvailability: %d items checked, allAvailable=%t", len(response.Items), response.AllAvailable)
+	return response, nil
+}
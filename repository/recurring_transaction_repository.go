@@ -0,0 +1,459 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"armario-mascota-me/db"
+	"armario-mascota-me/models"
+	"armario-mascota-me/utils"
+)
+
+// RecurringTransactionRepository handles database operations for recurring finance transactions
+type RecurringTransactionRepository struct{}
+
+// NewRecurringTransactionRepository creates a new RecurringTransactionRepository
+func NewRecurringTransactionRepository() *RecurringTransactionRepository {
+	return &RecurringTransactionRepository{}
+}
+
+// Ensure RecurringTransactionRepository implements RecurringTransactionRepositoryInterface
+var _ RecurringTransactionRepositoryInterface = (*RecurringTransactionRepository)(nil)
+
+const recurringTransactionColumns = `id, label, type, amount, destination, category, counterparty, notes, frequency,
+	day_of_month, day_of_week, start_date, is_active, last_generated_date, created_at, updated_at`
+
+// recurringDateLayout is the format of start_date and last_generated_date in requests and responses
+const recurringDateLayout = "2006-01-02"
+
+// scanRecurringTransaction scans a row selected with recurringTransactionColumns
+func scanRecurringTransaction(scanner interface{ Scan(dest ...any) error }) (*models.RecurringTransaction, error) {
+	var rec models.RecurringTransaction
+	var category, counterparty, notes sql.NullString
+	var dayOfMonth, dayOfWeek sql.NullInt64
+	var startDate time.Time
+	var lastGeneratedDate sql.NullTime
+	err := scanner.Scan(
+		&rec.ID,
+		&rec.Label,
+		&rec.Type,
+		&rec.Amount,
+		&rec.Destination,
+		&category,
+		&counterparty,
+		&notes,
+		&rec.Frequency,
+		&dayOfMonth,
+		&dayOfWeek,
+		&startDate,
+		&rec.IsActive,
+		&lastGeneratedDate,
+		&rec.CreatedAt,
+		&rec.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rec.Category = category.String
+	rec.Counterparty = counterparty.String
+	rec.Notes = notes.String
+	if dayOfMonth.Valid {
+		day := int(dayOfMonth.Int64)
+		rec.DayOfMonth = &day
+	}
+	if dayOfWeek.Valid {
+		day := int(dayOfWeek.Int64)
+		rec.DayOfWeek = &day
+	}
+	rec.StartDate = startDate.Format(recurringDateLayout)
+	if lastGeneratedDate.Valid {
+		last := lastGeneratedDate.Time.Format(recurringDateLayout)
+		rec.LastGeneratedDate = &last
+	}
+	return &rec, nil
+}
+
+// validateRecurringTransaction validates and trims a recurring transaction request in place.
+// It returns the parsed start date (today when empty).
+func validateRecurringTransaction(req *models.RecurringTransactionRequest) (time.Time, error) {
+	req.Label = strings.TrimSpace(req.Label)
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	req.Destination = strings.TrimSpace(req.Destination)
+	req.Frequency = strings.ToLower(strings.TrimSpace(req.Frequency))
+
+	if req.Label == "" {
+		return time.Time{}, fmt.Errorf("label is required")
+	}
+	if req.Type != "income" && req.Type != "expense" {
+		return time.Time{}, fmt.Errorf("type must be 'income' or 'expense'")
+	}
+	if req.Amount <= 0 {
+		return time.Time{}, fmt.Errorf("amount must be greater than 0")
+	}
+	if req.Destination == "" {
+		return time.Time{}, fmt.Errorf("destination is required")
+	}
+
+	switch req.Frequency {
+	case "monthly":
+		if req.DayOfMonth == nil || *req.DayOfMonth < 1 || *req.DayOfMonth > 31 {
+			return time.Time{}, fmt.Errorf("dayOfMonth must be between 1 and 31 for monthly recurrences")
+		}
+		req.DayOfWeek = nil
+	case "weekly":
+		if req.DayOfWeek == nil || *req.DayOfWeek < 0 || *req.DayOfWeek > 6 {
+			return time.Time{}, fmt.Errorf("dayOfWeek must be between 0 (Sunday) and 6 (Saturday) for weekly recurrences")
+		}
+		req.DayOfMonth = nil
+	default:
+		return time.Time{}, fmt.Errorf("frequency must be 'monthly' or 'weekly'")
+	}
+
+	startDate := recurringDate(time.Now().In(utils.AppLocation()))
+	if strings.TrimSpace(req.StartDate) != "" {
+		parsed, err := time.Parse(recurringDateLayout, strings.TrimSpace(req.StartDate))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid startDate format, use YYYY-MM-DD")
+		}
+		startDate = parsed
+	}
+	return startDate, nil
+}
+
+// nullIfNilInt converts a nil int pointer to SQL NULL
+func nullIfNilInt(value *int) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*value), Valid: true}
+}
+
+// recurringDate truncates a time to its calendar day (in its own location) as a UTC date
+func recurringDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// recurrenceDueDates returns the dates in [from, to] (inclusive, calendar days) on which a
+// recurrence is due. Monthly recurrences falling on a day a month does not have (e.g. 31)
+// are due on the last day of that month.
+func recurrenceDueDates(rec *models.RecurringTransaction, from, to time.Time) []time.Time {
+	var dates []time.Time
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		switch rec.Frequency {
+		case "monthly":
+			if rec.DayOfMonth == nil {
+				continue
+			}
+			lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+			dueDay := *rec.DayOfMonth
+			if dueDay > lastDay {
+				dueDay = lastDay
+			}
+			if day.Day() == dueDay {
+				dates = append(dates, day)
+			}
+		case "weekly":
+			if rec.DayOfWeek != nil && int(day.Weekday()) == *rec.DayOfWeek {
+				dates = append(dates, day)
+			}
+		}
+	}
+	return dates
+}
+
+// List retrieves all recurring transactions ordered by label
+func (r *RecurringTransactionRepository) List(ctx context.Context) ([]models.RecurringTransaction, error) {
+	log.Printf("📦 ListRecurringTransactions: Fetching recurring transactions")
+
+	rows, err := db.DB.QueryContext(ctx, `SELECT `+recurringTransactionColumns+` FROM recurring_transactions ORDER BY label ASC, id ASC`)
+	if err != nil {
+		log.Printf("❌ ListRecurringTransactions: Error fetching recurring transactions: %v", err)
+		return nil, fmt.Errorf("failed to fetch recurring transactions: %w", err)
+	}
+	defer rows.Close()
+
+	recurring := []models.RecurringTransaction{}
+	for rows.Next() {
+		rec, err := scanRecurringTransaction(rows)
+		if err != nil {
+			log.Printf("❌ ListRecurringTransactions: Error scanning recurring transaction: %v", err)
+			return nil, fmt.Errorf("failed to scan recurring transaction: %w", err)
+		}
+		recurring = append(recurring, *rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ ListRecurringTransactions: Error iterating recurring transactions: %v", err)
+		return nil, fmt.Errorf("failed to iterate recurring transactions: %w", err)
+	}
+
+	log.Printf("✅ ListRecurringTransactions: Successfully fetched %d recurring transactions", len(recurring))
+	return recurring, nil
+}
+
+// GetByID retrieves a recurring transaction by ID
+func (r *RecurringTransactionRepository) GetByID(ctx context.Context, id int64) (*models.RecurringTransaction, error) {
+	log.Printf("📦 GetRecurringTransaction: Fetching recurring transaction id=%d", id)
+
+	query := `SELECT ` + recurringTransactionColumns + ` FROM recurring_transactions WHERE id = $1`
+	rec, err := scanRecurringTransaction(db.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ GetRecurringTransaction: Recurring transaction not found: id=%d", id)
+			return nil, fmt.Errorf("recurring transaction not found")
+		}
+		log.Printf("❌ GetRecurringTransaction: Error fetching recurring transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch recurring transaction: %w", err)
+	}
+	return rec, nil
+}
+
+// Create inserts a new recurring transaction
+func (r *RecurringTransactionRepository) Create(ctx context.Context, req *models.RecurringTransactionRequest) (*models.RecurringTransaction, error) {
+	log.Printf("📦 CreateRecurringTransaction: label=%q, frequency=%s", req.Label, req.Frequency)
+
+	startDate, err := validateRecurringTransaction(req)
+	if err != nil {
+		log.Printf("❌ CreateRecurringTransaction: %v", err)
+		return nil, err
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	query := `
+		INSERT INTO recurring_transactions (label, type, amount, destination, category, counterparty, notes,
+		                                    frequency, day_of_month, day_of_week, start_date, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + recurringTransactionColumns
+	rec, err := scanRecurringTransaction(db.DB.QueryRowContext(ctx, query,
+		req.Label,
+		req.Type,
+		req.Amount,
+		req.Destination,
+		nullIfEmpty(req.Category),
+		nullIfEmpty(req.Counterparty),
+		nullIfEmpty(req.Notes),
+		req.Frequency,
+		nullIfNilInt(req.DayOfMonth),
+		nullIfNilInt(req.DayOfWeek),
+		startDate,
+		isActive,
+	))
+	if err != nil {
+		log.Printf("❌ CreateRecurringTransaction: Error inserting recurring transaction: %v", err)
+		return nil, fmt.Errorf("failed to insert recurring transaction: %w", err)
+	}
+
+	log.Printf("✅ CreateRecurringTransaction: Created recurring transaction id=%d", rec.ID)
+	return rec, nil
+}
+
+// Update replaces all fields of a recurring transaction. last_generated_date is kept, so
+// changing the schedule never regenerates dates that were already generated.
+func (r *RecurringTransactionRepository) Update(ctx context.Context, id int64, req *models.RecurringTransactionRequest) (*models.RecurringTransaction, error) {
+	log.Printf("📦 UpdateRecurringTransaction: id=%d, label=%q", id, req.Label)
+
+	startDate, err := validateRecurringTransaction(req)
+	if err != nil {
+		log.Printf("❌ UpdateRecurringTransaction: %v", err)
+		return nil, err
+	}
+
+	query := `
+		UPDATE recurring_transactions
+		SET label = $2, type = $3, amount = $4, destination = $5, category = $6, counterparty = $7, notes = $8,
+		    frequency = $9, day_of_month = $10, day_of_week = $11, start_date = $12,
+		    is_active = COALESCE($13, is_active), updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + recurringTransactionColumns
+	var isActive sql.NullBool
+	if req.IsActive != nil {
+		isActive = sql.NullBool{Bool: *req.IsActive, Valid: true}
+	}
+	rec, err := scanRecurringTransaction(db.DB.QueryRowContext(ctx, query,
+		id,
+		req.Label,
+		req.Type,
+		req.Amount,
+		req.Destination,
+		nullIfEmpty(req.Category),
+		nullIfEmpty(req.Counterparty),
+		nullIfEmpty(req.Notes),
+		req.Frequency,
+		nullIfNilInt(req.DayOfMonth),
+		nullIfNilInt(req.DayOfWeek),
+		startDate,
+		isActive,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ UpdateRecurringTransaction: Recurring transaction not found: id=%d", id)
+			return nil, fmt.Errorf("recurring transaction not found")
+		}
+		log.Printf("❌ UpdateRecurringTransaction: Error updating recurring transaction: %v", err)
+		return nil, fmt.Errorf("failed to update recurring transaction: %w", err)
+	}
+
+	log.Printf("✅ UpdateRecurringTransaction: Updated recurring transaction id=%d", id)
+	return rec, nil
+}
+
+// Delete removes a recurring transaction. Transactions already generated from it are not affected.
+func (r *RecurringTransactionRepository) Delete(ctx context.Context, id int64) error {
+	log.Printf("📦 DeleteRecurringTransaction: id=%d", id)
+
+	result, err := db.DB.ExecContext(ctx, `DELETE FROM recurring_transactions WHERE id = $1`, id)
+	if err != nil {
+		log.Printf("❌ DeleteRecurringTransaction: Error deleting recurring transaction: %v", err)
+		return fmt.Errorf("failed to delete recurring transaction: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Printf("❌ DeleteRecurringTransaction: Recurring transaction not found: id=%d", id)
+		return fmt.Errorf("recurring transaction not found")
+	}
+
+	log.Printf("✅ DeleteRecurringTransaction: Deleted recurring transaction id=%d", id)
+	return nil
+}
+
+// GenerateDue materializes every due date of the active recurrences up to today (inclusive) into
+// finance_transactions and returns how many transactions were created. Each recurrence is locked
+// and processed in its own transaction; last_generated_date is advanced in that same transaction,
+// so running it again (or from several instances) never duplicates a date.
+func (r *RecurringTransactionRepository) GenerateDue(ctx context.Context, today time.Time) (int, error) {
+	todayDate := recurringDate(today)
+	log.Printf("📦 GenerateDueRecurringTransactions: Generating due recurrences up to %s", todayDate.Format(recurringDateLayout))
+
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id FROM recurring_transactions
+		WHERE is_active = TRUE
+		  AND start_date <= $1
+		  AND (last_generated_date IS NULL OR last_generated_date < $1)
+		ORDER BY id ASC
+	`, todayDate)
+	if err != nil {
+		log.Printf("❌ GenerateDueRecurringTransactions: Error fetching recurrences: %v", err)
+		return 0, fmt.Errorf("failed to fetch recurring transactions: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			log.Printf("❌ GenerateDueRecurringTransactions: Error scanning recurrence id: %v", err)
+			return 0, fmt.Errorf("failed to scan recurring transaction: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		log.Printf("❌ GenerateDueRecurringTransactions: Error iterating recurrences: %v", err)
+		return 0, fmt.Errorf("failed to iterate recurring transactions: %w", err)
+	}
+	rows.Close()
+
+	created := 0
+	for _, id := range ids {
+		count, err := r.generateDueForRecurrence(ctx, id, todayDate)
+		if err != nil {
+			// Keep going: one broken recurrence should not block the others
+			log.Printf("❌ GenerateDueRecurringTransactions: Recurrence id=%d failed: %v", id, err)
+			continue
+		}
+		created += count
+	}
+
+	log.Printf("✅ GenerateDueRecurringTransactions: Created %d transactions from %d recurrences", created, len(ids))
+	return created, nil
+}
+
+// generateDueForRecurrence generates the due dates of one recurrence up to todayDate
+func (r *RecurringTransactionRepository) generateDueForRecurrence(ctx context.Context, id int64, todayDate time.Time) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + recurringTransactionColumns + ` FROM recurring_transactions WHERE id = $1 FOR UPDATE`
+	rec, err := scanRecurringTransaction(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Deleted since it was listed
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to fetch recurring transaction: %w", err)
+	}
+	if !rec.IsActive {
+		return 0, nil
+	}
+
+	// Start after the last generated date (re-read under lock), or at start_date
+	from, err := time.Parse(recurringDateLayout, rec.StartDate)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start date: %w", err)
+	}
+	if rec.LastGeneratedDate != nil {
+		last, err := time.Parse(recurringDateLayout, *rec.LastGeneratedDate)
+		if err != nil {
+			return 0, fmt.Errorf("invalid last generated date: %w", err)
+		}
+		if next := last.AddDate(0, 0, 1); next.After(from) {
+			from = next
+		}
+	}
+	if from.After(todayDate) {
+		return 0, nil
+	}
+
+	notes := fmt.Sprintf("Recurrente #%d (%s)", rec.ID, rec.Label)
+	if rec.Notes != "" {
+		notes += ": " + rec.Notes
+	}
+
+	queryInsert := `
+		INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes)
+		VALUES ($1, 'manual', NULL, $2, $3, $4, $5, $6, $7)
+	`
+	dueDates := recurrenceDueDates(rec, from, todayDate)
+	for _, dueDate := range dueDates {
+		// Midday store time keeps the transaction on its due date in every report
+		occurredAt := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 12, 0, 0, 0, utils.AppLocation())
+		_, err = tx.ExecContext(ctx, queryInsert,
+			rec.Type,
+			occurredAt,
+			rec.Amount,
+			rec.Destination,
+			nullIfEmpty(rec.Category),
+			nullIfEmpty(rec.Counterparty),
+			notes,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert finance transaction for %s: %w", dueDate.Format(recurringDateLayout), err)
+		}
+		log.Printf("💰 GenerateDueRecurringTransactions: Recurrence id=%d generated %s %d on %s",
+			rec.ID, rec.Type, rec.Amount, dueDate.Format(recurringDateLayout))
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE recurring_transactions SET last_generated_date = $1 WHERE id = $2`, todayDate, rec.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update last generated date: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(dueDates), nil
+}
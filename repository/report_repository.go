@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"armario-mascota-me/db"
+	"armario-mascota-me/models"
+	"armario-mascota-me/pricing"
+	"armario-mascota-me/utils"
+)
+
+// ReportRepository handles read-only reporting queries
+type ReportRepository struct{}
+
+// NewReportRepository creates a new ReportRepository
+func NewReportRepository() *ReportRepository {
+	return &ReportRepository{}
+}
+
+// Ensure ReportRepository implements ReportRepositoryInterface
+var _ ReportRepositoryInterface = (*ReportRepository)(nil)
+
+// GroupsCoverage reports, for every distinct hoodie_type present in active items, the pricing group it
+// resolves to (or that it is ungrouped) and whether the pricebook has entries for its size buckets
+func (r *ReportRepository) GroupsCoverage(ctx context.Context) (*models.GroupsCoverageResponse, error) {
+	log.Printf("📦 GroupsCoverage: Building pricing groups coverage report")
+
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		return nil, fmt.Errorf("pricing engine not available")
+	}
+
+	query := `
+		SELECT COALESCE(da.hoodie_type, '') as hoodie_type, i.size, COUNT(*) as item_count
+		FROM items i
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.is_active = true
+		GROUP BY COALESCE(da.hoodie_type, ''), i.size
+		ORDER BY hoodie_type ASC, i.size ASC
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("❌ GroupsCoverage: Error querying items: %v", err)
+		return nil, fmt.Errorf("failed to query items: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.GroupsCoverageResponse{
+		Entries: []models.GroupCoverageEntry{},
+	}
+	entryIndex := make(map[string]int) // hoodie_type -> index in response.Entries
+
+	for rows.Next() {
+		var hoodieType, size string
+		var itemCount int
+		if err := rows.Scan(&hoodieType, &size, &itemCount); err != nil {
+			log.Printf("❌ GroupsCoverage: Error scanning row: %v", err)
+			return nil, fmt.Errorf("failed to scan coverage row: %w", err)
+		}
+
+		group, sizeBucket, hasEntry := pricingEngine.GroupCoverage(hoodieType, size)
+
+		idx, exists := entryIndex[hoodieType]
+		if !exists {
+			response.Entries = append(response.Entries, models.GroupCoverageEntry{
+				HoodieType:      hoodieType,
+				HoodieTypeLabel: utils.MapCodeToHoodieType(hoodieType),
+				Group:           group,
+				Ungrouped:       group == "",
+				Sizes:           []models.GroupCoverageSize{},
+			})
+			idx = len(response.Entries) - 1
+			entryIndex[hoodieType] = idx
+			if group == "" {
+				response.UngroupedTypes++
+			}
+		}
+
+		entry := &response.Entries[idx]
+		entry.ItemCount += itemCount
+		entry.Sizes = append(entry.Sizes, models.GroupCoverageSize{
+			Size:              size,
+			SizeBucket:        sizeBucket,
+			HasPricebookEntry: hasEntry,
+			ItemCount:         itemCount,
+		})
+		if !hasEntry {
+			response.SizesWithoutPrices++
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ GroupsCoverage: Error iterating rows: %v", err)
+		return nil, fmt.Errorf("failed to iterate coverage rows: %w", err)
+	}
+
+	log.Printf("✅ GroupsCoverage: %d hoodie types, %d ungrouped, %d sizes without prices",
+		len(response.Entries), response.UngroupedTypes, response.SizesWithoutPrices)
+	return response, nil
+}
+
+// TimeToSell computes, per design, the average elapsed days between item creation (and the order line
+// creation, i.e. first reservation) and the sale, weighted by units sold. Only real sales are counted
+// (gifts and voided sales are excluded); designs with no sales in the range are not returned.
+func (r *ReportRepository) TimeToSell(ctx context.Context, from, to *string) (*models.TimeToSellResponse, error) {
+	log.Printf("📦 TimeToSell: Building time-to-sell report (from=%v, to=%v)", from, to)
+
+	query := `
+		SELECT da.id, da.code,
+		       COALESCE(da.description, '') as description,
+		       COALESCE(da.color_primary, '') as color_primary,
+		       COALESCE(da.color_secondary, '') as color_secondary,
+		       COALESCE(da.hoodie_type, '') as hoodie_type,
+		       COUNT(DISTINCT s.id) as sales_count,
+		       SUM(rol.qty) as units_sold,
+		       SUM(rol.qty * EXTRACT(EPOCH FROM (s.sold_at - i.created_at))) / SUM(rol.qty) / 86400.0 as avg_days_from_item,
+		       SUM(rol.qty * EXTRACT(EPOCH FROM (s.sold_at - rol.created_at))) / SUM(rol.qty) / 86400.0 as avg_days_from_reservation
+		FROM sales s
+		INNER JOIN reserved_order_lines rol ON rol.reserved_order_id = s.reserved_order_id
+		INNER JOIN items i ON rol.item_id = i.id
+		INNER JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE s.sale_type = 'sale' AND s.status <> 'voided' AND rol.qty > 0
+	`
+	var args []interface{}
+	response := &models.TimeToSellResponse{Designs: []models.TimeToSellEntry{}}
+
+	if from != nil && *from != "" {
+		fromDate, err := time.Parse("2006-01-02", *from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date format: %w", err)
+		}
+		args = append(args, fromDate)
+		query += fmt.Sprintf(" AND s.sold_at >= $%d", len(args))
+		response.From = *from
+	}
+	if to != nil && *to != "" {
+		toDate, err := time.Parse("2006-01-02", *to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date format: %w", err)
+		}
+		// Set to end of day
+		toDate = time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+		args = append(args, toDate)
+		query += fmt.Sprintf(" AND s.sold_at <= $%d", len(args))
+		response.To = *to
+	}
+
+	query += `
+		GROUP BY da.id, da.code, da.description, da.color_primary, da.color_secondary, da.hoodie_type
+		ORDER BY avg_days_from_item ASC, da.id ASC
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("❌ TimeToSell: Error querying sales: %v", err)
+		return nil, fmt.Errorf("failed to query time to sell: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.TimeToSellEntry
+		var colorPrimary, colorSecondary, hoodieType string
+		if err := rows.Scan(
+			&entry.DesignAssetID,
+			&entry.Code,
+			&entry.Description,
+			&colorPrimary,
+			&colorSecondary,
+			&hoodieType,
+			&entry.SalesCount,
+			&entry.UnitsSold,
+			&entry.AvgDaysFromItemCreation,
+			&entry.AvgDaysFromFirstReservation,
+		); err != nil {
+			log.Printf("❌ TimeToSell: Error scanning row: %v", err)
+			return nil, fmt.Errorf("failed to scan time to sell row: %w", err)
+		}
+
+		entry.ColorPrimaryLabel = utils.MapCodeToColor(colorPrimary)
+		entry.ColorSecondaryLabel = utils.MapCodeToColor(colorSecondary)
+		entry.HoodieTypeLabel = utils.MapCodeToHoodieType(hoodieType)
+		// Round to one decimal for readability
+		entry.AvgDaysFromItemCreation = math.Round(entry.AvgDaysFromItemCreation*10) / 10
+		entry.AvgDaysFromFirstReservation = math.Round(entry.AvgDaysFromFirstReservation*10) / 10
+
+		response.Designs = append(response.Designs, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ TimeToSell: Error iterating rows: %v", err)
+		return nil, fmt.Errorf("failed to iterate time to sell rows: %w", err)
+	}
+
+	log.Printf("✅ TimeToSell: %d designs with sales", len(response.Designs))
+	return response, nil
+}
+
+// InventoryDashboard splits the stock of active items into units reserved by open carts and units
+// available, overall, by size and by pricing group (hoodie types grouped as the pricing engine does)
+func (r *ReportRepository) InventoryDashboard(ctx context.Context) (*models.InventoryDashboardResponse, error) {
+	log.Printf("📦 InventoryDashboard: Building inventory dashboard")
+
+	query := `
+		SELECT COALESCE(da.hoodie_type, '') as hoodie_type, i.size,
+		       COUNT(*) as item_count,
+		       SUM(i.stock_total) as on_hand,
+		       SUM(i.stock_reserved) as reserved,
+		       SUM(GREATEST(i.stock_total - i.stock_reserved, 0)) as available,
+		       COUNT(*) FILTER (WHERE i.stock_reserved > 0 AND i.stock_total <= i.stock_reserved) as fully_reserved
+		FROM items i
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE i.is_active = true
+		GROUP BY COALESCE(da.hoodie_type, ''), i.size
+		ORDER BY CASE i.size
+		           WHEN 'MN' THEN 1
+		           WHEN 'IT' THEN 2
+		           WHEN 'XS' THEN 3
+		           WHEN 'S' THEN 4
+		           WHEN 'M' THEN 5
+		           WHEN 'L' THEN 6
+		           WHEN 'XL' THEN 7
+		           ELSE 8
+		         END, i.size, hoodie_type
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("❌ InventoryDashboard: Error querying items: %v", err)
+		return nil, fmt.Errorf("failed to query items: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.InventoryDashboardResponse{
+		BySize:  []models.InventorySizeSplit{},
+		ByGroup: []models.InventoryGroupSplit{},
+	}
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		response.Warnings = append(response.Warnings, "pricing engine not initialized: every hoodie type is reported as ungrouped")
+	}
+	sizeIndex := make(map[string]int)   // size -> index in response.BySize
+	groupIndex := make(map[string]int)  // group -> index in response.ByGroup
+	groupTypes := make(map[string]bool) // group + hoodie_type already listed in HoodieTypes
+
+	for rows.Next() {
+		var hoodieType, size string
+		var split models.InventoryStockSplit
+		if err := rows.Scan(&hoodieType, &size, &split.ItemCount, &split.OnHand, &split.Reserved, &split.Available, &split.FullyReservedItems); err != nil {
+			log.Printf("❌ InventoryDashboard: Error scanning row: %v", err)
+			return nil, fmt.Errorf("failed to scan inventory row: %w", err)
+		}
+
+		group := ""
+		if pricingEngine != nil {
+			group, _, _ = pricingEngine.GroupCoverage(hoodieType, size)
+		}
+
+		addInventorySplit(&response.InventoryStockSplit, split)
+
+		idx, exists := sizeIndex[size]
+		if !exists {
+			response.BySize = append(response.BySize, models.InventorySizeSplit{Size: size})
+			idx = len(response.BySize) - 1
+			sizeIndex[size] = idx
+		}
+		addInventorySplit(&response.BySize[idx].InventoryStockSplit, split)
+
+		idx, exists = groupIndex[group]
+		if !exists {
+			response.ByGroup = append(response.ByGroup, models.InventoryGroupSplit{
+				Group:       group,
+				Ungrouped:   group == "",
+				HoodieTypes: []string{},
+			})
+			idx = len(response.ByGroup) - 1
+			groupIndex[group] = idx
+		}
+		groupSplit := &response.ByGroup[idx]
+		addInventorySplit(&groupSplit.InventoryStockSplit, split)
+		if key := group + "|" + hoodieType; !groupTypes[key] {
+			groupTypes[key] = true
+			groupSplit.HoodieTypes = append(groupSplit.HoodieTypes, hoodieType)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ InventoryDashboard: Error iterating rows: %v", err)
+		return nil, fmt.Errorf("failed to iterate inventory rows: %w", err)
+	}
+
+	response.ReservedPercent = reservedPercent(response.InventoryStockSplit)
+	for i := range response.BySize {
+		response.BySize[i].ReservedPercent = reservedPercent(response.BySize[i].InventoryStockSplit)
+	}
+	for i := range response.ByGroup {
+		response.ByGroup[i].ReservedPercent = reservedPercent(response.ByGroup[i].InventoryStockSplit)
+		sort.Strings(response.ByGroup[i].HoodieTypes)
+	}
+	sort.SliceStable(response.ByGroup, func(i, j int) bool {
+		if response.ByGroup[i].Ungrouped != response.ByGroup[j].Ungrouped {
+			return !response.ByGroup[i].Ungrouped
+		}
+		return response.ByGroup[i].Group < response.ByGroup[j].Group
+	})
+
+	log.Printf("✅ InventoryDashboard: on hand=%d, reserved=%d, available=%d, fully reserved items=%d",
+		response.OnHand, response.Reserved, response.Available, response.FullyReservedItems)
+	return response, nil
+}
+
+// addInventorySplit adds the counts of split to total
+func addInventorySplit(total *models.InventoryStockSplit, split models.InventoryStockSplit) {
+	total.ItemCount += split.ItemCount
+	total.OnHand += split.OnHand
+	total.Reserved += split.Reserved
+	total.Available += split.Available
+	total.FullyReservedItems += split.FullyReservedItems
+}
+
+// reservedPercent returns reserved units as a percentage of units on hand, rounded to one decimal
+func reservedPercent(split models.InventoryStockSplit) float64 {
+	if split.OnHand <= 0 {
+		return 0
+	}
+	return math.Round(float64(split.Reserved)/float64(split.OnHand)*1000) / 10
+}
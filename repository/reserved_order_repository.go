@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"armario-mascota-me/db"
+	"armario-mascota-me/events"
 	"armario-mascota-me/models"
 	"armario-mascota-me/pricing"
+	"armario-mascota-me/utils"
 )
 
 // ReservedOrderRepository handles database operations for reserved orders
@@ -38,29 +42,52 @@ func (r *ReservedOrderRepository) Create(ctx context.Context, req *models.Create
 	// Normalize orderType to lowercase
 	normalizedOrderType := strings.ToLower(strings.TrimSpace(req.OrderType))
 
+	priority, err := normalizeOrderPriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	discountType, discountValue, err := normalizeOrderDiscount(req.DiscountType, req.DiscountValue)
+	if err != nil {
+		return nil, err
+	}
+
+	couponCode := normalizeCouponCode(req.CouponCode)
+
 	query := `
-		INSERT INTO reserved_orders (status, assigned_to, order_type, customer_name, customer_phone, notes)
-		VALUES ('reserved', $1, $2, $3, $4, $5)
-		RETURNING id, status, assigned_to, order_type, customer_name, customer_phone, notes, created_at, updated_at
+		INSERT INTO reserved_orders (status, assigned_to, order_type, customer_name, customer_phone, notes, priority, discount_type, discount_value, coupon_code, hold_until)
+		VALUES ('reserved', $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, status, assigned_to, order_type, priority, customer_name, customer_phone, notes,
+		          discount_type, discount_value, COALESCE(coupon_code, ''), hold_until, created_at, updated_at
 	`
 
 	var order models.ReservedOrder
-	var customerName, customerPhone, notes sql.NullString
+	var customerName, customerPhone, notes, holdUntil sql.NullString
 
-	err := db.DB.QueryRowContext(ctx, query,
+	err = db.DB.QueryRowContext(ctx, query,
 		req.AssignedTo,
 		normalizedOrderType,
 		sql.NullString{String: req.CustomerName, Valid: req.CustomerName != ""},
 		sql.NullString{String: req.CustomerPhone, Valid: req.CustomerPhone != ""},
 		sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		priority,
+		discountType,
+		discountValue,
+		sql.NullString{String: couponCode, Valid: couponCode != ""},
+		newOrderHoldUntil(),
 	).Scan(
 		&order.ID,
 		&order.Status,
 		&order.AssignedTo,
 		&order.OrderType,
+		&order.Priority,
 		&customerName,
 		&customerPhone,
 		&notes,
+		&order.DiscountType,
+		&order.DiscountValue,
+		&order.CouponCode,
+		&holdUntil,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
@@ -69,6 +96,7 @@ func (r *ReservedOrderRepository) Create(ctx context.Context, req *models.Create
 		log.Printf("❌ Create: Error creating reserved order: %v", err)
 		return nil, fmt.Errorf("failed to create reserved order: %w", err)
 	}
+	order.HoldUntil = nullStringPtr(holdUntil)
 
 	if customerName.Valid {
 		order.CustomerName = customerName.String
@@ -80,6 +108,8 @@ func (r *ReservedOrderRepository) Create(ctx context.Context, req *models.Create
 		order.Notes = notes.String
 	}
 
+	events.PublishOrderChange(order.ID, events.OrderCreated)
+
 	log.Printf("✅ Create: Successfully created reserved order id=%d", order.ID)
 	return &order, nil
 }
@@ -92,154 +122,403 @@ func (r *ReservedOrderRepository) AddItem(ctx context.Context, orderID int64, it
 		return nil, fmt.Errorf("qty must be greater than 0")
 	}
 
-	// Start transaction
+	var line models.ReservedOrderLine
+	var itemSize, hoodieType string
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		// Validate order exists and is in 'reserved' status, get order_type
+		var orderStatus, orderType string
+		queryOrder := `SELECT status, order_type FROM reserved_orders WHERE id = $1`
+		err := tx.QueryRowContext(ctx, queryOrder, orderID).Scan(&orderStatus, &orderType)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Printf("❌ AddItem: Order not found: id=%d", orderID)
+				return fmt.Errorf("order not found")
+			}
+			log.Printf("❌ AddItem: Error fetching order: %v", err)
+			return fmt.Errorf("failed to fetch order: %w", err)
+		}
+
+		if orderStatus != "reserved" {
+			log.Printf("❌ AddItem: Order not in reserved status: status=%s", orderStatus)
+			return fmt.Errorf("order not in reserved status")
+		}
+
+		// Validate item exists and is active, lock it for update
+		// Also get hoodie_type and size to calculate correct price
+		var stockTotal, stockReserved int
+		var itemPrice int64
+		var isActive bool
+		queryItem := `
+			SELECT i.stock_total, i.stock_reserved, i.price, i.is_active, i.size,
+			       COALESCE(da.hoodie_type, '') as hoodie_type
+			FROM items i
+			INNER JOIN design_assets da ON i.design_asset_id = da.id
+			WHERE i.id = $1
+			FOR UPDATE
+		`
+		err = tx.QueryRowContext(ctx, queryItem, itemID).Scan(&stockTotal, &stockReserved, &itemPrice, &isActive, &itemSize, &hoodieType)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Printf("❌ AddItem: Item not found: id=%d", itemID)
+				return fmt.Errorf("item not found")
+			}
+			log.Printf("❌ AddItem: Error fetching item: %v", err)
+			return fmt.Errorf("failed to fetch item: %w", err)
+		}
+
+		if !isActive {
+			log.Printf("❌ AddItem: Item is not active: id=%d", itemID)
+			return fmt.Errorf("item not found or inactive")
+		}
+
+		// Validate stock availability
+		available := stockTotal - stockReserved
+		if available < qty {
+			log.Printf("❌ AddItem: Insufficient stock: available=%d, requested=%d", available, qty)
+			return fmt.Errorf("insufficient stock: available %d, requested %d", available, qty)
+		}
+
+		// NOTE: Pricing is NOT calculated here. Prices will be calculated dynamically when querying the order.
+		// Set unit_price to 0 as placeholder - it will be calculated on-read for "reserved" orders
+		// or frozen when completing the sale.
+		placeholderPrice := int64(0)
+		log.Printf("💰 AddItem: Not calculating price here - will be calculated on-read. Using placeholder price: %d", placeholderPrice)
+
+		// Upsert reserved_order_lines (if exists, add to qty; if not, create new)
+		// Use placeholder price (0) - pricing will be calculated dynamically
+		// Convert customCode *string to sql.NullString for database insertion
+		var customCodeDB sql.NullString
+		if customCode != nil {
+			customCodeDB = sql.NullString{String: *customCode, Valid: true}
+		}
+
+		queryUpsertLine := `
+			INSERT INTO reserved_order_lines (reserved_order_id, item_id, qty, unit_price, custom_code)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (reserved_order_id, item_id)
+			DO UPDATE SET qty = reserved_order_lines.qty + EXCLUDED.qty
+			RETURNING id, reserved_order_id, item_id, qty, unit_price, created_at, custom_code
+		`
+
+		var customCodeReturned sql.NullString
+		err = tx.QueryRowContext(ctx, queryUpsertLine, orderID, itemID, qty, placeholderPrice, customCodeDB).Scan(
+			&line.ID,
+			&line.ReservedOrderID,
+			&line.ItemID,
+			&line.Qty,
+			&line.UnitPrice,
+			&line.CreatedAt,
+			&customCodeReturned,
+		)
+		if err == nil && customCodeReturned.Valid {
+			line.CustomCode = &customCodeReturned.String
+		}
+		if err != nil {
+			log.Printf("❌ AddItem: Error upserting line: %v", err)
+			return fmt.Errorf("failed to upsert order line: %w", err)
+		}
+
+		// Update item stock_reserved
+		queryUpdateStock := `
+			UPDATE items
+			SET stock_reserved = stock_reserved + $1
+			WHERE id = $2
+		`
+		_, err = tx.ExecContext(ctx, queryUpdateStock, qty, itemID)
+		if err != nil {
+			log.Printf("❌ AddItem: Error updating stock_reserved: %v", err)
+			return fmt.Errorf("failed to update stock_reserved: %w", err)
+		}
+
+		if err := extendOrderHold(ctx, tx, orderID); err != nil {
+			log.Printf("❌ AddItem: Error extending hold: %v", err)
+			return fmt.Errorf("failed to extend order hold: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	events.PublishOrderChange(orderID, events.OrderItemsAdded)
+
+	// Surface silent pricing fallbacks so the UI can show them without blocking
+	if pricingEngine := pricing.GetEngine(); pricingEngine == nil {
+		line.Warnings = append(line.Warnings, "pricing engine not initialized: order will be priced with stored prices")
+	} else if reason := pricingEngine.FallbackPriceReason(hoodieType, itemSize); reason != "" {
+		line.Warnings = append(line.Warnings, fmt.Sprintf("item %d will be priced with a fallback price: %s", itemID, reason))
+	}
+	for _, warning := range line.Warnings {
+		log.Printf("⚠️ AddItem: %s", warning)
+	}
+
+	log.Printf("✅ AddItem: Successfully added item to order: line_id=%d", line.ID)
+	return &line, nil
+}
+
+// maxBulkAddItems caps the number of items accepted by a single BulkAddItems call
+const maxBulkAddItems = 200
+
+// BulkAddItems adds several items to a reserved order in a single transaction.
+// Every item is validated (custom fields, active, available stock summed per item) before anything is
+// written; if any item fails the whole batch is rolled back and the response lists each failing item
+// in Errors with no lines. Items are locked in ascending item_id order so overlapping bulk requests
+// cannot deadlock.
+func (r *ReservedOrderRepository) BulkAddItems(ctx context.Context, orderID int64, items []models.AddItemToOrderRequest) (*models.BulkAddItemsResponse, error) {
+	log.Printf("📦 BulkAddItems: Adding %d items to order_id=%d", len(items), orderID)
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("items must not be empty")
+	}
+	if len(items) > maxBulkAddItems {
+		return nil, fmt.Errorf("too many items: max %d per request", maxBulkAddItems)
+	}
+
+	response := &models.BulkAddItemsResponse{
+		OrderID: orderID,
+		Lines:   []models.ReservedOrderLine{},
+		Errors:  []models.BulkAddItemError{},
+	}
+	addError := func(index int, itemID int64, message string) {
+		response.Errors = append(response.Errors, models.BulkAddItemError{Index: index, ItemID: itemID, Error: message})
+	}
+
+	// Validate request fields and build custom codes
+	type bulkEntry struct {
+		index      int
+		itemID     int64
+		qty        int
+		customCode *string
+	}
+	var entries []bulkEntry
+	for i, item := range items {
+		if item.ItemID <= 0 {
+			addError(i, item.ItemID, "item_id must be greater than 0")
+			continue
+		}
+		if item.Qty <= 0 {
+			addError(i, item.ItemID, "qty must be greater than 0")
+			continue
+		}
+		var customCode *string
+		if strings.ToLower(strings.TrimSpace(item.Type)) == "custom" {
+			if item.PrimaryColor == "" || item.SecondaryColor == "" || item.HoodieType == "" {
+				addError(i, item.ItemID, "custom type requires primaryColor, secondaryColor, and hoodieType")
+				continue
+			}
+			code := utils.BuildCustomCode(item.PrimaryColor, item.SecondaryColor, item.HoodieType)
+			customCode = &code
+		}
+		entries = append(entries, bulkEntry{index: i, itemID: item.ItemID, qty: item.Qty, customCode: customCode})
+	}
+
+	// Deterministic lock order: by item_id, then by request position
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].itemID < entries[j].itemID
+	})
+
 	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
-		log.Printf("❌ AddItem: Error starting transaction: %v", err)
+		log.Printf("❌ BulkAddItems: Error starting transaction: %v", err)
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Validate order exists and is in 'reserved' status, get order_type
-	var orderStatus, orderType string
-	queryOrder := `SELECT status, order_type FROM reserved_orders WHERE id = $1`
-	err = tx.QueryRowContext(ctx, queryOrder, orderID).Scan(&orderStatus, &orderType)
+	// Validate order exists and is in 'reserved' status
+	var orderStatus string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM reserved_orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&orderStatus)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Printf("❌ AddItem: Order not found: id=%d", orderID)
+			log.Printf("❌ BulkAddItems: Order not found: id=%d", orderID)
 			return nil, fmt.Errorf("order not found")
 		}
-		log.Printf("❌ AddItem: Error fetching order: %v", err)
+		log.Printf("❌ BulkAddItems: Error fetching order: %v", err)
 		return nil, fmt.Errorf("failed to fetch order: %w", err)
 	}
-
 	if orderStatus != "reserved" {
-		log.Printf("❌ AddItem: Order not in reserved status: status=%s", orderStatus)
+		log.Printf("❌ BulkAddItems: Order not in reserved status: status=%s", orderStatus)
 		return nil, fmt.Errorf("order not in reserved status")
 	}
 
-	// Validate item exists and is active, lock it for update
-	// Also get hoodie_type and size to calculate correct price
-	var stockTotal, stockReserved int
-	var itemPrice int64
-	var isActive bool
-	var itemSize string
-	var hoodieType string
+	// Requested qty per item, so repeated items are checked against their combined qty
+	requestedByItem := make(map[int64]int)
+	for _, entry := range entries {
+		requestedByItem[entry.itemID] += entry.qty
+	}
+
+	type lockedItem struct {
+		size       string
+		hoodieType string
+	}
+	locked := make(map[int64]lockedItem)
+	failed := make(map[int64]string)
+
 	queryItem := `
-		SELECT i.stock_total, i.stock_reserved, i.price, i.is_active, i.size,
+		SELECT i.stock_total, i.stock_reserved, i.is_active, i.size,
 		       COALESCE(da.hoodie_type, '') as hoodie_type
 		FROM items i
 		INNER JOIN design_assets da ON i.design_asset_id = da.id
 		WHERE i.id = $1
 		FOR UPDATE
 	`
-	err = tx.QueryRowContext(ctx, queryItem, itemID).Scan(&stockTotal, &stockReserved, &itemPrice, &isActive, &itemSize, &hoodieType)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Printf("❌ AddItem: Item not found: id=%d", itemID)
-			return nil, fmt.Errorf("item not found")
+	for _, entry := range entries {
+		if _, seen := locked[entry.itemID]; seen {
+			continue
+		}
+		if itemErr, seen := failed[entry.itemID]; seen {
+			addError(entry.index, entry.itemID, itemErr)
+			continue
 		}
-		log.Printf("❌ AddItem: Error fetching item: %v", err)
-		return nil, fmt.Errorf("failed to fetch item: %w", err)
-	}
 
-	if !isActive {
-		log.Printf("❌ AddItem: Item is not active: id=%d", itemID)
-		return nil, fmt.Errorf("item not found or inactive")
-	}
+		var stockTotal, stockReserved int
+		var isActive bool
+		var item lockedItem
+		err = tx.QueryRowContext(ctx, queryItem, entry.itemID).Scan(&stockTotal, &stockReserved, &isActive, &item.size, &item.hoodieType)
+		if err != nil && err != sql.ErrNoRows {
+			log.Printf("❌ BulkAddItems: Error fetching item %d: %v", entry.itemID, err)
+			return nil, fmt.Errorf("failed to fetch item: %w", err)
+		}
 
-	// Validate stock availability
-	available := stockTotal - stockReserved
-	if available < qty {
-		log.Printf("❌ AddItem: Insufficient stock: available=%d, requested=%d", available, qty)
-		return nil, fmt.Errorf("insufficient stock: available %d, requested %d", available, qty)
-	}
+		var itemErr string
+		switch {
+		case err == sql.ErrNoRows:
+			itemErr = "item not found"
+		case !isActive:
+			itemErr = "item not found or inactive"
+		default:
+			available := stockTotal - stockReserved
+			if requested := requestedByItem[entry.itemID]; available < requested {
+				itemErr = fmt.Sprintf("insufficient stock: available %d, requested %d", available, requested)
+			}
+		}
+		if itemErr != "" {
+			log.Printf("❌ BulkAddItems: item_id=%d: %s", entry.itemID, itemErr)
+			addError(entry.index, entry.itemID, itemErr)
+			failed[entry.itemID] = itemErr
+			continue
+		}
 
-	// NOTE: Pricing is NOT calculated here. Prices will be calculated dynamically when querying the order.
-	// Set unit_price to 0 as placeholder - it will be calculated on-read for "reserved" orders
-	// or frozen when completing the sale.
-	placeholderPrice := int64(0)
-	log.Printf("💰 AddItem: Not calculating price here - will be calculated on-read. Using placeholder price: %d", placeholderPrice)
+		locked[entry.itemID] = item
+	}
 
-	// Upsert reserved_order_lines (if exists, add to qty; if not, create new)
-	// Use placeholder price (0) - pricing will be calculated dynamically
-	// Convert customCode *string to sql.NullString for database insertion
-	var customCodeDB sql.NullString
-	if customCode != nil {
-		customCodeDB = sql.NullString{String: *customCode, Valid: true}
+	if len(response.Errors) > 0 {
+		sort.SliceStable(response.Errors, func(i, j int) bool {
+			return response.Errors[i].Index < response.Errors[j].Index
+		})
+		log.Printf("❌ BulkAddItems: %d of %d items failed, rolling back", len(response.Errors), len(items))
+		return response, nil
 	}
 
+	// Upsert lines (placeholder price 0, see AddItem) and reserve stock
 	queryUpsertLine := `
 		INSERT INTO reserved_order_lines (reserved_order_id, item_id, qty, unit_price, custom_code)
-		VALUES ($1, $2, $3, $4, $5)
+		VALUES ($1, $2, $3, 0, $4)
 		ON CONFLICT (reserved_order_id, item_id)
 		DO UPDATE SET qty = reserved_order_lines.qty + EXCLUDED.qty
 		RETURNING id, reserved_order_id, item_id, qty, unit_price, created_at, custom_code
 	`
+	for _, entry := range entries {
+		var customCodeDB sql.NullString
+		if entry.customCode != nil {
+			customCodeDB = sql.NullString{String: *entry.customCode, Valid: true}
+		}
 
-	var line models.ReservedOrderLine
-	var customCodeReturned sql.NullString
-	err = tx.QueryRowContext(ctx, queryUpsertLine, orderID, itemID, qty, placeholderPrice, customCodeDB).Scan(
-		&line.ID,
-		&line.ReservedOrderID,
-		&line.ItemID,
-		&line.Qty,
-		&line.UnitPrice,
-		&line.CreatedAt,
-		&customCodeReturned,
-	)
-	if err == nil && customCodeReturned.Valid {
-		line.CustomCode = &customCodeReturned.String
-	}
-	if err != nil {
-		log.Printf("❌ AddItem: Error upserting line: %v", err)
-		return nil, fmt.Errorf("failed to upsert order line: %w", err)
+		var line models.ReservedOrderLine
+		var customCodeReturned sql.NullString
+		err = tx.QueryRowContext(ctx, queryUpsertLine, orderID, entry.itemID, entry.qty, customCodeDB).Scan(
+			&line.ID,
+			&line.ReservedOrderID,
+			&line.ItemID,
+			&line.Qty,
+			&line.UnitPrice,
+			&line.CreatedAt,
+			&customCodeReturned,
+		)
+		if err != nil {
+			log.Printf("❌ BulkAddItems: Error upserting line for item_id=%d: %v", entry.itemID, err)
+			return nil, fmt.Errorf("failed to upsert order line: %w", err)
+		}
+		if customCodeReturned.Valid {
+			line.CustomCode = &customCodeReturned.String
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE items SET stock_reserved = stock_reserved + $1 WHERE id = $2`, entry.qty, entry.itemID)
+		if err != nil {
+			log.Printf("❌ BulkAddItems: Error updating stock_reserved for item_id=%d: %v", entry.itemID, err)
+			return nil, fmt.Errorf("failed to update stock_reserved: %w", err)
+		}
+
+		response.Lines = append(response.Lines, line)
 	}
 
-	// Update item stock_reserved
-	queryUpdateStock := `
-		UPDATE items
-		SET stock_reserved = stock_reserved + $1
-		WHERE id = $2
-	`
-	_, err = tx.ExecContext(ctx, queryUpdateStock, qty, itemID)
-	if err != nil {
-		log.Printf("❌ AddItem: Error updating stock_reserved: %v", err)
-		return nil, fmt.Errorf("failed to update stock_reserved: %w", err)
+	if err := extendOrderHold(ctx, tx, orderID); err != nil {
+		log.Printf("❌ BulkAddItems: Error extending hold: %v", err)
+		return nil, fmt.Errorf("failed to extend order hold: %w", err)
 	}
 
-	// Commit transaction
 	if err := tx.Commit(); err != nil {
-		log.Printf("❌ AddItem: Error committing transaction: %v", err)
+		log.Printf("❌ BulkAddItems: Error committing transaction: %v", err)
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	events.PublishOrderChange(orderID, events.OrderItemsAdded)
 
-	log.Printf("✅ AddItem: Successfully added item to order: line_id=%d", line.ID)
-	return &line, nil
+	// Surface silent pricing fallbacks, same as AddItem
+	pricingEngine := pricing.GetEngine()
+	for i := range response.Lines {
+		line := &response.Lines[i]
+		if pricingEngine == nil {
+			line.Warnings = append(line.Warnings, "pricing engine not initialized: order will be priced with stored prices")
+			continue
+		}
+		item := locked[line.ItemID]
+		if reason := pricingEngine.FallbackPriceReason(item.hoodieType, item.size); reason != "" {
+			line.Warnings = append(line.Warnings, fmt.Sprintf("item %d will be priced with a fallback price: %s", line.ItemID, reason))
+		}
+	}
+
+	log.Printf("✅ BulkAddItems: Added %d lines to order_id=%d", len(response.Lines), orderID)
+	return response, nil
 }
 
 // GetByID retrieves a reserved order by ID with its lines
 func (r *ReservedOrderRepository) GetByID(ctx context.Context, id int64) (*models.ReservedOrderResponse, error) {
+	return r.getByID(ctx, id, false)
+}
+
+// GetByIDWithBreakdown retrieves a reserved order like GetByID and, for reserved orders, also returns
+// the full pricing breakdown the line prices came from (bundle/retail split and rules per line)
+func (r *ReservedOrderRepository) GetByIDWithBreakdown(ctx context.Context, id int64) (*models.ReservedOrderResponse, error) {
+	return r.getByID(ctx, id, true)
+}
+
+// getByID loads a reserved order with its lines; includeBreakdown attaches the pricing breakdown
+func (r *ReservedOrderRepository) getByID(ctx context.Context, id int64, includeBreakdown bool) (*models.ReservedOrderResponse, error) {
 	log.Printf("📦 GetByID: Fetching order id=%d", id)
 
 	// Get order
 	queryOrder := `
-		SELECT id, status, assigned_to, order_type, customer_name, customer_phone, notes, created_at, updated_at
+		SELECT id, status, assigned_to, order_type, priority, customer_name, customer_phone, notes,
+		       discount_type, discount_value, COALESCE(coupon_code, ''), hold_until, created_at, updated_at
 		FROM reserved_orders
 		WHERE id = $1
 	`
 
 	var order models.ReservedOrder
-	var customerName, customerPhone, notes sql.NullString
+	var customerName, customerPhone, notes, holdUntil sql.NullString
 
 	err := db.DB.QueryRowContext(ctx, queryOrder, id).Scan(
 		&order.ID,
 		&order.Status,
 		&order.AssignedTo,
 		&order.OrderType,
+		&order.Priority,
 		&customerName,
 		&customerPhone,
 		&notes,
+		&order.DiscountType,
+		&order.DiscountValue,
+		&order.CouponCode,
+		&holdUntil,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
@@ -262,6 +541,7 @@ func (r *ReservedOrderRepository) GetByID(ctx context.Context, id int64) (*model
 	if notes.Valid {
 		order.Notes = notes.String
 	}
+	order.HoldUntil = nullStringPtr(holdUntil)
 
 	// Get lines with complete item and design asset information
 	queryLines := `
@@ -289,7 +569,7 @@ func (r *ReservedOrderRepository) GetByID(ctx context.Context, id int64) (*model
 	defer rows.Close()
 
 	var lines []models.ReservedOrderLineWithItem
-	var total int64
+	var total, listTotal int64
 
 	for rows.Next() {
 		var line models.ReservedOrderLineWithItem
@@ -327,12 +607,14 @@ func (r *ReservedOrderRepository) GetByID(ctx context.Context, id int64) (*model
 			continue
 		}
 
+		item.StockAvailable = stockAvailable(item.StockTotal, item.StockReserved)
 		line.Item = item
 		lines = append(lines, line)
 		// For completed/canceled orders, use stored unit_price
 		// For reserved orders, pricing will be recalculated below
 		if order.Status != "reserved" {
 			total += int64(line.Qty) * line.UnitPrice
+			listTotal += int64(line.Qty) * item.Price
 		}
 	}
 
@@ -342,16 +624,26 @@ func (r *ReservedOrderRepository) GetByID(ctx context.Context, id int64) (*model
 	}
 
 	// Calculate pricing based on order status
+	var warnings []string
+	var coupon *models.PricingCoupon
+	var pricingBreakdown *models.PricingBreakdown
 	if order.Status == "reserved" {
 		// Calculate pricing dynamically using pricing engine
 		pricingEngine := pricing.GetEngine()
 		if pricingEngine == nil {
 			log.Printf("⚠️ GetByID: Pricing engine not initialized, using stored prices")
+			warnings = append(warnings, "pricing engine not initialized: total uses stored prices")
 			// Fallback to stored prices if engine not available
 			for _, line := range lines {
 				total += int64(line.Qty) * line.UnitPrice
 			}
 		} else {
+			for _, line := range lines {
+				if reason := pricingEngine.FallbackPriceReason(line.Item.HoodieType, line.Item.Size); reason != "" {
+					warnings = append(warnings, fmt.Sprintf("item %d (%s) is priced with a fallback price: %s", line.ItemID, line.Item.SKU, reason))
+				}
+			}
+
 			// Calculate pricing breakdown
 			breakdown, err := pricingEngine.CalculateOrderPricing(ctx, id)
 			if err != nil {
@@ -372,6 +664,10 @@ func (r *ReservedOrderRepository) GetByID(ctx context.Context, id int64) (*model
 			}
 
 			total = breakdown.Total
+			coupon = breakdown.Coupon
+			if includeBreakdown {
+				pricingBreakdown = breakdown
+			}
 
 			// Update order_type if it changed
 			newOrderType := breakdown.OrderType
@@ -390,42 +686,106 @@ func (r *ReservedOrderRepository) GetByID(ctx context.Context, id int64) (*model
 		log.Printf("📋 GetByID: Order status=%s, using stored prices", order.Status)
 	}
 
+	// Order-level discount applies on top of the pricing engine (or stored prices); completed
+	// orders already have it spread into their frozen prices
+	var subtotal, discount int64
+	if order.Status == "completed" {
+		subtotal, discount, total = frozenOrderTotals(listTotal, total)
+	} else {
+		subtotal = total
+		total, discount = applyOrderDiscount(subtotal, order.DiscountType, order.DiscountValue)
+	}
+
 	response := &models.ReservedOrderResponse{
 		ReservedOrder: order,
 		Lines:         lines,
+		Subtotal:      subtotal,
+		Discount:      discount,
 		Total:         total,
+		Warnings:      warnings,
+		Coupon:        coupon,
+		Breakdown:     pricingBreakdown,
 	}
 
 	log.Printf("✅ GetByID: Successfully fetched order id=%d with %d lines, total=%d", id, len(lines), total)
 	return response, nil
 }
 
-// List retrieves reserved orders filtered by status
-func (r *ReservedOrderRepository) List(ctx context.Context, status *string) ([]models.ReservedOrderListItem, error) {
-	log.Printf("📦 List: Fetching orders with status=%v", status)
+// List retrieves reserved orders filtered by status, assignee and a customer name/phone search
+// with cursor pagination. Ordered by created_at DESC, id DESC so the cursor is stable across pages
+func (r *ReservedOrderRepository) List(ctx context.Context, req *models.ReservedOrderListRequest) (*models.ReservedOrderListResponse, error) {
+	log.Printf("📦 List: Fetching orders with status=%v, q=%v, assignedTo=%v, limit=%d", req.Status, req.Q, req.AssignedTo, req.Limit)
 
-	query := `
-		SELECT ro.id, ro.status, ro.assigned_to, ro.order_type, ro.customer_name, ro.customer_phone, ro.notes,
-		       ro.created_at, ro.updated_at,
-		       COUNT(rol.id) as line_count,
-		       COALESCE(SUM(rol.qty * rol.unit_price), 0) as total
-		FROM reserved_orders ro
-		LEFT JOIN reserved_order_lines rol ON ro.id = rol.reserved_order_id
-	`
+	// Set default limit
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	where := " WHERE 1=1"
 	var args []interface{}
 	argIndex := 1
 
-	if status != nil && *status != "" {
-		query += fmt.Sprintf(" WHERE ro.status = $%d", argIndex)
-		args = append(args, *status)
+	if req.Status != nil && *req.Status != "" {
+		where += fmt.Sprintf(" AND ro.status = $%d", argIndex)
+		args = append(args, *req.Status)
 		argIndex++
 	}
 
-	query += `
-		GROUP BY ro.id, ro.status, ro.assigned_to, ro.order_type, ro.customer_name, ro.customer_phone, ro.notes,
-		         ro.created_at, ro.updated_at
-		ORDER BY ro.created_at DESC
-	`
+	if req.AssignedTo != nil && *req.AssignedTo != "" {
+		where += fmt.Sprintf(" AND ro.assigned_to = $%d", argIndex)
+		args = append(args, *req.AssignedTo)
+		argIndex++
+	}
+
+	// Text search filter (q) - search in customer name and phone
+	if req.Q != nil && *req.Q != "" {
+		searchTerm := "%" + *req.Q + "%"
+		where += fmt.Sprintf(" AND (ro.customer_name ILIKE $%d OR ro.customer_phone ILIKE $%d)", argIndex, argIndex)
+		args = append(args, searchTerm)
+		argIndex++
+	}
+
+	// Total count ignores the cursor so it stays the same across pages
+	var totalCount int
+	err := db.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM reserved_orders ro`+where, args...).Scan(&totalCount)
+	if err != nil {
+		log.Printf("❌ List: Error counting orders: %v", err)
+		return nil, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	// Cursor pagination
+	if req.Cursor != nil && *req.Cursor != "" {
+		cursorCreatedAt, cursorID, err := decodeCursor(*req.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		where += fmt.Sprintf(" AND (ro.created_at, ro.id) < ($%d, $%d)", argIndex, argIndex+1)
+		args = append(args, cursorCreatedAt, cursorID)
+		argIndex += 2
+	}
+
+	query := `
+		SELECT ro.id, ro.status, ro.assigned_to, ro.order_type, ro.priority, ro.customer_name, ro.customer_phone, ro.notes,
+		       ro.hold_until, ro.created_at, ro.updated_at,
+		       COUNT(rol.id) as line_count,
+		       COALESCE(SUM(rol.qty * rol.unit_price), 0) as total,
+		       ro.created_at as cursor_created_at
+		FROM reserved_orders ro
+		LEFT JOIN reserved_order_lines rol ON ro.id = rol.reserved_order_id
+	` + where
+
+	// Order and limit (fetch limit+1 to check if there's a next page)
+	query += fmt.Sprintf(`
+		GROUP BY ro.id, ro.status, ro.assigned_to, ro.order_type, ro.priority, ro.customer_name, ro.customer_phone, ro.notes,
+		         ro.hold_until, ro.created_at, ro.updated_at
+		ORDER BY ro.created_at DESC, ro.id DESC
+		LIMIT $%d
+	`, argIndex)
+	args = append(args, limit+1)
 
 	rows, err := db.DB.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -434,24 +794,29 @@ func (r *ReservedOrderRepository) List(ctx context.Context, status *string) ([]m
 	}
 	defer rows.Close()
 
-	var orders []models.ReservedOrderListItem
+	orders := []models.ReservedOrderListItem{}
+	var createdAts []time.Time
 
 	for rows.Next() {
 		var order models.ReservedOrderListItem
-		var customerName, customerPhone, notes sql.NullString
+		var customerName, customerPhone, notes, holdUntil sql.NullString
+		var cursorCreatedAt time.Time
 
 		err := rows.Scan(
 			&order.ID,
 			&order.Status,
 			&order.AssignedTo,
 			&order.OrderType,
+			&order.Priority,
 			&customerName,
 			&customerPhone,
 			&notes,
+			&holdUntil,
 			&order.CreatedAt,
 			&order.UpdatedAt,
 			&order.LineCount,
 			&order.Total,
+			&cursorCreatedAt,
 		)
 		if err != nil {
 			log.Printf("❌ List: Error scanning order: %v", err)
@@ -467,8 +832,10 @@ func (r *ReservedOrderRepository) List(ctx context.Context, status *string) ([]m
 		if notes.Valid {
 			order.Notes = notes.String
 		}
+		order.HoldUntil = nullStringPtr(holdUntil)
 
 		orders = append(orders, order)
+		createdAts = append(createdAts, cursorCreatedAt)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -476,165 +843,425 @@ func (r *ReservedOrderRepository) List(ctx context.Context, status *string) ([]m
 		return nil, fmt.Errorf("failed to iterate orders: %w", err)
 	}
 
-	log.Printf("✅ List: Successfully fetched %d orders", len(orders))
-	return orders, nil
+	// Check if there's a next page; the cursor points at the last order returned
+	var nextCursor *string
+	if len(orders) > limit {
+		orders = orders[:limit]
+		cursor := encodeCursor(createdAts[limit-1], orders[limit-1].ID)
+		nextCursor = &cursor
+	}
+
+	log.Printf("✅ List: Successfully fetched %d of %d orders", len(orders), totalCount)
+	return &models.ReservedOrderListResponse{
+		Orders:     orders,
+		TotalCount: totalCount,
+		Pagination: models.PaginationInfo{
+			Limit:      limit,
+			NextCursor: nextCursor,
+		},
+	}, nil
 }
 
 // Cancel cancels a reserved order and releases stock reservations
 func (r *ReservedOrderRepository) Cancel(ctx context.Context, id int64) (*models.ReservedOrder, error) {
+	return r.cancel(ctx, id, nil)
+}
+
+// cancel cancels a reserved order and releases its stock reservations. When expiredAt is set the
+// order is only canceled if its hold had passed by then (re-checked under the row lock so a
+// concurrent extend-hold wins), and the change is published as hold_expired
+func (r *ReservedOrderRepository) cancel(ctx context.Context, id int64, expiredAt *time.Time) (*models.ReservedOrder, error) {
 	log.Printf("📦 Cancel: Canceling order id=%d", id)
 
-	// Start transaction
-	tx, err := db.DB.BeginTx(ctx, nil)
-	if err != nil {
-		log.Printf("❌ Cancel: Error starting transaction: %v", err)
-		return nil, fmt.Errorf("failed to start transaction: %w", err)
-	}
-	defer tx.Rollback()
+	var order models.ReservedOrder
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		// Validate order exists and is in 'reserved' status
+		var orderStatus string
+		var currentHold sql.NullTime
+		queryOrder := `SELECT status, hold_until FROM reserved_orders WHERE id = $1 FOR UPDATE`
+		err := tx.QueryRowContext(ctx, queryOrder, id).Scan(&orderStatus, &currentHold)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Printf("❌ Cancel: Order not found: id=%d", id)
+				return fmt.Errorf("order not found")
+			}
+			log.Printf("❌ Cancel: Error fetching order: %v", err)
+			return fmt.Errorf("failed to fetch order: %w", err)
+		}
 
-	// Validate order exists and is in 'reserved' status
-	var orderStatus string
-	queryOrder := `SELECT status FROM reserved_orders WHERE id = $1 FOR UPDATE`
-	err = tx.QueryRowContext(ctx, queryOrder, id).Scan(&orderStatus)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Printf("❌ Cancel: Order not found: id=%d", id)
-			return nil, fmt.Errorf("order not found")
+		if orderStatus != "reserved" {
+			log.Printf("❌ Cancel: Order not in reserved status: status=%s", orderStatus)
+			return fmt.Errorf("order not in reserved status")
 		}
-		log.Printf("❌ Cancel: Error fetching order: %v", err)
-		return nil, fmt.Errorf("failed to fetch order: %w", err)
-	}
 
-	if orderStatus != "reserved" {
-		log.Printf("❌ Cancel: Order not in reserved status: status=%s", orderStatus)
-		return nil, fmt.Errorf("order not in reserved status")
-	}
+		if expiredAt != nil && (!currentHold.Valid || currentHold.Time.After(*expiredAt)) {
+			return fmt.Errorf("order hold has not expired")
+		}
 
-	// Get all lines for this order
-	queryLines := `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1`
-	rows, err := tx.QueryContext(ctx, queryLines, id)
-	if err != nil {
-		log.Printf("❌ Cancel: Error fetching lines: %v", err)
-		return nil, fmt.Errorf("failed to fetch order lines: %w", err)
-	}
-	defer rows.Close()
+		// Get all lines for this order
+		queryLines := `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1`
+		rows, err := tx.QueryContext(ctx, queryLines, id)
+		if err != nil {
+			log.Printf("❌ Cancel: Error fetching lines: %v", err)
+			return fmt.Errorf("failed to fetch order lines: %w", err)
+		}
+		defer rows.Close()
 
-	type lineInfo struct {
-		itemID int64
-		qty    int
-	}
-	var lines []lineInfo
+		type lineInfo struct {
+			itemID int64
+			qty    int
+		}
+		var lines []lineInfo
 
-	for rows.Next() {
-		var l lineInfo
-		if err := rows.Scan(&l.itemID, &l.qty); err != nil {
-			log.Printf("❌ Cancel: Error scanning line: %v", err)
-			continue
+		for rows.Next() {
+			var l lineInfo
+			if err := rows.Scan(&l.itemID, &l.qty); err != nil {
+				log.Printf("❌ Cancel: Error scanning line: %v", err)
+				continue
+			}
+			lines = append(lines, l)
 		}
-		lines = append(lines, l)
-	}
 
-	if err := rows.Err(); err != nil {
-		log.Printf("❌ Cancel: Error iterating lines: %v", err)
-		return nil, fmt.Errorf("failed to iterate order lines: %w", err)
-	}
+		if err := rows.Err(); err != nil {
+			log.Printf("❌ Cancel: Error iterating lines: %v", err)
+			return fmt.Errorf("failed to iterate order lines: %w", err)
+		}
 
-	// Release stock reservations for each line
-	for _, line := range lines {
-		queryUpdateStock := `
-			UPDATE items
-			SET stock_reserved = GREATEST(0, stock_reserved - $1)
-			WHERE id = $2
-		`
-		_, err = tx.ExecContext(ctx, queryUpdateStock, line.qty, line.itemID)
+		// Release stock reservations for each line
+		for _, line := range lines {
+			queryUpdateStock := `
+				UPDATE items
+				SET stock_reserved = GREATEST(0, stock_reserved - $1)
+				WHERE id = $2
+			`
+			_, err = tx.ExecContext(ctx, queryUpdateStock, line.qty, line.itemID)
+			if err != nil {
+				log.Printf("❌ Cancel: Error updating stock for item_id=%d: %v", line.itemID, err)
+				return fmt.Errorf("failed to release stock reservation: %w", err)
+			}
+		}
+
+		// Update order status to 'canceled'
+		queryUpdateOrder := `
+			UPDATE reserved_orders
+			SET status = 'canceled', updated_at = NOW()
+			WHERE id = $1
+			RETURNING id, status, assigned_to, order_type, customer_name, customer_phone, notes, created_at, updated_at
+		`
+
+		var customerName, customerPhone, notes sql.NullString
+
+		err = tx.QueryRowContext(ctx, queryUpdateOrder, id).Scan(
+			&order.ID,
+			&order.Status,
+			&order.AssignedTo,
+			&order.OrderType,
+			&customerName,
+			&customerPhone,
+			&notes,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+		)
 		if err != nil {
-			log.Printf("❌ Cancel: Error updating stock for item_id=%d: %v", line.itemID, err)
-			return nil, fmt.Errorf("failed to release stock reservation: %w", err)
+			log.Printf("❌ Cancel: Error updating order: %v", err)
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		if customerName.Valid {
+			order.CustomerName = customerName.String
+		}
+		if customerPhone.Valid {
+			order.CustomerPhone = customerPhone.String
+		}
+		if notes.Valid {
+			order.Notes = notes.String
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expiredAt != nil {
+		events.PublishOrderChange(id, events.OrderHoldExpired)
+	} else {
+		events.PublishOrderChange(id, events.OrderCanceled)
 	}
 
-	// Update order status to 'canceled'
-	queryUpdateOrder := `
-		UPDATE reserved_orders
-		SET status = 'canceled', updated_at = NOW()
-		WHERE id = $1
-		RETURNING id, status, assigned_to, order_type, customer_name, customer_phone, notes, created_at, updated_at
-	`
+	log.Printf("✅ Cancel: Successfully canceled order id=%d", id)
+	return &order, nil
+}
 
-	var order models.ReservedOrder
-	var customerName, customerPhone, notes sql.NullString
+// RecalculateOrderTypes prices every reserved order with the current pricing config and persists
+// order_type where it differs from the stored value, so list views (which read the stored column)
+// match what opening each order would show. A failing order is counted and skipped
+func (r *ReservedOrderRepository) RecalculateOrderTypes(ctx context.Context) (*models.RecalculateOrderTypesResponse, error) {
+	log.Printf("📦 RecalculateOrderTypes: Recalculating order_type for reserved orders")
 
-	err = tx.QueryRowContext(ctx, queryUpdateOrder, id).Scan(
-		&order.ID,
-		&order.Status,
-		&order.AssignedTo,
-		&order.OrderType,
-		&customerName,
-		&customerPhone,
-		&notes,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		return nil, fmt.Errorf("pricing engine not initialized")
+	}
+
+	rows, err := db.DB.QueryContext(ctx, `SELECT id, order_type FROM reserved_orders WHERE status = 'reserved' ORDER BY id ASC`)
 	if err != nil {
-		log.Printf("❌ Cancel: Error updating order: %v", err)
-		return nil, fmt.Errorf("failed to update order: %w", err)
+		log.Printf("❌ RecalculateOrderTypes: Error fetching orders: %v", err)
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	type storedOrder struct {
+		id        int64
+		orderType string
+	}
+	var orders []storedOrder
+	for rows.Next() {
+		var o storedOrder
+		if err := rows.Scan(&o.id, &o.orderType); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate orders: %w", err)
 	}
 
-	if customerName.Valid {
-		order.CustomerName = customerName.String
+	response := &models.RecalculateOrderTypesResponse{Changes: []models.OrderTypeChange{}}
+	for _, o := range orders {
+		response.Checked++
+		breakdown, err := pricingEngine.CalculateOrderPricing(ctx, o.id)
+		if err != nil {
+			log.Printf("⚠️  RecalculateOrderTypes: Error pricing order id=%d: %v", o.id, err)
+			response.Failed++
+			continue
+		}
+		newOrderType := strings.ToLower(breakdown.OrderType)
+		if strings.ToLower(o.orderType) == newOrderType {
+			continue
+		}
+		if err := pricingEngine.UpdateOrderType(ctx, o.id, newOrderType); err != nil {
+			log.Printf("⚠️  RecalculateOrderTypes: Error updating order id=%d: %v", o.id, err)
+			response.Failed++
+			continue
+		}
+		response.Changed++
+		response.Changes = append(response.Changes, models.OrderTypeChange{
+			OrderID: o.id,
+			From:    o.orderType,
+			To:      newOrderType,
+		})
 	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
+
+	log.Printf("✅ RecalculateOrderTypes: checked=%d changed=%d failed=%d", response.Checked, response.Changed, response.Failed)
+	return response, nil
+}
+
+// maxOrderHoldExtensionHours caps a single extend-hold request (30 days)
+const maxOrderHoldExtensionHours = 720
+
+// ExtendHold pushes forward the stock hold of a reserved order, either by req.Hours from the later of
+// now and the current hold, or to req.HoldUntil. A hold can only move forward; orders without a hold
+// get one
+func (r *ReservedOrderRepository) ExtendHold(ctx context.Context, orderID int64, req *models.ExtendHoldRequest) (*models.ReservedOrderHold, error) {
+	log.Printf("📦 ExtendHold: Extending hold for order id=%d (hours=%d, holdUntil=%q)", orderID, req.Hours, req.HoldUntil)
+
+	holdUntilRaw := strings.TrimSpace(req.HoldUntil)
+	if (req.Hours == 0) == (holdUntilRaw == "") {
+		return nil, fmt.Errorf("exactly one of hours or holdUntil is required")
 	}
-	if notes.Valid {
-		order.Notes = notes.String
+	if holdUntilRaw == "" && (req.Hours < 1 || req.Hours > maxOrderHoldExtensionHours) {
+		return nil, fmt.Errorf("hours must be between 1 and %d", maxOrderHoldExtensionHours)
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ ExtendHold: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var orderStatus string
+	var currentHold sql.NullTime
+	err = tx.QueryRowContext(ctx, `SELECT status, hold_until FROM reserved_orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&orderStatus, &currentHold)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ ExtendHold: Order not found: id=%d", orderID)
+			return nil, fmt.Errorf("order not found")
+		}
+		log.Printf("❌ ExtendHold: Error fetching order: %v", err)
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+	if orderStatus != "reserved" {
+		log.Printf("❌ ExtendHold: Order not in reserved status: status=%s", orderStatus)
+		return nil, fmt.Errorf("order not in reserved status")
+	}
+
+	now := time.Now()
+	var newHold time.Time
+	if holdUntilRaw != "" {
+		newHold, err = time.Parse(time.RFC3339, holdUntilRaw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holdUntil format, expected RFC3339: %s", holdUntilRaw)
+		}
+		if !newHold.After(now) {
+			return nil, fmt.Errorf("holdUntil must be in the future")
+		}
+		if currentHold.Valid && newHold.Before(currentHold.Time) {
+			return nil, fmt.Errorf("holdUntil cannot be earlier than the current hold (%s)", currentHold.Time.Format(time.RFC3339))
+		}
+	} else {
+		base := now
+		if currentHold.Valid && currentHold.Time.After(now) {
+			base = currentHold.Time
+		}
+		newHold = base.Add(time.Duration(req.Hours) * time.Hour)
+	}
+
+	var holdUntil string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE reserved_orders
+		SET hold_until = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING hold_until
+	`, newHold, orderID).Scan(&holdUntil)
+	if err != nil {
+		log.Printf("❌ ExtendHold: Error updating hold: %v", err)
+		return nil, fmt.Errorf("failed to update hold: %w", err)
 	}
 
-	// Commit transaction
 	if err := tx.Commit(); err != nil {
-		log.Printf("❌ Cancel: Error committing transaction: %v", err)
+		log.Printf("❌ ExtendHold: Error committing transaction: %v", err)
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	events.PublishOrderChange(orderID, events.OrderHoldExtended)
 
-	log.Printf("✅ Cancel: Successfully canceled order id=%d", id)
-	return &order, nil
+	response := &models.ReservedOrderHold{
+		OrderID:   orderID,
+		HoldUntil: holdUntil,
+	}
+	if currentHold.Valid {
+		previous := currentHold.Time.Format(time.RFC3339)
+		response.PreviousHoldUntil = &previous
+	}
+
+	log.Printf("✅ ExtendHold: Order id=%d now held until %s", orderID, holdUntil)
+	return response, nil
 }
 
-// Complete completes a reserved order and deducts stock
-func (r *ReservedOrderRepository) Complete(ctx context.Context, id int64) (*models.ReservedOrder, error) {
-	log.Printf("📦 Complete: Completing order id=%d", id)
+// ExpireHolds cancels every reserved order whose hold passed before now, releasing its stock.
+// Orders are canceled one at a time so a failure only skips that order; returns how many were canceled
+func (r *ReservedOrderRepository) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id FROM reserved_orders
+		WHERE status = 'reserved' AND hold_until IS NOT NULL AND hold_until <= $1
+		ORDER BY hold_until ASC
+	`, now)
+	if err != nil {
+		log.Printf("❌ ExpireHolds: Error fetching expired orders: %v", err)
+		return 0, fmt.Errorf("failed to fetch expired orders: %w", err)
+	}
+	var orderIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan expired order: %w", err)
+		}
+		orderIDs = append(orderIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate expired orders: %w", err)
+	}
+
+	expired := 0
+	for _, id := range orderIDs {
+		if _, err := r.cancel(ctx, id, &now); err != nil {
+			// Extended, sold or canceled since the query above: nothing to do
+			log.Printf("⚠️  ExpireHolds: Skipping order id=%d: %v", id, err)
+			continue
+		}
+		log.Printf("⏰ ExpireHolds: Canceled order id=%d, hold expired", id)
+		expired++
+	}
+	return expired, nil
+}
+
+// newOrderHoldUntil returns the hold expiry for an order created (or reopened) now,
+// or NULL when holds are disabled (ORDER_HOLD_HOURS=0)
+func newOrderHoldUntil() sql.NullTime {
+	duration := utils.OrderHoldDuration()
+	if duration <= 0 {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: time.Now().Add(duration), Valid: true}
+}
+
+// resetOrderHold is the SET clause for an order that goes back to 'reserved' (Reopen, VoidSale): an
+// order that had a hold gets the fresh newOrderHoldUntil() value bound as $2, otherwise the hold worker
+// would cancel it again right away. Orders without a hold keep never expiring
+const resetOrderHold = `hold_until = CASE WHEN hold_until IS NULL THEN NULL ELSE $2::timestamptz END`
+
+// extendOrderHold pushes an order's hold forward to now + the hold duration after items are added.
+// Orders without a hold keep never expiring
+func extendOrderHold(ctx context.Context, tx *sql.Tx, orderID int64) error {
+	holdUntil := newOrderHoldUntil()
+	if !holdUntil.Valid {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		UPDATE reserved_orders
+		SET hold_until = GREATEST(hold_until, $1)
+		WHERE id = $2 AND hold_until IS NOT NULL
+	`, holdUntil.Time, orderID)
+	return err
+}
+
+// nullStringPtr returns a pointer to the string, or nil when it is NULL
+func nullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+// Reopen moves a canceled order back to 'reserved', re-reserving stock for each of its lines.
+// Availability is validated line by line; if any line cannot be re-reserved (item inactive or not
+// enough available stock) nothing changes and the error names the short item
+func (r *ReservedOrderRepository) Reopen(ctx context.Context, id int64) (*models.ReservedOrder, error) {
+	log.Printf("📦 Reopen: Reopening order id=%d", id)
 
 	// Start transaction
 	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
-		log.Printf("❌ Complete: Error starting transaction: %v", err)
+		log.Printf("❌ Reopen: Error starting transaction: %v", err)
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Validate order exists and is in 'reserved' status
+	// Validate order exists and is in 'canceled' status
 	var orderStatus string
 	queryOrder := `SELECT status FROM reserved_orders WHERE id = $1 FOR UPDATE`
 	err = tx.QueryRowContext(ctx, queryOrder, id).Scan(&orderStatus)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Printf("❌ Complete: Order not found: id=%d", id)
+			log.Printf("❌ Reopen: Order not found: id=%d", id)
 			return nil, fmt.Errorf("order not found")
 		}
-		log.Printf("❌ Complete: Error fetching order: %v", err)
+		log.Printf("❌ Reopen: Error fetching order: %v", err)
 		return nil, fmt.Errorf("failed to fetch order: %w", err)
 	}
 
-	if orderStatus != "reserved" {
-		log.Printf("❌ Complete: Order not in reserved status: status=%s", orderStatus)
-		return nil, fmt.Errorf("order not in reserved status")
+	if orderStatus != "canceled" {
+		log.Printf("❌ Reopen: Order not in canceled status: status=%s", orderStatus)
+		return nil, fmt.Errorf("order not in canceled status")
 	}
 
-	// Get all lines for this order
-	queryLines := `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1`
+	// Get all lines for this order, in item id order so concurrent reopens lock items consistently
+	queryLines := `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1 ORDER BY item_id ASC`
 	rows, err := tx.QueryContext(ctx, queryLines, id)
 	if err != nil {
-		log.Printf("❌ Complete: Error fetching lines: %v", err)
+		log.Printf("❌ Reopen: Error fetching lines: %v", err)
 		return nil, fmt.Errorf("failed to fetch order lines: %w", err)
 	}
 	defer rows.Close()
@@ -648,59 +1275,60 @@ func (r *ReservedOrderRepository) Complete(ctx context.Context, id int64) (*mode
 	for rows.Next() {
 		var l lineInfo
 		if err := rows.Scan(&l.itemID, &l.qty); err != nil {
-			log.Printf("❌ Complete: Error scanning line: %v", err)
-			continue
+			log.Printf("❌ Reopen: Error scanning line: %v", err)
+			return nil, fmt.Errorf("failed to scan order line: %w", err)
 		}
 		lines = append(lines, l)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("❌ Complete: Error iterating lines: %v", err)
+		log.Printf("❌ Reopen: Error iterating lines: %v", err)
 		return nil, fmt.Errorf("failed to iterate order lines: %w", err)
 	}
 
-	// Process each line: validate stock_reserved and deduct stock_total and stock_reserved
+	// Re-reserve stock for each line
 	for _, line := range lines {
-		// Lock item for update and validate stock_reserved
-		var stockReserved int
-		queryItem := `SELECT stock_reserved FROM items WHERE id = $1 FOR UPDATE`
-		err = tx.QueryRowContext(ctx, queryItem, line.itemID).Scan(&stockReserved)
+		var stockTotal, stockReserved int
+		var isActive bool
+		queryItem := `SELECT stock_total, stock_reserved, is_active FROM items WHERE id = $1 FOR UPDATE`
+		err = tx.QueryRowContext(ctx, queryItem, line.itemID).Scan(&stockTotal, &stockReserved, &isActive)
 		if err != nil {
-			log.Printf("❌ Complete: Error fetching item stock: %v", err)
-			return nil, fmt.Errorf("failed to fetch item stock: %w", err)
+			log.Printf("❌ Reopen: Error fetching item_id=%d: %v", line.itemID, err)
+			return nil, fmt.Errorf("failed to fetch item %d: %w", line.itemID, err)
 		}
 
-		if stockReserved < line.qty {
-			log.Printf("❌ Complete: Insufficient reserved stock: reserved=%d, required=%d", stockReserved, line.qty)
-			return nil, fmt.Errorf("insufficient reserved stock: reserved %d, required %d", stockReserved, line.qty)
+		if !isActive {
+			log.Printf("❌ Reopen: Item is not active: id=%d", line.itemID)
+			return nil, fmt.Errorf("cannot reopen order: item %d is inactive", line.itemID)
 		}
 
-		// Deduct stock_total and stock_reserved
-		queryUpdateStock := `
-			UPDATE items
-			SET stock_total = stock_total - $1,
-			    stock_reserved = stock_reserved - $1
-			WHERE id = $2
-		`
+		available := stockTotal - stockReserved
+		if available < line.qty {
+			log.Printf("❌ Reopen: Insufficient stock for item_id=%d: available=%d, requested=%d", line.itemID, available, line.qty)
+			return nil, fmt.Errorf("insufficient stock for item %d: available %d, requested %d", line.itemID, available, line.qty)
+		}
+
+		queryUpdateStock := `UPDATE items SET stock_reserved = stock_reserved + $1 WHERE id = $2`
 		_, err = tx.ExecContext(ctx, queryUpdateStock, line.qty, line.itemID)
 		if err != nil {
-			log.Printf("❌ Complete: Error updating stock for item_id=%d: %v", line.itemID, err)
-			return nil, fmt.Errorf("failed to deduct stock: %w", err)
+			log.Printf("❌ Reopen: Error updating stock for item_id=%d: %v", line.itemID, err)
+			return nil, fmt.Errorf("failed to reserve stock: %w", err)
 		}
 	}
 
-	// Update order status to 'completed'
+	// Update order status back to 'reserved'. An order that had a hold gets a fresh one,
+	// otherwise the hold worker would cancel it again right away
 	queryUpdateOrder := `
 		UPDATE reserved_orders
-		SET status = 'completed', updated_at = NOW()
+		SET status = 'reserved', updated_at = NOW(), ` + resetOrderHold + `
 		WHERE id = $1
-		RETURNING id, status, assigned_to, order_type, customer_name, customer_phone, notes, created_at, updated_at
+		RETURNING id, status, assigned_to, order_type, customer_name, customer_phone, notes, hold_until, created_at, updated_at
 	`
 
 	var order models.ReservedOrder
-	var customerName, customerPhone, notes sql.NullString
+	var customerName, customerPhone, notes, holdUntil sql.NullString
 
-	err = tx.QueryRowContext(ctx, queryUpdateOrder, id).Scan(
+	err = tx.QueryRowContext(ctx, queryUpdateOrder, id, newOrderHoldUntil()).Scan(
 		&order.ID,
 		&order.Status,
 		&order.AssignedTo,
@@ -708,13 +1336,15 @@ func (r *ReservedOrderRepository) Complete(ctx context.Context, id int64) (*mode
 		&customerName,
 		&customerPhone,
 		&notes,
+		&holdUntil,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
 	if err != nil {
-		log.Printf("❌ Complete: Error updating order: %v", err)
+		log.Printf("❌ Reopen: Error updating order: %v", err)
 		return nil, fmt.Errorf("failed to update order: %w", err)
 	}
+	order.HoldUntil = nullStringPtr(holdUntil)
 
 	if customerName.Valid {
 		order.CustomerName = customerName.String
@@ -728,44 +1358,152 @@ func (r *ReservedOrderRepository) Complete(ctx context.Context, id int64) (*mode
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
-		log.Printf("❌ Complete: Error committing transaction: %v", err)
+		log.Printf("❌ Reopen: Error committing transaction: %v", err)
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	events.PublishOrderChange(id, events.OrderReopened)
 
-	log.Printf("✅ Complete: Successfully completed order id=%d", id)
+	log.Printf("✅ Reopen: Successfully reopened order id=%d (%d lines re-reserved)", id, len(lines))
 	return &order, nil
 }
 
-// GetAllWithFullItems retrieves all reserved orders with complete item and design asset information
-// If status is provided, filters orders by that status
-func (r *ReservedOrderRepository) GetAllWithFullItems(ctx context.Context, status *string) ([]models.ReservedOrderWithFullItems, error) {
-	log.Printf("📦 GetAllWithFullItems: Fetching orders with full item information (status=%v)", status)
+// Order flows that deduct stock; an order goes through exactly one of them, once
+const (
+	stockDeductionComplete = "complete"
+	stockDeductionSell     = "sell"
+)
 
-	// Build query with optional status filter
-	queryOrders := `
-		SELECT id, status, assigned_to, order_type, customer_name, customer_phone, notes, created_at, updated_at
-		FROM reserved_orders
-	`
-	var args []interface{}
-	if status != nil && *status != "" {
-		queryOrders += ` WHERE status = $1`
-		args = append(args, *status)
+// checkStockDeduction guards against double stock deduction across Complete and Sell. Both deduct
+// stock, so only a reserved order without a sale can go through either of them: an order with a sale
+// was already deducted by Sell, and a completed order without one was already deducted by Complete
+func checkStockDeduction(flow, orderStatus string, hasSale bool) error {
+	if hasSale {
+		return fmt.Errorf("order already has a sale associated")
 	}
-	queryOrders += ` ORDER BY created_at DESC`
-
-	rows, err := db.DB.QueryContext(ctx, queryOrders, args...)
-	if err != nil {
-		log.Printf("❌ GetAllWithFullItems: Error fetching orders: %v", err)
-		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	if flow == stockDeductionSell && orderStatus == "completed" {
+		return fmt.Errorf("order already completed without a sale: stock was already deducted")
 	}
-	defer rows.Close()
+	if orderStatus != "reserved" {
+		return fmt.Errorf("order not in reserved status")
+	}
+	return nil
+}
 
-	var orders []models.ReservedOrder
-	var customerName, customerPhone, notes sql.NullString
+// Complete completes a reserved order and deducts stock WITHOUT recording a sale or any money.
+// Use it only for orders that leave inventory without payment (e.g. gifts, samples); to sell an
+// order use SaleRepository.Sell, which completes the order itself. Both paths deduct stock, so
+// Complete rejects orders that already have a sale and Sell rejects orders completed here.
+// Unit prices are frozen first (see freezeOrderPrices), so completed orders keep a stored total.
+func (r *ReservedOrderRepository) Complete(ctx context.Context, id int64) (*models.ReservedOrder, error) {
+	log.Printf("📦 Complete: Completing order id=%d", id)
 
-	for rows.Next() {
-		var order models.ReservedOrder
-		err := rows.Scan(
+	var order models.ReservedOrder
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		// Validate order exists and is in 'reserved' status
+		var orderStatus string
+		queryOrder := `SELECT status FROM reserved_orders WHERE id = $1 FOR UPDATE`
+		err := tx.QueryRowContext(ctx, queryOrder, id).Scan(&orderStatus)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Printf("❌ Complete: Order not found: id=%d", id)
+				return fmt.Errorf("order not found")
+			}
+			log.Printf("❌ Complete: Error fetching order: %v", err)
+			return fmt.Errorf("failed to fetch order: %w", err)
+		}
+
+		// Guard against double stock deduction: a sold order already had its stock deducted by Sell
+		var existingSaleID int64
+		hasSale := true
+		err = tx.QueryRowContext(ctx, `SELECT id FROM sales WHERE reserved_order_id = $1 AND status <> 'voided'`, id).Scan(&existingSaleID)
+		if err == sql.ErrNoRows {
+			hasSale = false
+		} else if err != nil {
+			log.Printf("❌ Complete: Error checking existing sale: %v", err)
+			return fmt.Errorf("failed to check existing sale: %w", err)
+		}
+
+		if err := checkStockDeduction(stockDeductionComplete, orderStatus, hasSale); err != nil {
+			log.Printf("❌ Complete: Order id=%d cannot be completed: status=%s, sale_id=%d: %v", id, orderStatus, existingSaleID, err)
+			return err
+		}
+
+		// Get all lines for this order
+		queryLines := `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1`
+		rows, err := tx.QueryContext(ctx, queryLines, id)
+		if err != nil {
+			log.Printf("❌ Complete: Error fetching lines: %v", err)
+			return fmt.Errorf("failed to fetch order lines: %w", err)
+		}
+		defer rows.Close()
+
+		type lineInfo struct {
+			itemID int64
+			qty    int
+		}
+		var lines []lineInfo
+
+		for rows.Next() {
+			var l lineInfo
+			if err := rows.Scan(&l.itemID, &l.qty); err != nil {
+				log.Printf("❌ Complete: Error scanning line: %v", err)
+				continue
+			}
+			lines = append(lines, l)
+		}
+
+		if err := rows.Err(); err != nil {
+			log.Printf("❌ Complete: Error iterating lines: %v", err)
+			return fmt.Errorf("failed to iterate order lines: %w", err)
+		}
+
+		// Freeze final unit prices before completing, so the stored total of a completed order is never 0
+		if err := freezeOrderPrices(ctx, tx, id); err != nil {
+			log.Printf("❌ Complete: %v", err)
+			return err
+		}
+
+		// Process each line: validate stock_reserved and deduct stock_total and stock_reserved
+		for _, line := range lines {
+			// Lock item for update and validate stock_reserved
+			var stockReserved int
+			queryItem := `SELECT stock_reserved FROM items WHERE id = $1 FOR UPDATE`
+			err = tx.QueryRowContext(ctx, queryItem, line.itemID).Scan(&stockReserved)
+			if err != nil {
+				log.Printf("❌ Complete: Error fetching item stock: %v", err)
+				return fmt.Errorf("failed to fetch item stock: %w", err)
+			}
+
+			if stockReserved < line.qty {
+				log.Printf("❌ Complete: Insufficient reserved stock: reserved=%d, required=%d", stockReserved, line.qty)
+				return fmt.Errorf("insufficient reserved stock: reserved %d, required %d", stockReserved, line.qty)
+			}
+
+			// Deduct stock_total and stock_reserved
+			queryUpdateStock := `
+				UPDATE items
+				SET stock_total = stock_total - $1,
+				    stock_reserved = stock_reserved - $1
+				WHERE id = $2
+			`
+			_, err = tx.ExecContext(ctx, queryUpdateStock, line.qty, line.itemID)
+			if err != nil {
+				log.Printf("❌ Complete: Error updating stock for item_id=%d: %v", line.itemID, err)
+				return fmt.Errorf("failed to deduct stock: %w", err)
+			}
+		}
+
+		// Update order status to 'completed'
+		queryUpdateOrder := `
+			UPDATE reserved_orders
+			SET status = 'completed', updated_at = NOW()
+			WHERE id = $1
+			RETURNING id, status, assigned_to, order_type, customer_name, customer_phone, notes, created_at, updated_at
+		`
+
+		var customerName, customerPhone, notes sql.NullString
+
+		err = tx.QueryRowContext(ctx, queryUpdateOrder, id).Scan(
 			&order.ID,
 			&order.Status,
 			&order.AssignedTo,
@@ -777,8 +1515,8 @@ func (r *ReservedOrderRepository) GetAllWithFullItems(ctx context.Context, statu
 			&order.UpdatedAt,
 		)
 		if err != nil {
-			log.Printf("❌ GetAllWithFullItems: Error scanning order: %v", err)
-			continue
+			log.Printf("❌ Complete: Error updating order: %v", err)
+			return fmt.Errorf("failed to update order: %w", err)
 		}
 
 		if customerName.Valid {
@@ -791,19 +1529,148 @@ func (r *ReservedOrderRepository) GetAllWithFullItems(ctx context.Context, statu
 			order.Notes = notes.String
 		}
 
-		orders = append(orders, order)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	events.PublishOrderChange(id, events.OrderCompleted)
 
-	if err := rows.Err(); err != nil {
-		log.Printf("❌ GetAllWithFullItems: Error iterating orders: %v", err)
-		return nil, fmt.Errorf("failed to iterate orders: %w", err)
+	log.Printf("✅ Complete: Successfully completed order id=%d", id)
+	return &order, nil
+}
+
+// freezeOrderPrices writes the final unit_price of every line of an order inside tx, mirroring the
+// snapshot SaleRepository.Sell takes: with the pricing engine, the order-level discount is spread over
+// the line totals (see discountPricingLines), each line gets its effective unit price (lineTotal / qty,
+// so bundle contributions are included) and the order its calculated order_type; without it, lines
+// fall back to items.price
+func freezeOrderPrices(ctx context.Context, tx *sql.Tx, orderID int64) error {
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		log.Printf("⚠️ freezeOrderPrices: Pricing engine not initialized, freezing order %d at item prices", orderID)
+		_, err := tx.ExecContext(ctx, `
+			UPDATE reserved_order_lines rol
+			SET unit_price = i.price
+			FROM items i
+			WHERE rol.item_id = i.id AND rol.reserved_order_id = $1
+		`, orderID)
+		if err != nil {
+			return fmt.Errorf("failed to freeze pricing snapshot: %w", err)
+		}
+		return nil
 	}
 
-	// Build result with lines for each order
-	result := make([]models.ReservedOrderWithFullItems, 0, len(orders))
+	var discountType string
+	var discountValue int64
+	err := tx.QueryRowContext(ctx, `SELECT discount_type, discount_value FROM reserved_orders WHERE id = $1`, orderID).Scan(&discountType, &discountValue)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order discount: %w", err)
+	}
 
-	for _, order := range orders {
-		// Get lines with complete item and design asset information
+	breakdown, err := pricingEngine.CalculateOrderPricing(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to calculate pricing: %w", err)
+	}
+	total, discount := discountPricingLines(breakdown, discountType, discountValue)
+
+	for _, pricingLine := range breakdown.Lines {
+		effectiveUnitPrice := pricingLine.UnitPrice
+		if pricingLine.Qty > 0 {
+			effectiveUnitPrice = pricingLine.LineTotal / int64(pricingLine.Qty)
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE reserved_order_lines SET unit_price = $1 WHERE id = $2`, effectiveUnitPrice, pricingLine.LineID)
+		if err != nil {
+			return fmt.Errorf("failed to freeze pricing snapshot: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE reserved_orders SET order_type = $1 WHERE id = $2`, strings.ToLower(breakdown.OrderType), orderID)
+	if err != nil {
+		// Pricing is more important than the order type label, same as Sell
+		log.Printf("⚠️ freezeOrderPrices: Failed to update order_type for order %d: %v", orderID, err)
+	}
+
+	log.Printf("💰 freezeOrderPrices: Frozen %d lines for order %d (total=%d, discount=%d, orderType=%s)", len(breakdown.Lines), orderID, total, discount, breakdown.OrderType)
+	return nil
+}
+
+// GetAllWithFullItems retrieves all reserved orders with complete item and design asset information
+// If status is provided, filters orders by that status
+func (r *ReservedOrderRepository) GetAllWithFullItems(ctx context.Context, status *string) ([]models.ReservedOrderWithFullItems, error) {
+	log.Printf("📦 GetAllWithFullItems: Fetching orders with full item information (status=%v)", status)
+
+	// Build query with optional status filter
+	queryOrders := `
+		SELECT id, status, assigned_to, order_type, priority, customer_name, customer_phone, notes,
+		       discount_type, discount_value, COALESCE(coupon_code, ''), hold_until, created_at, updated_at
+		FROM reserved_orders
+	`
+	var args []interface{}
+	if status != nil && *status != "" {
+		queryOrders += ` WHERE status = $1`
+		args = append(args, *status)
+	}
+	queryOrders += ` ORDER BY created_at DESC`
+
+	rows, err := db.DB.QueryContext(ctx, queryOrders, args...)
+	if err != nil {
+		log.Printf("❌ GetAllWithFullItems: Error fetching orders: %v", err)
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []models.ReservedOrder
+	var customerName, customerPhone, notes sql.NullString
+
+	for rows.Next() {
+		var order models.ReservedOrder
+		var holdUntil sql.NullString
+		err := rows.Scan(
+			&order.ID,
+			&order.Status,
+			&order.AssignedTo,
+			&order.OrderType,
+			&order.Priority,
+			&customerName,
+			&customerPhone,
+			&notes,
+			&order.DiscountType,
+			&order.DiscountValue,
+			&order.CouponCode,
+			&holdUntil,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+		)
+		if err != nil {
+			log.Printf("❌ GetAllWithFullItems: Error scanning order: %v", err)
+			continue
+		}
+
+		if customerName.Valid {
+			order.CustomerName = customerName.String
+		}
+		if customerPhone.Valid {
+			order.CustomerPhone = customerPhone.String
+		}
+		if notes.Valid {
+			order.Notes = notes.String
+		}
+		order.HoldUntil = nullStringPtr(holdUntil)
+
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ GetAllWithFullItems: Error iterating orders: %v", err)
+		return nil, fmt.Errorf("failed to iterate orders: %w", err)
+	}
+
+	// Build result with lines for each order
+	result := make([]models.ReservedOrderWithFullItems, 0, len(orders))
+
+	for _, order := range orders {
+		// Get lines with complete item and design asset information
 		queryLines := `
 			SELECT rol.id, rol.reserved_order_id, rol.item_id, rol.qty, rol.unit_price, rol.created_at, rol.custom_code,
 			       i.id, i.sku, i.size, i.price, i.stock_total, i.stock_reserved, i.design_asset_id,
@@ -828,7 +1695,7 @@ func (r *ReservedOrderRepository) GetAllWithFullItems(ctx context.Context, statu
 		}
 
 		var lines []models.ReservedOrderLineWithItem
-		var total int64
+		var total, listTotal int64
 
 		for lineRows.Next() {
 			var line models.ReservedOrderLineWithItem
@@ -866,12 +1733,14 @@ func (r *ReservedOrderRepository) GetAllWithFullItems(ctx context.Context, statu
 				continue
 			}
 
+			item.StockAvailable = stockAvailable(item.StockTotal, item.StockReserved)
 			line.Item = item
 			lines = append(lines, line)
 			// For completed/canceled orders, use stored unit_price
 			// For reserved orders, pricing will be recalculated below
 			if order.Status != "reserved" {
 				total += int64(line.Qty) * line.UnitPrice
+				listTotal += int64(line.Qty) * item.Price
 			}
 		}
 		lineRows.Close()
@@ -933,9 +1802,21 @@ func (r *ReservedOrderRepository) GetAllWithFullItems(ctx context.Context, statu
 			log.Printf("📋 GetAllWithFullItems: Order %d status=%s, using stored prices", order.ID, order.Status)
 		}
 
+		// Order-level discount applies on top of the pricing engine (or stored prices); completed
+		// orders already have it spread into their frozen prices
+		var subtotal, discount int64
+		if order.Status == "completed" {
+			subtotal, discount, total = frozenOrderTotals(listTotal, total)
+		} else {
+			subtotal = total
+			total, discount = applyOrderDiscount(subtotal, order.DiscountType, order.DiscountValue)
+		}
+
 		result = append(result, models.ReservedOrderWithFullItems{
 			ReservedOrder: order,
 			Lines:         lines,
+			Subtotal:      subtotal,
+			Discount:      discount,
 			Total:         total,
 		})
 	}
@@ -1023,6 +1904,7 @@ func (r *ReservedOrderRepository) RemoveItem(ctx context.Context, orderID int64,
 		log.Printf("❌ RemoveItem: Error committing transaction: %v", err)
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	events.PublishOrderChange(orderID, events.OrderItemsRemoved)
 
 	log.Printf("✅ RemoveItem: Successfully removed item_id=%d (qty=%d) from order_id=%d", itemID, qty, orderID)
 	return nil
@@ -1133,6 +2015,11 @@ func (r *ReservedOrderRepository) UpdateItemQuantity(ctx context.Context, orderI
 			return nil, fmt.Errorf("failed to update stock_reserved: %w", err)
 		}
 		log.Printf("✅ UpdateItemQuantity: Reserved additional %d units of stock", qtyDiff)
+
+		if err := extendOrderHold(ctx, tx, orderID); err != nil {
+			log.Printf("❌ UpdateItemQuantity: Error extending hold: %v", err)
+			return nil, fmt.Errorf("failed to extend order hold: %w", err)
+		}
 	} else {
 		// Decreasing quantity, release stock reservation
 		queryUpdateStock := `
@@ -1179,6 +2066,7 @@ func (r *ReservedOrderRepository) UpdateItemQuantity(ctx context.Context, orderI
 		log.Printf("❌ UpdateItemQuantity: Error committing transaction: %v", err)
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	events.PublishOrderChange(orderID, events.OrderItemsUpdated)
 
 	log.Printf("✅ UpdateItemQuantity: Successfully updated item_id=%d quantity from %d to %d in order_id=%d", itemID, currentQty, newQty, orderID)
 	return &line, nil
@@ -1188,283 +2076,1162 @@ func (r *ReservedOrderRepository) UpdateItemQuantity(ctx context.Context, orderI
 func (r *ReservedOrderRepository) UpdateOrder(ctx context.Context, req *models.UpdateReservedOrderRequest) (*models.ReservedOrderResponse, error) {
 	log.Printf("📦 UpdateOrder: Updating order_id=%d", req.ID)
 
-	// Start transaction
-	tx, err := db.DB.BeginTx(ctx, nil)
+	// Optimistic concurrency: only update the order if it was not modified since the client read it
+	expectedUpdatedAt, err := parseExpectedUpdatedAt(req.ExpectedUpdatedAt)
 	if err != nil {
-		log.Printf("❌ UpdateOrder: Error starting transaction: %v", err)
-		return nil, fmt.Errorf("failed to start transaction: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback()
 
-	// Validate order exists and is in 'reserved' status
-	var currentStatus string
-	var orderType string
-	queryOrder := `SELECT status, order_type FROM reserved_orders WHERE id = $1 FOR UPDATE`
-	err = tx.QueryRowContext(ctx, queryOrder, req.ID).Scan(&currentStatus, &orderType)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Printf("❌ UpdateOrder: Order not found: id=%d", req.ID)
-			return nil, fmt.Errorf("order not found")
+	err = withTx(ctx, func(tx *sql.Tx) error {
+		// Validate order exists and is in 'reserved' status
+		var currentStatus string
+		var orderType string
+		queryOrder := `SELECT status, order_type FROM reserved_orders WHERE id = $1 FOR UPDATE`
+		err := tx.QueryRowContext(ctx, queryOrder, req.ID).Scan(&currentStatus, &orderType)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Printf("❌ UpdateOrder: Order not found: id=%d", req.ID)
+				return fmt.Errorf("order not found")
+			}
+			log.Printf("❌ UpdateOrder: Error fetching order: %v", err)
+			return fmt.Errorf("failed to fetch order: %w", err)
 		}
-		log.Printf("❌ UpdateOrder: Error fetching order: %v", err)
-		return nil, fmt.Errorf("failed to fetch order: %w", err)
-	}
 
-	if currentStatus != "reserved" {
-		log.Printf("❌ UpdateOrder: Order not in reserved status: status=%s", currentStatus)
-		return nil, fmt.Errorf("order not in reserved status")
-	}
+		if currentStatus != "reserved" {
+			log.Printf("❌ UpdateOrder: Order not in reserved status: status=%s", currentStatus)
+			return fmt.Errorf("order not in reserved status")
+		}
 
-	// Update order fields (status should remain "reserved" unless explicitly changed)
-	updateStatus := req.Status
-	if updateStatus == "" {
-		updateStatus = "reserved"
-	}
+		// Update order fields (status should remain "reserved" unless explicitly changed)
+		updateStatus := req.Status
+		if updateStatus == "" {
+			updateStatus = "reserved"
+		}
 
-	queryUpdateOrder := `
-		UPDATE reserved_orders
-		SET assigned_to = $1,
-		    order_type = $2,
-		    customer_name = $3,
-		    customer_phone = $4,
-		    notes = $5,
-		    status = $6,
-		    updated_at = NOW()
-		WHERE id = $7
-	`
-	_, err = tx.ExecContext(ctx, queryUpdateOrder,
-		req.AssignedTo,
-		req.OrderType,
-		sql.NullString{String: req.CustomerName, Valid: req.CustomerName != ""},
-		sql.NullString{String: req.CustomerPhone, Valid: req.CustomerPhone != ""},
-		sql.NullString{String: req.Notes, Valid: req.Notes != ""},
-		updateStatus,
-		req.ID,
-	)
-	if err != nil {
-		log.Printf("❌ UpdateOrder: Error updating order: %v", err)
-		return nil, fmt.Errorf("failed to update order: %w", err)
-	}
+		// Empty priority keeps the current one
+		var updatePriority sql.NullString
+		if strings.TrimSpace(req.Priority) != "" {
+			priority, err := normalizeOrderPriority(req.Priority)
+			if err != nil {
+				return err
+			}
+			updatePriority = sql.NullString{String: priority, Valid: true}
+		}
 
-	// Get current lines
-	queryCurrentLines := `
-		SELECT id, item_id, qty
-		FROM reserved_order_lines
-		WHERE reserved_order_id = $1
-	`
-	rows, err := tx.QueryContext(ctx, queryCurrentLines, req.ID)
-	if err != nil {
-		log.Printf("❌ UpdateOrder: Error fetching current lines: %v", err)
-		return nil, fmt.Errorf("failed to fetch current lines: %w", err)
-	}
-	defer rows.Close()
+		// Empty discount type keeps the current discount
+		var updateDiscountType sql.NullString
+		var updateDiscountValue sql.NullInt64
+		if strings.TrimSpace(req.DiscountType) != "" {
+			discountType, discountValue, err := normalizeOrderDiscount(req.DiscountType, req.DiscountValue)
+			if err != nil {
+				return err
+			}
+			updateDiscountType = sql.NullString{String: discountType, Valid: true}
+			updateDiscountValue = sql.NullInt64{Int64: discountValue, Valid: true}
+		}
 
-	type currentLine struct {
-		id     int64
-		itemID int64
-		qty    int
-	}
-	currentLinesMap := make(map[int64]currentLine) // key: item_id
-	for rows.Next() {
-		var cl currentLine
-		if err := rows.Scan(&cl.id, &cl.itemID, &cl.qty); err != nil {
-			log.Printf("❌ UpdateOrder: Error scanning current line: %v", err)
-			continue
+		// Omitted coupon code keeps the current one, an empty string removes it
+		var updateCouponCode sql.NullString
+		clearCouponCode := false
+		if req.CouponCode != nil {
+			couponCode := normalizeCouponCode(*req.CouponCode)
+			updateCouponCode = sql.NullString{String: couponCode, Valid: couponCode != ""}
+			clearCouponCode = couponCode == ""
 		}
-		currentLinesMap[cl.itemID] = cl
-	}
-	if err := rows.Err(); err != nil {
-		log.Printf("❌ UpdateOrder: Error iterating current lines: %v", err)
-		return nil, fmt.Errorf("failed to iterate current lines: %w", err)
-	}
-
-	// Build map of requested lines (key: item_id)
-	// Include lines with qty > 0 for updates/additions
-	// Lines with qty = 0 will be processed separately for deletion
-	requestedLinesMap := make(map[int64]models.UpdateReservedOrderLineRequest)
-	linesToDelete := make(map[int64]models.UpdateReservedOrderLineRequest) // Lines with qty = 0
-	for _, line := range req.Lines {
-		if line.Qty == 0 {
-			linesToDelete[line.ItemID] = line
-		} else {
-			requestedLinesMap[line.ItemID] = line
+
+		queryUpdateOrder := `
+			UPDATE reserved_orders
+			SET assigned_to = $1,
+			    order_type = $2,
+			    customer_name = $3,
+			    customer_phone = $4,
+			    notes = $5,
+			    status = $6,
+			    priority = COALESCE($8, priority),
+			    discount_type = COALESCE($9, discount_type),
+			    discount_value = COALESCE($10, discount_value),
+			    coupon_code = CASE WHEN $12 THEN NULL ELSE COALESCE($11, coupon_code) END,
+			    updated_at = NOW()
+			WHERE id = $7
+			  AND ($13::timestamptz IS NULL OR updated_at = $13)
+		`
+		result, err := tx.ExecContext(ctx, queryUpdateOrder,
+			req.AssignedTo,
+			req.OrderType,
+			sql.NullString{String: req.CustomerName, Valid: req.CustomerName != ""},
+			sql.NullString{String: req.CustomerPhone, Valid: req.CustomerPhone != ""},
+			sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+			updateStatus,
+			req.ID,
+			updatePriority,
+			updateDiscountType,
+			updateDiscountValue,
+			updateCouponCode,
+			clearCouponCode,
+			expectedUpdatedAt,
+		)
+		if err != nil {
+			log.Printf("❌ UpdateOrder: Error updating order: %v", err)
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			log.Printf("❌ UpdateOrder: Order changed since expectedUpdatedAt=%s: id=%d", expectedUpdatedAt.Time.Format(time.RFC3339Nano), req.ID)
+			return fmt.Errorf("order changed: reload it and try again")
+		}
+
+		// Get current lines
+		queryCurrentLines := `
+			SELECT id, item_id, qty
+			FROM reserved_order_lines
+			WHERE reserved_order_id = $1
+		`
+		rows, err := tx.QueryContext(ctx, queryCurrentLines, req.ID)
+		if err != nil {
+			log.Printf("❌ UpdateOrder: Error fetching current lines: %v", err)
+			return fmt.Errorf("failed to fetch current lines: %w", err)
+		}
+		defer rows.Close()
+
+		type currentLine struct {
+			id     int64
+			itemID int64
+			qty    int
+		}
+		currentLinesMap := make(map[int64]currentLine) // key: item_id
+		for rows.Next() {
+			var cl currentLine
+			if err := rows.Scan(&cl.id, &cl.itemID, &cl.qty); err != nil {
+				log.Printf("❌ UpdateOrder: Error scanning current line: %v", err)
+				continue
+			}
+			currentLinesMap[cl.itemID] = cl
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("❌ UpdateOrder: Error iterating current lines: %v", err)
+			return fmt.Errorf("failed to iterate current lines: %w", err)
 		}
-	}
 
-	// Process deletions: lines in current but not in requested, or explicitly marked with qty=0
-		for itemID, cl := range currentLinesMap {
-		shouldDelete := false
-		if _, exists := requestedLinesMap[itemID]; !exists {
-			// Not in requested lines (or has qty=0)
-			if _, hasDeleteFlag := linesToDelete[itemID]; hasDeleteFlag {
-				// Explicitly marked for deletion with qty=0
-				log.Printf("🗑️  UpdateOrder: Deleting line for item_id=%d (qty=0 in request, current qty=%d)", itemID, cl.qty)
-				shouldDelete = true
+		// Build map of requested lines (key: item_id)
+		// Include lines with qty > 0 for updates/additions
+		// Lines with qty = 0 will be processed separately for deletion
+		requestedLinesMap := make(map[int64]models.UpdateReservedOrderLineRequest)
+		linesToDelete := make(map[int64]models.UpdateReservedOrderLineRequest) // Lines with qty = 0
+		for _, line := range req.Lines {
+			if line.Qty == 0 {
+				linesToDelete[line.ItemID] = line
 			} else {
-				// Not in request at all
-				log.Printf("🗑️  UpdateOrder: Deleting line for item_id=%d (not in request, current qty=%d)", itemID, cl.qty)
-				shouldDelete = true
+				requestedLinesMap[line.ItemID] = line
 			}
 		}
 
-		if shouldDelete {
-			// Delete line and release stock
-			queryDeleteLine := `DELETE FROM reserved_order_lines WHERE id = $1`
-			_, err = tx.ExecContext(ctx, queryDeleteLine, cl.id)
-			if err != nil {
-				log.Printf("❌ UpdateOrder: Error deleting line: %v", err)
-				return nil, fmt.Errorf("failed to delete line: %w", err)
+		// Process deletions: lines in current but not in requested, or explicitly marked with qty=0
+			for itemID, cl := range currentLinesMap {
+			shouldDelete := false
+			if _, exists := requestedLinesMap[itemID]; !exists {
+				// Not in requested lines (or has qty=0)
+				if _, hasDeleteFlag := linesToDelete[itemID]; hasDeleteFlag {
+					// Explicitly marked for deletion with qty=0
+					log.Printf("🗑️  UpdateOrder: Deleting line for item_id=%d (qty=0 in request, current qty=%d)", itemID, cl.qty)
+					shouldDelete = true
+				} else {
+					// Not in request at all
+					log.Printf("🗑️  UpdateOrder: Deleting line for item_id=%d (not in request, current qty=%d)", itemID, cl.qty)
+					shouldDelete = true
+				}
 			}
 
-			// Release stock reservation
-			queryUpdateStock := `
-				UPDATE items
-				SET stock_reserved = GREATEST(0, stock_reserved - $1)
-				WHERE id = $2
-			`
-			_, err = tx.ExecContext(ctx, queryUpdateStock, cl.qty, itemID)
-			if err != nil {
-				log.Printf("❌ UpdateOrder: Error releasing stock: %v", err)
-				return nil, fmt.Errorf("failed to release stock: %w", err)
+			if shouldDelete {
+				// Delete line and release stock
+				queryDeleteLine := `DELETE FROM reserved_order_lines WHERE id = $1`
+				_, err = tx.ExecContext(ctx, queryDeleteLine, cl.id)
+				if err != nil {
+					log.Printf("❌ UpdateOrder: Error deleting line: %v", err)
+					return fmt.Errorf("failed to delete line: %w", err)
+				}
+
+				// Release stock reservation
+				queryUpdateStock := `
+					UPDATE items
+					SET stock_reserved = GREATEST(0, stock_reserved - $1)
+					WHERE id = $2
+				`
+				_, err = tx.ExecContext(ctx, queryUpdateStock, cl.qty, itemID)
+				if err != nil {
+					log.Printf("❌ UpdateOrder: Error releasing stock: %v", err)
+					return fmt.Errorf("failed to release stock: %w", err)
+				}
 			}
 		}
-	}
 
-	// Process updates and additions
-	for itemID, reqLine := range requestedLinesMap {
-		if cl, exists := currentLinesMap[itemID]; exists {
-			// Update existing line
-			if cl.qty != reqLine.Qty {
-				qtyDiff := reqLine.Qty - cl.qty
-				log.Printf("🔄 UpdateOrder: Updating item_id=%d from qty=%d to qty=%d (diff=%d)", itemID, cl.qty, reqLine.Qty, qtyDiff)
+		// Process updates and additions
+		itemsAdded := false
+		for itemID, reqLine := range requestedLinesMap {
+			if cl, exists := currentLinesMap[itemID]; exists {
+				// Update existing line
+				if cl.qty != reqLine.Qty {
+					qtyDiff := reqLine.Qty - cl.qty
+					log.Printf("🔄 UpdateOrder: Updating item_id=%d from qty=%d to qty=%d (diff=%d)", itemID, cl.qty, reqLine.Qty, qtyDiff)
+
+					if qtyDiff > 0 {
+						// Increase quantity - validate and reserve stock
+						var stockTotal, stockReserved int
+						queryItem := `SELECT stock_total, stock_reserved FROM items WHERE id = $1 FOR UPDATE`
+						err = tx.QueryRowContext(ctx, queryItem, itemID).Scan(&stockTotal, &stockReserved)
+						if err != nil {
+							log.Printf("❌ UpdateOrder: Error fetching item: %v", err)
+							return fmt.Errorf("failed to fetch item: %w", err)
+						}
 
-				if qtyDiff > 0 {
-					// Increase quantity - validate and reserve stock
-					var stockTotal, stockReserved int
-					queryItem := `SELECT stock_total, stock_reserved FROM items WHERE id = $1 FOR UPDATE`
-					err = tx.QueryRowContext(ctx, queryItem, itemID).Scan(&stockTotal, &stockReserved)
-					if err != nil {
-						log.Printf("❌ UpdateOrder: Error fetching item: %v", err)
-						return nil, fmt.Errorf("failed to fetch item: %w", err)
-					}
+						available := stockTotal - stockReserved
+						if available < qtyDiff {
+							log.Printf("❌ UpdateOrder: Insufficient stock: available=%d, requested=%d", available, qtyDiff)
+							return fmt.Errorf("insufficient stock: available %d, requested %d", available, qtyDiff)
+						}
 
-					available := stockTotal - stockReserved
-					if available < qtyDiff {
-						log.Printf("❌ UpdateOrder: Insufficient stock: available=%d, requested=%d", available, qtyDiff)
-						return nil, fmt.Errorf("insufficient stock: available %d, requested %d", available, qtyDiff)
+						// Reserve additional stock
+						queryUpdateStock := `
+							UPDATE items
+							SET stock_reserved = stock_reserved + $1
+							WHERE id = $2
+						`
+						_, err = tx.ExecContext(ctx, queryUpdateStock, qtyDiff, itemID)
+						if err != nil {
+							log.Printf("❌ UpdateOrder: Error reserving stock: %v", err)
+							return fmt.Errorf("failed to reserve stock: %w", err)
+						}
+						itemsAdded = true
+					} else {
+						// Decrease quantity - release stock
+						queryUpdateStock := `
+							UPDATE items
+							SET stock_reserved = GREATEST(0, stock_reserved - $1)
+							WHERE id = $2
+						`
+						_, err = tx.ExecContext(ctx, queryUpdateStock, -qtyDiff, itemID)
+						if err != nil {
+							log.Printf("❌ UpdateOrder: Error releasing stock: %v", err)
+							return fmt.Errorf("failed to release stock: %w", err)
+						}
 					}
 
-					// Reserve additional stock
-					queryUpdateStock := `
-						UPDATE items
-						SET stock_reserved = stock_reserved + $1
-						WHERE id = $2
-					`
-					_, err = tx.ExecContext(ctx, queryUpdateStock, qtyDiff, itemID)
+					// Update line quantity
+					queryUpdateLine := `UPDATE reserved_order_lines SET qty = $1 WHERE id = $2`
+					_, err = tx.ExecContext(ctx, queryUpdateLine, reqLine.Qty, cl.id)
 					if err != nil {
-						log.Printf("❌ UpdateOrder: Error reserving stock: %v", err)
-						return nil, fmt.Errorf("failed to reserve stock: %w", err)
+						log.Printf("❌ UpdateOrder: Error updating line: %v", err)
+						return fmt.Errorf("failed to update line: %w", err)
 					}
-				} else {
-					// Decrease quantity - release stock
-					queryUpdateStock := `
-						UPDATE items
-						SET stock_reserved = GREATEST(0, stock_reserved - $1)
-						WHERE id = $2
-					`
-					_, err = tx.ExecContext(ctx, queryUpdateStock, -qtyDiff, itemID)
-					if err != nil {
-						log.Printf("❌ UpdateOrder: Error releasing stock: %v", err)
-						return nil, fmt.Errorf("failed to release stock: %w", err)
+				}
+			} else {
+				// Add new line
+				log.Printf("➕ UpdateOrder: Adding new line for item_id=%d (qty=%d)", itemID, reqLine.Qty)
+
+				// Validate item exists and get price
+				var stockTotal, stockReserved int
+				var itemPrice int64
+				var isActive bool
+				var itemSize string
+				var hoodieType string
+				queryItem := `
+					SELECT i.stock_total, i.stock_reserved, i.price, i.is_active, i.size,
+					       COALESCE(da.hoodie_type, '') as hoodie_type
+					FROM items i
+					INNER JOIN design_assets da ON i.design_asset_id = da.id
+					WHERE i.id = $1
+					FOR UPDATE
+				`
+				err = tx.QueryRowContext(ctx, queryItem, itemID).Scan(&stockTotal, &stockReserved, &itemPrice, &isActive, &itemSize, &hoodieType)
+				if err != nil {
+					if err == sql.ErrNoRows {
+						log.Printf("❌ UpdateOrder: Item not found: id=%d", itemID)
+						return fmt.Errorf("item not found: id=%d", itemID)
 					}
+					log.Printf("❌ UpdateOrder: Error fetching item: %v", err)
+					return fmt.Errorf("failed to fetch item: %w", err)
+				}
+
+				if !isActive {
+					log.Printf("❌ UpdateOrder: Item is not active: id=%d", itemID)
+					return fmt.Errorf("item not found or inactive: id=%d", itemID)
 				}
 
-				// Update line quantity
-				queryUpdateLine := `UPDATE reserved_order_lines SET qty = $1 WHERE id = $2`
-				_, err = tx.ExecContext(ctx, queryUpdateLine, reqLine.Qty, cl.id)
+				// Validate stock availability
+				available := stockTotal - stockReserved
+				if available < reqLine.Qty {
+					log.Printf("❌ UpdateOrder: Insufficient stock: available=%d, requested=%d", available, reqLine.Qty)
+					return fmt.Errorf("insufficient stock: available %d, requested %d", available, reqLine.Qty)
+				}
+
+				// NOTE: Pricing is NOT calculated here. Prices will be calculated dynamically when querying the order.
+				// Set unit_price to 0 as placeholder - it will be calculated on-read for "reserved" orders
+				placeholderPrice := int64(0)
+				log.Printf("💰 UpdateOrder: Not calculating price here - will be calculated on-read. Using placeholder price: %d", placeholderPrice)
+
+				// Insert line (custom_code is NULL for UpdateOrder as it's not provided in the request)
+				queryInsertLine := `
+					INSERT INTO reserved_order_lines (reserved_order_id, item_id, qty, unit_price, custom_code)
+					VALUES ($1, $2, $3, $4, $5)
+				`
+				_, err = tx.ExecContext(ctx, queryInsertLine, req.ID, itemID, reqLine.Qty, placeholderPrice, nil)
 				if err != nil {
-					log.Printf("❌ UpdateOrder: Error updating line: %v", err)
-					return nil, fmt.Errorf("failed to update line: %w", err)
+					log.Printf("❌ UpdateOrder: Error inserting line: %v", err)
+					return fmt.Errorf("failed to insert line: %w", err)
 				}
-			}
-		} else {
-			// Add new line
-			log.Printf("➕ UpdateOrder: Adding new line for item_id=%d (qty=%d)", itemID, reqLine.Qty)
-
-			// Validate item exists and get price
-			var stockTotal, stockReserved int
-			var itemPrice int64
-			var isActive bool
-			var itemSize string
-			var hoodieType string
-			queryItem := `
-				SELECT i.stock_total, i.stock_reserved, i.price, i.is_active, i.size,
-				       COALESCE(da.hoodie_type, '') as hoodie_type
-				FROM items i
-				INNER JOIN design_assets da ON i.design_asset_id = da.id
-				WHERE i.id = $1
-				FOR UPDATE
-			`
-			err = tx.QueryRowContext(ctx, queryItem, itemID).Scan(&stockTotal, &stockReserved, &itemPrice, &isActive, &itemSize, &hoodieType)
-			if err != nil {
-				if err == sql.ErrNoRows {
-					log.Printf("❌ UpdateOrder: Item not found: id=%d", itemID)
-					return nil, fmt.Errorf("item not found: id=%d", itemID)
+
+				// Reserve stock
+				queryUpdateStock := `
+					UPDATE items
+					SET stock_reserved = stock_reserved + $1
+					WHERE id = $2
+				`
+				_, err = tx.ExecContext(ctx, queryUpdateStock, reqLine.Qty, itemID)
+				if err != nil {
+					log.Printf("❌ UpdateOrder: Error reserving stock: %v", err)
+					return fmt.Errorf("failed to reserve stock: %w", err)
 				}
-				log.Printf("❌ UpdateOrder: Error fetching item: %v", err)
-				return nil, fmt.Errorf("failed to fetch item: %w", err)
+				itemsAdded = true
 			}
+		}
 
-			if !isActive {
-				log.Printf("❌ UpdateOrder: Item is not active: id=%d", itemID)
-				return nil, fmt.Errorf("item not found or inactive: id=%d", itemID)
+		// Adding items pushes the hold forward, as in AddItem
+		if itemsAdded {
+			if err := extendOrderHold(ctx, tx, req.ID); err != nil {
+				log.Printf("❌ UpdateOrder: Error extending hold: %v", err)
+				return fmt.Errorf("failed to extend order hold: %w", err)
 			}
+		}
 
-			// Validate stock availability
-			available := stockTotal - stockReserved
-			if available < reqLine.Qty {
-				log.Printf("❌ UpdateOrder: Insufficient stock: available=%d, requested=%d", available, reqLine.Qty)
-				return nil, fmt.Errorf("insufficient stock: available %d, requested %d", available, reqLine.Qty)
-			}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	events.PublishOrderChange(req.ID, events.OrderUpdated)
 
-			// NOTE: Pricing is NOT calculated here. Prices will be calculated dynamically when querying the order.
-			// Set unit_price to 0 as placeholder - it will be calculated on-read for "reserved" orders
-			placeholderPrice := int64(0)
-			log.Printf("💰 UpdateOrder: Not calculating price here - will be calculated on-read. Using placeholder price: %d", placeholderPrice)
+	// Fetch updated order with lines
+	log.Printf("✅ UpdateOrder: Successfully updated order_id=%d", req.ID)
+	return r.GetByID(ctx, req.ID)
+}
 
-			// Insert line (custom_code is NULL for UpdateOrder as it's not provided in the request)
-			queryInsertLine := `
-				INSERT INTO reserved_order_lines (reserved_order_id, item_id, qty, unit_price, custom_code)
-				VALUES ($1, $2, $3, $4, $5)
-			`
-			_, err = tx.ExecContext(ctx, queryInsertLine, req.ID, itemID, reqLine.Qty, placeholderPrice, nil)
-			if err != nil {
-				log.Printf("❌ UpdateOrder: Error inserting line: %v", err)
-				return nil, fmt.Errorf("failed to insert line: %w", err)
-			}
+// PatchOrder updates only the provided scalar fields of a reserved order (assignedTo, customerName,
+// customerPhone, notes, orderType). Unlike UpdateOrder it never touches lines or stock
+func (r *ReservedOrderRepository) PatchOrder(ctx context.Context, id int64, req *models.PatchReservedOrderRequest) (*models.ReservedOrderResponse, error) {
+	log.Printf("📦 PatchOrder: Patching order_id=%d", id)
 
-			// Reserve stock
-			queryUpdateStock := `
-				UPDATE items
-				SET stock_reserved = stock_reserved + $1
-				WHERE id = $2
-			`
-			_, err = tx.ExecContext(ctx, queryUpdateStock, reqLine.Qty, itemID)
-			if err != nil {
-				log.Printf("❌ UpdateOrder: Error reserving stock: %v", err)
-				return nil, fmt.Errorf("failed to reserve stock: %w", err)
+	if req.AssignedTo == nil && req.CustomerName == nil && req.CustomerPhone == nil && req.Notes == nil && req.OrderType == nil {
+		log.Printf("❌ PatchOrder: No fields to update")
+		return nil, fmt.Errorf("at least one field is required")
+	}
+	if req.AssignedTo != nil && strings.TrimSpace(*req.AssignedTo) == "" {
+		return nil, fmt.Errorf("assignedTo cannot be empty")
+	}
+	if req.OrderType != nil && strings.TrimSpace(*req.OrderType) == "" {
+		return nil, fmt.Errorf("orderType cannot be empty")
+	}
+	expectedUpdatedAt, err := parseExpectedUpdatedAt(req.ExpectedUpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = withTx(ctx, func(tx *sql.Tx) error {
+		var currentStatus string
+		err := tx.QueryRowContext(ctx, `SELECT status FROM reserved_orders WHERE id = $1 FOR UPDATE`, id).Scan(&currentStatus)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Printf("❌ PatchOrder: Order not found: id=%d", id)
+				return fmt.Errorf("order not found")
 			}
+			log.Printf("❌ PatchOrder: Error fetching order: %v", err)
+			return fmt.Errorf("failed to fetch order: %w", err)
+		}
+		if currentStatus != "reserved" {
+			log.Printf("❌ PatchOrder: Order not in reserved status: status=%s", currentStatus)
+			return fmt.Errorf("order not in reserved status")
+		}
+
+		// Omitted fields keep their value; optional text fields set to "" are cleared
+		queryPatchOrder := `
+			UPDATE reserved_orders
+			SET assigned_to = COALESCE($2, assigned_to),
+			    order_type = COALESCE($3, order_type),
+			    customer_name = CASE WHEN $4 THEN $5 ELSE customer_name END,
+			    customer_phone = CASE WHEN $6 THEN $7 ELSE customer_phone END,
+			    notes = CASE WHEN $8 THEN $9 ELSE notes END,
+			    updated_at = NOW()
+			WHERE id = $1
+			  AND ($10::timestamptz IS NULL OR updated_at = $10)
+		`
+		result, err := tx.ExecContext(ctx, queryPatchOrder,
+			id,
+			trimmedNullString(req.AssignedTo),
+			trimmedNullString(req.OrderType),
+			req.CustomerName != nil,
+			trimmedNullString(req.CustomerName),
+			req.CustomerPhone != nil,
+			trimmedNullString(req.CustomerPhone),
+			req.Notes != nil,
+			trimmedNullString(req.Notes),
+			expectedUpdatedAt,
+		)
+		if err != nil {
+			log.Printf("❌ PatchOrder: Error updating order: %v", err)
+			return fmt.Errorf("failed to update order: %w", err)
 		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			log.Printf("❌ PatchOrder: Order changed since expectedUpdatedAt=%s: id=%d", expectedUpdatedAt.Time.Format(time.RFC3339Nano), id)
+			return fmt.Errorf("order changed: reload it and try again")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	events.PublishOrderChange(id, events.OrderUpdated)
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		log.Printf("❌ UpdateOrder: Error committing transaction: %v", err)
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	log.Printf("✅ PatchOrder: Successfully patched order_id=%d", id)
+	return r.GetByID(ctx, id)
+}
+
+// parseExpectedUpdatedAt parses the optional expectedUpdatedAt of an order edit (RFC3339).
+// An invalid NullTime means the edit is not conditioned on updated_at
+func parseExpectedUpdatedAt(value *string) (sql.NullTime, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return sql.NullTime{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(*value))
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("invalid expectedUpdatedAt: must be an RFC3339 timestamp")
 	}
+	return sql.NullTime{Time: parsed, Valid: true}, nil
+}
 
-	// Fetch updated order with lines
-	log.Printf("✅ UpdateOrder: Successfully updated order_id=%d", req.ID)
-	return r.GetByID(ctx, req.ID)
+// trimmedNullString trims value, mapping a nil or empty value to NULL
+func trimmedNullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	trimmed := strings.TrimSpace(*value)
+	return sql.NullString{String: trimmed, Valid: trimmed != ""}
 }
 
+// ClaimStock moves reserved units of an item from a lower-priority order (donor) to a higher-priority
+// order (claimant) atomically, recording an audit entry. Overall stock_reserved does not change,
+// the reservation just changes owner.
+func (r *ReservedOrderRepository) ClaimStock(ctx context.Context, orderID int64, req *models.ClaimStockRequest) (*models.ReservedOrderStockClaim, error) {
+	log.Printf("📦 ClaimStock: order_id=%d claiming item_id=%d, qty=%d from order_id=%d", orderID, req.ItemID, req.Qty, req.FromOrderID)
+
+	if req.FromOrderID <= 0 {
+		return nil, fmt.Errorf("fromOrderId is required")
+	}
+	if req.ConfirmFromOrderID != req.FromOrderID {
+		return nil, fmt.Errorf("confirmFromOrderId must match fromOrderId")
+	}
+	if req.FromOrderID == orderID {
+		return nil, fmt.Errorf("cannot claim stock from the same order")
+	}
+	if req.ItemID <= 0 {
+		return nil, fmt.Errorf("itemId is required")
+	}
+	if req.Qty <= 0 {
+		return nil, fmt.Errorf("qty must be greater than 0")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	// Start transaction
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ ClaimStock: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock both orders in id order to avoid deadlocks between opposite claims
+	queryOrders := `
+		SELECT id, status, priority
+		FROM reserved_orders
+		WHERE id IN ($1, $2)
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, queryOrders, orderID, req.FromOrderID)
+	if err != nil {
+		log.Printf("❌ ClaimStock: Error fetching orders: %v", err)
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	statuses := make(map[int64]string)
+	priorities := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var status, priority string
+		if err := rows.Scan(&id, &status, &priority); err != nil {
+			rows.Close()
+			log.Printf("❌ ClaimStock: Error scanning order: %v", err)
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		statuses[id] = status
+		priorities[id] = priority
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		log.Printf("❌ ClaimStock: Error iterating orders: %v", err)
+		return nil, fmt.Errorf("failed to iterate orders: %w", err)
+	}
+	rows.Close()
+
+	if _, ok := statuses[orderID]; !ok {
+		log.Printf("❌ ClaimStock: Order not found: id=%d", orderID)
+		return nil, fmt.Errorf("order not found")
+	}
+	if _, ok := statuses[req.FromOrderID]; !ok {
+		log.Printf("❌ ClaimStock: Donor order not found: id=%d", req.FromOrderID)
+		return nil, fmt.Errorf("donor order not found")
+	}
+	if statuses[orderID] != "reserved" || statuses[req.FromOrderID] != "reserved" {
+		log.Printf("❌ ClaimStock: Orders not in reserved status: claimant=%s, donor=%s", statuses[orderID], statuses[req.FromOrderID])
+		return nil, fmt.Errorf("order not in reserved status")
+	}
+	if orderPriorityRank(priorities[orderID]) <= orderPriorityRank(priorities[req.FromOrderID]) {
+		log.Printf("❌ ClaimStock: Claimant priority %s is not higher than donor priority %s", priorities[orderID], priorities[req.FromOrderID])
+		return nil, fmt.Errorf("claimant order priority must be higher than donor order priority")
+	}
+
+	// Lock the donor line
+	var donorLineID int64
+	var donorQty int
+	queryDonorLine := `
+		SELECT id, qty
+		FROM reserved_order_lines
+		WHERE reserved_order_id = $1 AND item_id = $2
+		FOR UPDATE
+	`
+	err = tx.QueryRowContext(ctx, queryDonorLine, req.FromOrderID, req.ItemID).Scan(&donorLineID, &donorQty)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ ClaimStock: Item not found in donor order: item_id=%d, order_id=%d", req.ItemID, req.FromOrderID)
+			return nil, fmt.Errorf("item not found in donor order")
+		}
+		log.Printf("❌ ClaimStock: Error fetching donor line: %v", err)
+		return nil, fmt.Errorf("failed to fetch donor line: %w", err)
+	}
+
+	if req.Qty > donorQty {
+		log.Printf("❌ ClaimStock: Insufficient reserved qty in donor order: reserved=%d, requested=%d", donorQty, req.Qty)
+		return nil, fmt.Errorf("insufficient reserved qty in donor order: reserved %d, requested %d", donorQty, req.Qty)
+	}
+
+	// Release units from the donor order (delete the line when nothing is left)
+	if req.Qty == donorQty {
+		_, err = tx.ExecContext(ctx, `DELETE FROM reserved_order_lines WHERE id = $1`, donorLineID)
+	} else {
+		_, err = tx.ExecContext(ctx, `UPDATE reserved_order_lines SET qty = qty - $1 WHERE id = $2`, req.Qty, donorLineID)
+	}
+	if err != nil {
+		log.Printf("❌ ClaimStock: Error releasing donor line: %v", err)
+		return nil, fmt.Errorf("failed to release donor line: %w", err)
+	}
+
+	// Grant units to the claimant order (same placeholder price as AddItem, priced on-read)
+	queryUpsertLine := `
+		INSERT INTO reserved_order_lines (reserved_order_id, item_id, qty, unit_price)
+		VALUES ($1, $2, $3, 0)
+		ON CONFLICT (reserved_order_id, item_id)
+		DO UPDATE SET qty = reserved_order_lines.qty + EXCLUDED.qty
+	`
+	_, err = tx.ExecContext(ctx, queryUpsertLine, orderID, req.ItemID, req.Qty)
+	if err != nil {
+		log.Printf("❌ ClaimStock: Error upserting claimant line: %v", err)
+		return nil, fmt.Errorf("failed to upsert claimant line: %w", err)
+	}
+
+	if err := extendOrderHold(ctx, tx, orderID); err != nil {
+		log.Printf("❌ ClaimStock: Error extending hold: %v", err)
+		return nil, fmt.Errorf("failed to extend order hold: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE reserved_orders SET updated_at = NOW() WHERE id IN ($1, $2)`, orderID, req.FromOrderID)
+	if err != nil {
+		log.Printf("❌ ClaimStock: Error touching orders: %v", err)
+		return nil, fmt.Errorf("failed to update orders: %w", err)
+	}
+
+	// Audit entry
+	var claim models.ReservedOrderStockClaim
+	queryAudit := `
+		INSERT INTO reserved_order_stock_claims (claimant_order_id, donor_order_id, item_id, qty, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, claimant_order_id, donor_order_id, item_id, qty, reason, created_at
+	`
+	err = tx.QueryRowContext(ctx, queryAudit, orderID, req.FromOrderID, req.ItemID, req.Qty, reason).Scan(
+		&claim.ID,
+		&claim.ClaimantOrderID,
+		&claim.DonorOrderID,
+		&claim.ItemID,
+		&claim.Qty,
+		&claim.Reason,
+		&claim.CreatedAt,
+	)
+	if err != nil {
+		log.Printf("❌ ClaimStock: Error inserting audit entry: %v", err)
+		return nil, fmt.Errorf("failed to insert stock claim: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ ClaimStock: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	events.PublishOrderChange(orderID, events.OrderItemsAdded)
+	events.PublishOrderChange(req.FromOrderID, events.OrderItemsRemoved)
+
+	log.Printf("✅ ClaimStock: Successfully moved %d units of item_id=%d from order_id=%d to order_id=%d (claim_id=%d)",
+		req.Qty, req.ItemID, req.FromOrderID, orderID, claim.ID)
+	return &claim, nil
+}
+
+// MergeOrders moves every line of the source order into the target order and cancels the source.
+// Stock is already reserved by the source lines, so stock_reserved is left untouched: overlapping
+// items just sum their qty on the target line and canceling the source does not release anything.
+// Both orders must be in 'reserved' status and assigned.
+func (r *ReservedOrderRepository) MergeOrders(ctx context.Context, targetID, sourceID int64) (*models.ReservedOrderResponse, error) {
+	log.Printf("📦 MergeOrders: Merging order_id=%d into order_id=%d", sourceID, targetID)
+
+	if sourceID <= 0 {
+		return nil, fmt.Errorf("sourceOrderId is required")
+	}
+	if sourceID == targetID {
+		return nil, fmt.Errorf("cannot merge an order into itself")
+	}
+
+	// Start transaction
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock both orders in id order to avoid deadlocks between opposite merges
+	queryOrders := `
+		SELECT id, status, assigned_to
+		FROM reserved_orders
+		WHERE id IN ($1, $2)
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, queryOrders, targetID, sourceID)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Error fetching orders: %v", err)
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	statuses := make(map[int64]string)
+	assignees := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var status, assignedTo string
+		if err := rows.Scan(&id, &status, &assignedTo); err != nil {
+			rows.Close()
+			log.Printf("❌ MergeOrders: Error scanning order: %v", err)
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		statuses[id] = status
+		assignees[id] = assignedTo
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		log.Printf("❌ MergeOrders: Error iterating orders: %v", err)
+		return nil, fmt.Errorf("failed to iterate orders: %w", err)
+	}
+	rows.Close()
+
+	if _, ok := statuses[targetID]; !ok {
+		log.Printf("❌ MergeOrders: Order not found: id=%d", targetID)
+		return nil, fmt.Errorf("order not found")
+	}
+	if _, ok := statuses[sourceID]; !ok {
+		log.Printf("❌ MergeOrders: Source order not found: id=%d", sourceID)
+		return nil, fmt.Errorf("source order not found")
+	}
+	if statuses[targetID] != "reserved" || statuses[sourceID] != "reserved" {
+		log.Printf("❌ MergeOrders: Orders not in reserved status: target=%s, source=%s", statuses[targetID], statuses[sourceID])
+		return nil, fmt.Errorf("order not in reserved status")
+	}
+	if strings.TrimSpace(assignees[targetID]) == "" || strings.TrimSpace(assignees[sourceID]) == "" {
+		log.Printf("❌ MergeOrders: Orders not assigned: target=%q, source=%q", assignees[targetID], assignees[sourceID])
+		return nil, fmt.Errorf("order not assigned")
+	}
+
+	// Move the source lines into the target (same placeholder price as AddItem, priced on-read).
+	// Overlapping items sum their qty and keep the target's custom code when it has one.
+	queryMergeLines := `
+		INSERT INTO reserved_order_lines (reserved_order_id, item_id, qty, unit_price, custom_code)
+		SELECT $1, item_id, qty, 0, custom_code
+		FROM reserved_order_lines
+		WHERE reserved_order_id = $2
+		ON CONFLICT (reserved_order_id, item_id)
+		DO UPDATE SET qty = reserved_order_lines.qty + EXCLUDED.qty,
+		              custom_code = COALESCE(reserved_order_lines.custom_code, EXCLUDED.custom_code)
+	`
+	result, err := tx.ExecContext(ctx, queryMergeLines, targetID, sourceID)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Error merging lines: %v", err)
+		return nil, fmt.Errorf("failed to merge order lines: %w", err)
+	}
+	movedLines, _ := result.RowsAffected()
+
+	if movedLines > 0 {
+		if err := extendOrderHold(ctx, tx, targetID); err != nil {
+			log.Printf("❌ MergeOrders: Error extending hold: %v", err)
+			return nil, fmt.Errorf("failed to extend order hold: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM reserved_order_lines WHERE reserved_order_id = $1`, sourceID)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Error deleting source lines: %v", err)
+		return nil, fmt.Errorf("failed to delete source order lines: %w", err)
+	}
+
+	// Cancel the now-empty source without releasing stock (the units now belong to the target)
+	_, err = tx.ExecContext(ctx, `UPDATE reserved_orders SET status = 'canceled', updated_at = NOW() WHERE id = $1`, sourceID)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Error canceling source order: %v", err)
+		return nil, fmt.Errorf("failed to cancel source order: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE reserved_orders SET updated_at = NOW() WHERE id = $1`, targetID)
+	if err != nil {
+		log.Printf("❌ MergeOrders: Error touching target order: %v", err)
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ MergeOrders: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	events.PublishOrderChange(targetID, events.OrderItemsAdded)
+	events.PublishOrderChange(sourceID, events.OrderCanceled)
+
+	log.Printf("✅ MergeOrders: Moved %d lines from order_id=%d into order_id=%d", movedLines, sourceID, targetID)
+	return r.GetByID(ctx, targetID)
+}
+
+// normalizeOrderPriority validates and normalizes an order priority, defaulting to "normal"
+func normalizeOrderPriority(priority string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(priority))
+	if normalized == "" {
+		return "normal", nil
+	}
+	if normalized != "normal" && normalized != "high" {
+		return "", fmt.Errorf("priority must be 'normal' or 'high'")
+	}
+	return normalized, nil
+}
+
+// normalizeOrderDiscount validates and normalizes an order-level discount, defaulting to "none".
+// A percent discount must be between 0 and 99: a sale always has something to pay, free orders
+// are recorded as gift sales.
+func normalizeOrderDiscount(discountType string, discountValue int64) (string, int64, error) {
+	normalized := strings.ToLower(strings.TrimSpace(discountType))
+	if normalized == "" {
+		normalized = "none"
+	}
+	switch normalized {
+	case "none":
+		return normalized, 0, nil
+	case "flat":
+		if discountValue < 0 {
+			return "", 0, fmt.Errorf("discountValue must be greater than or equal to 0")
+		}
+	case "percent":
+		if discountValue < 0 || discountValue >= 100 {
+			return "", 0, fmt.Errorf("discountValue must be between 0 and 99 for percent discounts")
+		}
+	default:
+		return "", 0, fmt.Errorf("invalid discountType: must be none, flat or percent")
+	}
+	return normalized, discountValue, nil
+}
+
+// stockAvailable returns the units that can still be reserved, never negative
+func stockAvailable(stockTotal, stockReserved int) int {
+	if stockTotal < stockReserved {
+		return 0
+	}
+	return stockTotal - stockReserved
+}
+
+// normalizeCouponCode trims and upper-cases a coupon code, matching how the pricing engine looks it up.
+// Codes are not validated here: unknown or expired codes are ignored by the engine when pricing.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// applyOrderDiscount applies an order-level discount to a computed total and returns the
+// discounted total and the discount amount. The total is never driven below 0.
+func applyOrderDiscount(total int64, discountType string, discountValue int64) (int64, int64) {
+	var discount int64
+	switch discountType {
+	case "flat":
+		discount = discountValue
+	case "percent":
+		discount = total * discountValue / 100
+	}
+	if discount > total {
+		discount = total
+	}
+	if discount < 0 {
+		discount = 0
+	}
+	return total - discount, discount
+}
+
+// discountPricingLines applies an order-level discount to a pricing breakdown and spreads it over
+// the breakdown's line totals, so unit prices frozen from the lines add up to the discounted total.
+// Every path that freezes prices goes through it. Returns the discounted total and the discount.
+func discountPricingLines(breakdown *models.PricingBreakdown, discountType string, discountValue int64) (int64, int64) {
+	total, discount := applyOrderDiscount(breakdown.Total, discountType, discountValue)
+	pricing.SpreadDiscount(breakdown.Lines, discount)
+	return total, discount
+}
+
+// frozenOrderTotals returns the subtotal, discount and total of a completed order. Its unit prices
+// were frozen with the order-level discount already spread over them, so the discount is not applied
+// again: it is reported as what the lines' list prices exceed the stored total by.
+func frozenOrderTotals(listTotal, storedTotal int64) (int64, int64, int64) {
+	if listTotal < storedTotal {
+		return storedTotal, 0, storedTotal
+	}
+	return listTotal, listTotal - storedTotal, storedTotal
+}
+
+// orderPriorityRank returns a comparable rank for an order priority
+func orderPriorityRank(priority string) int {
+	if priority == "high" {
+		return 1
+	}
+	return 0
+}
+
+// SummaryBySeller counts the reserved orders of each seller (assigned_to) and sums their totals,
+// priced with the pricing engine like GetAllWithFullItems (stored prices when the engine is not
+// available or fails for an order), without loading the order lines. Unlike GetAllWithFullItems it
+// never writes order_type back.
+func (r *ReservedOrderRepository) SummaryBySeller(ctx context.Context) (*models.SellerCartSummaryResponse, error) {
+	log.Printf("📦 SummaryBySeller: Summarizing reserved orders by seller")
+
+	query := `
+		SELECT ro.id, ro.assigned_to, ro.discount_type, ro.discount_value,
+		       COALESCE(SUM(rol.qty * rol.unit_price), 0) as stored_total
+		FROM reserved_orders ro
+		LEFT JOIN reserved_order_lines rol ON rol.reserved_order_id = ro.id
+		WHERE ro.status = 'reserved'
+		GROUP BY ro.id, ro.assigned_to, ro.discount_type, ro.discount_value
+		ORDER BY ro.id ASC
+	`
+	rows, err := db.DB.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("❌ SummaryBySeller: Error fetching orders: %v", err)
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	type openOrder struct {
+		id            int64
+		assignedTo    string
+		discountType  string
+		discountValue int64
+		storedTotal   int64
+	}
+	var orders []openOrder
+	for rows.Next() {
+		var o openOrder
+		if err := rows.Scan(&o.id, &o.assignedTo, &o.discountType, &o.discountValue, &o.storedTotal); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate orders: %w", err)
+	}
+
+	response := &models.SellerCartSummaryResponse{Sellers: []models.SellerCartSummary{}}
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil && len(orders) > 0 {
+		log.Printf("⚠️ SummaryBySeller: Pricing engine not initialized, using stored prices")
+		response.Warnings = append(response.Warnings, "pricing engine not initialized: totals use stored prices")
+	}
+
+	sellerIndex := make(map[string]int)
+	for _, o := range orders {
+		total := o.storedTotal
+		if pricingEngine != nil {
+			breakdown, err := pricingEngine.CalculateOrderPricing(ctx, o.id)
+			if err != nil {
+				log.Printf("⚠️ SummaryBySeller: Error pricing order id=%d, using stored prices: %v", o.id, err)
+				response.Warnings = append(response.Warnings, fmt.Sprintf("pricing failed for order %d: total uses stored prices", o.id))
+			} else {
+				total = breakdown.Total
+			}
+		}
+		total, _ = applyOrderDiscount(total, o.discountType, o.discountValue)
+
+		i, exists := sellerIndex[o.assignedTo]
+		if !exists {
+			i = len(response.Sellers)
+			sellerIndex[o.assignedTo] = i
+			response.Sellers = append(response.Sellers, models.SellerCartSummary{AssignedTo: o.assignedTo})
+		}
+		response.Sellers[i].OrderCount++
+		response.Sellers[i].TotalValue += total
+		response.OrderCount++
+		response.TotalValue += total
+	}
+
+	sort.SliceStable(response.Sellers, func(i, j int) bool {
+		if response.Sellers[i].TotalValue != response.Sellers[j].TotalValue {
+			return response.Sellers[i].TotalValue > response.Sellers[j].TotalValue
+		}
+		return response.Sellers[i].AssignedTo < response.Sellers[j].AssignedTo
+	})
+
+	log.Printf("✅ SummaryBySeller: %d orders across %d sellers", response.OrderCount, len(response.Sellers))
+	return response, nil
+}
+
+// GetPickingList aggregates line quantities per item (and custom code) across orders matching
+// the given status and, optionally, assigned user. Items are sorted by SKU for a single warehouse pass.
+func (r *ReservedOrderRepository) GetPickingList(ctx context.Context, assignedTo *string, status string) (*models.PickingListResponse, error) {
+	log.Printf("📦 GetPickingList: Building picking list (assignedTo=%v, status=%s)", assignedTo, status)
+
+	query := `
+		SELECT rol.reserved_order_id, rol.qty, rol.custom_code,
+		       i.id, i.sku, i.size, i.price, i.stock_total, i.stock_reserved, i.design_asset_id,
+		       COALESCE(da.description, '') as description,
+		       COALESCE(da.color_primary, '') as color_primary,
+		       COALESCE(da.color_secondary, '') as color_secondary,
+		       COALESCE(da.hoodie_type, '') as hoodie_type,
+		       COALESCE(da.image_type, '') as image_type,
+		       COALESCE(da.deco_id, '') as deco_id,
+		       COALESCE(da.deco_base, '') as deco_base
+		FROM reserved_order_lines rol
+		INNER JOIN reserved_orders ro ON rol.reserved_order_id = ro.id
+		INNER JOIN items i ON rol.item_id = i.id
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE ro.status = $1
+	`
+	args := []interface{}{status}
+	if assignedTo != nil && *assignedTo != "" {
+		query += ` AND ro.assigned_to = $2`
+		args = append(args, *assignedTo)
+	}
+	query += ` ORDER BY i.sku ASC, i.id ASC, rol.custom_code ASC NULLS FIRST, ro.id ASC`
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("❌ GetPickingList: Error fetching lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch order lines: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.PickingListResponse{
+		Status: status,
+		Items:  []models.PickingListItem{},
+	}
+	if assignedTo != nil {
+		response.AssignedTo = *assignedTo
+	}
+
+	// key: item_id + custom_code
+	indexByKey := make(map[string]int)
+	orders := make(map[int64]bool)
+
+	for rows.Next() {
+		var orderID int64
+		var qty int
+		var customCode sql.NullString
+		var item models.ItemFullInfo
+
+		err := rows.Scan(
+			&orderID,
+			&qty,
+			&customCode,
+			&item.ID,
+			&item.SKU,
+			&item.Size,
+			&item.Price,
+			&item.StockTotal,
+			&item.StockReserved,
+			&item.DesignAssetID,
+			&item.Description,
+			&item.ColorPrimary,
+			&item.ColorSecondary,
+			&item.HoodieType,
+			&item.ImageType,
+			&item.DecoID,
+			&item.DecoBase,
+		)
+		if err != nil {
+			log.Printf("❌ GetPickingList: Error scanning line: %v", err)
+			return nil, fmt.Errorf("failed to scan order line: %w", err)
+		}
+		item.StockAvailable = stockAvailable(item.StockTotal, item.StockReserved)
+
+		key := fmt.Sprintf("%d|%s", item.ID, customCode.String)
+		idx, exists := indexByKey[key]
+		if !exists {
+			entry := models.PickingListItem{
+				Item:     item,
+				OrderIDs: []int64{},
+			}
+			if customCode.Valid && customCode.String != "" {
+				code := customCode.String
+				entry.CustomCode = &code
+			}
+			response.Items = append(response.Items, entry)
+			idx = len(response.Items) - 1
+			indexByKey[key] = idx
+		}
+
+		entry := &response.Items[idx]
+		entry.Qty += qty
+		if n := len(entry.OrderIDs); n == 0 || entry.OrderIDs[n-1] != orderID {
+			entry.OrderIDs = append(entry.OrderIDs, orderID)
+		}
+		response.TotalUnits += qty
+		orders[orderID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ GetPickingList: Error iterating lines: %v", err)
+		return nil, fmt.Errorf("failed to iterate order lines: %w", err)
+	}
+
+	response.OrderCount = len(orders)
+
+	log.Printf("✅ GetPickingList: %d distinct items, %d units across %d orders", len(response.Items), response.TotalUnits, response.OrderCount)
+	return response, nil
+}
+
+// GetCompletionImpact previews, without modifying anything, how completing an order would affect stock:
+// per item the current stock, the post-completion stock_total and whether it goes low or hits zero.
+// Quantities of the same item across several lines (e.g. custom variants) are combined.
+func (r *ReservedOrderRepository) GetCompletionImpact(ctx context.Context, orderID int64, threshold int) (*models.CompletionImpactResponse, error) {
+	log.Printf("📦 GetCompletionImpact: order id=%d, threshold=%d", orderID, threshold)
+
+	response := &models.CompletionImpactResponse{
+		OrderID:           orderID,
+		LowStockThreshold: threshold,
+		Lines:             []models.CompletionImpactLine{},
+		CanComplete:       true,
+	}
+
+	err := db.DB.QueryRowContext(ctx, `SELECT status FROM reserved_orders WHERE id = $1`, orderID).Scan(&response.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ GetCompletionImpact: Order not found: id=%d", orderID)
+			return nil, fmt.Errorf("order not found")
+		}
+		log.Printf("❌ GetCompletionImpact: Error fetching order: %v", err)
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+
+	if response.Status != "reserved" {
+		log.Printf("❌ GetCompletionImpact: Order not in reserved status: status=%s", response.Status)
+		return nil, fmt.Errorf("order not in reserved status")
+	}
+
+	query := `
+		SELECT SUM(rol.qty) as qty,
+		       i.id, i.sku, i.size, i.price, i.stock_total, i.stock_reserved, i.design_asset_id,
+		       COALESCE(da.description, '') as description,
+		       COALESCE(da.color_primary, '') as color_primary,
+		       COALESCE(da.color_secondary, '') as color_secondary,
+		       COALESCE(da.hoodie_type, '') as hoodie_type,
+		       COALESCE(da.image_type, '') as image_type,
+		       COALESCE(da.deco_id, '') as deco_id,
+		       COALESCE(da.deco_base, '') as deco_base
+		FROM reserved_order_lines rol
+		INNER JOIN items i ON rol.item_id = i.id
+		LEFT JOIN design_assets da ON i.design_asset_id = da.id
+		WHERE rol.reserved_order_id = $1
+		GROUP BY i.id, da.id
+		ORDER BY i.sku ASC, i.id ASC
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, orderID)
+	if err != nil {
+		log.Printf("❌ GetCompletionImpact: Error fetching lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch order lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.CompletionImpactLine
+		item := &line.Item
+		err := rows.Scan(
+			&line.Qty,
+			&item.ID,
+			&item.SKU,
+			&item.Size,
+			&item.Price,
+			&item.StockTotal,
+			&item.StockReserved,
+			&item.DesignAssetID,
+			&item.Description,
+			&item.ColorPrimary,
+			&item.ColorSecondary,
+			&item.HoodieType,
+			&item.ImageType,
+			&item.DecoID,
+			&item.DecoBase,
+		)
+		if err != nil {
+			log.Printf("❌ GetCompletionImpact: Error scanning line: %v", err)
+			return nil, fmt.Errorf("failed to scan order line: %w", err)
+		}
+		item.StockAvailable = stockAvailable(item.StockTotal, item.StockReserved)
+
+		// Completion deducts qty from stock_total and stock_reserved alike
+		line.CurrentStockTotal = item.StockTotal
+		line.CurrentStockReserved = item.StockReserved
+		line.CurrentAvailable = item.StockTotal - item.StockReserved
+		line.PostCompletionStockTotal = item.StockTotal - line.Qty
+		line.IsLowAfterCompletion = line.PostCompletionStockTotal <= threshold
+		line.CrossesLowStock = line.CurrentStockTotal > threshold && line.IsLowAfterCompletion
+		line.HitsZero = line.PostCompletionStockTotal <= 0
+		line.InsufficientReserved = item.StockReserved < line.Qty
+
+		if line.CrossesLowStock {
+			response.ItemsGoingLow++
+		}
+		if line.HitsZero {
+			response.ItemsHittingZero++
+		}
+		if line.InsufficientReserved {
+			response.CanComplete = false
+		}
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ GetCompletionImpact: Error iterating lines: %v", err)
+		return nil, fmt.Errorf("failed to iterate order lines: %w", err)
+	}
+
+	log.Printf("✅ GetCompletionImpact: order id=%d, %d items, %d going low, %d hitting zero",
+		orderID, len(response.Lines), response.ItemsGoingLow, response.ItemsHittingZero)
+	return response, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckStockDeduction(t *testing.T) {
+	tests := []struct {
+		name        string
+		flow        string
+		orderStatus string
+		hasSale     bool
+		wantErr     string
+	}{
+		{name: "complete a reserved order", flow: stockDeductionComplete, orderStatus: "reserved"},
+		{name: "sell a reserved order", flow: stockDeductionSell, orderStatus: "reserved"},
+		{
+			name:        "sell after complete",
+			flow:        stockDeductionSell,
+			orderStatus: "completed",
+			wantErr:     "already completed without a sale",
+		},
+		{
+			name:        "complete after sell",
+			flow:        stockDeductionComplete,
+			orderStatus: "completed",
+			hasSale:     true,
+			wantErr:     "already has a sale",
+		},
+		{
+			name:        "sell twice",
+			flow:        stockDeductionSell,
+			orderStatus: "completed",
+			hasSale:     true,
+			wantErr:     "already has a sale",
+		},
+		{
+			name:        "complete twice",
+			flow:        stockDeductionComplete,
+			orderStatus: "completed",
+			wantErr:     "not in reserved status",
+		},
+		{
+			name:        "reserved order with a leftover sale",
+			flow:        stockDeductionComplete,
+			orderStatus: "reserved",
+			hasSale:     true,
+			wantErr:     "already has a sale",
+		},
+		{
+			name:        "sell a canceled order",
+			flow:        stockDeductionSell,
+			orderStatus: "canceled",
+			wantErr:     "not in reserved status",
+		},
+		{
+			name:        "complete a canceled order",
+			flow:        stockDeductionComplete,
+			orderStatus: "canceled",
+			wantErr:     "not in reserved status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStockDeduction(tt.flow, tt.orderStatus, tt.hasSale)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkStockDeduction(%q, %q, %v) = %v, want nil", tt.flow, tt.orderStatus, tt.hasSale, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkStockDeduction(%q, %q, %v) = %v, want error containing %q", tt.flow, tt.orderStatus, tt.hasSale, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewOrderHoldUntil covers the hold given to an order that goes back to 'reserved' (Reopen,
+// VoidSale): it must lie in the future so the hold worker does not cancel the order right away
+func TestNewOrderHoldUntil(t *testing.T) {
+	tests := []struct {
+		name      string
+		hoursEnv  string
+		wantValid bool
+		wantHold  time.Duration
+	}{
+		{name: "default hold", hoursEnv: "", wantValid: true, wantHold: 24 * time.Hour},
+		{name: "configured hold", hoursEnv: "48", wantValid: true, wantHold: 48 * time.Hour},
+		{name: "invalid value falls back to default", hoursEnv: "-1", wantValid: true, wantHold: 24 * time.Hour},
+		{name: "holds disabled", hoursEnv: "0", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ORDER_HOLD_HOURS", tt.hoursEnv)
+			before := time.Now()
+			got := newOrderHoldUntil()
+			after := time.Now()
+
+			if got.Valid != tt.wantValid {
+				t.Fatalf("newOrderHoldUntil().Valid = %v, want %v", got.Valid, tt.wantValid)
+			}
+			if !tt.wantValid {
+				return
+			}
+			if got.Time.Before(before.Add(tt.wantHold)) || got.Time.After(after.Add(tt.wantHold)) {
+				t.Errorf("newOrderHoldUntil() = %v, want now + %v", got.Time, tt.wantHold)
+			}
+		})
+	}
+}
+
+func TestResetOrderHoldKeepsOrdersWithoutHold(t *testing.T) {
+	if !strings.Contains(resetOrderHold, "WHEN hold_until IS NULL THEN NULL") {
+		t.Errorf("resetOrderHold = %q, want orders without a hold to keep none", resetOrderHold)
+	}
+	if !strings.Contains(resetOrderHold, "$2::timestamptz") {
+		t.Errorf("resetOrderHold = %q, want the new hold bound as $2", resetOrderHold)
+	}
+}
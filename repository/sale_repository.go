@@ -3,19 +3,26 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
 	"armario-mascota-me/db"
+	"armario-mascota-me/events"
+	"armario-mascota-me/metrics"
 	"armario-mascota-me/models"
 	"armario-mascota-me/pricing"
+	"armario-mascota-me/utils"
 )
 
 // SaleRepository handles database operations for sales
 type SaleRepository struct{}
 
+// errSaleReplayed ends the sell transaction early when the idempotency key matches an existing sale
+var errSaleReplayed = errors.New("sale replayed from idempotency key")
+
 // NewSaleRepository creates a new SaleRepository
 func NewSaleRepository() *SaleRepository {
 	return &SaleRepository{}
@@ -24,276 +31,497 @@ func NewSaleRepository() *SaleRepository {
 // Ensure SaleRepository implements SaleRepositoryInterface
 var _ SaleRepositoryInterface = (*SaleRepository)(nil)
 
-// Sell sells a reserved order by completing it, creating a sale record, and recording a financial transaction
-// All operations are performed atomically in a single transaction
-func (r *SaleRepository) Sell(ctx context.Context, reservedOrderID int64, req *models.SellRequest) (*models.Sale, error) {
-	log.Printf("📦 Sell: Selling reserved order id=%d", reservedOrderID)
-
-	// Start transaction
-	tx, err := db.DB.BeginTx(ctx, nil)
-	if err != nil {
-		log.Printf("❌ Sell: Error starting transaction: %v", err)
-		return nil, fmt.Errorf("failed to start transaction: %w", err)
-	}
-	defer tx.Rollback()
+// idempotencyKeyIndex is the unique index on sales.idempotency_key (migration 018)
+const idempotencyKeyIndex = "idx_sales_idempotency_key"
 
-	// Lock order and validate it exists and is in 'reserved' status
-	var orderStatus, customerName string
-	var customerNameNull sql.NullString
-	queryOrder := `
-		SELECT status, customer_name 
-		FROM reserved_orders 
-		WHERE id = $1 
-		FOR UPDATE
+// getSaleByIdempotencyKey returns the sale stored with key, or nil when no sale uses it.
+// queryRow is tx.QueryRowContext or db.DB.QueryRowContext
+func getSaleByIdempotencyKey(ctx context.Context, queryRow func(ctx context.Context, query string, args ...interface{}) *sql.Row, key string) (*models.Sale, error) {
+	query := `
+		SELECT id, reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, created_at, sale_type, gift_reason, COALESCE(seller, '')
+		FROM sales
+		WHERE idempotency_key = $1
 	`
-	err = tx.QueryRowContext(ctx, queryOrder, reservedOrderID).Scan(&orderStatus, &customerNameNull)
+
+	var sale models.Sale
+	var customerName, notes, giftReason sql.NullString
+	err := queryRow(ctx, query, key).Scan(
+		&sale.ID,
+		&sale.ReservedOrderID,
+		&sale.SoldAt,
+		&customerName,
+		&sale.AmountPaid,
+		&sale.PaymentMethod,
+		&sale.PaymentDestination,
+		&sale.Status,
+		&notes,
+		&sale.CreatedAt,
+		&sale.SaleType,
+		&giftReason,
+		&sale.Seller,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Printf("❌ Sell: Order not found: id=%d", reservedOrderID)
-			return nil, fmt.Errorf("order not found")
-		}
-		log.Printf("❌ Sell: Error fetching order: %v", err)
-		return nil, fmt.Errorf("failed to fetch order: %w", err)
+		return nil, fmt.Errorf("failed to fetch sale by idempotency key: %w", err)
 	}
 
-	if customerNameNull.Valid {
-		customerName = customerNameNull.String
+	if customerName.Valid {
+		sale.CustomerName = customerName.String
 	}
-
-	if orderStatus != "reserved" {
-		log.Printf("❌ Sell: Order not in reserved status: status=%s", orderStatus)
-		return nil, fmt.Errorf("order not in reserved status")
+	if notes.Valid {
+		sale.Notes = notes.String
+	}
+	if giftReason.Valid {
+		sale.GiftReason = giftReason.String
 	}
+	sale.IdempotentReplay = true
+	return &sale, nil
+}
 
-	// Check if sale already exists for this reserved_order_id
-	var existingSaleID int64
-	queryExistingSale := `SELECT id FROM sales WHERE reserved_order_id = $1`
-	err = tx.QueryRowContext(ctx, queryExistingSale, reservedOrderID).Scan(&existingSaleID)
-	if err != sql.ErrNoRows {
-		if err == nil {
-			log.Printf("❌ Sell: Sale already exists for reserved_order_id=%d, sale_id=%d", reservedOrderID, existingSaleID)
-			return nil, fmt.Errorf("order already has a sale associated")
-		}
-		log.Printf("❌ Sell: Error checking existing sale: %v", err)
-		return nil, fmt.Errorf("failed to check existing sale: %w", err)
+// replayIdempotentSale returns the original sale for a replayed idempotency key, or an error when
+// the key was used to sell a different order
+func replayIdempotentSale(sale *models.Sale, reservedOrderID int64, key string) (*models.Sale, error) {
+	if sale.ReservedOrderID != reservedOrderID {
+		log.Printf("❌ Sell: Idempotency key %q already used for order %d (request for order %d)", key, sale.ReservedOrderID, reservedOrderID)
+		return nil, fmt.Errorf("idempotency key already used for order %d", sale.ReservedOrderID)
 	}
+	log.Printf("🔁 Sell: Idempotency key %q replayed, returning sale id=%d", key, sale.ID)
+	return sale, nil
+}
+
+// Sell sells a reserved order by completing it, creating a sale record, and recording a financial transaction
+// All operations are performed atomically in a single transaction.
+// When req.IdempotencyKey is set and a sale was already stored with that key, the original sale is
+// returned (IdempotentReplay=true) instead of an error, so clients can safely retry the request.
+// Sell is the only path that both completes an order and records money; it must not be combined with
+// ReservedOrderRepository.Complete on the same order, since both deduct stock. Orders already
+// completed via Complete are rejected here instead of deducting stock a second time.
+func (r *SaleRepository) Sell(ctx context.Context, reservedOrderID int64, req *models.SellRequest) (*models.Sale, error) {
+	start := time.Now()
+	sale, err := r.sell(ctx, reservedOrderID, req)
+	metrics.SellDuration.ObserveSince(start, metrics.Result(err))
+	return sale, err
+}
+
+// sell runs the sell transaction for Sell
+func (r *SaleRepository) sell(ctx context.Context, reservedOrderID int64, req *models.SellRequest) (*models.Sale, error) {
+	utils.Logf(ctx, "📦 Sell: Selling reserved order id=%d", reservedOrderID)
 
-	// Get all lines for this order
-	queryLines := `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1`
-	rows, err := tx.QueryContext(ctx, queryLines, reservedOrderID)
+	saleType, err := normalizeSaleType(req.SaleType)
 	if err != nil {
-		log.Printf("❌ Sell: Error fetching lines: %v", err)
-		return nil, fmt.Errorf("failed to fetch order lines: %w", err)
+		utils.Logf(ctx, "❌ Sell: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	type lineInfo struct {
-		itemID int64
-		qty    int
+	isGift := saleType == "gift"
+	giftReason := strings.TrimSpace(req.Reason)
+	if isGift && giftReason == "" {
+		utils.Logf(ctx, "❌ Sell: reason is required for gift sales")
+		return nil, fmt.Errorf("reason is required for gift sales")
 	}
-	var lines []lineInfo
 
-	for rows.Next() {
-		var l lineInfo
-		if err := rows.Scan(&l.itemID, &l.qty); err != nil {
-			log.Printf("❌ Sell: Error scanning line: %v", err)
-			continue
+	idempotencyKey := strings.TrimSpace(req.IdempotencyKey)
+	var sale models.Sale
+	var replayedSale *models.Sale
+	err = withTx(ctx, func(tx *sql.Tx) error {
+		// Lock order and validate it exists and is in 'reserved' status
+		var orderStatus, customerName, discountType, assignedTo string
+		var discountValue int64
+		var customerNameNull sql.NullString
+		queryOrder := `
+			SELECT status, customer_name, discount_type, discount_value, assigned_to
+			FROM reserved_orders 
+			WHERE id = $1 
+			FOR UPDATE
+		`
+		err := tx.QueryRowContext(ctx, queryOrder, reservedOrderID).Scan(&orderStatus, &customerNameNull, &discountType, &discountValue, &assignedTo)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				utils.Logf(ctx, "❌ Sell: Order not found: id=%d", reservedOrderID)
+				return fmt.Errorf("order not found")
+			}
+			utils.Logf(ctx, "❌ Sell: Error fetching order: %v", err)
+			return fmt.Errorf("failed to fetch order: %w", err)
 		}
-		lines = append(lines, l)
-	}
 
-	if err := rows.Err(); err != nil {
-		log.Printf("❌ Sell: Error iterating lines: %v", err)
-		return nil, fmt.Errorf("failed to iterate order lines: %w", err)
-	}
+		if customerNameNull.Valid {
+			customerName = customerNameNull.String
+		}
 
-	// Calculate final pricing using pricing engine BEFORE completing the sale
-	// This will freeze the snapshot by updating unit_price in reserved_order_lines
-	pricingEngine := pricing.GetEngine()
-	var calculatedTotal int64
-	var calculatedOrderType string
+		// Replayed idempotency key: checked after locking the order, so a concurrent retry for the
+		// same order waits for the first request and then sees its sale
+		if idempotencyKey != "" {
+			existingSale, err := getSaleByIdempotencyKey(ctx, tx.QueryRowContext, idempotencyKey)
+			if err != nil {
+				utils.Logf(ctx, "❌ Sell: %v", err)
+				return err
+			}
+			if existingSale != nil {
+				replayedSale = existingSale
+				return errSaleReplayed
+			}
+		}
 
-	if pricingEngine != nil {
-		log.Printf("💰 Sell: Calculating final pricing for order %d", reservedOrderID)
-		
-		// Note: We need to use a context that can work with the transaction
-		// Since pricing engine uses db.DB directly, we'll calculate outside transaction first
-		// then update within transaction
-		breakdown, err := pricingEngine.CalculateOrderPricing(ctx, reservedOrderID)
+		// Check if sale already exists for this reserved_order_id
+		var existingSaleID int64
+		hasSale := true
+		queryExistingSale := `SELECT id FROM sales WHERE reserved_order_id = $1 AND status <> 'voided'`
+		err = tx.QueryRowContext(ctx, queryExistingSale, reservedOrderID).Scan(&existingSaleID)
+		if err == sql.ErrNoRows {
+			hasSale = false
+		} else if err != nil {
+			utils.Logf(ctx, "❌ Sell: Error checking existing sale: %v", err)
+			return fmt.Errorf("failed to check existing sale: %w", err)
+		}
+
+		// Guard against double stock deduction: an order completed via Complete already had its stock deducted
+		if err := checkStockDeduction(stockDeductionSell, orderStatus, hasSale); err != nil {
+			utils.Logf(ctx, "❌ Sell: Order id=%d cannot be sold: status=%s, sale_id=%d: %v", reservedOrderID, orderStatus, existingSaleID, err)
+			return err
+		}
+
+		// Get all lines for this order
+		queryLines := `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1`
+		rows, err := tx.QueryContext(ctx, queryLines, reservedOrderID)
 		if err != nil {
-			log.Printf("❌ Sell: Error calculating pricing: %v", err)
-			return nil, fmt.Errorf("failed to calculate pricing: %w", err)
+			utils.Logf(ctx, "❌ Sell: Error fetching lines: %v", err)
+			return fmt.Errorf("failed to fetch order lines: %w", err)
+		}
+		defer rows.Close()
+
+		type lineInfo struct {
+			itemID int64
+			qty    int
+		}
+		var lines []lineInfo
+
+		for rows.Next() {
+			var l lineInfo
+			if err := rows.Scan(&l.itemID, &l.qty); err != nil {
+				utils.Logf(ctx, "❌ Sell: Error scanning line: %v", err)
+				continue
+			}
+			lines = append(lines, l)
+		}
+
+		if err := rows.Err(); err != nil {
+			utils.Logf(ctx, "❌ Sell: Error iterating lines: %v", err)
+			return fmt.Errorf("failed to iterate order lines: %w", err)
 		}
 
-		calculatedTotal = breakdown.Total
-		calculatedOrderType = breakdown.OrderType
-		log.Printf("💰 Sell: Calculated total=%d, orderType=%s", calculatedTotal, calculatedOrderType)
+		// Calculate final pricing using pricing engine BEFORE completing the sale
+		// This will freeze the snapshot by updating unit_price in reserved_order_lines
+		pricingEngine := pricing.GetEngine()
+		var calculatedTotal, orderDiscount int64
+		var calculatedOrderType string
 
-		// Freeze snapshot: Update unit_price in reserved_order_lines with calculated prices
-		// Use effective unit price (lineTotal / qty) to include bundle contributions
-		for _, pricingLine := range breakdown.Lines {
-			// Calculate effective unit price (includes bundle contributions)
-			effectiveUnitPrice := pricingLine.UnitPrice
-			if pricingLine.Qty > 0 {
-				effectiveUnitPrice = pricingLine.LineTotal / int64(pricingLine.Qty)
+		if isGift {
+			// Gifts carry no money: freeze every line at 0 so the sale total matches its lines
+			utils.Logf(ctx, "🎁 Sell: Gift sale for order %d, freezing line prices at 0 (reason=%q)", reservedOrderID, giftReason)
+			_, err = tx.ExecContext(ctx, `UPDATE reserved_order_lines SET unit_price = 0 WHERE reserved_order_id = $1`, reservedOrderID)
+			if err != nil {
+				utils.Logf(ctx, "❌ Sell: Error freezing gift line prices: %v", err)
+				return fmt.Errorf("failed to freeze pricing snapshot: %w", err)
+			}
+		} else if pricingEngine != nil {
+			utils.Logf(ctx, "💰 Sell: Calculating final pricing for order %d", reservedOrderID)
+		
+			// Note: We need to use a context that can work with the transaction
+			// Since pricing engine uses db.DB directly, we'll calculate outside transaction first
+			// then update within transaction
+			breakdown, err := pricingEngine.CalculateOrderPricing(ctx, reservedOrderID)
+			if err != nil {
+				utils.Logf(ctx, "❌ Sell: Error calculating pricing: %v", err)
+				return fmt.Errorf("failed to calculate pricing: %w", err)
 			}
+
+			// Freeze the order-level discount into the total and spread it over the line totals,
+			// so the frozen prices add up to what the customer pays
+			calculatedTotal, orderDiscount = discountPricingLines(breakdown, discountType, discountValue)
+			calculatedOrderType = breakdown.OrderType
+			utils.Logf(ctx, "💰 Sell: Calculated total=%d (discount=%d), orderType=%s", calculatedTotal, orderDiscount, calculatedOrderType)
+			if orderDiscount > 0 && calculatedTotal <= 0 {
+				utils.Logf(ctx, "❌ Sell: Order discount %d leaves nothing to pay for order %d", orderDiscount, reservedOrderID)
+				return fmt.Errorf("order discount leaves nothing to pay: lower the discount or sell it as a gift")
+			}
+
+			// Freeze snapshot: Update unit_price in reserved_order_lines with calculated prices
+			// Use effective unit price (lineTotal / qty) to include bundle contributions
+			for _, pricingLine := range breakdown.Lines {
+				// Calculate effective unit price (includes bundle contributions)
+				effectiveUnitPrice := pricingLine.UnitPrice
+				if pricingLine.Qty > 0 {
+					effectiveUnitPrice = pricingLine.LineTotal / int64(pricingLine.Qty)
+				}
 			
-			queryUpdatePrice := `
-				UPDATE reserved_order_lines
-				SET unit_price = $1
+				queryUpdatePrice := `
+					UPDATE reserved_order_lines
+					SET unit_price = $1
+					WHERE id = $2
+				`
+				_, err = tx.ExecContext(ctx, queryUpdatePrice, effectiveUnitPrice, pricingLine.LineID)
+				if err != nil {
+					utils.Logf(ctx, "❌ Sell: Error freezing price for line %d: %v", pricingLine.LineID, err)
+					return fmt.Errorf("failed to freeze pricing snapshot: %w", err)
+				}
+				utils.Logf(ctx, "💰 Sell: Frozen line %d: qty=%d, lineTotal=%d, effectiveUnitPrice=%d", 
+					pricingLine.LineID, pricingLine.Qty, pricingLine.LineTotal, effectiveUnitPrice)
+			}
+			utils.Logf(ctx, "✅ Sell: Frozen pricing snapshot for all lines")
+
+			// Update order_type in reserved_orders
+			queryUpdateOrderType := `
+				UPDATE reserved_orders
+				SET order_type = $1
+				WHERE id = $2
+			`
+			_, err = tx.ExecContext(ctx, queryUpdateOrderType, strings.ToLower(calculatedOrderType), reservedOrderID)
+			if err != nil {
+				utils.Logf(ctx, "⚠️ Sell: Failed to update order_type: %v", err)
+				// Continue anyway - pricing is more important
+			} else {
+				utils.Logf(ctx, "✅ Sell: Updated order_type to %s", calculatedOrderType)
+			}
+		} else {
+			utils.Logf(ctx, "⚠️ Sell: Pricing engine not initialized, using request amount_paid")
+			calculatedTotal = req.AmountPaid
+			calculatedOrderType = "detal" // Default
+		}
+
+		// Process each line: validate stock_reserved and deduct stock_total and stock_reserved
+		for _, line := range lines {
+			// Lock item for update and validate stock_reserved
+			var stockReserved int
+			queryItem := `SELECT stock_reserved FROM items WHERE id = $1 FOR UPDATE`
+			err = tx.QueryRowContext(ctx, queryItem, line.itemID).Scan(&stockReserved)
+			if err != nil {
+				utils.Logf(ctx, "❌ Sell: Error fetching item stock: %v", err)
+				return fmt.Errorf("failed to fetch item stock: %w", err)
+			}
+
+			if stockReserved < line.qty {
+				utils.Logf(ctx, "❌ Sell: Insufficient reserved stock: reserved=%d, required=%d", stockReserved, line.qty)
+				return fmt.Errorf("insufficient reserved stock: reserved %d, required %d", stockReserved, line.qty)
+			}
+
+			// Deduct stock_total and stock_reserved
+			queryUpdateStock := `
+				UPDATE items
+				SET stock_total = stock_total - $1,
+				    stock_reserved = stock_reserved - $1
 				WHERE id = $2
 			`
-			_, err = tx.ExecContext(ctx, queryUpdatePrice, effectiveUnitPrice, pricingLine.LineID)
+			_, err = tx.ExecContext(ctx, queryUpdateStock, line.qty, line.itemID)
 			if err != nil {
-				log.Printf("❌ Sell: Error freezing price for line %d: %v", pricingLine.LineID, err)
-				return nil, fmt.Errorf("failed to freeze pricing snapshot: %w", err)
+				utils.Logf(ctx, "❌ Sell: Error updating stock for item_id=%d: %v", line.itemID, err)
+				return fmt.Errorf("failed to deduct stock: %w", err)
 			}
-			log.Printf("💰 Sell: Frozen line %d: qty=%d, lineTotal=%d, effectiveUnitPrice=%d", 
-				pricingLine.LineID, pricingLine.Qty, pricingLine.LineTotal, effectiveUnitPrice)
 		}
-		log.Printf("✅ Sell: Frozen pricing snapshot for all lines")
 
-		// Update order_type in reserved_orders
-		queryUpdateOrderType := `
+		// Update order status to 'completed'
+		queryUpdateOrder := `
 			UPDATE reserved_orders
-			SET order_type = $1
-			WHERE id = $2
+			SET status = 'completed', updated_at = NOW()
+			WHERE id = $1
 		`
-		_, err = tx.ExecContext(ctx, queryUpdateOrderType, strings.ToLower(calculatedOrderType), reservedOrderID)
+		_, err = tx.ExecContext(ctx, queryUpdateOrder, reservedOrderID)
 		if err != nil {
-			log.Printf("⚠️ Sell: Failed to update order_type: %v", err)
-			// Continue anyway - pricing is more important
+			utils.Logf(ctx, "❌ Sell: Error updating order: %v", err)
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		// Insert into sales
+		soldAt := time.Now()
+		queryInsertSale := `
+			INSERT INTO sales (reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, sale_type, gift_reason, idempotency_key, seller)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING id, reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, created_at, sale_type, gift_reason, COALESCE(seller, '')
+		`
+
+		var saleCustomerName, saleNotes, saleGiftReason sql.NullString
+
+		// Use calculated total if pricing engine was used, otherwise use request amount_paid
+		// Silent fallbacks are reported as warnings, the sale itself still succeeds
+		var warnings []string
+		amountPaid := req.AmountPaid
+		paymentMethod := req.PaymentMethod
+		paymentDestination := req.PaymentDestination
+		if isGift {
+			amountPaid = 0
+			paymentMethod = giftPaymentLabel
+			paymentDestination = giftPaymentLabel
+		} else if pricingEngine == nil {
+			warnings = append(warnings, "pricing engine not initialized: amount_paid taken from request and line prices were not frozen")
+		} else if calculatedTotal > 0 || orderDiscount > 0 {
+			amountPaid = calculatedTotal
+			utils.Logf(ctx, "💰 Sell: Using calculated total %d for amount_paid (request had %d)", calculatedTotal, req.AmountPaid)
+			if req.AmountPaid != calculatedTotal {
+				warnings = append(warnings, fmt.Sprintf("requested amountPaid %d differs from calculated total %d: calculated total was used", req.AmountPaid, calculatedTotal))
+			}
 		} else {
-			log.Printf("✅ Sell: Updated order_type to %s", calculatedOrderType)
+			warnings = append(warnings, "calculated total is 0: amount_paid taken from request")
 		}
-	} else {
-		log.Printf("⚠️ Sell: Pricing engine not initialized, using request amount_paid")
-		calculatedTotal = req.AmountPaid
-		calculatedOrderType = "detal" // Default
-	}
 
-	// Process each line: validate stock_reserved and deduct stock_total and stock_reserved
-	for _, line := range lines {
-		// Lock item for update and validate stock_reserved
-		var stockReserved int
-		queryItem := `SELECT stock_reserved FROM items WHERE id = $1 FOR UPDATE`
-		err = tx.QueryRowContext(ctx, queryItem, line.itemID).Scan(&stockReserved)
-		if err != nil {
-			log.Printf("❌ Sell: Error fetching item stock: %v", err)
-			return nil, fmt.Errorf("failed to fetch item stock: %w", err)
+		// Every payment split is stored in sale_payments with its own finance transaction; the sale row
+		// also keeps the largest split as its payment method/destination (the default refund destination)
+		var payments []models.SalePayment
+		if !isGift {
+			payments, err = resolveSalePayments(req, amountPaid)
+			if err != nil {
+				utils.Logf(ctx, "❌ Sell: %v", err)
+				return err
+			}
+			primary := primarySalePayment(payments)
+			paymentMethod = primary.Method
+			paymentDestination = primary.Destination
 		}
 
-		if stockReserved < line.qty {
-			log.Printf("❌ Sell: Insufficient reserved stock: reserved=%d, required=%d", stockReserved, line.qty)
-			return nil, fmt.Errorf("insufficient reserved stock: reserved %d, required %d", stockReserved, line.qty)
+		// The seller defaults to the person the order is assigned to
+		seller := strings.TrimSpace(req.Seller)
+		if seller == "" {
+			seller = strings.TrimSpace(assignedTo)
 		}
 
-		// Deduct stock_total and stock_reserved
-		queryUpdateStock := `
-			UPDATE items
-			SET stock_total = stock_total - $1,
-			    stock_reserved = stock_reserved - $1
-			WHERE id = $2
-		`
-		_, err = tx.ExecContext(ctx, queryUpdateStock, line.qty, line.itemID)
+		err = tx.QueryRowContext(ctx, queryInsertSale,
+			reservedOrderID,
+			soldAt,
+			sql.NullString{String: customerName, Valid: customerName != ""},
+			amountPaid,
+			paymentMethod,
+			paymentDestination,
+			"paid",
+			sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+			saleType,
+			sql.NullString{String: giftReason, Valid: isGift},
+			sql.NullString{String: idempotencyKey, Valid: idempotencyKey != ""},
+			sql.NullString{String: seller, Valid: seller != ""},
+		).Scan(
+			&sale.ID,
+			&sale.ReservedOrderID,
+			&sale.SoldAt,
+			&saleCustomerName,
+			&sale.AmountPaid,
+			&sale.PaymentMethod,
+			&sale.PaymentDestination,
+			&sale.Status,
+			&saleNotes,
+			&sale.CreatedAt,
+			&sale.SaleType,
+			&saleGiftReason,
+			&sale.Seller,
+		)
 		if err != nil {
-			log.Printf("❌ Sell: Error updating stock for item_id=%d: %v", line.itemID, err)
-			return nil, fmt.Errorf("failed to deduct stock: %w", err)
+			// The same key was used concurrently for another order: the unique index rejected this sale
+			if idempotencyKey != "" && strings.Contains(err.Error(), idempotencyKeyIndex) {
+				tx.Rollback()
+				existingSale, lookupErr := getSaleByIdempotencyKey(ctx, db.DB.QueryRowContext, idempotencyKey)
+				if lookupErr == nil && existingSale != nil {
+					replayedSale = existingSale
+					return errSaleReplayed
+				}
+			}
+			utils.Logf(ctx, "❌ Sell: Error inserting sale: %v", err)
+			return fmt.Errorf("failed to insert sale: %w", err)
 		}
-	}
 
-	// Update order status to 'completed'
-	queryUpdateOrder := `
-		UPDATE reserved_orders
-		SET status = 'completed', updated_at = NOW()
-		WHERE id = $1
-	`
-	_, err = tx.ExecContext(ctx, queryUpdateOrder, reservedOrderID)
-	if err != nil {
-		log.Printf("❌ Sell: Error updating order: %v", err)
-		return nil, fmt.Errorf("failed to update order: %w", err)
-	}
+		if saleCustomerName.Valid {
+			sale.CustomerName = saleCustomerName.String
+		}
+		if saleNotes.Valid {
+			sale.Notes = saleNotes.String
+		}
+		if saleGiftReason.Valid {
+			sale.GiftReason = saleGiftReason.String
+		}
 
-	// Insert into sales
-	soldAt := time.Now()
-	queryInsertSale := `
-		INSERT INTO sales (reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, created_at
-	`
+		// Insert into finance_transactions
+		// Gifts record no income, which keeps them out of every finance-based revenue figure
+		if isGift {
+			utils.Logf(ctx, "🎁 Sell: Skipping finance transaction for gift sale id=%d", sale.ID)
+		} else {
+			queryInsertTransaction := `
+				INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			`
+			queryInsertPayment := `
+				INSERT INTO sale_payments (sale_id, method, destination, amount)
+				VALUES ($1, $2, $3, $4)
+			`
+			for _, payment := range payments {
+				_, err = tx.ExecContext(ctx, queryInsertPayment, sale.ID, payment.Method, payment.Destination, payment.Amount)
+				if err != nil {
+					utils.Logf(ctx, "❌ Sell: Error inserting sale payment: %v", err)
+					return fmt.Errorf("failed to insert sale payment: %w", err)
+				}
 
-	var sale models.Sale
-	var saleCustomerName, saleNotes sql.NullString
-
-	// Use calculated total if pricing engine was used, otherwise use request amount_paid
-	amountPaid := req.AmountPaid
-	if pricingEngine != nil && calculatedTotal > 0 {
-		amountPaid = calculatedTotal
-		log.Printf("💰 Sell: Using calculated total %d for amount_paid (request had %d)", calculatedTotal, req.AmountPaid)
-	}
-
-	err = tx.QueryRowContext(ctx, queryInsertSale,
-		reservedOrderID,
-		soldAt,
-		sql.NullString{String: customerName, Valid: customerName != ""},
-		amountPaid,
-		req.PaymentMethod,
-		req.PaymentDestination,
-		"paid",
-		sql.NullString{String: req.Notes, Valid: req.Notes != ""},
-	).Scan(
-		&sale.ID,
-		&sale.ReservedOrderID,
-		&sale.SoldAt,
-		&saleCustomerName,
-		&sale.AmountPaid,
-		&sale.PaymentMethod,
-		&sale.PaymentDestination,
-		&sale.Status,
-		&saleNotes,
-		&sale.CreatedAt,
-	)
+				_, err = tx.ExecContext(ctx, queryInsertTransaction,
+					"income",
+					"sale",
+					sale.ID,
+					soldAt,
+					payment.Amount, // Splits sum to the calculated amount_paid
+					payment.Destination,
+					"venta",
+					sql.NullString{}, // counterparty is NULL for sale transactions
+					sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+				)
+				if err != nil {
+					utils.Logf(ctx, "❌ Sell: Error inserting finance transaction: %v", err)
+					return fmt.Errorf("failed to insert finance transaction: %w", err)
+				}
+			}
+			sale.Payments = payments
+			if len(payments) > 1 {
+				utils.Logf(ctx, "💰 Sell: Recorded %d payment splits for sale id=%d", len(payments), sale.ID)
+			}
+		}
+
+		sale.Warnings = warnings
+		return nil
+	})
+	if errors.Is(err, errSaleReplayed) {
+		return replayIdempotentSale(replayedSale, reservedOrderID, idempotencyKey)
+	}
 	if err != nil {
-		log.Printf("❌ Sell: Error inserting sale: %v", err)
-		return nil, fmt.Errorf("failed to insert sale: %w", err)
+		return nil, err
 	}
+	events.PublishOrderChange(reservedOrderID, events.OrderSold)
 
-	if saleCustomerName.Valid {
-		sale.CustomerName = saleCustomerName.String
-	}
-	if saleNotes.Valid {
-		sale.Notes = saleNotes.String
+	for _, warning := range sale.Warnings {
+		utils.Logf(ctx, "⚠️ Sell: %s", warning)
 	}
 
-	// Insert into finance_transactions
-	queryInsertTransaction := `
-		INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-	`
-	_, err = tx.ExecContext(ctx, queryInsertTransaction,
-		"income",
-		"sale",
-		sale.ID,
-		soldAt,
-		amountPaid, // Use calculated amount_paid
-		req.PaymentDestination,
-		"venta",
-		sql.NullString{}, // counterparty is NULL for sale transactions
-		sql.NullString{String: req.Notes, Valid: req.Notes != ""},
-	)
+	utils.Logf(ctx, "✅ Sell: Successfully sold order id=%d, sale id=%d", reservedOrderID, sale.ID)
+	return &sale, nil
+}
+
+// GetByReservedOrder retrieves the current (non-voided) sale of a reserved order with the same details
+// as GetByID; voided sales are only listed by ListByReservedOrder. Returns "reserved order not found" or "sale not found" errors
+func (r *SaleRepository) GetByReservedOrder(ctx context.Context, reservedOrderID int64) (*models.SaleDetailResponse, error) {
+	log.Printf("📦 GetByReservedOrder: Fetching sale for reserved order id=%d", reservedOrderID)
+
+	var saleID sql.NullInt64
+	err := db.DB.QueryRowContext(ctx, `
+		SELECT s.id
+		FROM reserved_orders ro
+		LEFT JOIN sales s ON s.reserved_order_id = ro.id AND s.status <> 'voided'
+		WHERE ro.id = $1
+	`, reservedOrderID).Scan(&saleID)
 	if err != nil {
-		log.Printf("❌ Sell: Error inserting finance transaction: %v", err)
-		return nil, fmt.Errorf("failed to insert finance transaction: %w", err)
+		if err == sql.ErrNoRows {
+			log.Printf("❌ GetByReservedOrder: Reserved order not found: id=%d", reservedOrderID)
+			return nil, fmt.Errorf("reserved order not found")
+		}
+		log.Printf("❌ GetByReservedOrder: Error fetching sale: %v", err)
+		return nil, fmt.Errorf("failed to fetch sale: %w", err)
 	}
-
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		log.Printf("❌ Sell: Error committing transaction: %v", err)
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	if !saleID.Valid {
+		log.Printf("❌ GetByReservedOrder: Reserved order id=%d has no sale", reservedOrderID)
+		return nil, fmt.Errorf("sale not found for reserved order %d", reservedOrderID)
 	}
 
-	log.Printf("✅ Sell: Successfully sold order id=%d, sale id=%d", reservedOrderID, sale.ID)
-	return &sale, nil
+	return r.GetByID(ctx, saleID.Int64)
 }
 
 // GetByID retrieves a sale by ID with its associated order details
@@ -302,13 +530,13 @@ func (r *SaleRepository) GetByID(ctx context.Context, saleID int64) (*models.Sal
 
 	// Get sale
 	querySale := `
-		SELECT id, reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, created_at
+		SELECT id, reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, created_at, sale_type, gift_reason, COALESCE(seller, ''), voided_at, void_reason
 		FROM sales
 		WHERE id = $1
 	`
 
 	var sale models.Sale
-	var customerName, notes sql.NullString
+	var customerName, notes, giftReason, voidedAt, voidReason sql.NullString
 
 	err := db.DB.QueryRowContext(ctx, querySale, saleID).Scan(
 		&sale.ID,
@@ -321,6 +549,11 @@ func (r *SaleRepository) GetByID(ctx context.Context, saleID int64) (*models.Sal
 		&sale.Status,
 		&notes,
 		&sale.CreatedAt,
+		&sale.SaleType,
+		&giftReason,
+		&sale.Seller,
+		&voidedAt,
+		&voidReason,
 	)
 
 	if err != nil {
@@ -338,6 +571,17 @@ func (r *SaleRepository) GetByID(ctx context.Context, saleID int64) (*models.Sal
 	if notes.Valid {
 		sale.Notes = notes.String
 	}
+	if giftReason.Valid {
+		sale.GiftReason = giftReason.String
+	}
+	sale.VoidedAt = voidedAt.String
+	sale.VoidReason = voidReason.String
+
+	sale.Payments, err = getSalePayments(ctx, db.DB.QueryContext, sale.ID)
+	if err != nil {
+		log.Printf("❌ GetByID: Error fetching payments: %v", err)
+		return nil, err
+	}
 
 	// Get associated order using ReservedOrderRepository
 	// We need to get the repository, but we can't import it circularly
@@ -358,88 +602,1416 @@ func (r *SaleRepository) GetByID(ctx context.Context, saleID int64) (*models.Sal
 	return response, nil
 }
 
-// List retrieves sales filtered by date range
-func (r *SaleRepository) List(ctx context.Context, from, to *string) ([]models.SaleListItem, error) {
-	log.Printf("📦 List: Fetching sales (from=%v, to=%v)", from, to)
+// Report aggregates sales (count and sum of amount_paid) by day, week or month, plus a breakdown by
+// payment method and destination built from the sale's payment splits (sale_payments), so a split
+// sale credits each method with its own amount. Gift sales are excluded since they record no money,
+// and voided sales since their money was reversed.
+// Date filtering matches List: from at start of day, to inclusive until end of day
+func (r *SaleRepository) Report(ctx context.Context, from, to *string, groupBy string) (*models.SalesReportResponse, error) {
+	log.Printf("📊 SalesReport: from=%v, to=%v, groupBy=%s", from, to, groupBy)
 
-	query := `
-		SELECT id, sold_at, reserved_order_id, customer_name, amount_paid, payment_destination, payment_method
-		FROM sales
-	`
-	var args []interface{}
-	argIndex := 1
+	switch groupBy {
+	case "day", "week", "month":
+	default:
+		return nil, fmt.Errorf("invalid groupBy %q: must be day, week or month", groupBy)
+	}
 
-	if from != nil && *from != "" {
-		// Parse date and use start of day (00:00:00)
-		fromDate, err := time.Parse("2006-01-02", *from)
-		if err != nil {
-			return nil, fmt.Errorf("invalid from date format: %w", err)
-		}
-		query += fmt.Sprintf(" WHERE sold_at >= $%d", argIndex)
-		args = append(args, fromDate)
-		argIndex++
+	conditions, args, err := soldAtConditions(from, to)
+	if err != nil {
+		return nil, err
 	}
+	conditions = append(conditions, "sale_type <> 'gift'", "status <> 'voided'")
+	where := " WHERE " + strings.Join(conditions, " AND ")
 
-	if to != nil && *to != "" {
-		// Parse date and use end of day (23:59:59.999999)
-		toDate, err := time.Parse("2006-01-02", *to)
-		if err != nil {
-			return nil, fmt.Errorf("invalid to date format: %w", err)
-		}
-		// Set to end of day
-		toDate = time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
-		if argIndex == 1 {
-			query += " WHERE"
-		} else {
-			query += " AND"
-		}
-		query += fmt.Sprintf(" sold_at <= $%d", argIndex)
-		args = append(args, toDate)
-		argIndex++
+	response := &models.SalesReportResponse{
+		GroupBy:              groupBy,
+		Periods:              []models.SalesReportPeriod{},
+		ByPaymentMethod:      []models.SalesReportBreakdown{},
+		ByPaymentDestination: []models.SalesReportBreakdown{},
+	}
+	if from != nil {
+		response.From = *from
+	}
+	if to != nil {
+		response.To = *to
 	}
 
-	query += " ORDER BY sold_at DESC"
+	// groupBy is validated above, so it is safe to inline in date_trunc
+	queryPeriods := fmt.Sprintf(`
+		SELECT date_trunc('%s', sold_at) as period, COUNT(*), COALESCE(SUM(amount_paid), 0)
+		FROM sales
+		%s
+		GROUP BY period
+		ORDER BY period ASC
+	`, groupBy, where)
 
-	rows, err := db.DB.QueryContext(ctx, query, args...)
+	rows, err := db.DB.QueryContext(ctx, queryPeriods, args...)
 	if err != nil {
-		log.Printf("❌ List: Error fetching sales: %v", err)
-		return nil, fmt.Errorf("failed to fetch sales: %w", err)
+		log.Printf("❌ SalesReport: Error aggregating periods: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales: %w", err)
 	}
-	defer rows.Close()
-
-	var sales []models.SaleListItem
-
 	for rows.Next() {
-		var sale models.SaleListItem
-		var customerName sql.NullString
+		var period models.SalesReportPeriod
+		var periodStart time.Time
+		if err := rows.Scan(&periodStart, &period.Count, &period.AmountPaid); err != nil {
+			rows.Close()
+			log.Printf("❌ SalesReport: Error scanning period: %v", err)
+			return nil, fmt.Errorf("failed to scan sales period: %w", err)
+		}
+		period.Period = periodStart.Format("2006-01-02")
+		response.TotalCount += period.Count
+		response.TotalAmountPaid += period.AmountPaid
+		response.Periods = append(response.Periods, period)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ SalesReport: Error iterating periods: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales: %w", err)
+	}
 
-		err := rows.Scan(
-			&sale.ID,
-			&sale.SoldAt,
-			&sale.ReservedOrderID,
-			&customerName,
-			&sale.AmountPaid,
-			&sale.PaymentDestination,
-			&sale.PaymentMethod,
-		)
+	// Count is the number of sales paid (in part) with each method or destination
+	breakdown := func(column string) ([]models.SalesReportBreakdown, error) {
+		query := fmt.Sprintf(`
+			SELECT sp.%s, COUNT(DISTINCT sales.id), COALESCE(SUM(sp.amount), 0)
+			FROM sales
+			INNER JOIN sale_payments sp ON sp.sale_id = sales.id
+			%s
+			GROUP BY sp.%s
+			ORDER BY SUM(sp.amount) DESC, sp.%s ASC
+		`, column, where, column, column)
+
+		rows, err := db.DB.QueryContext(ctx, query, args...)
 		if err != nil {
-			log.Printf("❌ List: Error scanning sale: %v", err)
-			continue
+			return nil, err
 		}
+		defer rows.Close()
 
-		if customerName.Valid {
-			sale.CustomerName = customerName.String
+		result := []models.SalesReportBreakdown{}
+		for rows.Next() {
+			var entry models.SalesReportBreakdown
+			if err := rows.Scan(&entry.Key, &entry.Count, &entry.AmountPaid); err != nil {
+				return nil, err
+			}
+			result = append(result, entry)
 		}
-
-		sales = append(sales, sale)
+		return result, rows.Err()
 	}
 
-	if err := rows.Err(); err != nil {
-		log.Printf("❌ List: Error iterating sales: %v", err)
-		return nil, fmt.Errorf("failed to iterate sales: %w", err)
+	response.ByPaymentMethod, err = breakdown("method")
+	if err != nil {
+		log.Printf("❌ SalesReport: Error aggregating by payment method: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales by payment method: %w", err)
+	}
+	response.ByPaymentDestination, err = breakdown("destination")
+	if err != nil {
+		log.Printf("❌ SalesReport: Error aggregating by payment destination: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales by payment destination: %w", err)
 	}
 
-	log.Printf("✅ List: Successfully fetched %d sales", len(sales))
-	return sales, nil
+	log.Printf("✅ SalesReport: %d sales, total=%d across %d periods", response.TotalCount, response.TotalAmountPaid, len(response.Periods))
+	return response, nil
 }
 
+// BySeller aggregates sales (count and sum of amount_paid) per seller, highest total first.
+// Gift and voided sales are excluded like in Report. Date filtering matches List
+func (r *SaleRepository) BySeller(ctx context.Context, from, to *string) (*models.SalesBySellerResponse, error) {
+	log.Printf("📊 SalesBySeller: from=%v, to=%v", from, to)
+
+	conditions, args, err := soldAtConditions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, "sale_type <> 'gift'", "status <> 'voided'")
+
+	query := `
+		SELECT COALESCE(seller, ''), COUNT(*), COALESCE(SUM(amount_paid), 0)
+		FROM sales
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY COALESCE(seller, '')
+		ORDER BY SUM(amount_paid) DESC, COALESCE(seller, '') ASC
+	`
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("❌ SalesBySeller: Error aggregating sales: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales by seller: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.SalesBySellerResponse{
+		Sellers: []models.SellerSales{},
+	}
+	if from != nil {
+		response.From = *from
+	}
+	if to != nil {
+		response.To = *to
+	}
+
+	for rows.Next() {
+		var entry models.SellerSales
+		if err := rows.Scan(&entry.Seller, &entry.Count, &entry.AmountPaid); err != nil {
+			log.Printf("❌ SalesBySeller: Error scanning seller: %v", err)
+			return nil, fmt.Errorf("failed to scan seller sales: %w", err)
+		}
+		response.TotalCount += entry.Count
+		response.TotalAmountPaid += entry.AmountPaid
+		response.Sellers = append(response.Sellers, entry)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ SalesBySeller: Error iterating sellers: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales by seller: %w", err)
+	}
+
+	log.Printf("✅ SalesBySeller: %d sales across %d sellers", response.TotalCount, len(response.Sellers))
+	return response, nil
+}
+
+// TopCustomers aggregates sales (count and sum of amount_paid) per customer name of the reserved
+// order, highest total first, keeping the top limit customers. Orders without a customer name are
+// bucketed under "(sin nombre)". Gift and voided sales are excluded like in Report
+func (r *SaleRepository) TopCustomers(ctx context.Context, from, to *string, limit int) (*models.TopCustomersResponse, error) {
+	log.Printf("📊 TopCustomers: from=%v, to=%v, limit=%d", from, to, limit)
+
+	conditions, args, err := soldAtConditions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, "s.sale_type <> 'gift'", "s.status <> 'voided'")
+	args = append(args, limit)
+
+	query := `
+		SELECT COALESCE(NULLIF(TRIM(ro.customer_name), ''), '(sin nombre)') as customer, COUNT(*), COALESCE(SUM(s.amount_paid), 0)
+		FROM sales s
+		INNER JOIN reserved_orders ro ON s.reserved_order_id = ro.id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY customer
+		ORDER BY SUM(s.amount_paid) DESC, customer ASC
+		LIMIT ` + fmt.Sprintf("$%d", len(args)) + `
+	`
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("❌ TopCustomers: Error aggregating sales: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales by customer: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.TopCustomersResponse{
+		Limit:     limit,
+		Customers: []models.CustomerSales{},
+	}
+	if from != nil {
+		response.From = *from
+	}
+	if to != nil {
+		response.To = *to
+	}
+
+	for rows.Next() {
+		var entry models.CustomerSales
+		if err := rows.Scan(&entry.CustomerName, &entry.Count, &entry.AmountPaid); err != nil {
+			log.Printf("❌ TopCustomers: Error scanning customer: %v", err)
+			return nil, fmt.Errorf("failed to scan customer sales: %w", err)
+		}
+		response.Customers = append(response.Customers, entry)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ TopCustomers: Error iterating customers: %v", err)
+		return nil, fmt.Errorf("failed to aggregate sales by customer: %w", err)
+	}
+
+	log.Printf("✅ TopCustomers: Returning %d customers", len(response.Customers))
+	return response, nil
+}
+
+// soldAtConditions builds sold_at conditions for an optional YYYY-MM-DD date range.
+// from starts at 00:00:00 and to is inclusive up to the end of its day. Placeholders start at $1
+func soldAtConditions(from, to *string) ([]string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	if from != nil && *from != "" {
+		// Parse date and use start of day (00:00:00)
+		fromDate, err := time.Parse("2006-01-02", *from)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid from date format: %w", err)
+		}
+		args = append(args, fromDate)
+		conditions = append(conditions, fmt.Sprintf("sold_at >= $%d", len(args)))
+	}
+
+	if to != nil && *to != "" {
+		// Parse date and use end of day (23:59:59.999999)
+		toDate, err := time.Parse("2006-01-02", *to)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid to date format: %w", err)
+		}
+		// Set to end of day
+		toDate = time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+		args = append(args, toDate)
+		conditions = append(conditions, fmt.Sprintf("sold_at <= $%d", len(args)))
+	}
+
+	return conditions, args, nil
+}
+
+// List retrieves sales filtered by date range. Voided sales are left out; ListByReservedOrder keeps them
+func (r *SaleRepository) List(ctx context.Context, from, to *string) ([]models.SaleListItem, error) {
+	log.Printf("📦 List: Fetching sales (from=%v, to=%v)", from, to)
+
+	query := `
+		SELECT id, sold_at, reserved_order_id, customer_name, amount_paid, payment_destination, payment_method, sale_type
+		FROM sales
+	`
+	conditions, args, err := soldAtConditions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, "status <> 'voided'")
+	query += " WHERE " + strings.Join(conditions, " AND ")
+
+	query += " ORDER BY sold_at DESC"
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("❌ List: Error fetching sales: %v", err)
+		return nil, fmt.Errorf("failed to fetch sales: %w", err)
+	}
+	defer rows.Close()
+
+	var sales []models.SaleListItem
+
+	for rows.Next() {
+		var sale models.SaleListItem
+		var customerName sql.NullString
+
+		err := rows.Scan(
+			&sale.ID,
+			&sale.SoldAt,
+			&sale.ReservedOrderID,
+			&customerName,
+			&sale.AmountPaid,
+			&sale.PaymentDestination,
+			&sale.PaymentMethod,
+			&sale.SaleType,
+		)
+		if err != nil {
+			log.Printf("❌ List: Error scanning sale: %v", err)
+			continue
+		}
+
+		if customerName.Valid {
+			sale.CustomerName = customerName.String
+		}
+
+		sales = append(sales, sale)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ List: Error iterating sales: %v", err)
+		return nil, fmt.Errorf("failed to iterate sales: %w", err)
+	}
+
+	log.Printf("✅ List: Successfully fetched %d sales", len(sales))
+	return sales, nil
+}
+
+
+// checkRepriceable reports why a sale cannot be repriced: only paid, non-gift sales without refunds
+// can be. A refund returned units at the old frozen prices, so repricing the whole order afterwards
+// would change amounts that were already (partly) given back
+func checkRepriceable(status, saleType string, refundCount int) error {
+	if status != "paid" {
+		return fmt.Errorf("sale not in paid status")
+	}
+	if saleType == "gift" {
+		return fmt.Errorf("gift sales cannot be repriced: they record no income")
+	}
+	if refundCount > 0 {
+		return fmt.Errorf("sale already has refunds and cannot be repriced")
+	}
+	return nil
+}
+
+// Reprice recomputes the pricing of a completed sale with the current pricing engine.
+// It re-freezes unit_price on every line, updates the sale's amount_paid and records a
+// finance adjustment (income or expense) for the difference referencing the sale.
+// Sales with refunds are rejected. All operations are performed atomically in a single transaction
+func (r *SaleRepository) Reprice(ctx context.Context, saleID int64, reason string) (*models.RepriceSaleResponse, error) {
+	log.Printf("📦 Reprice: Repricing sale id=%d (reason=%q)", saleID, reason)
+
+	if strings.TrimSpace(reason) == "" {
+		log.Printf("❌ Reprice: reason is required")
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		log.Printf("❌ Reprice: Pricing engine not initialized")
+		return nil, fmt.Errorf("pricing engine not initialized")
+	}
+
+	// Start transaction
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ Reprice: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock sale row
+	querySale := `
+		SELECT id, reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, created_at, sale_type, gift_reason, COALESCE(seller, '')
+		FROM sales
+		WHERE id = $1
+		FOR UPDATE
+	`
+	var sale models.Sale
+	var customerName, notes, giftReason sql.NullString
+	err = tx.QueryRowContext(ctx, querySale, saleID).Scan(
+		&sale.ID,
+		&sale.ReservedOrderID,
+		&sale.SoldAt,
+		&customerName,
+		&sale.AmountPaid,
+		&sale.PaymentMethod,
+		&sale.PaymentDestination,
+		&sale.Status,
+		&notes,
+		&sale.CreatedAt,
+		&sale.SaleType,
+		&giftReason,
+		&sale.Seller,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ Reprice: Sale not found: id=%d", saleID)
+			return nil, fmt.Errorf("sale not found")
+		}
+		log.Printf("❌ Reprice: Error fetching sale: %v", err)
+		return nil, fmt.Errorf("failed to fetch sale: %w", err)
+	}
+	if customerName.Valid {
+		sale.CustomerName = customerName.String
+	}
+	if notes.Valid {
+		sale.Notes = notes.String
+	}
+	if giftReason.Valid {
+		sale.GiftReason = giftReason.String
+	}
+
+	// Refunds were computed from the frozen prices being replaced, so a refunded sale cannot be repriced
+	var refundCount int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sale_refunds WHERE sale_id = $1`, saleID).Scan(&refundCount)
+	if err != nil {
+		log.Printf("❌ Reprice: Error checking refunds: %v", err)
+		return nil, fmt.Errorf("failed to check refunds: %w", err)
+	}
+
+	if err := checkRepriceable(sale.Status, sale.SaleType, refundCount); err != nil {
+		log.Printf("❌ Reprice: Sale id=%d cannot be repriced: status=%s, sale_type=%s, refunds=%d: %v", saleID, sale.Status, sale.SaleType, refundCount, err)
+		return nil, err
+	}
+
+	// Lock order and validate it is completed
+	var orderStatus, discountType string
+	var discountValue int64
+	queryOrder := `SELECT status, discount_type, discount_value FROM reserved_orders WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, queryOrder, sale.ReservedOrderID).Scan(&orderStatus, &discountType, &discountValue)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ Reprice: Order not found: id=%d", sale.ReservedOrderID)
+			return nil, fmt.Errorf("order not found")
+		}
+		log.Printf("❌ Reprice: Error fetching order: %v", err)
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+	if orderStatus != "completed" {
+		log.Printf("❌ Reprice: Order not in completed status: status=%s", orderStatus)
+		return nil, fmt.Errorf("order not in completed status")
+	}
+
+	// Recalculate pricing with the current engine configuration
+	breakdown, err := pricingEngine.CalculateOrderPricing(ctx, sale.ReservedOrderID)
+	if err != nil {
+		log.Printf("❌ Reprice: Error calculating pricing: %v", err)
+		return nil, fmt.Errorf("failed to calculate pricing: %w", err)
+	}
+	if breakdown.Total <= 0 {
+		log.Printf("❌ Reprice: Recalculated total is not positive: %d", breakdown.Total)
+		return nil, fmt.Errorf("recalculated total must be greater than 0")
+	}
+
+	// The order-level discount is spread over the line totals, as in Sell
+	newAmount, orderDiscount := discountPricingLines(breakdown, discountType, discountValue)
+	if newAmount <= 0 {
+		log.Printf("❌ Reprice: Order discount %d leaves nothing to pay", orderDiscount)
+		return nil, fmt.Errorf("recalculated total must be greater than 0")
+	}
+
+	// Re-freeze snapshot using effective unit price (lineTotal / qty)
+	for _, pricingLine := range breakdown.Lines {
+		effectiveUnitPrice := pricingLine.UnitPrice
+		if pricingLine.Qty > 0 {
+			effectiveUnitPrice = pricingLine.LineTotal / int64(pricingLine.Qty)
+		}
+
+		queryUpdatePrice := `
+			UPDATE reserved_order_lines
+			SET unit_price = $1
+			WHERE id = $2
+		`
+		_, err = tx.ExecContext(ctx, queryUpdatePrice, effectiveUnitPrice, pricingLine.LineID)
+		if err != nil {
+			log.Printf("❌ Reprice: Error freezing price for line %d: %v", pricingLine.LineID, err)
+			return nil, fmt.Errorf("failed to freeze pricing snapshot: %w", err)
+		}
+	}
+
+	queryUpdateOrderType := `
+		UPDATE reserved_orders
+		SET order_type = $1
+		WHERE id = $2
+	`
+	_, err = tx.ExecContext(ctx, queryUpdateOrderType, strings.ToLower(breakdown.OrderType), sale.ReservedOrderID)
+	if err != nil {
+		log.Printf("⚠️ Reprice: Failed to update order_type: %v", err)
+	}
+
+	previousAmount := sale.AmountPaid
+	difference := newAmount - previousAmount
+
+	response := &models.RepriceSaleResponse{
+		PreviousAmount: previousAmount,
+		NewAmount:      newAmount,
+		Difference:     difference,
+		Reason:         reason,
+	}
+
+	if difference != 0 {
+		queryUpdateSale := `UPDATE sales SET amount_paid = $1 WHERE id = $2`
+		_, err = tx.ExecContext(ctx, queryUpdateSale, newAmount, sale.ID)
+		if err != nil {
+			log.Printf("❌ Reprice: Error updating sale amount: %v", err)
+			return nil, fmt.Errorf("failed to update sale amount: %w", err)
+		}
+		sale.AmountPaid = newAmount
+
+		// The difference goes to the largest payment split (the sale's payment destination, where the
+		// adjustment is posted), so the splits keep adding up to amount_paid
+		queryUpdatePayment := `
+			UPDATE sale_payments
+			SET amount = amount + $1
+			WHERE id = (SELECT id FROM sale_payments WHERE sale_id = $2 ORDER BY amount DESC, id ASC LIMIT 1)
+			  AND amount + $1 > 0
+		`
+		result, err := tx.ExecContext(ctx, queryUpdatePayment, difference, sale.ID)
+		if err != nil {
+			log.Printf("❌ Reprice: Error updating sale payment: %v", err)
+			return nil, fmt.Errorf("failed to update sale payment: %w", err)
+		}
+		if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+			log.Printf("❌ Reprice: Difference %d does not fit in the largest payment split of sale id=%d", difference, sale.ID)
+			return nil, fmt.Errorf("reprice difference %d exceeds the largest payment split: void and sell the order again instead", difference)
+		}
+
+		// Post the difference as an adjustment referencing the sale
+		adjustmentType := "income"
+		if difference < 0 {
+			adjustmentType = "expense"
+		}
+		queryInsertTransaction := `
+			INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id
+		`
+		var adjustmentID int64
+		err = tx.QueryRowContext(ctx, queryInsertTransaction,
+			adjustmentType,
+			"sale_adjustment",
+			sale.ID,
+			time.Now(),
+			abs(difference),
+			sale.PaymentDestination,
+			"ajuste_venta",
+			sql.NullString{},
+			fmt.Sprintf("Reprice venta #%d: %s", sale.ID, reason),
+		).Scan(&adjustmentID)
+		if err != nil {
+			log.Printf("❌ Reprice: Error inserting adjustment transaction: %v", err)
+			return nil, fmt.Errorf("failed to insert adjustment transaction: %w", err)
+		}
+		response.AdjustmentTransactionID = &adjustmentID
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ Reprice: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	response.Sale = sale
+	log.Printf("⚠️ Reprice: Sale id=%d repriced %d -> %d (difference=%d, reason=%q)", sale.ID, previousAmount, newAmount, difference, reason)
+	return response, nil
+}
+
+// Refund refunds part (or all) of a completed sale.
+// Refunded units go back to stock_total only: stock_reserved is left untouched since the
+// order is already completed. Each refunded quantity is recorded per line in sale_refund_lines
+// and the cumulative refunded quantity of a line can never exceed its sold quantity.
+// Lines are identified by lineId or itemId; when no lines and no amount are given, every unit not yet
+// refunded is refunded. An optional amount overrides the refunded money. The refunded money is always
+// capped by what is left of amount_paid, and the refund that returns the last units hands back all of
+// it, so rounding in the frozen unit prices never over- or under-refunds the sale. When every unit of
+// the order has been refunded, or the refunded money reaches amount_paid, the sale status becomes 'refunded'.
+// All operations are performed atomically in a single transaction
+func (r *SaleRepository) Refund(ctx context.Context, saleID int64, req *models.RefundSaleRequest) (*models.SaleRefund, error) {
+	log.Printf("📦 Refund: Refunding sale id=%d (%d lines)", saleID, len(req.Lines))
+
+	if strings.TrimSpace(req.Reason) == "" {
+		log.Printf("❌ Refund: reason is required")
+		return nil, fmt.Errorf("reason is required")
+	}
+	if req.Amount != nil && *req.Amount <= 0 {
+		log.Printf("❌ Refund: Invalid amount: %d", *req.Amount)
+		return nil, fmt.Errorf("amount must be greater than 0")
+	}
+	for _, line := range req.Lines {
+		if line.LineID == 0 && line.ItemID == 0 {
+			log.Printf("❌ Refund: Line without lineId or itemId")
+			return nil, fmt.Errorf("lineId or itemId is required for every line")
+		}
+		if line.Qty <= 0 {
+			log.Printf("❌ Refund: Invalid qty for line %d (item %d): %d", line.LineID, line.ItemID, line.Qty)
+			return nil, fmt.Errorf("qty must be greater than 0 for line %d (item %d)", line.LineID, line.ItemID)
+		}
+	}
+
+	// Start transaction
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ Refund: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock sale and validate status
+	var reservedOrderID, amountPaid int64
+	var saleStatus, paymentDestination, saleType string
+	querySale := `SELECT reserved_order_id, status, payment_destination, sale_type, amount_paid FROM sales WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, querySale, saleID).Scan(&reservedOrderID, &saleStatus, &paymentDestination, &saleType, &amountPaid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ Refund: Sale not found: id=%d", saleID)
+			return nil, fmt.Errorf("sale not found")
+		}
+		log.Printf("❌ Refund: Error fetching sale: %v", err)
+		return nil, fmt.Errorf("failed to fetch sale: %w", err)
+	}
+
+	if saleStatus == "refunded" {
+		log.Printf("❌ Refund: Sale id=%d already refunded", saleID)
+		return nil, fmt.Errorf("sale already refunded")
+	}
+	if saleStatus != "paid" {
+		log.Printf("❌ Refund: Sale not in paid status: status=%s", saleStatus)
+		return nil, fmt.Errorf("sale not in paid status")
+	}
+
+	if saleType == "gift" {
+		log.Printf("❌ Refund: Sale id=%d is a gift", saleID)
+		return nil, fmt.Errorf("gift sales cannot be refunded: they record no income")
+	}
+
+	destination := strings.TrimSpace(req.Destination)
+	if destination == "" {
+		destination = paymentDestination
+	}
+
+	refund := &models.SaleRefund{
+		SaleID:      saleID,
+		Destination: destination,
+		Reason:      strings.TrimSpace(req.Reason),
+		Lines:       []models.SaleRefundLine{},
+	}
+
+	reqLines := req.Lines
+	if len(reqLines) == 0 && req.Amount == nil {
+		// Full refund: every unit not refunded yet
+		queryRemaining := `
+			SELECT rol.id,
+			       rol.qty - COALESCE((SELECT SUM(srl.qty) FROM sale_refund_lines srl WHERE srl.reserved_order_line_id = rol.id), 0)
+			FROM reserved_order_lines rol
+			WHERE rol.reserved_order_id = $1
+			ORDER BY rol.id ASC
+		`
+		rows, err := tx.QueryContext(ctx, queryRemaining, reservedOrderID)
+		if err != nil {
+			log.Printf("❌ Refund: Error fetching remaining quantities: %v", err)
+			return nil, fmt.Errorf("failed to fetch remaining quantities: %w", err)
+		}
+		for rows.Next() {
+			var reqLine models.RefundLineRequest
+			if err := rows.Scan(&reqLine.LineID, &reqLine.Qty); err != nil {
+				rows.Close()
+				log.Printf("❌ Refund: Error scanning remaining quantity: %v", err)
+				return nil, fmt.Errorf("failed to scan remaining quantity: %w", err)
+			}
+			if reqLine.Qty > 0 {
+				reqLines = append(reqLines, reqLine)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			log.Printf("❌ Refund: Error iterating remaining quantities: %v", err)
+			return nil, fmt.Errorf("failed to fetch remaining quantities: %w", err)
+		}
+		if len(reqLines) == 0 {
+			log.Printf("❌ Refund: Sale id=%d has no units left to refund", saleID)
+			return nil, fmt.Errorf("sale already refunded")
+		}
+	}
+
+	seenLines := make(map[int64]bool)
+	for _, reqLine := range reqLines {
+		// Lock the order line and make sure it belongs to this sale's order
+		// (matched by lineId, or by itemId when lineId is omitted: an order has one line per item)
+		var line models.SaleRefundLine
+		var soldQty int
+		queryLine := `
+			SELECT id, item_id, qty, unit_price
+			FROM reserved_order_lines
+			WHERE reserved_order_id = $1
+			  AND (id = $2 OR ($2 = 0 AND item_id = $3))
+			FOR UPDATE
+		`
+		err = tx.QueryRowContext(ctx, queryLine, reservedOrderID, reqLine.LineID, reqLine.ItemID).Scan(&line.ReservedOrderLineID, &line.ItemID, &soldQty, &line.UnitPrice)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				if reqLine.LineID == 0 {
+					log.Printf("❌ Refund: Item %d not found in order %d", reqLine.ItemID, reservedOrderID)
+					return nil, fmt.Errorf("item %d not found in sale", reqLine.ItemID)
+				}
+				log.Printf("❌ Refund: Line %d not found in order %d", reqLine.LineID, reservedOrderID)
+				return nil, fmt.Errorf("order line %d not found in sale", reqLine.LineID)
+			}
+			log.Printf("❌ Refund: Error fetching line %d: %v", reqLine.LineID, err)
+			return nil, fmt.Errorf("failed to fetch order line: %w", err)
+		}
+		if seenLines[line.ReservedOrderLineID] {
+			log.Printf("❌ Refund: Duplicate line %d in request", line.ReservedOrderLineID)
+			return nil, fmt.Errorf("duplicate line %d in request", line.ReservedOrderLineID)
+		}
+		seenLines[line.ReservedOrderLineID] = true
+
+		// Validate cumulative refunded qty never exceeds sold qty
+		var alreadyRefunded int
+		queryRefunded := `SELECT COALESCE(SUM(qty), 0) FROM sale_refund_lines WHERE reserved_order_line_id = $1`
+		err = tx.QueryRowContext(ctx, queryRefunded, line.ReservedOrderLineID).Scan(&alreadyRefunded)
+		if err != nil {
+			log.Printf("❌ Refund: Error fetching refunded qty for line %d: %v", line.ReservedOrderLineID, err)
+			return nil, fmt.Errorf("failed to fetch refunded quantity: %w", err)
+		}
+		if alreadyRefunded+reqLine.Qty > soldQty {
+			log.Printf("❌ Refund: Refund qty exceeds sold qty for line %d: sold=%d, refunded=%d, requested=%d",
+				line.ReservedOrderLineID, soldQty, alreadyRefunded, reqLine.Qty)
+			return nil, fmt.Errorf("refund quantity exceeds sold quantity for line %d: sold %d, already refunded %d, requested %d",
+				line.ReservedOrderLineID, soldQty, alreadyRefunded, reqLine.Qty)
+		}
+
+		// Restore stock_total only (order is completed, nothing is reserved anymore)
+		queryRestoreStock := `UPDATE items SET stock_total = stock_total + $1 WHERE id = $2`
+		_, err = tx.ExecContext(ctx, queryRestoreStock, reqLine.Qty, line.ItemID)
+		if err != nil {
+			log.Printf("❌ Refund: Error restoring stock for item_id=%d: %v", line.ItemID, err)
+			return nil, fmt.Errorf("failed to restore stock: %w", err)
+		}
+
+		line.Qty = reqLine.Qty
+		line.Amount = line.UnitPrice * int64(reqLine.Qty)
+		refund.Amount += line.Amount
+		refund.Lines = append(refund.Lines, line)
+	}
+
+	// Money already refunded on this sale, used to cap the amount and to detect a full refund
+	var alreadyRefundedAmount int64
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM sale_refunds WHERE sale_id = $1`, saleID).Scan(&alreadyRefundedAmount)
+	if err != nil {
+		log.Printf("❌ Refund: Error fetching refunded amount: %v", err)
+		return nil, fmt.Errorf("failed to fetch refunded amount: %w", err)
+	}
+
+	// Units sold and already refunded, to tell whether this refund returns the last units
+	var soldTotal, refundedTotal int
+	queryTotals := `
+		SELECT
+			COALESCE((SELECT SUM(qty) FROM reserved_order_lines WHERE reserved_order_id = $1), 0),
+			COALESCE((SELECT SUM(srl.qty) FROM sale_refund_lines srl
+			          INNER JOIN sale_refunds sr ON sr.id = srl.sale_refund_id
+			          WHERE sr.sale_id = $2), 0)
+	`
+	err = tx.QueryRowContext(ctx, queryTotals, reservedOrderID, saleID).Scan(&soldTotal, &refundedTotal)
+	if err != nil {
+		log.Printf("❌ Refund: Error computing refund totals: %v", err)
+		return nil, fmt.Errorf("failed to compute refund totals: %w", err)
+	}
+	for _, line := range refund.Lines {
+		refundedTotal += line.Qty
+	}
+	allUnitsRefunded := refundedTotal >= soldTotal
+
+	remainingAmount := amountPaid - alreadyRefundedAmount
+	if remainingAmount < 0 {
+		remainingAmount = 0
+	}
+	if req.Amount != nil {
+		if *req.Amount > remainingAmount {
+			log.Printf("❌ Refund: Amount exceeds amount paid: paid=%d, refunded=%d, requested=%d", amountPaid, alreadyRefundedAmount, *req.Amount)
+			return nil, fmt.Errorf("refund amount exceeds amount paid: paid %d, already refunded %d, requested %d",
+				amountPaid, alreadyRefundedAmount, *req.Amount)
+		}
+		refund.Amount = *req.Amount
+	} else if allUnitsRefunded || refund.Amount > remainingAmount {
+		// Frozen unit prices are rounded (lineTotal / qty): the last units take whatever is left
+		log.Printf("💰 Refund: Refunding remaining amount %d instead of line amount %d", remainingAmount, refund.Amount)
+		refund.Amount = remainingAmount
+	}
+
+	// Insert refund header
+	queryInsertRefund := `
+		INSERT INTO sale_refunds (sale_id, amount, destination, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, queryInsertRefund, saleID, refund.Amount, refund.Destination, refund.Reason).Scan(&refund.ID, &refund.CreatedAt)
+	if err != nil {
+		log.Printf("❌ Refund: Error inserting refund: %v", err)
+		return nil, fmt.Errorf("failed to insert refund: %w", err)
+	}
+
+	// Insert refund lines
+	queryInsertRefundLine := `
+		INSERT INTO sale_refund_lines (sale_refund_id, reserved_order_line_id, item_id, qty, unit_price, amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	for i := range refund.Lines {
+		line := &refund.Lines[i]
+		err = tx.QueryRowContext(ctx, queryInsertRefundLine,
+			refund.ID, line.ReservedOrderLineID, line.ItemID, line.Qty, line.UnitPrice, line.Amount,
+		).Scan(&line.ID)
+		if err != nil {
+			log.Printf("❌ Refund: Error inserting refund line: %v", err)
+			return nil, fmt.Errorf("failed to insert refund line: %w", err)
+		}
+	}
+
+	// Record the money going out (zero-amount refunds, e.g. gifts, have nothing to record)
+	if refund.Amount > 0 {
+		queryInsertTransaction := `
+			INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`
+		_, err = tx.ExecContext(ctx, queryInsertTransaction,
+			"expense",
+			"sale_refund",
+			saleID,
+			time.Now(),
+			refund.Amount,
+			refund.Destination,
+			"devolucion",
+			sql.NullString{},
+			fmt.Sprintf("Devolución venta #%d: %s", saleID, refund.Reason),
+		)
+		if err != nil {
+			log.Printf("❌ Refund: Error inserting finance transaction: %v", err)
+			return nil, fmt.Errorf("failed to insert finance transaction: %w", err)
+		}
+	}
+
+	// Mark sale as refunded once every sold unit has been returned
+	if allUnitsRefunded || (amountPaid > 0 && alreadyRefundedAmount+refund.Amount >= amountPaid) {
+		_, err = tx.ExecContext(ctx, `UPDATE sales SET status = 'refunded' WHERE id = $1`, saleID)
+		if err != nil {
+			log.Printf("❌ Refund: Error updating sale status: %v", err)
+			return nil, fmt.Errorf("failed to update sale status: %w", err)
+		}
+		log.Printf("✅ Refund: Sale id=%d fully refunded", saleID)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ Refund: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("✅ Refund: Successfully refunded sale id=%d, refund id=%d, amount=%d", saleID, refund.ID, refund.Amount)
+	return refund, nil
+}
+
+// VoidSale undoes a sale that was recorded in error (wrong order), as opposed to a customer return:
+// the sale is kept and marked 'voided' (voided_at, void_reason), each of its finance transactions
+// (incomes and reprice adjustments) is reversed by an opposite 'sale_void' transaction, the sold units
+// go back to stock_total and stock_reserved, and the order returns to 'reserved' so it can be corrected
+// and sold again. Only paid sales without refunds can be voided, and only on the day they were sold
+// (store timezone) unless force is set. All operations are performed atomically in a single transaction
+func (r *SaleRepository) VoidSale(ctx context.Context, saleID int64, force bool, reason string) (*models.VoidSaleResponse, error) {
+	log.Printf("📦 VoidSale: Voiding sale id=%d (force=%v)", saleID, force)
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		log.Printf("❌ VoidSale: reason is required")
+		return nil, fmt.Errorf("reason is required to void a sale")
+	}
+
+	// Start transaction
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ VoidSale: Error starting transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock sale
+	response := &models.VoidSaleResponse{SaleID: saleID, OrderStatus: "reserved"}
+	var soldAt time.Time
+	var saleStatus string
+	querySale := `SELECT reserved_order_id, sold_at, amount_paid, status FROM sales WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, querySale, saleID).Scan(&response.ReservedOrderID, &soldAt, &response.AmountPaid, &saleStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ VoidSale: Sale not found: id=%d", saleID)
+			return nil, fmt.Errorf("sale not found")
+		}
+		log.Printf("❌ VoidSale: Error fetching sale: %v", err)
+		return nil, fmt.Errorf("failed to fetch sale: %w", err)
+	}
+
+	if saleStatus == "voided" {
+		log.Printf("❌ VoidSale: Sale id=%d is already voided", saleID)
+		return nil, fmt.Errorf("sale already voided")
+	}
+
+	if !force {
+		loc := utils.AppLocation()
+		if soldAt.In(loc).Format("2006-01-02") != time.Now().In(loc).Format("2006-01-02") {
+			log.Printf("❌ VoidSale: Sale id=%d was sold on %s, outside the void window", saleID, soldAt.In(loc).Format("2006-01-02"))
+			return nil, fmt.Errorf("sale can only be voided on the day it was sold: use force to void it")
+		}
+	}
+
+	var refundCount int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sale_refunds WHERE sale_id = $1`, saleID).Scan(&refundCount)
+	if err != nil {
+		log.Printf("❌ VoidSale: Error checking refunds: %v", err)
+		return nil, fmt.Errorf("failed to check refunds: %w", err)
+	}
+	if refundCount > 0 {
+		log.Printf("❌ VoidSale: Sale id=%d has %d refunds", saleID, refundCount)
+		return nil, fmt.Errorf("sale already has refunds and cannot be voided")
+	}
+	if saleStatus != "paid" {
+		log.Printf("❌ VoidSale: Sale not in paid status: status=%s", saleStatus)
+		return nil, fmt.Errorf("sale not in paid status")
+	}
+
+	// Lock order and validate it is the completed order of this sale
+	var orderStatus string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM reserved_orders WHERE id = $1 FOR UPDATE`, response.ReservedOrderID).Scan(&orderStatus)
+	if err != nil {
+		log.Printf("❌ VoidSale: Error fetching order: %v", err)
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+	if orderStatus != "completed" {
+		log.Printf("❌ VoidSale: Order not in completed status: status=%s", orderStatus)
+		return nil, fmt.Errorf("order not in completed status")
+	}
+
+	// Restore stock: the order is reserved again, so its units go back to stock_total and stock_reserved
+	rows, err := tx.QueryContext(ctx, `SELECT item_id, qty FROM reserved_order_lines WHERE reserved_order_id = $1 ORDER BY item_id`, response.ReservedOrderID)
+	if err != nil {
+		log.Printf("❌ VoidSale: Error fetching lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch order lines: %w", err)
+	}
+	type lineInfo struct {
+		itemID int64
+		qty    int
+	}
+	var lines []lineInfo
+	for rows.Next() {
+		var l lineInfo
+		if err := rows.Scan(&l.itemID, &l.qty); err != nil {
+			rows.Close()
+			log.Printf("❌ VoidSale: Error scanning line: %v", err)
+			return nil, fmt.Errorf("failed to scan order line: %w", err)
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ VoidSale: Error iterating lines: %v", err)
+		return nil, fmt.Errorf("failed to iterate order lines: %w", err)
+	}
+
+	for _, line := range lines {
+		queryUpdateStock := `
+			UPDATE items
+			SET stock_total = stock_total + $1,
+			    stock_reserved = stock_reserved + $1
+			WHERE id = $2
+		`
+		_, err = tx.ExecContext(ctx, queryUpdateStock, line.qty, line.itemID)
+		if err != nil {
+			log.Printf("❌ VoidSale: Error restoring stock for item_id=%d: %v", line.itemID, err)
+			return nil, fmt.Errorf("failed to restore stock: %w", err)
+		}
+		response.RestoredUnits += line.qty
+	}
+
+	// Reverse the money recorded for the sale (one income per payment split, plus reprice adjustments)
+	// with opposite transactions, so the ledger keeps both the sale and its void
+	queryReverse := `
+		INSERT INTO finance_transactions (type, source, source_id, occurred_at, amount, destination, category, counterparty, notes)
+		SELECT CASE WHEN type = 'income' THEN 'expense' ELSE 'income' END, 'sale_void', source_id, NOW(), amount, destination, 'anulacion_venta', NULL, $2
+		FROM finance_transactions
+		WHERE source IN ('sale', 'sale_adjustment') AND source_id = $1
+		ORDER BY id
+	`
+	result, err := tx.ExecContext(ctx, queryReverse, saleID, fmt.Sprintf("Anulación venta #%d: %s", saleID, reason))
+	if err != nil {
+		log.Printf("❌ VoidSale: Error reversing finance transactions: %v", err)
+		return nil, fmt.Errorf("failed to reverse finance transactions: %w", err)
+	}
+	reversed, _ := result.RowsAffected()
+	response.ReversedTransactions = int(reversed)
+
+	// The sale is kept as voided; only non-voided sales count towards sales.reserved_order_id uniqueness,
+	// so the order can be sold again
+	queryVoid := `
+		UPDATE sales
+		SET status = 'voided', voided_at = NOW(), void_reason = $1
+		WHERE id = $2
+		RETURNING id, reserved_order_id, sold_at, customer_name, amount_paid, payment_method, payment_destination, status, notes, created_at, sale_type, gift_reason, COALESCE(seller, ''), voided_at, void_reason
+	`
+	var sale models.Sale
+	var customerName, notes, giftReason, voidedAt, voidReason sql.NullString
+	err = tx.QueryRowContext(ctx, queryVoid, reason, saleID).Scan(
+		&sale.ID,
+		&sale.ReservedOrderID,
+		&sale.SoldAt,
+		&customerName,
+		&sale.AmountPaid,
+		&sale.PaymentMethod,
+		&sale.PaymentDestination,
+		&sale.Status,
+		&notes,
+		&sale.CreatedAt,
+		&sale.SaleType,
+		&giftReason,
+		&sale.Seller,
+		&voidedAt,
+		&voidReason,
+	)
+	if err != nil {
+		log.Printf("❌ VoidSale: Error voiding sale: %v", err)
+		return nil, fmt.Errorf("failed to void sale: %w", err)
+	}
+	sale.CustomerName = customerName.String
+	sale.Notes = notes.String
+	sale.GiftReason = giftReason.String
+	sale.VoidedAt = voidedAt.String
+	sale.VoidReason = voidReason.String
+
+	sale.Payments, err = getSalePayments(ctx, tx.QueryContext, sale.ID)
+	if err != nil {
+		log.Printf("❌ VoidSale: Error fetching payments: %v", err)
+		return nil, err
+	}
+	response.Sale = &sale
+
+	// The order is reserved again with a fresh hold, like Reopen: its old hold has usually passed
+	// and the hold worker would cancel it, releasing the stock restored above
+	queryUpdateOrder := `
+		UPDATE reserved_orders
+		SET status = 'reserved', updated_at = NOW(), ` + resetOrderHold + `
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, queryUpdateOrder, response.ReservedOrderID, newOrderHoldUntil()); err != nil {
+		log.Printf("❌ VoidSale: Error updating order: %v", err)
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ VoidSale: Error committing transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	events.PublishOrderChange(response.ReservedOrderID, events.OrderSaleVoided)
+
+	log.Printf("⚠️ VoidSale: Voided sale id=%d (order id=%d back to reserved, %d units restored, %d transactions reversed)",
+		saleID, response.ReservedOrderID, response.RestoredUnits, response.ReversedTransactions)
+	return response, nil
+}
+
+// ListRefunds retrieves the refund history of a sale with its refunded lines
+func (r *SaleRepository) ListRefunds(ctx context.Context, saleID int64) (*models.SaleRefundListResponse, error) {
+	log.Printf("📦 ListRefunds: Fetching refunds for sale id=%d", saleID)
+
+	var exists bool
+	err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM sales WHERE id = $1)`, saleID).Scan(&exists)
+	if err != nil {
+		log.Printf("❌ ListRefunds: Error checking sale: %v", err)
+		return nil, fmt.Errorf("failed to check sale: %w", err)
+	}
+	if !exists {
+		log.Printf("❌ ListRefunds: Sale not found: id=%d", saleID)
+		return nil, fmt.Errorf("sale not found")
+	}
+
+	queryRefunds := `
+		SELECT id, sale_id, amount, destination, reason, created_at
+		FROM sale_refunds
+		WHERE sale_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := db.DB.QueryContext(ctx, queryRefunds, saleID)
+	if err != nil {
+		log.Printf("❌ ListRefunds: Error fetching refunds: %v", err)
+		return nil, fmt.Errorf("failed to fetch refunds: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.SaleRefundListResponse{
+		SaleID:  saleID,
+		Refunds: []models.SaleRefund{},
+	}
+	refundIndex := make(map[int64]int)
+
+	for rows.Next() {
+		var refund models.SaleRefund
+		if err := rows.Scan(&refund.ID, &refund.SaleID, &refund.Amount, &refund.Destination, &refund.Reason, &refund.CreatedAt); err != nil {
+			log.Printf("❌ ListRefunds: Error scanning refund: %v", err)
+			continue
+		}
+		refund.Lines = []models.SaleRefundLine{}
+		refundIndex[refund.ID] = len(response.Refunds)
+		response.Refunds = append(response.Refunds, refund)
+		response.RefundedAmount += refund.Amount
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ ListRefunds: Error iterating refunds: %v", err)
+		return nil, fmt.Errorf("failed to iterate refunds: %w", err)
+	}
+
+	queryLines := `
+		SELECT srl.id, srl.sale_refund_id, srl.reserved_order_line_id, srl.item_id, srl.qty, srl.unit_price, srl.amount
+		FROM sale_refund_lines srl
+		INNER JOIN sale_refunds sr ON sr.id = srl.sale_refund_id
+		WHERE sr.sale_id = $1
+		ORDER BY srl.id ASC
+	`
+	lineRows, err := db.DB.QueryContext(ctx, queryLines, saleID)
+	if err != nil {
+		log.Printf("❌ ListRefunds: Error fetching refund lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch refund lines: %w", err)
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var line models.SaleRefundLine
+		var refundID int64
+		if err := lineRows.Scan(&line.ID, &refundID, &line.ReservedOrderLineID, &line.ItemID, &line.Qty, &line.UnitPrice, &line.Amount); err != nil {
+			log.Printf("❌ ListRefunds: Error scanning refund line: %v", err)
+			continue
+		}
+		if idx, ok := refundIndex[refundID]; ok {
+			response.Refunds[idx].Lines = append(response.Refunds[idx].Lines, line)
+		}
+	}
+	if err := lineRows.Err(); err != nil {
+		log.Printf("❌ ListRefunds: Error iterating refund lines: %v", err)
+		return nil, fmt.Errorf("failed to iterate refund lines: %w", err)
+	}
+
+	log.Printf("✅ ListRefunds: Successfully fetched %d refunds for sale id=%d", len(response.Refunds), saleID)
+	return response, nil
+}
+
+// resolveSalePayments returns the payment splits of a sale. Without req.Payments, the single-payment
+// fields form a one-element split for amountPaid; otherwise every split is validated and the splits
+// must add up to amountPaid exactly
+func resolveSalePayments(req *models.SellRequest, amountPaid int64) ([]models.SalePayment, error) {
+	if len(req.Payments) == 0 {
+		return []models.SalePayment{{
+			Method:      req.PaymentMethod,
+			Destination: req.PaymentDestination,
+			Amount:      amountPaid,
+		}}, nil
+	}
+
+	payments := make([]models.SalePayment, len(req.Payments))
+	var sum int64
+	for i, payment := range req.Payments {
+		payment.Method = strings.TrimSpace(payment.Method)
+		payment.Destination = strings.TrimSpace(payment.Destination)
+		if payment.Method == "" {
+			return nil, fmt.Errorf("payments[%d]: method is required", i)
+		}
+		if payment.Destination == "" {
+			return nil, fmt.Errorf("payments[%d]: destination is required", i)
+		}
+		if payment.Amount <= 0 {
+			return nil, fmt.Errorf("payments[%d]: amount must be greater than 0", i)
+		}
+		sum += payment.Amount
+		payments[i] = payment
+	}
+	if sum != amountPaid {
+		return nil, fmt.Errorf("payments must sum to the calculated total %d, got %d", amountPaid, sum)
+	}
+	return payments, nil
+}
+
+// primarySalePayment returns the largest split (the first one on ties)
+func primarySalePayment(payments []models.SalePayment) models.SalePayment {
+	primary := payments[0]
+	for _, payment := range payments[1:] {
+		if payment.Amount > primary.Amount {
+			primary = payment
+		}
+	}
+	return primary
+}
+
+// getSalePayments returns the payment splits stored for a sale, largest first (empty for gift sales).
+// query is tx.QueryContext or db.DB.QueryContext
+func getSalePayments(ctx context.Context, query func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error), saleID int64) ([]models.SalePayment, error) {
+	rows, err := query(ctx, `
+		SELECT method, destination, amount
+		FROM sale_payments
+		WHERE sale_id = $1
+		ORDER BY amount DESC, id ASC
+	`, saleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sale payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := []models.SalePayment{}
+	for rows.Next() {
+		var payment models.SalePayment
+		if err := rows.Scan(&payment.Method, &payment.Destination, &payment.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan sale payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sale payments: %w", err)
+	}
+	return payments, nil
+}
+
+// giftPaymentLabel is stored as payment method and destination of gift sales, which move no money
+const giftPaymentLabel = "muestra"
+
+// normalizeSaleType validates a sale type, defaulting to "sale" when empty
+func normalizeSaleType(saleType string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(saleType))
+	switch normalized {
+	case "":
+		return "sale", nil
+	case "sale", "gift":
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("saleType must be 'sale' or 'gift'")
+	}
+}
+
+// ListByReservedOrder retrieves every sale ever recorded for a reserved order, including
+// refunded and voided ones, ordered by sold_at. Each sale carries its refunded and net amounts
+func (r *SaleRepository) ListByReservedOrder(ctx context.Context, reservedOrderID int64) (*models.OrderSalesResponse, error) {
+	log.Printf("📦 ListByReservedOrder: Fetching sales for reserved order id=%d", reservedOrderID)
+
+	var exists bool
+	err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM reserved_orders WHERE id = $1)`, reservedOrderID).Scan(&exists)
+	if err != nil {
+		log.Printf("❌ ListByReservedOrder: Error checking reserved order: %v", err)
+		return nil, fmt.Errorf("failed to check reserved order: %w", err)
+	}
+	if !exists {
+		log.Printf("❌ ListByReservedOrder: Reserved order not found: id=%d", reservedOrderID)
+		return nil, fmt.Errorf("reserved order not found")
+	}
+
+	query := `
+		SELECT s.id, s.sold_at, s.reserved_order_id, s.customer_name, s.amount_paid,
+			s.payment_destination, s.payment_method, s.sale_type, s.status,
+			COALESCE((SELECT SUM(sr.amount) FROM sale_refunds sr WHERE sr.sale_id = s.id), 0)
+		FROM sales s
+		WHERE s.reserved_order_id = $1
+		ORDER BY s.sold_at ASC, s.id ASC
+	`
+	rows, err := db.DB.QueryContext(ctx, query, reservedOrderID)
+	if err != nil {
+		log.Printf("❌ ListByReservedOrder: Error fetching sales: %v", err)
+		return nil, fmt.Errorf("failed to fetch sales: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.OrderSalesResponse{
+		ReservedOrderID: reservedOrderID,
+		Sales:           []models.OrderSaleItem{},
+	}
+
+	for rows.Next() {
+		var sale models.OrderSaleItem
+		var customerName sql.NullString
+
+		err := rows.Scan(
+			&sale.ID,
+			&sale.SoldAt,
+			&sale.ReservedOrderID,
+			&customerName,
+			&sale.AmountPaid,
+			&sale.PaymentDestination,
+			&sale.PaymentMethod,
+			&sale.SaleType,
+			&sale.Status,
+			&sale.RefundedAmount,
+		)
+		if err != nil {
+			log.Printf("❌ ListByReservedOrder: Error scanning sale: %v", err)
+			continue
+		}
+
+		if customerName.Valid {
+			sale.CustomerName = customerName.String
+		}
+		// A voided sale's money was reversed, so it nets to 0
+		if sale.Status != "voided" {
+			sale.NetAmount = sale.AmountPaid - sale.RefundedAmount
+		}
+
+		response.Sales = append(response.Sales, sale)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ ListByReservedOrder: Error iterating sales: %v", err)
+		return nil, fmt.Errorf("failed to iterate sales: %w", err)
+	}
+
+	log.Printf("✅ ListByReservedOrder: Found %d sales for reserved order id=%d", len(response.Sales), reservedOrderID)
+	return response, nil
+}
+
+// SellCheck runs Sell's validations without writing anything and reports every blocker found:
+// order status and existing sale, lines present, items active with enough reserved stock, and
+// a pricing engine that can price every line. It only reads, so the result may go stale
+func (r *SaleRepository) SellCheck(ctx context.Context, reservedOrderID int64) (*models.SellCheckResponse, error) {
+	log.Printf("📦 SellCheck: Checking reserved order id=%d", reservedOrderID)
+
+	response := &models.SellCheckResponse{
+		OrderID: reservedOrderID,
+		Issues:  []models.SellCheckIssue{},
+	}
+	addIssue := func(code, message string, itemID, lineID *int64) {
+		response.Issues = append(response.Issues, models.SellCheckIssue{Code: code, Message: message, ItemID: itemID, LineID: lineID})
+	}
+
+	var discountType string
+	var discountValue int64
+	err := db.DB.QueryRowContext(ctx, `SELECT status, discount_type, discount_value FROM reserved_orders WHERE id = $1`, reservedOrderID).Scan(&response.Status, &discountType, &discountValue)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ SellCheck: Order not found: id=%d", reservedOrderID)
+			return nil, fmt.Errorf("order not found")
+		}
+		log.Printf("❌ SellCheck: Error fetching order: %v", err)
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+
+	var hasSale bool
+	err = db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM sales WHERE reserved_order_id = $1 AND status <> 'voided')`, reservedOrderID).Scan(&hasSale)
+	if err != nil {
+		log.Printf("❌ SellCheck: Error checking existing sale: %v", err)
+		return nil, fmt.Errorf("failed to check existing sale: %w", err)
+	}
+
+	switch {
+	case hasSale:
+		addIssue("already_sold", "order already has a sale associated", nil, nil)
+	case response.Status == "completed":
+		addIssue("already_completed", "order already completed without a sale: stock was already deducted", nil, nil)
+	case response.Status != "reserved":
+		addIssue("not_reserved", fmt.Sprintf("order is %s, not reserved", response.Status), nil, nil)
+	}
+
+	// Stock is checked per item, since an item may appear on more than one line
+	queryItems := `
+		SELECT i.id, i.sku, i.is_active, i.stock_reserved, SUM(rol.qty) as qty
+		FROM reserved_order_lines rol
+		INNER JOIN items i ON rol.item_id = i.id
+		WHERE rol.reserved_order_id = $1
+		GROUP BY i.id
+		ORDER BY i.id ASC
+	`
+	rows, err := db.DB.QueryContext(ctx, queryItems, reservedOrderID)
+	if err != nil {
+		log.Printf("❌ SellCheck: Error fetching lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch order lines: %w", err)
+	}
+	defer rows.Close()
+
+	itemCount := 0
+	for rows.Next() {
+		var itemID int64
+		var sku string
+		var isActive bool
+		var stockReserved, qty int
+		if err := rows.Scan(&itemID, &sku, &isActive, &stockReserved, &qty); err != nil {
+			log.Printf("❌ SellCheck: Error scanning line: %v", err)
+			return nil, fmt.Errorf("failed to scan order line: %w", err)
+		}
+		itemCount++
+
+		id := itemID
+		if !isActive {
+			addIssue("item_inactive", fmt.Sprintf("item %s is inactive", sku), &id, nil)
+		}
+		if stockReserved < qty {
+			addIssue("insufficient_reserved_stock", fmt.Sprintf("item %s has %d reserved, order needs %d", sku, stockReserved, qty), &id, nil)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ SellCheck: Error iterating lines: %v", err)
+		return nil, fmt.Errorf("failed to iterate order lines: %w", err)
+	}
+
+	if itemCount == 0 {
+		addIssue("no_lines", "order has no lines", nil, nil)
+	}
+
+	pricingEngine := pricing.GetEngine()
+	if pricingEngine == nil {
+		addIssue("pricing_unavailable", "pricing engine not initialized", nil, nil)
+	} else if itemCount > 0 {
+		breakdown, err := pricingEngine.CalculateOrderPricing(ctx, reservedOrderID)
+		if err != nil {
+			log.Printf("⚠️ SellCheck: Pricing failed for order %d: %v", reservedOrderID, err)
+			addIssue("pricing_failed", fmt.Sprintf("failed to calculate pricing: %v", err), nil, nil)
+		} else {
+			response.ExpectedTotal, _ = applyOrderDiscount(breakdown.Total, discountType, discountValue)
+			for _, line := range breakdown.Lines {
+				if line.LineTotal <= 0 {
+					itemID, lineID := line.ItemID, line.LineID
+					addIssue("line_not_priceable", fmt.Sprintf("line %d has no price", line.LineID), &itemID, &lineID)
+				}
+			}
+		}
+	}
+
+	response.Sellable = len(response.Issues) == 0
+
+	log.Printf("✅ SellCheck: order id=%d sellable=%v issues=%d", reservedOrderID, response.Sellable, len(response.Issues))
+	return response, nil
+}
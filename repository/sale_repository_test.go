@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckRepriceable(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		saleType    string
+		refundCount int
+		wantErr     string
+	}{
+		{name: "paid sale without refunds", status: "paid", saleType: "sale"},
+		{
+			name:        "partially refunded sale",
+			status:      "paid",
+			saleType:    "sale",
+			refundCount: 1,
+			wantErr:     "already has refunds",
+		},
+		{
+			name:        "fully refunded sale",
+			status:      "refunded",
+			saleType:    "sale",
+			refundCount: 2,
+			wantErr:     "not in paid status",
+		},
+		{
+			name:     "voided sale",
+			status:   "voided",
+			saleType: "sale",
+			wantErr:  "not in paid status",
+		},
+		{
+			name:     "gift sale",
+			status:   "paid",
+			saleType: "gift",
+			wantErr:  "gift sales cannot be repriced",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRepriceable(tt.status, tt.saleType, tt.refundCount)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkRepriceable(%q, %q, %d) = %v, want nil", tt.status, tt.saleType, tt.refundCount, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkRepriceable(%q, %q, %d) = %v, want error containing %q", tt.status, tt.saleType, tt.refundCount, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"armario-mascota-me/db"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxTxAttempts is how many times withTx runs a transaction before giving up
+const maxTxAttempts = 3
+
+// txRetryBackoff is the base delay between attempts; it grows linearly per attempt
+const txRetryBackoff = 50 * time.Millisecond
+
+// withTx runs fn inside a transaction and commits it. When Postgres aborts the
+// transaction with a serialization failure (40001) or a deadlock (40P01) the
+// whole transaction is retried, so fn must not have side effects outside tx.
+func withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = runTx(ctx, fn)
+		if err == nil || !isRetryableTxError(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		log.Printf("⚠️ withTx: Retrying transaction (attempt %d/%d): %v", attempt+1, maxTxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * txRetryBackoff):
+		}
+	}
+	return err
+}
+
+// runTx runs a single attempt of fn inside a transaction
+func runTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("❌ withTx: Error starting transaction: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("❌ withTx: Error committing transaction: %v", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// isRetryableTxError reports whether err is a Postgres serialization failure or deadlock
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"armario-mascota-me/db"
+	"armario-mascota-me/models"
+)
+
+// WebhookFailureRepository handles database operations for webhook dead-letter entries
+type WebhookFailureRepository struct{}
+
+// NewWebhookFailureRepository creates a new WebhookFailureRepository
+func NewWebhookFailureRepository() *WebhookFailureRepository {
+	return &WebhookFailureRepository{}
+}
+
+// Ensure WebhookFailureRepository implements WebhookFailureRepositoryInterface
+var _ WebhookFailureRepositoryInterface = (*WebhookFailureRepository)(nil)
+
+const webhookFailureColumns = `id, sale_id, payload, last_error, attempts, resolved_at, created_at, updated_at`
+
+// scanWebhookFailure scans a row selected with webhookFailureColumns
+func scanWebhookFailure(scanner interface{ Scan(dest ...any) error }) (*models.WebhookFailure, error) {
+	var failure models.WebhookFailure
+	var payload []byte
+	var resolvedAt sql.NullString
+	err := scanner.Scan(
+		&failure.ID,
+		&failure.SaleID,
+		&payload,
+		&failure.LastError,
+		&failure.Attempts,
+		&resolvedAt,
+		&failure.CreatedAt,
+		&failure.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	failure.Payload = payload
+	if resolvedAt.Valid {
+		failure.ResolvedAt = &resolvedAt.String
+	}
+	return &failure, nil
+}
+
+// Create stores an undelivered sale webhook
+func (r *WebhookFailureRepository) Create(ctx context.Context, saleID int64, payload []byte, lastError string, attempts int) (*models.WebhookFailure, error) {
+	log.Printf("📦 CreateWebhookFailure: sale_id=%d, attempts=%d", saleID, attempts)
+
+	query := `
+		INSERT INTO webhook_failures (sale_id, payload, last_error, attempts)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + webhookFailureColumns
+
+	failure, err := scanWebhookFailure(db.DB.QueryRowContext(ctx, query, saleID, string(payload), lastError, attempts))
+	if err != nil {
+		log.Printf("❌ CreateWebhookFailure: Error inserting webhook failure: %v", err)
+		return nil, fmt.Errorf("failed to insert webhook failure: %w", err)
+	}
+
+	log.Printf("✅ CreateWebhookFailure: Stored webhook failure id=%d for sale_id=%d", failure.ID, saleID)
+	return failure, nil
+}
+
+// List returns webhook failures, newest first. Resolved entries are only included when requested
+func (r *WebhookFailureRepository) List(ctx context.Context, includeResolved bool) ([]models.WebhookFailure, error) {
+	log.Printf("📦 ListWebhookFailures: includeResolved=%v", includeResolved)
+
+	query := `SELECT ` + webhookFailureColumns + ` FROM webhook_failures`
+	if !includeResolved {
+		query += ` WHERE resolved_at IS NULL`
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := db.DB.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("❌ ListWebhookFailures: Error fetching webhook failures: %v", err)
+		return nil, fmt.Errorf("failed to fetch webhook failures: %w", err)
+	}
+	defer rows.Close()
+
+	failures := []models.WebhookFailure{}
+	for rows.Next() {
+		failure, err := scanWebhookFailure(rows)
+		if err != nil {
+			log.Printf("❌ ListWebhookFailures: Error scanning webhook failure: %v", err)
+			continue
+		}
+		failures = append(failures, *failure)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("❌ ListWebhookFailures: Error iterating webhook failures: %v", err)
+		return nil, fmt.Errorf("failed to iterate webhook failures: %w", err)
+	}
+
+	log.Printf("✅ ListWebhookFailures: Successfully fetched %d webhook failures", len(failures))
+	return failures, nil
+}
+
+// GetByID retrieves a webhook failure by ID
+func (r *WebhookFailureRepository) GetByID(ctx context.Context, id int64) (*models.WebhookFailure, error) {
+	log.Printf("📦 GetWebhookFailure: Fetching webhook failure id=%d", id)
+
+	query := `SELECT ` + webhookFailureColumns + ` FROM webhook_failures WHERE id = $1`
+	failure, err := scanWebhookFailure(db.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("❌ GetWebhookFailure: Webhook failure not found: id=%d", id)
+			return nil, fmt.Errorf("webhook failure not found")
+		}
+		log.Printf("❌ GetWebhookFailure: Error fetching webhook failure: %v", err)
+		return nil, fmt.Errorf("failed to fetch webhook failure: %w", err)
+	}
+	return failure, nil
+}
+
+// RecordRetry records the outcome of a manual retry: success resolves the entry,
+// failure increments attempts and stores the new error
+func (r *WebhookFailureRepository) RecordRetry(ctx context.Context, id int64, retryErr error) (*models.WebhookFailure, error) {
+	log.Printf("📦 RecordWebhookRetry: id=%d, success=%v", id, retryErr == nil)
+
+	var query string
+	var args []interface{}
+	if retryErr == nil {
+		query = `
+			UPDATE webhook_failures
+			SET attempts = attempts + 1, resolved_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+			RETURNING ` + webhookFailureColumns
+		args = []interface{}{id}
+	} else {
+		query = `
+			UPDATE webhook_failures
+			SET attempts = attempts + 1, last_error = $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING ` + webhookFailureColumns
+		args = []interface{}{id, retryErr.Error()}
+	}
+
+	failure, err := scanWebhookFailure(db.DB.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook failure not found")
+		}
+		log.Printf("❌ RecordWebhookRetry: Error updating webhook failure: %v", err)
+		return nil, fmt.Errorf("failed to update webhook failure: %w", err)
+	}
+	return failure, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"armario-mascota-me/models"
+)
+
+// autoTagGroups are the named regex groups a rule may use to extract attributes
+var autoTagGroups = map[string]bool{
+	"colorPrimary":   true,
+	"colorSecondary": true,
+	"hoodieType":     true,
+	"imageType":      true,
+	"decoBase":       true,
+}
+
+// AutoTagRule extracts design attributes from a pending asset's filename or code.
+// Pattern is a regular expression whose named groups (see autoTagGroups) become the parsed values
+type AutoTagRule struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"` // "filename" (default) or "code"
+	Pattern string `json:"pattern"`
+	re      *regexp.Regexp
+}
+
+// AutoTagConfig represents the auto-tag rules file
+type AutoTagConfig struct {
+	Rules []AutoTagRule `json:"rules"`
+}
+
+// AutoTagService matches pending design assets against the configured naming rules
+type AutoTagService struct {
+	rules []AutoTagRule
+}
+
+// NewAutoTagService loads and compiles the auto-tag rules from configPath
+func NewAutoTagService(configPath string) (*AutoTagService, error) {
+	if !filepath.IsAbs(configPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get working directory: %w", err)
+		}
+		configPath = filepath.Join(wd, configPath)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auto-tag rules: %w", err)
+	}
+
+	var config AutoTagConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse auto-tag rules: %w", err)
+	}
+
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("invalid auto-tag rules: rule %d has no name", i)
+		}
+		if rule.Source == "" {
+			rule.Source = "filename"
+		}
+		if rule.Source != "filename" && rule.Source != "code" {
+			return nil, fmt.Errorf("invalid auto-tag rules: rule %s has unknown source %q", rule.Name, rule.Source)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto-tag rules: rule %s: %w", rule.Name, err)
+		}
+		for _, group := range re.SubexpNames() {
+			if group != "" && !autoTagGroups[group] {
+				return nil, fmt.Errorf("invalid auto-tag rules: rule %s uses unknown group %q", rule.Name, group)
+			}
+		}
+		rule.re = re
+	}
+
+	return &AutoTagService{rules: config.Rules}, nil
+}
+
+// Parse returns the first rule matching the asset and the attributes it extracted.
+// Parsed values are trimmed and uppercased to match the stored codes
+func (s *AutoTagService) Parse(asset models.DesignAssetDetail) (string, models.AutoTagFields, bool) {
+	for _, rule := range s.rules {
+		input := asset.SourceFilename
+		if rule.Source == "code" {
+			input = asset.Code
+		}
+		if input == "" {
+			continue
+		}
+
+		matches := rule.re.FindStringSubmatch(input)
+		if matches == nil {
+			continue
+		}
+
+		var fields models.AutoTagFields
+		for i, group := range rule.re.SubexpNames() {
+			value := strings.ToUpper(strings.TrimSpace(matches[i]))
+			switch group {
+			case "colorPrimary":
+				fields.ColorPrimary = value
+			case "colorSecondary":
+				fields.ColorSecondary = value
+			case "hoodieType":
+				fields.HoodieType = value
+			case "imageType":
+				fields.ImageType = value
+			case "decoBase":
+				fields.DecoBase = value
+			}
+		}
+		if fields.IsEmpty() {
+			continue
+		}
+		return rule.Name, fields, true
+	}
+	return "", models.AutoTagFields{}, false
+}
@@ -9,16 +9,19 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"armario-mascota-me/metrics"
 	"armario-mascota-me/models"
 	"armario-mascota-me/pricing"
 	"armario-mascota-me/repository"
 	"armario-mascota-me/utils"
 
-	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 )
 
@@ -106,10 +109,11 @@ func (s *CatalogService) fetchImageAsBase64(imageURL string) (string, error) {
 }
 
 // convertItemsToBase64 converts image URLs to base64 for all items
-func (s *CatalogService) convertItemsToBase64(ctx context.Context, items []models.CatalogItem) {
+// Image URLs are signed with a render token for the catalog size so the fetch passes admin auth
+func (s *CatalogService) convertItemsToBase64(ctx context.Context, items []models.CatalogItem, size string) {
 	for i := range items {
 		if items[i].ImageURL != "" {
-			base64, err := s.fetchImageAsBase64(items[i].ImageURL)
+			base64, err := s.fetchImageAsBase64(utils.SignRenderImageURL(items[i].ImageURL, size))
 			if err != nil {
 				log.Printf("⚠️  Warning: Failed to fetch image for item %d: %v", items[i].ID, err)
 				// Continue without image
@@ -163,9 +167,61 @@ func (s *CatalogService) loadStaticAsset(filename string, useBase64 bool) (strin
 	return urlPath, "", nil
 }
 
-// paginateItems splits items into pages of 9 items each
-func paginateItems(items []models.CatalogItem) [][]models.CatalogItem {
-	const itemsPerPage = 9
+// CatalogOptions holds the optional catalog parameters that must reach the render endpoint
+// so PDF/PNG output matches the requested catalog
+type CatalogOptions struct {
+	PerPage int
+	Filters repository.CatalogFilterParams
+	// Paper is the page size; the zero value means utils.DefaultCatalogPaper (210mm x 350mm)
+	Paper utils.CatalogPaper
+}
+
+// pdfPaperSize returns the PDF paper size in inches for a catalog paper, keeping
+// CatalogPaperSize for the default so existing catalogs print exactly as before
+func pdfPaperSize(paper utils.CatalogPaper) PDFPaperSize {
+	if paper.IsDefault() {
+		return CatalogPaperSize
+	}
+	paper = paper.OrDefault()
+	return PDFPaperSize{Width: paper.WidthMM / 25.4, Height: paper.HeightMM / 25.4}
+}
+
+// CatalogRenderScope returns the render token scope for a list of sizes: the size itself for a
+// single-size catalog, or the comma-separated sizes for a combined one
+func CatalogRenderScope(sizes []string) string {
+	return strings.Join(sizes, ",")
+}
+
+// buildRenderURL builds the internal render URL for one or more sizes, signed with a short-lived
+// render token so chromedp can load it even when /admin/* requires authentication.
+// A single size is passed as size=XS; several sizes as sizes=MN,IT,S
+func (s *CatalogService) buildRenderURL(sizes []string, opts CatalogOptions) string {
+	scope := CatalogRenderScope(sizes)
+	sizeParam := "size"
+	if len(sizes) > 1 {
+		sizeParam = "sizes"
+	}
+	renderURL := fmt.Sprintf("%s/admin/catalog/render?%s=%s&perPage=%d",
+		s.baseURL, sizeParam, url.QueryEscape(scope), opts.PerPage)
+	if opts.Filters.ColorPrimary != "" {
+		renderURL += "&color=" + url.QueryEscape(opts.Filters.ColorPrimary)
+	}
+	if opts.Filters.HoodieType != "" {
+		renderURL += "&hoodieType=" + url.QueryEscape(opts.Filters.HoodieType)
+	}
+	if opts.Filters.Sort != "" {
+		renderURL += "&sort=" + url.QueryEscape(opts.Filters.Sort)
+	}
+	if !opts.Paper.IsDefault() {
+		renderURL += "&paper=custom&paperWidth=" + url.QueryEscape(strconv.FormatFloat(opts.Paper.WidthMM, 'f', -1, 64)) +
+			"&paperHeight=" + url.QueryEscape(strconv.FormatFloat(opts.Paper.HeightMM, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s&%s=%s", renderURL, utils.RenderTokenParam, url.QueryEscape(utils.GenerateRenderToken(scope)))
+}
+
+// paginateItems splits items into pages of perPage items each (clamped to the allowed range)
+func paginateItems(items []models.CatalogItem, perPage int) [][]models.CatalogItem {
+	itemsPerPage := utils.ClampCatalogPerPage(perPage)
 	var pages [][]models.CatalogItem
 
 	for i := 0; i < len(items); i += itemsPerPage {
@@ -179,15 +235,52 @@ func paginateItems(items []models.CatalogItem) [][]models.CatalogItem {
 	return pages
 }
 
-// RenderCatalogHTML renders the catalog HTML template
-func (s *CatalogService) RenderCatalogHTML(ctx context.Context, size string, items []models.CatalogItem, useBase64 bool) (string, error) {
-	// Convert images to base64 if needed for HTML direct view (not for PDF/PNG)
-	if useBase64 {
-		s.convertItemsToBase64(ctx, items)
-	}
+// CatalogSizeGroup holds the items of one size within a (possibly combined) catalog
+type CatalogSizeGroup struct {
+	Size  string
+	Items []models.CatalogItem
+}
+
+// catalogSizeSection is the template data for one size: its intro page prices and product pages
+type catalogSizeSection struct {
+	Size           string
+	Pages          [][]models.CatalogItem
+	RetailPrice    string
+	WholesalePrice string
+}
 
-	// Paginate items
-	pages := paginateItems(items)
+// RenderCatalogHTML renders the catalog HTML template with perPage items per product page on the given paper.
+// Each group is rendered as its own intro page followed by its product pages, in the given order
+func (s *CatalogService) RenderCatalogHTML(ctx context.Context, groups []CatalogSizeGroup, useBase64 bool, perPage int, paper utils.CatalogPaper) (string, error) {
+	engine := pricing.GetEngine()
+	sections := make([]catalogSizeSection, 0, len(groups))
+	for _, group := range groups {
+		// Convert images to base64 if needed for HTML direct view (not for PDF/PNG)
+		if useBase64 {
+			s.convertItemsToBase64(ctx, group.Items, group.Size)
+		} else {
+			// Sign image URLs so chromedp can load them through admin auth
+			for i := range group.Items {
+				if group.Items[i].ImageURL != "" {
+					group.Items[i].ImageURL = utils.SignRenderImageURL(group.Items[i].ImageURL, group.Size)
+				}
+			}
+		}
+
+		section := catalogSizeSection{
+			Size:  group.Size,
+			Pages: paginateItems(group.Items, perPage),
+		}
+
+		// Pricing for intro page (BUSOS pricebook by size bucket)
+		if engine != nil {
+			if r, w, ok := engine.GetCatalogBusoPrices(group.Size); ok {
+				section.RetailPrice = utils.FormatCOP(r)
+				section.WholesalePrice = utils.FormatCOP(w)
+			}
+		}
+		sections = append(sections, section)
+	}
 
 	// Always use absolute URLs for logo and background
 	// Determine file extension
@@ -227,33 +320,27 @@ func (s *CatalogService) RenderCatalogHTML(ctx context.Context, size string, ite
 		introURL = fmt.Sprintf("%s/static/catalog/intro%s", s.baseURL, introExt)
 	}
 
-	// Pricing for intro page (BUSOS pricebook by size bucket)
-	retailPrice := ""
-	wholesalePrice := ""
-	if engine := pricing.GetEngine(); engine != nil {
-		if r, w, ok := engine.GetCatalogBusoPrices(size); ok {
-			retailPrice = utils.FormatCOP(r)
-			wholesalePrice = utils.FormatCOP(w)
-		}
-	}
-
 	// Prepare template data
+	sizes := make([]string, 0, len(sections))
+	for _, section := range sections {
+		sizes = append(sizes, section.Size)
+	}
 	templateData := struct {
-		Size           string
-		Pages          [][]models.CatalogItem
-		LogoURL        string
-		BackgroundURL  string
-		IntroURL       string
-		RetailPrice    string
-		WholesalePrice string
+		Size          string
+		Sections      []catalogSizeSection
+		LogoURL       string
+		BackgroundURL string
+		IntroURL      string
+		PageWidth     string
+		PageHeight    string
 	}{
-		Size:           size,
-		Pages:          pages,
-		LogoURL:        logoURL,
-		BackgroundURL:  backgroundURL,
-		IntroURL:       introURL,
-		RetailPrice:    retailPrice,
-		WholesalePrice: wholesalePrice,
+		Size:          strings.Join(sizes, ", "),
+		Sections:      sections,
+		LogoURL:       logoURL,
+		BackgroundURL: backgroundURL,
+		IntroURL:      introURL,
+		PageWidth:     paper.CSSWidth(),
+		PageHeight:    paper.CSSHeight(),
 	}
 
 	// Load template
@@ -274,56 +361,18 @@ func (s *CatalogService) RenderCatalogHTML(ctx context.Context, size string, ite
 }
 
 // GeneratePDF generates a PDF from HTML using chromedp
-// size parameter is used to construct the render URL
-func (s *CatalogService) GeneratePDF(ctx context.Context, size string) ([]byte, error) {
-	// Create context with timeout (30 seconds)
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	// Detect Chrome/Chromium path and configure chromedp
-	chromePath := detectChromePath()
-	var allocCtx context.Context
-	var allocCancel context.CancelFunc
-
-	if chromePath != "" {
-		// Use detected Chrome path
-		opts := append(chromedp.DefaultExecAllocatorOptions[:],
-			chromedp.ExecPath(chromePath),
-			chromedp.NoSandbox,                          // Required for running in Docker/containers
-			chromedp.Flag("enable-print-preview", true), // Enable print preview
-		)
-		allocCtx, allocCancel = chromedp.NewExecAllocator(ctx, opts...)
-		defer allocCancel()
-	} else {
-		// Let chromedp auto-detect (may fail in containers)
-		opts := append(chromedp.DefaultExecAllocatorOptions[:],
-			chromedp.NoSandbox,
-			chromedp.Flag("enable-print-preview", true), // Enable print preview
-		)
-		allocCtx, allocCancel = chromedp.NewExecAllocator(ctx, opts...)
-		defer allocCancel()
-	}
-
-	chromedpCtx, chromedpCancel := chromedp.NewContext(allocCtx)
-	defer chromedpCancel()
-
-	// Enable Page domain for printing
-	if err := chromedp.Run(chromedpCtx, chromedp.ActionFunc(func(ctx context.Context) error {
-		return page.Enable().Do(ctx)
-	})); err != nil {
-		// Log warning but continue
-	}
-
+// sizes and opts are used to construct the render URL; several sizes produce one combined PDF
+func (s *CatalogService) GeneratePDF(ctx context.Context, sizes []string, opts CatalogOptions) ([]byte, error) {
 	// Construct render URL
-	renderURL := fmt.Sprintf("%s/admin/catalog/render?size=%s", s.baseURL, size)
-
-	var pdfBuf []byte
+	renderURL := s.buildRenderURL(sizes, opts)
 
 	// Run chromedp with proper viewport and wait for network/idle
-	// 210mm = 794px at 96 DPI, 350mm = 1323px at 96 DPI
+	// The viewport width is the paper width at 96 DPI (210mm = 794px)
 	// Use a larger viewport height to accommodate multiple pages
-	err := chromedp.Run(chromedpCtx,
-		chromedp.EmulateViewport(794, 5000), // Large height to show all pages
+	viewportWidth, _ := opts.Paper.ViewportSize()
+	start := time.Now()
+	pdfData, err := RenderPDF(ctx, pdfPaperSize(opts.Paper),
+		chromedp.EmulateViewport(viewportWidth, 5000), // Large height to show all pages
 		chromedp.Navigate(renderURL),
 		chromedp.WaitReady("body"),
 		chromedp.Sleep(2000), // Wait for initial page load
@@ -347,51 +396,49 @@ func (s *CatalogService) GeneratePDF(ctx context.Context, size string) ([]byte,
 			})();
 		`, nil),
 		// Set html and body width, but let height be auto to accommodate all pages
-		chromedp.Evaluate(`
-			document.documentElement.style.width = '210mm';
+		chromedp.Evaluate(fmt.Sprintf(`
+			document.documentElement.style.width = '%[1]s';
 			document.documentElement.style.height = 'auto';
-			document.documentElement.style.minHeight = '350mm';
-			document.body.style.width = '210mm';
+			document.documentElement.style.minHeight = '%[2]s';
+			document.body.style.width = '%[1]s';
 			document.body.style.height = 'auto';
-			document.body.style.minHeight = '350mm';
-		`, nil),
+			document.body.style.minHeight = '%[2]s';
+		`, opts.Paper.CSSWidth(), opts.Paper.CSSHeight()), nil),
 		chromedp.Sleep(1000), // Final wait for layout
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			var err error
-			// 210mm x 350mm = 8.27" x 13.78" (1mm = 0.03937 inches)
-			// PrintToPDF will automatically handle page breaks via CSS page-break-after
-			pdfBuf, _, err = page.PrintToPDF().
-				WithPrintBackground(true).
-				WithPaperWidth(8.27).   // 210mm in inches
-				WithPaperHeight(13.78). // 350mm in inches
-				WithMarginTop(0).       // No margins, padding is in CSS
-				WithMarginBottom(0).
-				WithMarginLeft(0).
-				WithMarginRight(0).
-				Do(ctx)
-			return err
-		}),
 	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate PDF: %w", err)
-	}
-
-	return pdfBuf, nil
+	metrics.CatalogRenderDuration.ObserveSince(start, "pdf", metrics.Result(err))
+	return pdfData, err
 }
 
 // GeneratePNG generates PNG images from HTML using chromedp
 // Returns a map of page number to PNG data, or error
-// size parameter is used to construct the render URL
-func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int][]byte, error) {
-	// Get items to calculate expected page count
-	items, err := s.repository.GetItemsBySizeForCatalog(ctx, size)
+// sizes and opts are used to construct the render URL; pages of several sizes are numbered consecutively
+func (s *CatalogService) GeneratePNG(ctx context.Context, sizes []string, opts CatalogOptions) (map[int][]byte, error) {
+	start := time.Now()
+	pngs, err := s.generatePNG(ctx, sizes, opts)
+	metrics.CatalogRenderDuration.ObserveSince(start, "png", metrics.Result(err))
+	return pngs, err
+}
+
+// generatePNG renders and screenshots the catalog pages for GeneratePNG
+func (s *CatalogService) generatePNG(ctx context.Context, sizes []string, opts CatalogOptions) (map[int][]byte, error) {
+	size := CatalogRenderScope(sizes)
+
+	// Get items to calculate expected page count, summed across sizes
 	var expectedPages int
-	if err != nil {
-		expectedPages = 0
-	} else {
-		// Ceiling division for product pages (9 items per page) + 1 intro page
-		expectedPages = (len(items)+8)/9 + 1
+	itemsPerPage := utils.ClampCatalogPerPage(opts.PerPage)
+	for _, groupSize := range sizes {
+		items, err := s.repository.GetItemsBySizeForCatalog(ctx, groupSize, opts.Filters)
+		if err != nil {
+			expectedPages = 0
+			break
+		}
+		// Sizes without items are left out of the rendered catalog
+		if len(items) == 0 {
+			continue
+		}
+		// Ceiling division for product pages (perPage items per page) + 1 intro page
+		expectedPages += (len(items)+itemsPerPage-1)/itemsPerPage + 1
 	}
 
 	// PNG generation can be slower than PDF because we screenshot each page.
@@ -432,13 +479,16 @@ func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int]
 	defer chromedpCancel()
 
 	// Construct render URL
-	renderURL := fmt.Sprintf("%s/admin/catalog/render?size=%s", s.baseURL, size)
+	renderURL := s.buildRenderURL(sizes, opts)
+
+	// Viewport matching one page at 96 DPI (210mm x 350mm = 794 x 1323)
+	viewportWidth, viewportHeight := opts.Paper.ViewportSize()
 
 	// Get page count using JavaScript evaluation
 	// Use a larger viewport to see all pages
 	var pageCountVal float64
-	err = chromedp.Run(chromedpCtx,
-		chromedp.EmulateViewport(794, 5000), // Large height to see all pages
+	err := chromedp.Run(chromedpCtx,
+		chromedp.EmulateViewport(viewportWidth, 5000), // Large height to see all pages
 		chromedp.Navigate(renderURL),
 		chromedp.WaitReady("body"),
 		chromedp.Sleep(2000), // Wait for initial page load
@@ -462,14 +512,14 @@ func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int]
 			})();
 		`, nil),
 		// Set width but let height be auto to show all pages
-		chromedp.Evaluate(`
-			document.documentElement.style.width = '210mm';
+		chromedp.Evaluate(fmt.Sprintf(`
+			document.documentElement.style.width = '%[1]s';
 			document.documentElement.style.height = 'auto';
-			document.documentElement.style.minHeight = '350mm';
-			document.body.style.width = '210mm';
+			document.documentElement.style.minHeight = '%[2]s';
+			document.body.style.width = '%[1]s';
 			document.body.style.height = 'auto';
-			document.body.style.minHeight = '350mm';
-		`, nil),
+			document.body.style.minHeight = '%[2]s';
+		`, opts.Paper.CSSWidth(), opts.Paper.CSSHeight()), nil),
 		chromedp.Sleep(2000), // Wait for initial layout
 		// Scroll to bottom to ensure all pages are rendered
 		chromedp.Evaluate(`
@@ -533,7 +583,7 @@ func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int]
 	if pageCount == 1 {
 		var buf []byte
 		err = chromedp.Run(chromedpCtx,
-			chromedp.EmulateViewport(794, 1323),
+			chromedp.EmulateViewport(viewportWidth, viewportHeight),
 			chromedp.Navigate(renderURL),
 			chromedp.WaitReady("body"),
 			chromedp.Sleep(2000),
@@ -557,12 +607,12 @@ func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int]
 				})();
 			`, nil),
 			// Set body and html to exact size
-			chromedp.Evaluate(`
-				document.documentElement.style.width = '210mm';
-				document.documentElement.style.height = '350mm';
-				document.body.style.width = '210mm';
-				document.body.style.height = '350mm';
-			`, nil),
+			chromedp.Evaluate(fmt.Sprintf(`
+				document.documentElement.style.width = '%[1]s';
+				document.documentElement.style.height = '%[2]s';
+				document.body.style.width = '%[1]s';
+				document.body.style.height = '%[2]s';
+			`, opts.Paper.CSSWidth(), opts.Paper.CSSHeight()), nil),
 			chromedp.Sleep(1000),
 			chromedp.CaptureScreenshot(&buf),
 		)
@@ -605,7 +655,7 @@ func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int]
 			buf = nil
 			lastErr = chromedp.Run(chromedpCtx,
 				// Set viewport to match page size
-				chromedp.EmulateViewport(794, 1323), // 210mm x 350mm
+				chromedp.EmulateViewport(viewportWidth, viewportHeight),
 				// Hide all pages except the current one and adjust body height
 				chromedp.Evaluate(fmt.Sprintf(`
 					(function() {
@@ -614,7 +664,7 @@ func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int]
 							return 0;
 						}
 						pages.forEach((page, index) => {
-							if (index === %d - 1) {
+							if (index === %[1]d - 1) {
 								page.style.display = 'flex';
 								page.style.visibility = 'visible';
 								page.style.position = 'relative';
@@ -624,15 +674,15 @@ func (s *CatalogService) GeneratePNG(ctx context.Context, size string) (map[int]
 							}
 						});
 						// Adjust body and html height to match single page
-						document.documentElement.style.width = '210mm';
-						document.documentElement.style.height = '350mm';
+						document.documentElement.style.width = '%[2]s';
+						document.documentElement.style.height = '%[3]s';
 						document.documentElement.style.overflow = 'hidden';
-						document.body.style.width = '210mm';
-						document.body.style.height = '350mm';
+						document.body.style.width = '%[2]s';
+						document.body.style.height = '%[3]s';
 						document.body.style.overflow = 'hidden';
 						return pages.length;
 					})();
-				`, pageNum), nil),
+				`, pageNum, opts.Paper.CSSWidth(), opts.Paper.CSSHeight()), nil),
 				chromedp.Sleep(900), // Wait for display change and layout
 				chromedp.CaptureScreenshot(&buf),
 			)
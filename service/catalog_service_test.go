@@ -0,0 +1,80 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"armario-mascota-me/repository"
+	"armario-mascota-me/utils"
+)
+
+// TestBuildRenderURLPassesRenderAuth checks that the URLs chromedp loads while generating a PDF/PNG
+// carry a render token the admin auth middleware accepts, and only for the sizes they were built for
+func TestBuildRenderURLPassesRenderAuth(t *testing.T) {
+	s := &CatalogService{baseURL: "http://localhost:8080"}
+
+	tests := []struct {
+		name      string
+		sizes     []string
+		opts      CatalogOptions
+		otherSize string
+	}{
+		{
+			name:      "single size",
+			sizes:     []string{"XS"},
+			opts:      CatalogOptions{PerPage: 9},
+			otherSize: "L",
+		},
+		{
+			name:      "combined sizes",
+			sizes:     []string{"MN", "IT", "S"},
+			opts:      CatalogOptions{PerPage: 6},
+			otherSize: "MN",
+		},
+		{
+			name:  "filters, sort and custom paper",
+			sizes: []string{"M"},
+			opts: CatalogOptions{
+				PerPage: 4,
+				Filters: repository.CatalogFilterParams{ColorPrimary: "NG", HoodieType: "BU", Sort: "newest"},
+				Paper:   utils.CatalogPaper{WidthMM: 210, HeightMM: 297},
+			},
+			otherSize: "XS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderURL := s.buildRenderURL(tt.sizes, tt.opts)
+			r := httptest.NewRequest(http.MethodGet, renderURL, nil)
+			if !utils.IsAuthorizedRenderRequest(r) {
+				t.Fatalf("render URL %s is not accepted by render auth", renderURL)
+			}
+
+			// Swapping the size must invalidate the token
+			sizeParam := "size"
+			if len(tt.sizes) > 1 {
+				sizeParam = "sizes"
+			}
+			query := r.URL.Query()
+			query.Set(sizeParam, tt.otherSize)
+			r.URL.RawQuery = query.Encode()
+			if utils.IsAuthorizedRenderRequest(r) {
+				t.Errorf("render URL with %s=%s is accepted, want rejected", sizeParam, tt.otherSize)
+			}
+
+			// Images on the rendered page are signed for each size of the catalog
+			for _, size := range tt.sizes {
+				imageURL := utils.SignRenderImageURL("/admin/design-assets/pending/7/image?size=medium", size)
+				if !utils.IsAuthorizedRenderRequest(httptest.NewRequest(http.MethodGet, imageURL, nil)) {
+					t.Errorf("image URL %s is not accepted by render auth", imageURL)
+				}
+				if !strings.Contains(imageURL, "size=medium") {
+					t.Errorf("image URL %s lost its image size", imageURL)
+				}
+			}
+		})
+	}
+}
@@ -121,7 +121,7 @@ func (ds *DownloadService) DownloadAllImages(folderID string) (int, int, int, []
 		}
 
 		// Optimize image
-		optimizedData, err := OptimizeImage(imageData, "medium")
+		optimizedData, err := OptimizeImage(imageData, "medium", 0)
 		if err != nil {
 			errorMsg := fmt.Sprintf("Failed to optimize image %s (%s): %v", fileName, asset.DriveFileID, err)
 			log.Printf("❌ %s", errorMsg)
@@ -67,7 +67,7 @@ func (ds *DriveService) ListDesignAssets(folderID string) ([]models.DesignAsset,
 	for {
 		call := ds.client.Files.List().
 			Q(query).
-			Fields("nextPageToken, files(id, name, mimeType, createdTime, modifiedTime)")
+			Fields("nextPageToken, files(id, name, mimeType, createdTime, modifiedTime, size, imageMediaMetadata(width, height))")
 
 		if pageToken != "" {
 			call = call.PageToken(pageToken)
@@ -110,6 +110,12 @@ func (ds *DriveService) ListDesignAssets(folderID string) ([]models.DesignAsset,
 		asset := models.DesignAsset{
 			DriveFileID: file.Id,
 			ImageURL:    imageURL,
+			SizeBytes:   file.Size,
+			FileName:    file.Name,
+		}
+		if file.ImageMediaMetadata != nil {
+			asset.Width = int(file.ImageMediaMetadata.Width)
+			asset.Height = int(file.ImageMediaMetadata.Height)
 		}
 
 		designAssets = append(designAssets, asset)
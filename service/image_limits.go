@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	// Default limits for source (original) images before optimization
+	defaultMaxSourceBytes     = 25 * 1024 * 1024
+	defaultMaxSourceDimension = 8000
+)
+
+// ErrSourceImageTooLarge is returned when an original image exceeds the configured source limits
+var ErrSourceImageTooLarge = errors.New("source image exceeds configured limits")
+
+// MaxSourceImageBytes returns the maximum accepted size of an original image in bytes
+// Configurable via IMAGE_MAX_SOURCE_BYTES (default 25 MB)
+func MaxSourceImageBytes() int64 {
+	return int64(envPositiveInt("IMAGE_MAX_SOURCE_BYTES", defaultMaxSourceBytes))
+}
+
+// MaxSourceImageDimension returns the maximum accepted width/height of an original image in pixels
+// Configurable via IMAGE_MAX_SOURCE_DIMENSION (default 8000)
+func MaxSourceImageDimension() int {
+	return envPositiveInt("IMAGE_MAX_SOURCE_DIMENSION", defaultMaxSourceDimension)
+}
+
+// CheckSourceImageLimits returns a human-readable rejection reason when the original exceeds the limits,
+// or "" when it is acceptable. Zero values mean "unknown" and are not checked.
+func CheckSourceImageLimits(sizeBytes int64, width, height int) string {
+	if maxBytes := MaxSourceImageBytes(); sizeBytes > maxBytes {
+		return fmt.Sprintf("source size %d bytes exceeds limit of %d bytes", sizeBytes, maxBytes)
+	}
+	if maxDim := MaxSourceImageDimension(); width > maxDim || height > maxDim {
+		return fmt.Sprintf("source dimensions %dx%d exceed limit of %dpx", width, height, maxDim)
+	}
+	return ""
+}
+
+// envPositiveInt reads a positive integer from the environment, falling back to def when unset or invalid
+func envPositiveInt(name string, def int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		log.Printf("⚠️  Invalid %s=%q, using default %d", name, raw, def)
+		return def
+	}
+	return value
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"image"
 	"image/color"
@@ -10,6 +12,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/disintegration/imaging"
 )
@@ -22,11 +26,27 @@ const (
 	// Size settings (max dimension)
 	maxSizeThumb  = 300
 	maxSizeMedium = 800
+	// Bounds for an explicit pixel width (?w=) requested instead of a named size
+	minImageWidth = 64
+	maxImageWidth = 1200
 	// Background color for PNG transparency flattening
 	// Using white (#FFFFFF) as default
 	backgroundColor = "#FFFFFF"
 )
 
+// ImageFormatJPEG is the only output format of optimized images. The standard library and x/image
+// only decode WebP, so requests for any other format are rejected instead of silently served as JPEG
+const ImageFormatJPEG = "jpeg"
+
+// ValidateImageFormat checks a requested output format. Empty means the default (JPEG)
+func ValidateImageFormat(format string) error {
+	normalized := strings.ToLower(strings.TrimSpace(format))
+	if normalized == "" || normalized == ImageFormatJPEG || normalized == "jpg" {
+		return nil
+	}
+	return fmt.Errorf("unsupported image format %q: only jpeg is available", format)
+}
+
 // getBackgroundColor returns the background color for flattening transparent images
 func getBackgroundColor() color.Color {
 	// Parse hex color #FFFFFF (white)
@@ -34,6 +54,30 @@ func getBackgroundColor() color.Color {
 	return color.RGBA{R: 255, G: 255, B: 255, A: 255}
 }
 
+// WidthImageSize returns the size name for an explicit pixel width, clamped to [minImageWidth, maxImageWidth].
+// The name ("w320") is what OptimizeImage and GetCachePath take, so each width gets its own cache file
+func WidthImageSize(width int) string {
+	if width < minImageWidth {
+		width = minImageWidth
+	}
+	if width > maxImageWidth {
+		width = maxImageWidth
+	}
+	return fmt.Sprintf("w%d", width)
+}
+
+// parseWidthImageSize returns the pixel width of a size built by WidthImageSize
+func parseWidthImageSize(size string) (int, bool) {
+	if !strings.HasPrefix(size, "w") {
+		return 0, false
+	}
+	width, err := strconv.Atoi(strings.TrimPrefix(size, "w"))
+	if err != nil || width < minImageWidth || width > maxImageWidth {
+		return 0, false
+	}
+	return width, true
+}
+
 // EnsureCacheDir ensures the cache directory exists, creates it if it doesn't
 func EnsureCacheDir() error {
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
@@ -63,6 +107,59 @@ func ReadFromCache(cachePath string) ([]byte, error) {
 	return data, nil
 }
 
+// ImageETag returns a strong ETag for optimized image bytes, so browsers can revalidate cached images
+func ImageETag(imageData []byte) string {
+	sum := sha256.Sum256(imageData)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// RemoveCachedImages deletes every cached size (thumb, medium and explicit widths) of a design
+// asset, so the next request re-downloads and re-processes the image. Returns how many files were removed
+func RemoveCachedImages(assetID int) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(cacheDir, fmt.Sprintf("design_asset_%d_*", assetID)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cached images: %w", err)
+	}
+
+	removed := 0
+	for _, path := range paths {
+		err := os.Remove(path)
+		if err == nil {
+			removed++
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove cached image %s: %w", filepath.Base(path), err)
+		}
+	}
+	log.Printf("✓ Image cache invalidated for design asset %d (%d files)", assetID, removed)
+	return removed, nil
+}
+
+// ClearCache deletes every file in the image cache directory. Returns how many files were removed
+func ClearCache() (int, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cacheDir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove cached image %s: %w", entry.Name(), err)
+		}
+		removed++
+	}
+	log.Printf("✓ Image cache cleared (%d files)", removed)
+	return removed, nil
+}
+
 // SaveToCache saves an image to the cache
 func SaveToCache(cachePath string, imageData []byte) error {
 	// Ensure parent directory exists
@@ -79,19 +176,67 @@ func SaveToCache(cachePath string, imageData []byte) error {
 	return nil
 }
 
+// ParseImageRotation parses a manual rotation ("", "0", "90", "180" or "270", clockwise degrees)
+func ParseImageRotation(value string) (int, error) {
+	switch strings.TrimSpace(value) {
+	case "", "0":
+		return 0, nil
+	case "90":
+		return 90, nil
+	case "180":
+		return 180, nil
+	case "270":
+		return 270, nil
+	}
+	return 0, fmt.Errorf("invalid rotate '%s': must be 90, 180 or 270", value)
+}
+
+// rotateClockwise rotates img by the given clockwise degrees (imaging rotates counter-clockwise)
+func rotateClockwise(img image.Image, rotation int) image.Image {
+	switch rotation {
+	case 90:
+		return imaging.Rotate270(img)
+	case 180:
+		return imaging.Rotate180(img)
+	case 270:
+		return imaging.Rotate90(img)
+	}
+	return img
+}
+
 // OptimizeImage optimizes an image by converting to JPEG and resizing
 // imageData: raw image bytes (PNG, JPEG, etc.)
-// size: "thumb" or "medium"
-// Returns optimized JPEG image bytes
-// Note: Using JPEG instead of WebP to avoid CGO dependency. Can be changed to WebP later if needed.
-func OptimizeImage(imageData []byte, size string) ([]byte, error) {
-	// Decode the image
-	img, format, err := image.Decode(bytes.NewReader(imageData))
+// size: "thumb", "medium" or an explicit width from WidthImageSize
+// rotation: manual clockwise rotation (0, 90, 180 or 270) applied after the EXIF orientation
+// Returns optimized image bytes
+func OptimizeImage(imageData []byte, size string, rotation int) ([]byte, error) {
+	// Reject pathological originals before decoding them fully (decoding allocates width*height*4 bytes)
+	if reason := CheckSourceImageLimits(int64(len(imageData)), 0, 0); reason != "" {
+		log.Printf("⚠️  Rejecting source image: %s", reason)
+		return nil, fmt.Errorf("%w: %s", ErrSourceImageTooLarge, reason)
+	}
+	sourceFormat := ""
+	if cfg, cfgFormat, err := image.DecodeConfig(bytes.NewReader(imageData)); err == nil {
+		sourceFormat = cfgFormat
+		if reason := CheckSourceImageLimits(0, cfg.Width, cfg.Height); reason != "" {
+			log.Printf("⚠️  Rejecting source image: %s", reason)
+			return nil, fmt.Errorf("%w: %s", ErrSourceImageTooLarge, reason)
+		}
+	}
+
+	// Decode the image, rotating/flipping it as the EXIF orientation tag says (phone photos are
+	// usually stored sideways with the orientation in EXIF, and JPEG re-encoding drops the tag)
+	img, err := imaging.Decode(bytes.NewReader(imageData), imaging.AutoOrientation(true))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode image: %w", err)
 	}
 
-	log.Printf("📸 Image decoded: format=%s, bounds=%v", format, img.Bounds())
+	log.Printf("📸 Image decoded: format=%s, bounds=%v", sourceFormat, img.Bounds())
+
+	if rotation != 0 {
+		log.Printf("🔄 Rotating image %d° clockwise", rotation)
+		img = rotateClockwise(img, rotation)
+	}
 
 	// Flatten transparent images onto a solid background
 	// JPEG doesn't support transparency, so we need to flatten PNG images with alpha channel
@@ -99,7 +244,7 @@ func OptimizeImage(imageData []byte, size string) ([]byte, error) {
 	
 	// Check if image might have transparency (PNG format or NRGBA type)
 	needsFlattening := false
-	if format == "png" {
+	if sourceFormat == "png" {
 		needsFlattening = true
 	} else if _, ok := img.(*image.NRGBA); ok {
 		needsFlattening = true
@@ -122,14 +267,20 @@ func OptimizeImage(imageData []byte, size string) ([]byte, error) {
 	// Determine max dimension and quality based on size
 	var maxDim int
 	var quality int
+	targetWidth, isWidth := parseWidthImageSize(size)
 
-	switch size {
-	case "thumb":
+	switch {
+	case size == "thumb":
 		maxDim = maxSizeThumb
 		quality = qualityThumb
-	case "medium":
+	case size == "medium":
 		maxDim = maxSizeMedium
 		quality = qualityMedium
+	case isWidth:
+		quality = qualityMedium
+		if targetWidth <= maxSizeThumb {
+			quality = qualityThumb
+		}
 	default:
 		maxDim = maxSizeMedium
 		quality = qualityMedium
@@ -142,7 +293,14 @@ func OptimizeImage(imageData []byte, size string) ([]byte, error) {
 	height := bounds.Dy()
 
 	var resizedImg image.Image = processedImg
-	if width > maxDim || height > maxDim {
+	if isWidth {
+		// Explicit widths only scale down, keeping the aspect ratio
+		if width > targetWidth {
+			newHeight := int(float64(height) * float64(targetWidth) / float64(width))
+			log.Printf("🔄 Resizing image: %dx%d -> %dx%d", width, height, targetWidth, newHeight)
+			resizedImg = imaging.Resize(processedImg, targetWidth, newHeight, imaging.Lanczos)
+		}
+	} else if width > maxDim || height > maxDim {
 		// Calculate new dimensions maintaining aspect ratio
 		var newWidth, newHeight int
 		if width > height {
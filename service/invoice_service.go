@@ -0,0 +1,57 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"time"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/utils"
+)
+
+// InvoiceService renders sale invoices to HTML and PDF
+type InvoiceService struct{}
+
+// NewInvoiceService creates a new InvoiceService
+func NewInvoiceService() *InvoiceService {
+	return &InvoiceService{}
+}
+
+// invoiceTemplateFuncs formats money and dates for templates/invoice.html
+var invoiceTemplateFuncs = template.FuncMap{
+	"money": utils.FormatCOP,
+	"date": func(value string) string {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t.Format("02/01/2006")
+		}
+		return value
+	},
+}
+
+// RenderInvoiceHTML renders the invoice HTML template
+func (s *InvoiceService) RenderInvoiceHTML(invoice *models.SaleInvoice) (string, error) {
+	templatePath := filepath.Join("templates", "invoice.html")
+	tmpl, err := template.New("invoice.html").Funcs(invoiceTemplateFuncs).ParseFiles(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, invoice); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateInvoicePDF renders the invoice and prints it to a letter-size PDF
+func (s *InvoiceService) GenerateInvoicePDF(ctx context.Context, invoice *models.SaleInvoice) ([]byte, error) {
+	htmlContent, err := s.RenderInvoiceHTML(invoice)
+	if err != nil {
+		return nil, err
+	}
+	return RenderHTMLToPDF(ctx, htmlContent, LetterPaperSize)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"armario-mascota-me/repository"
+)
+
+// orderHoldCheckInterval is how often the worker looks for reserved orders whose hold has passed.
+// An expired order keeps its stock for at most this long after holdUntil.
+const orderHoldCheckInterval = 5 * time.Minute
+
+// OrderHoldWorker cancels reserved orders whose per-order hold (holdUntil) has expired,
+// releasing their reserved stock
+type OrderHoldWorker struct {
+	repo     repository.ReservedOrderRepositoryInterface
+	interval time.Duration
+}
+
+// NewOrderHoldWorker creates a new OrderHoldWorker
+func NewOrderHoldWorker(repo repository.ReservedOrderRepositoryInterface) *OrderHoldWorker {
+	return &OrderHoldWorker{
+		repo:     repo,
+		interval: orderHoldCheckInterval,
+	}
+}
+
+// Start runs the worker in the background: once right away, then every interval
+func (w *OrderHoldWorker) Start() {
+	go func() {
+		w.run()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			w.run()
+		}
+	}()
+	log.Printf("✅ OrderHoldWorker: Started (interval=%s)", w.interval)
+}
+
+// run cancels every reserved order whose hold expired before now
+func (w *OrderHoldWorker) run() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	expired, err := w.repo.ExpireHolds(ctx, time.Now())
+	if err != nil {
+		log.Printf("❌ OrderHoldWorker: Error expiring holds: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("⏰ OrderHoldWorker: Canceled %d orders with expired holds", expired)
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/utils"
+)
+
+// BuildOrderSummaryText renders a reserved order as plain text ready to paste into WhatsApp:
+// customer name, one "2x Buso negro talla M — $24.000" row per line, the total and the order type.
+// Lines are expected to be decorated already (readable color/hoodie type labels)
+func BuildOrderSummaryText(order *models.ReservedOrderResponse) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Pedido #%d\n", order.ID)
+	customerName := strings.TrimSpace(order.CustomerName)
+	if customerName == "" {
+		customerName = "(sin nombre)"
+	}
+	fmt.Fprintf(&b, "Cliente: %s\n\n", customerName)
+
+	for _, line := range order.Lines {
+		lineTotal := int64(line.Qty) * line.UnitPrice
+		fmt.Fprintf(&b, "%dx %s — %s\n", line.Qty, orderSummaryLineLabel(line.Item), utils.FormatCOP(lineTotal))
+	}
+	if len(order.Lines) == 0 {
+		b.WriteString("(sin productos)\n")
+	}
+
+	b.WriteString("\n")
+	if order.Discount > 0 {
+		fmt.Fprintf(&b, "Subtotal: %s\n", utils.FormatCOP(order.Subtotal))
+		fmt.Fprintf(&b, "Descuento: -%s\n", utils.FormatCOP(order.Discount))
+	}
+	fmt.Fprintf(&b, "Total: %s\n", utils.FormatCOP(order.Total))
+	if orderType := strings.TrimSpace(order.OrderType); orderType != "" {
+		fmt.Fprintf(&b, "Tipo de pedido: %s\n", orderType)
+	}
+
+	return b.String()
+}
+
+// orderSummaryLineLabel describes an item as "Buso tipo esqueleto negro con azul cielo talla M"
+func orderSummaryLineLabel(item models.ItemFullInfo) string {
+	parts := []string{}
+	if item.HoodieTypeLabel != "" {
+		parts = append(parts, item.HoodieTypeLabel)
+	}
+	if item.ColorPrimaryLabel != "" {
+		color := item.ColorPrimaryLabel
+		if item.ColorSecondaryLabel != "" && item.ColorSecondaryLabel != item.ColorPrimaryLabel {
+			color += " con " + item.ColorSecondaryLabel
+		}
+		parts = append(parts, color)
+	}
+	if len(parts) == 0 {
+		parts = append(parts, item.SKU)
+	}
+	if item.Size != "" {
+		parts = append(parts, "talla "+item.Size)
+	}
+
+	label := strings.Join(parts, " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// pdfRenderTimeout bounds a single headless Chrome PDF render
+const pdfRenderTimeout = 30 * time.Second
+
+// PDFPaperSize is the paper size in inches used when printing to PDF
+type PDFPaperSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	// CatalogPaperSize is 210mm x 350mm (1mm = 0.03937 inches)
+	CatalogPaperSize = PDFPaperSize{Width: 8.27, Height: 13.78}
+	// LetterPaperSize is US Letter (8.5" x 11")
+	LetterPaperSize = PDFPaperSize{Width: 8.5, Height: 11}
+)
+
+// newChromeAllocator creates a headless Chrome allocator using the detected Chrome path
+// NoSandbox is always set because we run inside Docker/containers
+func newChromeAllocator(ctx context.Context) (context.Context, context.CancelFunc) {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.NoSandbox,                          // Required for running in Docker/containers
+		chromedp.Flag("enable-print-preview", true), // Enable print preview
+	)
+	if chromePath := detectChromePath(); chromePath != "" {
+		opts = append(opts, chromedp.ExecPath(chromePath))
+	}
+	// Without a detected path chromedp auto-detects (may fail in containers)
+	return chromedp.NewExecAllocator(ctx, opts...)
+}
+
+// RenderPDF launches headless Chrome, runs the load actions and prints the resulting page to PDF
+// Margins are zero; padding belongs in the page CSS. Page breaks follow CSS page-break-after
+func RenderPDF(ctx context.Context, paper PDFPaperSize, load ...chromedp.Action) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, pdfRenderTimeout)
+	defer cancel()
+
+	allocCtx, allocCancel := newChromeAllocator(ctx)
+	defer allocCancel()
+
+	chromedpCtx, chromedpCancel := chromedp.NewContext(allocCtx)
+	defer chromedpCancel()
+
+	// Enable Page domain for printing
+	if err := chromedp.Run(chromedpCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		return page.Enable().Do(ctx)
+	})); err != nil {
+		// Log warning but continue
+	}
+
+	var pdfBuf []byte
+	actions := append(load, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		pdfBuf, _, err = page.PrintToPDF().
+			WithPrintBackground(true).
+			WithPaperWidth(paper.Width).
+			WithPaperHeight(paper.Height).
+			WithMarginTop(0).
+			WithMarginBottom(0).
+			WithMarginLeft(0).
+			WithMarginRight(0).
+			Do(ctx)
+		return err
+	}))
+
+	if err := chromedp.Run(chromedpCtx, actions...); err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	return pdfBuf, nil
+}
+
+// RenderHTMLToPDF prints an already rendered HTML document to PDF
+// The document is injected into a blank page, so it must not depend on authenticated resources
+func RenderHTMLToPDF(ctx context.Context, htmlContent string, paper PDFPaperSize) ([]byte, error) {
+	return RenderPDF(ctx, paper,
+		chromedp.Navigate("about:blank"),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			frameTree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+			return page.SetDocumentContent(frameTree.Frame.ID, htmlContent).Do(ctx)
+		}),
+		chromedp.WaitReady("body"),
+	)
+}
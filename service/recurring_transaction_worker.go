@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"armario-mascota-me/repository"
+	"armario-mascota-me/utils"
+)
+
+// recurringTransactionCheckInterval is how often the worker looks for due recurrences.
+// Generation is idempotent per date, so checking more than once a day only catches up sooner
+// after a restart or a failed run.
+const recurringTransactionCheckInterval = time.Hour
+
+// RecurringTransactionWorker materializes due recurring transactions into finance_transactions
+type RecurringTransactionWorker struct {
+	repo     repository.RecurringTransactionRepositoryInterface
+	interval time.Duration
+}
+
+// NewRecurringTransactionWorker creates a new RecurringTransactionWorker
+func NewRecurringTransactionWorker(repo repository.RecurringTransactionRepositoryInterface) *RecurringTransactionWorker {
+	return &RecurringTransactionWorker{
+		repo:     repo,
+		interval: recurringTransactionCheckInterval,
+	}
+}
+
+// Start runs the worker in the background: once right away, then every interval
+func (w *RecurringTransactionWorker) Start() {
+	go func() {
+		w.run()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			w.run()
+		}
+	}()
+	log.Printf("✅ RecurringTransactionWorker: Started (interval=%s)", w.interval)
+}
+
+// run generates every recurrence due up to today
+func (w *RecurringTransactionWorker) run() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// Today in the store timezone, not the server's
+	created, err := w.repo.GenerateDue(ctx, time.Now().In(utils.AppLocation()))
+	if err != nil {
+		log.Printf("❌ RecurringTransactionWorker: Error generating due recurrences: %v", err)
+		return
+	}
+	if created > 0 {
+		log.Printf("💰 RecurringTransactionWorker: Generated %d finance transactions", created)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"armario-mascota-me/models"
+	"armario-mascota-me/repository"
+)
+
+// SaleWebhookEvent is the event name sent when a sale is completed
+const SaleWebhookEvent = "sale.completed"
+
+// SaleVoidedWebhookEvent is the event name sent when a sale recorded in error is voided
+const SaleVoidedWebhookEvent = "sale.voided"
+
+// saleWebhookMaxAttempts is the number of delivery attempts before a webhook is dead-lettered
+const saleWebhookMaxAttempts = 3
+
+// SaleWebhookService delivers sale events to an external URL (SALE_WEBHOOK_URL).
+// Deliveries are retried with backoff; once retries are exhausted the payload is stored in
+// webhook_failures so a downstream outage never loses a sale event.
+type SaleWebhookService struct {
+	url         string
+	client      *http.Client
+	failureRepo repository.WebhookFailureRepositoryInterface
+	backoff     time.Duration
+}
+
+// NewSaleWebhookService creates a new SaleWebhookService. An empty url disables delivery
+func NewSaleWebhookService(url string, failureRepo repository.WebhookFailureRepositoryInterface) *SaleWebhookService {
+	return &SaleWebhookService{
+		url:         url,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		failureRepo: failureRepo,
+		backoff:     2 * time.Second,
+	}
+}
+
+// Enabled reports whether a webhook URL is configured
+func (s *SaleWebhookService) Enabled() bool {
+	return s != nil && s.url != ""
+}
+
+// NotifySale delivers the sale event in the background so the sale request is never blocked
+func (s *SaleWebhookService) NotifySale(sale *models.Sale) {
+	s.notify(SaleWebhookEvent, sale)
+}
+
+// NotifySaleVoided delivers the void event of a sale in the background, so downstream systems that
+// received its sale.completed event can undo it
+func (s *SaleWebhookService) NotifySaleVoided(sale *models.Sale) {
+	s.notify(SaleVoidedWebhookEvent, sale)
+}
+
+// notify delivers a sale event with retries, dead-lettering it into webhook_failures once retries are exhausted
+func (s *SaleWebhookService) notify(event string, sale *models.Sale) {
+	if !s.Enabled() || sale == nil {
+		return
+	}
+
+	payload, err := json.Marshal(models.SaleWebhookPayload{Event: event, Sale: *sale})
+	if err != nil {
+		log.Printf("❌ NotifySale: Error encoding %s webhook payload for sale_id=%d: %v", event, sale.ID, err)
+		return
+	}
+
+	go func(saleID int64) {
+		var lastErr error
+		for attempt := 1; attempt <= saleWebhookMaxAttempts; attempt++ {
+			lastErr = s.deliver(payload)
+			if lastErr == nil {
+				log.Printf("✅ NotifySale: Delivered webhook for sale_id=%d (attempt %d)", saleID, attempt)
+				return
+			}
+			log.Printf("⚠️ NotifySale: Attempt %d/%d failed for sale_id=%d: %v", attempt, saleWebhookMaxAttempts, saleID, lastErr)
+			if attempt < saleWebhookMaxAttempts {
+				time.Sleep(s.backoff * time.Duration(attempt))
+			}
+		}
+
+		// Retries exhausted: dead-letter the payload
+		if _, err := s.failureRepo.Create(context.Background(), saleID, payload, lastErr.Error(), saleWebhookMaxAttempts); err != nil {
+			log.Printf("❌ NotifySale: Could not store webhook failure for sale_id=%d, payload=%s: %v", saleID, string(payload), err)
+		}
+	}(sale.ID)
+}
+
+// RetryFailure replays a dead-lettered webhook once and records the outcome
+func (s *SaleWebhookService) RetryFailure(ctx context.Context, failureID int64) (*models.WebhookFailure, error) {
+	if !s.Enabled() {
+		return nil, fmt.Errorf("sale webhook is not configured")
+	}
+
+	failure, err := s.failureRepo.GetByID(ctx, failureID)
+	if err != nil {
+		return nil, err
+	}
+	if failure.ResolvedAt != nil {
+		return nil, fmt.Errorf("webhook failure already resolved")
+	}
+
+	deliverErr := s.deliver(failure.Payload)
+	if deliverErr != nil {
+		log.Printf("⚠️ RetryFailure: Replay failed for webhook failure id=%d: %v", failureID, deliverErr)
+	} else {
+		log.Printf("✅ RetryFailure: Replayed webhook failure id=%d (sale_id=%d)", failureID, failure.SaleID)
+	}
+	return s.failureRepo.RecordRetry(ctx, failureID, deliverErr)
+}
+
+// deliver POSTs the payload once; any non-2xx status is an error
+func (s *SaleWebhookService) deliver(payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
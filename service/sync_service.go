@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 
@@ -56,7 +58,7 @@ func (s *SyncService) SyncDesignAssetsWithStats(ctx context.Context, folderID st
 	total = len(driveAssets)
 
 	// Process each asset
-	for _, asset := range driveAssets {
+	for i, asset := range driveAssets {
 		// Check if asset already exists
 		exists, err := s.repository.ExistsByDriveFileID(ctx, asset.DriveFileID)
 		if err != nil {
@@ -74,11 +76,24 @@ func (s *SyncService) SyncDesignAssetsWithStats(ctx context.Context, folderID st
 
 		// Convert to database model - only drive_file_id and image_url
 		dbAsset := &models.DesignAssetDB{
-			DriveFileID: asset.DriveFileID,
-			ImageURL:    asset.ImageURL,
+			DriveFileID:    asset.DriveFileID,
+			ImageURL:       asset.ImageURL,
+			SourceFilename: asset.FileName,
 			// All other fields will be set from the frontend interface
 		}
 
+		// Flag originals that exceed the source limits so they are never downloaded/optimized
+		if reason := CheckSourceImageLimits(asset.SizeBytes, asset.Width, asset.Height); reason != "" {
+			log.Printf("⚠️  Original exceeds source limits, flagging as rejected (drive_file_id: %s): %s", asset.DriveFileID, reason)
+			dbAsset.ImageRejectedReason = reason
+			driveAssets[i].Note = fmt.Sprintf("image rejected: %s", reason)
+		} else if data, err := s.driveService.DownloadImage(asset.DriveFileID); err != nil {
+			// The hash is only used to spot duplicates, so a failed download must not block the import
+			log.Printf("⚠️  Could not download image to compute hash (drive_file_id: %s): %v", asset.DriveFileID, err)
+		} else {
+			dbAsset.ImageHash = ImageContentHash(data)
+		}
+
 		// Insert into database with the specified status
 		log.Printf("💾 Attempting to insert into database (drive_file_id: %s, status: %s)", asset.DriveFileID, status)
 		if err := s.repository.Insert(ctx, dbAsset, status); err != nil {
@@ -93,3 +108,10 @@ func (s *SyncService) SyncDesignAssetsWithStats(ctx context.Context, folderID st
 	log.Printf("🎉 Synchronization completed successfully: %d inserted, %d skipped, %d total processed", inserted, skipped, total)
 	return driveAssets, inserted, skipped, total, nil
 }
+
+// ImageContentHash returns the hex-encoded SHA-256 of the original image bytes, used to detect
+// the same image imported under different Drive file ids
+func ImageContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Catalog items-per-page limits. The default matches the 3x3 grid of templates/catalog.html
+const (
+	DefaultCatalogPerPage = 9
+	MinCatalogPerPage     = 1
+	MaxCatalogPerPage     = 12
+)
+
+// ParseCatalogPerPage parses an optional perPage query value, defaulting to DefaultCatalogPerPage
+// and clamping it to [MinCatalogPerPage, MaxCatalogPerPage]
+func ParseCatalogPerPage(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return DefaultCatalogPerPage, nil
+	}
+	perPage, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("perPage must be an integer")
+	}
+	return ClampCatalogPerPage(perPage), nil
+}
+
+// ClampCatalogPerPage clamps perPage to [MinCatalogPerPage, MaxCatalogPerPage]
+func ClampCatalogPerPage(perPage int) int {
+	if perPage < MinCatalogPerPage {
+		return MinCatalogPerPage
+	}
+	if perPage > MaxCatalogPerPage {
+		return MaxCatalogPerPage
+	}
+	return perPage
+}
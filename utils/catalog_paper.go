@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Catalog paper limits in millimeters. The default matches the 210mm x 350mm layout of templates/catalog.html
+const (
+	DefaultCatalogPaperWidthMM  = 210.0
+	DefaultCatalogPaperHeightMM = 350.0
+	MinCatalogPaperMM           = 100.0
+	MaxCatalogPaperMM           = 600.0
+)
+
+// CatalogPaper is the catalog page size in millimeters
+type CatalogPaper struct {
+	WidthMM  float64
+	HeightMM float64
+}
+
+// catalogPaperPresets maps the named paper presets accepted by ParseCatalogPaper to their size
+var catalogPaperPresets = map[string]CatalogPaper{
+	"catalog": {WidthMM: DefaultCatalogPaperWidthMM, HeightMM: DefaultCatalogPaperHeightMM},
+	"a4":      {WidthMM: 210, HeightMM: 297},
+	"letter":  {WidthMM: 215.9, HeightMM: 279.4},
+}
+
+// DefaultCatalogPaper returns the default 210mm x 350mm catalog paper
+func DefaultCatalogPaper() CatalogPaper {
+	return catalogPaperPresets["catalog"]
+}
+
+// ParseCatalogPaper parses the optional paper, paperWidth and paperHeight query values.
+// paper is a preset (catalog, a4, letter) or "custom"; custom needs paperWidth and paperHeight in mm,
+// and giving both without a preset implies custom. Empty values default to DefaultCatalogPaper
+func ParseCatalogPaper(paper, width, height string) (CatalogPaper, error) {
+	paper = strings.ToLower(strings.TrimSpace(paper))
+	width = strings.TrimSpace(width)
+	height = strings.TrimSpace(height)
+
+	if paper == "" {
+		if width == "" && height == "" {
+			return DefaultCatalogPaper(), nil
+		}
+		paper = "custom"
+	}
+
+	if paper != "custom" {
+		preset, ok := catalogPaperPresets[paper]
+		if !ok {
+			return CatalogPaper{}, fmt.Errorf("Invalid paper. Valid papers: catalog, a4, letter, custom")
+		}
+		if width != "" || height != "" {
+			return CatalogPaper{}, fmt.Errorf("paperWidth and paperHeight are only allowed with paper=custom")
+		}
+		return preset, nil
+	}
+
+	if width == "" || height == "" {
+		return CatalogPaper{}, fmt.Errorf("paperWidth and paperHeight are required for a custom paper")
+	}
+	widthMM, err := parseCatalogPaperMM("paperWidth", width)
+	if err != nil {
+		return CatalogPaper{}, err
+	}
+	heightMM, err := parseCatalogPaperMM("paperHeight", height)
+	if err != nil {
+		return CatalogPaper{}, err
+	}
+	return CatalogPaper{WidthMM: widthMM, HeightMM: heightMM}, nil
+}
+
+// parseCatalogPaperMM parses one paper dimension in mm and checks it against the allowed range
+func parseCatalogPaperMM(name, raw string) (float64, error) {
+	mm, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(mm) || math.IsInf(mm, 0) {
+		return 0, fmt.Errorf("%s must be a number of millimeters", name)
+	}
+	if mm < MinCatalogPaperMM || mm > MaxCatalogPaperMM {
+		return 0, fmt.Errorf("%s must be between %.0f and %.0f mm", name, MinCatalogPaperMM, MaxCatalogPaperMM)
+	}
+	// Tenths of a millimeter are plenty for print sizes and keep URLs and cache keys stable
+	return math.Round(mm*10) / 10, nil
+}
+
+// OrDefault returns the paper, or DefaultCatalogPaper when it is unset
+func (p CatalogPaper) OrDefault() CatalogPaper {
+	if p.WidthMM <= 0 || p.HeightMM <= 0 {
+		return DefaultCatalogPaper()
+	}
+	return p
+}
+
+// IsDefault reports whether the paper is the default 210mm x 350mm catalog paper
+func (p CatalogPaper) IsDefault() bool {
+	return p.OrDefault() == DefaultCatalogPaper()
+}
+
+// CSSWidth returns the paper width as a CSS length (e.g., "210mm")
+func (p CatalogPaper) CSSWidth() string {
+	return formatCatalogPaperMM(p.OrDefault().WidthMM) + "mm"
+}
+
+// CSSHeight returns the paper height as a CSS length (e.g., "350mm")
+func (p CatalogPaper) CSSHeight() string {
+	return formatCatalogPaperMM(p.OrDefault().HeightMM) + "mm"
+}
+
+// ViewportSize returns the paper size in CSS pixels at 96 DPI (210mm x 350mm = 794 x 1323)
+func (p CatalogPaper) ViewportSize() (int64, int64) {
+	paper := p.OrDefault()
+	return int64(math.Round(paper.WidthMM * 96 / 25.4)), int64(math.Round(paper.HeightMM * 96 / 25.4))
+}
+
+// String returns the paper as "210x350" (mm)
+func (p CatalogPaper) String() string {
+	paper := p.OrDefault()
+	return formatCatalogPaperMM(paper.WidthMM) + "x" + formatCatalogPaperMM(paper.HeightMM)
+}
+
+// formatCatalogPaperMM formats a paper dimension without trailing zeros
+func formatCatalogPaperMM(mm float64) string {
+	return strconv.FormatFloat(mm, 'f', -1, 64)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -87,37 +89,38 @@ func MapImageTypeToCode(imageType string) string {
 	return strings.ToUpper(imageLower)
 }
 
+// codeToColorMap maps color codes to their readable names
+var codeToColorMap = map[string]string{
+	"AM_JS": "amarillo jaspeado",
+	"AC":    "azul cielo",
+	"AM":    "amarillo",
+	"FS":    "fucsia",
+	"RS":    "rosado",
+	"TA":    "tabaco",
+	"AC_ES": "azul cielo estampado",
+	"AP":    "azul petróleo",
+	"RO":    "rojo",
+	"VL":    "verde limón",
+	"CF":    "café",
+	"NA":    "naranja",
+	"TE_CA": "tela tipo franela",
+	"GR_JS": "gris jaspeado",
+	"ML":    "moraleche",
+	"NG":    "negro",
+	"PR":    "palo de rosa",
+	"RP":    "rosa claro",
+	"RS_ES": "rosado estampado",
+	"RS_JS": "rosado jaspeado",
+	"VS":    "verde sapo",
+	"VM":    "verde militar",
+}
+
 // MapCodeToColor maps color codes back to their readable names
 // Input is normalized to uppercase before mapping
 // Returns lowercase readable name
 func MapCodeToColor(code string) string {
 	codeUpper := strings.ToUpper(strings.TrimSpace(code))
 
-	codeToColorMap := map[string]string{
-		"AM_JS": "amarillo jaspeado",
-		"AC":    "azul cielo",
-		"AM":    "amarillo",
-		"FS":    "fucsia",
-		"RS":    "rosado",
-		"TA":    "tabaco",
-		"AC_ES": "azul cielo estampado",
-		"AP":    "azul petróleo",
-		"RO":    "rojo",
-		"VL":    "verde limón",
-		"CF":    "café",
-		"NA":    "naranja",
-		"TE_CA": "tela tipo franela",
-		"GR_JS": "gris jaspeado",
-		"ML":    "moraleche",
-		"NG":    "negro",
-		"PR":    "palo de rosa",
-		"RP":    "rosa claro",
-		"RS_ES": "rosado estampado",
-		"RS_JS": "rosado jaspeado",
-		"VS":    "verde sapo",
-		"VM":    "verde militar",
-	}
-
 	if color, exists := codeToColorMap[codeUpper]; exists {
 		return color
 	}
@@ -126,22 +129,23 @@ func MapCodeToColor(code string) string {
 	return strings.ToLower(codeUpper)
 }
 
+// codeToHoodieMap maps hoodie type codes to their readable names
+var codeToHoodieMap = map[string]string{
+	"BU": "buso estándar",
+	"BE": "buso tipo esqueleto",
+	"CA": "camiseta",
+	"IM": "impermeable",
+	"HW": "camiseta halloween",
+	"PA": "pañoleta",
+	"BC": "buso sin mangas",
+}
+
 // MapCodeToHoodieType maps hoodie type codes back to their readable names
 // Input is normalized to uppercase before mapping
 // Returns lowercase readable name
 func MapCodeToHoodieType(code string) string {
 	codeUpper := strings.ToUpper(strings.TrimSpace(code))
 
-	codeToHoodieMap := map[string]string{
-		"BU": "buso estándar",
-		"BE": "buso tipo esqueleto",
-		"CA": "camiseta",
-		"IM": "impermeable",
-		"HW": "camiseta halloween",
-		"PA": "pañoleta",
-		"BC": "buso sin mangas",
-	}
-
 	if hoodieType, exists := codeToHoodieMap[codeUpper]; exists {
 		return hoodieType
 	}
@@ -228,18 +232,19 @@ func MapCodeToImageType(code string) string {
 	return strings.ToLower(codeTrimmed)
 }
 
+// codeToDecoBaseMap maps deco base codes to their readable names
+var codeToDecoBaseMap = map[string]string{
+	"0": "N/A",
+	"C": "Círculo",
+	"N": "Nube",
+}
+
 // MapCodeToDecoBase maps deco base codes back to their readable names
 // Input is normalized to uppercase before mapping
 // Returns capitalized readable name
 func MapCodeToDecoBase(code string) string {
 	codeUpper := strings.ToUpper(strings.TrimSpace(code))
 
-	codeToDecoBaseMap := map[string]string{
-		"0": "N/A",
-		"C": "Círculo",
-		"N": "Nube",
-	}
-
 	if decoBase, exists := codeToDecoBaseMap[codeUpper]; exists {
 		return decoBase
 	}
@@ -248,6 +253,61 @@ func MapCodeToDecoBase(code string) string {
 	return codeUpper
 }
 
+// customAssetCode is the code stored for custom colors, hoodie types, image types and deco bases
+const customAssetCode = "CSM"
+
+// imageTypeSizeCodes are the size codes concatenated by ParseImageTypeSizes, longest match first
+var imageTypeSizeCodes = []string{"Mn", "It", "X", "S", "M", "L", "H"}
+
+// IsKnownColorCode reports whether code is a color code known to MapCodeToColor, or CSM
+func IsKnownColorCode(code string) bool {
+	codeUpper := strings.ToUpper(strings.TrimSpace(code))
+	_, exists := codeToColorMap[codeUpper]
+	return exists || codeUpper == customAssetCode
+}
+
+// IsKnownHoodieTypeCode reports whether code is a hoodie type code known to MapCodeToHoodieType, or CSM
+func IsKnownHoodieTypeCode(code string) bool {
+	codeUpper := strings.ToUpper(strings.TrimSpace(code))
+	_, exists := codeToHoodieMap[codeUpper]
+	return exists || codeUpper == customAssetCode
+}
+
+// IsKnownDecoBaseCode reports whether code is a deco base code known to MapCodeToDecoBase, or CSM
+func IsKnownDecoBaseCode(code string) bool {
+	codeUpper := strings.ToUpper(strings.TrimSpace(code))
+	_, exists := codeToDecoBaseMap[codeUpper]
+	return exists || codeUpper == customAssetCode
+}
+
+// IsKnownImageTypeCode reports whether code is an image type code: an old format code (IT, DP, XL),
+// CSM, or size codes concatenated by ParseImageTypeSizes (e.g. "ItMn", "MnSML"). Codes are case-sensitive
+// since "IT" (old format) and "It" (Intermedio) mean different things
+func IsKnownImageTypeCode(code string) bool {
+	code = strings.TrimSpace(code)
+	switch code {
+	case "IT", "DP", "XL", customAssetCode:
+		return true
+	case "":
+		return false
+	}
+
+	for len(code) > 0 {
+		matched := false
+		for _, sizeCode := range imageTypeSizeCodes {
+			if strings.HasPrefix(code, sizeCode) {
+				code = code[len(sizeCode):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
 // ParseImageTypeSizes parses comma-separated size values and returns concatenated codes
 // Input format: "Intermedio,Mini,XS" or "Mini,S,M,L"
 // Returns: "ItMnX" or "MnSML"
@@ -301,3 +361,36 @@ func ParseImageTypeSizes(imageType string) string {
 	
 	return result.String()
 }
+
+// BuildCustomCode builds the custom code stored on custom order lines: primaryColor_secondaryColor_hoodieType
+// Inputs are readable names and are mapped to their codes
+func BuildCustomCode(primaryColor, secondaryColor, hoodieType string) string {
+	return fmt.Sprintf("%s_%s_%s", MapColorToCode(primaryColor), MapColorToCode(secondaryColor), MapHoodieTypeToCode(hoodieType))
+}
+
+// ColorCodesMatching returns the color codes whose readable name contains query (case-insensitive),
+// e.g. "azul" matches AC, AP... Used to search by readable label while the database stores codes
+func ColorCodesMatching(query string) []string {
+	return codesMatching(codeToColorMap, query)
+}
+
+// HoodieTypeCodesMatching returns the hoodie type codes whose readable name contains query (case-insensitive)
+func HoodieTypeCodesMatching(query string) []string {
+	return codesMatching(codeToHoodieMap, query)
+}
+
+// codesMatching returns the sorted codes of labels whose readable name contains query
+func codesMatching(labels map[string]string, query string) []string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	codes := []string{}
+	if query == "" {
+		return codes
+	}
+	for code, label := range labels {
+		if strings.Contains(label, query) {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
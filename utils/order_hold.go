@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultOrderHoldHours is how long a reserved order holds its stock when ORDER_HOLD_HOURS is not set
+const defaultOrderHoldHours = 24
+
+// OrderHoldDuration returns how long a reserved order holds its stock (ORDER_HOLD_HOURS, default 24).
+// New orders get holdUntil = now + this duration and adding items pushes it forward by the same amount.
+// ORDER_HOLD_HOURS=0 disables holds for new orders, which then never expire
+func OrderHoldDuration() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("ORDER_HOLD_HOURS"))
+	if raw == "" {
+		return defaultOrderHoldHours * time.Hour
+	}
+	hours, err := strconv.Atoi(raw)
+	if err != nil || hours < 0 {
+		log.Printf("⚠️  Invalid ORDER_HOLD_HOURS=%q, using default %d", raw, defaultOrderHoldHours)
+		return defaultOrderHoldHours * time.Hour
+	}
+	return time.Duration(hours) * time.Hour
+}
@@ -72,6 +72,22 @@ func NormalizeSize(size string) string {
 	return sizeUpper
 }
 
+// knownSizes lists the normalized sizes items can be stocked in
+var knownSizes = map[string]bool{
+	"XS": true,
+	"S":  true,
+	"M":  true,
+	"L":  true,
+	"XL": true,
+	"MN": true, // Mini
+	"IT": true, // Intermedio
+}
+
+// IsKnownSize reports whether size (after NormalizeSize) is one of the known item sizes
+func IsKnownSize(size string) bool {
+	return knownSizes[NormalizeSize(size)]
+}
+
 // normalizeSize is an internal alias for NormalizeSize
 func normalizeSize(size string) string {
 	return NormalizeSize(size)
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RenderTokenParam is the query parameter carrying the internal render token
+const RenderTokenParam = "renderToken"
+
+// RenderTokenTTL is how long an internal render token stays valid.
+// It must cover the longest chromedp run (PNG generation is capped at 3 minutes).
+const RenderTokenTTL = 5 * time.Minute
+
+var (
+	renderSecret     []byte
+	renderSecretOnce sync.Once
+)
+
+// getRenderSecret returns the HMAC secret used to sign render tokens (and stream tokens, see stream_token.go).
+// Uses RENDER_TOKEN_SECRET when set, otherwise a random per-process secret
+// (fine because the same process both issues and verifies the token)
+func getRenderSecret() []byte {
+	renderSecretOnce.Do(func() {
+		if secret := os.Getenv("RENDER_TOKEN_SECRET"); secret != "" {
+			renderSecret = []byte(secret)
+			return
+		}
+		renderSecret = make([]byte, 32)
+		if _, err := rand.Read(renderSecret); err != nil {
+			// Extremely unlikely; fall back to a time-based secret so tokens still work in-process
+			renderSecret = []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+		}
+	})
+	return renderSecret
+}
+
+// signRender computes the signature for a catalog render scope and expiry
+func signRender(scope string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, getRenderSecret())
+	mac.Write([]byte(fmt.Sprintf("catalog-render|%s|%d", scope, expiresAt)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateRenderToken creates a short-lived signed token scoped to rendering the catalog
+// for the given size. Format: "<expiresAtUnix>.<hexSignature>"
+func GenerateRenderToken(size string) string {
+	expiresAt := time.Now().Add(RenderTokenTTL).Unix()
+	return fmt.Sprintf("%d.%s", expiresAt, signRender(NormalizeSize(size), expiresAt))
+}
+
+// VerifyRenderToken checks that a token is well-formed, not expired and was issued for the given size
+func VerifyRenderToken(token string, size string) bool {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+	expected := signRender(NormalizeSize(size), expiresAt)
+	return hmac.Equal([]byte(parts[1]), []byte(expected))
+}
+
+// RenderSizeParam is the query parameter carrying the catalog size a render token was issued for,
+// used on image URLs where "size" already means thumb/medium
+const RenderSizeParam = "renderSize"
+
+// SignRenderImageURL appends a render token to a design asset image URL so the image can be
+// fetched while rendering the catalog for the given size
+func SignRenderImageURL(imageURL string, size string) string {
+	separator := "?"
+	if strings.Contains(imageURL, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s%s=%s&%s=%s", imageURL, separator,
+		RenderSizeParam, NormalizeSize(size), RenderTokenParam, GenerateRenderToken(size))
+}
+
+// IsAuthorizedRenderRequest reports whether the request is an internal catalog render request
+// carrying a valid render token. Only two routes are in scope:
+//   - GET /admin/catalog/render?size=XS&renderToken=... (or sizes=MN,IT,S)
+//   - GET /admin/design-assets/pending/:id/image?renderSize=XS&renderToken=...
+// Auth middleware protecting /admin/* must accept these so chromedp can load the page
+func IsAuthorizedRenderRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	token := r.URL.Query().Get(RenderTokenParam)
+	if token == "" {
+		return false
+	}
+
+	switch {
+	case r.URL.Path == "/admin/catalog/render":
+		// Combined catalogs carry sizes=MN,IT,S and are signed for that whole list
+		if sizes := r.URL.Query().Get("sizes"); sizes != "" {
+			return VerifyRenderToken(token, sizes)
+		}
+		return VerifyRenderToken(token, r.URL.Query().Get("size"))
+	case strings.HasPrefix(r.URL.Path, "/admin/design-assets/pending/") && strings.HasSuffix(r.URL.Path, "/image"):
+		return VerifyRenderToken(token, r.URL.Query().Get(RenderSizeParam))
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifyRenderToken(t *testing.T) {
+	expired := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name  string
+		token string
+		size  string
+		want  bool
+	}{
+		{name: "valid token for the size", token: GenerateRenderToken("XS"), size: "XS", want: true},
+		{name: "size aliases are normalized", token: GenerateRenderToken("mini"), size: "MN", want: true},
+		{name: "combined sizes scope", token: GenerateRenderToken("MN,IT,S"), size: "MN,IT,S", want: true},
+		{name: "token issued for another size", token: GenerateRenderToken("XS"), size: "L", want: false},
+		{name: "expired token", token: fmt.Sprintf("%d.%s", expired, signRender("XS", expired)), size: "XS", want: false},
+		{name: "tampered signature", token: GenerateRenderToken("XS") + "00", size: "XS", want: false},
+		{name: "missing signature", token: "1700000000", size: "XS", want: false},
+		{name: "non numeric expiry", token: "soon.abcdef", size: "XS", want: false},
+		{name: "empty token", token: "", size: "XS", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyRenderToken(tt.token, tt.size); got != tt.want {
+				t.Errorf("VerifyRenderToken(%q, %q) = %v, want %v", tt.token, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAuthorizedRenderRequest(t *testing.T) {
+	xsToken := GenerateRenderToken("XS")
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   bool
+	}{
+		{
+			name:   "catalog render for the signed size",
+			method: http.MethodGet,
+			target: "/admin/catalog/render?size=XS&" + RenderTokenParam + "=" + xsToken,
+			want:   true,
+		},
+		{
+			name:   "combined catalog render signed for the size list",
+			method: http.MethodGet,
+			target: "/admin/catalog/render?sizes=MN,IT,S&" + RenderTokenParam + "=" + GenerateRenderToken("MN,IT,S"),
+			want:   true,
+		},
+		{
+			name:   "signed design asset image",
+			method: http.MethodGet,
+			target: SignRenderImageURL("/admin/design-assets/pending/7/image?size=medium", "XS"),
+			want:   true,
+		},
+		{
+			name:   "catalog render for another size",
+			method: http.MethodGet,
+			target: "/admin/catalog/render?size=L&" + RenderTokenParam + "=" + xsToken,
+			want:   false,
+		},
+		{
+			name:   "combined catalog with a single size token",
+			method: http.MethodGet,
+			target: "/admin/catalog/render?sizes=MN,IT,S&" + RenderTokenParam + "=" + GenerateRenderToken("MN"),
+			want:   false,
+		},
+		{
+			name:   "catalog render without token",
+			method: http.MethodGet,
+			target: "/admin/catalog/render?size=XS",
+			want:   false,
+		},
+		{
+			name:   "token outside the render routes",
+			method: http.MethodGet,
+			target: "/admin/sales?" + RenderTokenParam + "=" + xsToken + "&size=XS",
+			want:   false,
+		},
+		{
+			name:   "non GET request",
+			method: http.MethodPost,
+			target: "/admin/catalog/render?size=XS&" + RenderTokenParam + "=" + xsToken,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			if got := IsAuthorizedRenderRequest(r); got != tt.want {
+				t.Errorf("IsAuthorizedRenderRequest(%s %s) = %v, want %v", tt.method, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RequestIDHeader is the header carrying the request correlation id (honored on input, echoed on output)
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key holding the request id
+type requestIDKey struct{}
+
+// NewRequestID returns a random 16-character hex id
+func NewRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Extremely unlikely; fall back to a time-based id so requests stay traceable
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID returns a copy of ctx carrying the request id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id stored in ctx, or "" when there is none
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// Logf is log.Printf prefixed with the request id from ctx (when present), so log lines from
+// concurrent requests can be correlated. Example: "[req=3f9a1c0d2b7e4a65] 📦 Sell: ..."
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		log.Printf("[req=%s] "+format, append([]interface{}{requestID}, args...)...)
+		return
+	}
+	log.Printf(format, args...)
+}
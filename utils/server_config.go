@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultServerPort is used when PORT is not set
+const defaultServerPort = "8080"
+
+// ServerPort returns the port to listen on (PORT, default 8080) without a leading colon.
+// PORT may be given as "3000" or ":3000" (Render sets it without the colon)
+func ServerPort() string {
+	port := strings.TrimPrefix(strings.TrimSpace(os.Getenv("PORT")), ":")
+	if port == "" {
+		return defaultServerPort
+	}
+	return port
+}
+
+// ServerAddr returns the listen address on all interfaces (required for Docker/Render)
+func ServerAddr() string {
+	return "0.0.0.0:" + ServerPort()
+}
+
+// BaseURL returns the public URL of this server (BASE_URL, without trailing slash), used to build
+// absolute image endpoints for catalog PDF/PNG generation. When unset it falls back to
+// http://localhost:<PORT>, which only works when the renderer runs on the same host
+func BaseURL() string {
+	baseURL := strings.TrimRight(strings.TrimSpace(os.Getenv("BASE_URL")), "/")
+	if baseURL == "" {
+		return "http://localhost:" + ServerPort()
+	}
+	return baseURL
+}
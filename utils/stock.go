@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultLowStockThreshold is the stock level at or below which an item is considered low on stock
+const DefaultLowStockThreshold = 5
+
+// ParseLowStockThreshold parses an optional threshold query value, defaulting to DefaultLowStockThreshold
+func ParseLowStockThreshold(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return DefaultLowStockThreshold, nil
+	}
+	threshold, err := strconv.Atoi(raw)
+	if err != nil || threshold < 0 {
+		return 0, fmt.Errorf("threshold must be a non-negative integer")
+	}
+	return threshold, nil
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultStoreName is shown on invoices when STORE_NAME is not set
+const defaultStoreName = "Armario Mascota"
+
+// DefaultCurrency is the store currency used when the pricing engine is not loaded
+const DefaultCurrency = "COP"
+
+// StoreName returns the store name printed on customer-facing documents (STORE_NAME, default "Armario Mascota")
+func StoreName() string {
+	if name := strings.TrimSpace(os.Getenv("STORE_NAME")); name != "" {
+		return name
+	}
+	return defaultStoreName
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamTokenParam is the query parameter carrying the reserved order events stream token
+const StreamTokenParam = "streamToken"
+
+// StreamTokenPath is the only route a stream token opens
+const StreamTokenPath = "/admin/reserved-orders/events"
+
+// StreamTokenTTL is how long a stream token can open the events stream. An open stream is not cut
+// when the token expires, but EventSource reconnects after that get a 401 and need a new token
+const StreamTokenTTL = 5 * time.Minute
+
+// signStream computes the signature for a stream token expiry. The "order-events" prefix keeps
+// stream and render tokens from being swapped even though they share the secret
+func signStream(expiresAt int64) string {
+	mac := hmac.New(sha256.New, getRenderSecret())
+	mac.Write([]byte(fmt.Sprintf("order-events|%d", expiresAt)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateStreamToken creates a short-lived signed token scoped to the reserved order events stream,
+// for EventSource clients that cannot send the admin key header. Format: "<expiresAtUnix>.<hexSignature>"
+func GenerateStreamToken() (string, time.Time) {
+	expiresAt := time.Now().Add(StreamTokenTTL)
+	return fmt.Sprintf("%d.%s", expiresAt.Unix(), signStream(expiresAt.Unix())), expiresAt
+}
+
+// VerifyStreamToken checks that a token is well-formed, not expired and was issued for the events stream
+func VerifyStreamToken(token string) bool {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(parts[1]), []byte(signStream(expiresAt)))
+}
+
+// IsAuthorizedStreamRequest reports whether the request opens the reserved order events stream with a
+// valid stream token: GET /admin/reserved-orders/events?streamToken=...
+// Auth middleware protecting /admin/* must accept it since EventSource cannot set headers
+func IsAuthorizedStreamRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet || r.URL.Path != StreamTokenPath {
+		return false
+	}
+	token := r.URL.Query().Get(StreamTokenParam)
+	return token != "" && VerifyStreamToken(token)
+}
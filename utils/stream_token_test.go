@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsAuthorizedStreamRequest(t *testing.T) {
+	token, expiresAt := GenerateStreamToken()
+	if ttl := time.Until(expiresAt); ttl <= 0 || ttl > StreamTokenTTL {
+		t.Fatalf("GenerateStreamToken expiry in %v, want within %v", ttl, StreamTokenTTL)
+	}
+	expired := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   bool
+	}{
+		{
+			name:   "events stream with a valid token",
+			method: http.MethodGet,
+			target: StreamTokenPath + "?" + StreamTokenParam + "=" + token,
+			want:   true,
+		},
+		{
+			name:   "expired token",
+			method: http.MethodGet,
+			target: StreamTokenPath + "?" + StreamTokenParam + "=" + fmt.Sprintf("%d.%s", expired, signStream(expired)),
+			want:   false,
+		},
+		{
+			name:   "tampered signature",
+			method: http.MethodGet,
+			target: StreamTokenPath + "?" + StreamTokenParam + "=" + token + "00",
+			want:   false,
+		},
+		{
+			name:   "render token used as stream token",
+			method: http.MethodGet,
+			target: StreamTokenPath + "?" + StreamTokenParam + "=" + GenerateRenderToken("XS"),
+			want:   false,
+		},
+		{
+			name:   "missing token",
+			method: http.MethodGet,
+			target: StreamTokenPath,
+			want:   false,
+		},
+		{
+			name:   "token on another admin route",
+			method: http.MethodGet,
+			target: "/admin/reserved-orders?" + StreamTokenParam + "=" + token,
+			want:   false,
+		},
+		{
+			name:   "non GET request",
+			method: http.MethodPost,
+			target: StreamTokenPath + "?" + StreamTokenParam + "=" + token,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			if got := IsAuthorizedStreamRequest(r); got != tt.want {
+				t.Errorf("IsAuthorizedStreamRequest(%s %s) = %v, want %v", tt.method, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultAppTimezone is the store's local timezone, used when APP_TIMEZONE is not set
+const defaultAppTimezone = "America/Bogota"
+
+var (
+	appLocation     *time.Location
+	appLocationOnce sync.Once
+)
+
+// AppLocation returns the store's timezone (APP_TIMEZONE, default America/Bogota), loaded once.
+// Day boundaries for date filters ("from"/"to" as YYYY-MM-DD) are computed in this zone.
+func AppLocation() *time.Location {
+	appLocationOnce.Do(func() {
+		name := os.Getenv("APP_TIMEZONE")
+		if name == "" {
+			name = defaultAppTimezone
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			// Bogota has no DST, so a fixed UTC-5 offset is exact when tzdata is missing
+			log.Printf("⚠️ AppLocation: Could not load timezone %s (%v), using UTC-5", name, err)
+			loc = time.FixedZone(defaultAppTimezone, -5*60*60)
+		}
+		appLocation = loc
+	})
+	return appLocation
+}
+
+// ParseLocalDate parses a YYYY-MM-DD date as the start of that day in AppLocation
+func ParseLocalDate(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, AppLocation())
+}
+
+// EndOfDay returns the last instant of t's day in t's location
+func EndOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, t.Location())
+}